@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	_ "time/tzdata" // the image has no system zoneinfo.
 
 	"github.com/andreyxaxa/calendar/config"
 	"github.com/andreyxaxa/calendar/internal/app"
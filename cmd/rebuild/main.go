@@ -0,0 +1,29 @@
+// Command rebuild replays the log of the eventstore storage (EVENTSTORE_DIR) into new projections
+// and replaces their checkpoint. The app must be stopped meanwhile.
+package main
+
+import (
+	"log"
+
+	"github.com/andreyxaxa/calendar/config"
+	"github.com/andreyxaxa/calendar/internal/repo/eventstore"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("no .env file found")
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		log.Fatalf("config error: %s", err)
+	}
+
+	seq, err := eventstore.Rebuild(cfg.EventStore.Dir)
+	if err != nil {
+		log.Fatalf("rebuild error: %s", err)
+	}
+
+	log.Printf("projections of %s rebuilt from %d domain events", cfg.EventStore.Dir, seq)
+}
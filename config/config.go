@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 )
@@ -9,14 +10,23 @@ import (
 type (
 	// Config -.
 	Config struct {
-		HTTP    HTTP
-		Log     Log
-		Swagger Swagger
+		HTTP        HTTP
+		Log         Log
+		Swagger     Swagger
+		Storage     Storage
+		PG          PG
+		SQLite      SQLite
+		InMemory    InMemory
+		EventStore  EventStore
+		Idempotency Idempotency
+		Trash       Trash
+		Webhooks    Webhooks
 	}
 
-	// HTTP -.
+	// HTTP - BodyLimitMB bounds request bodies, .ics imports are the largest.
 	HTTP struct {
-		Port string `env:"HTTP_PORT,required"`
+		Port        string `env:"HTTP_PORT,required"`
+		BodyLimitMB int    `env:"HTTP_BODY_LIMIT_MB" envDefault:"16"`
 	}
 
 	// Log -.
@@ -28,6 +38,56 @@ type (
 	Swagger struct {
 		Enabled bool `env:"SWAGGER_ENABLED" envDefault:"false"`
 	}
+
+	// Storage - Driver is one of inmemory, sqlite, postgres, eventstore.
+	Storage struct {
+		Driver string `env:"STORAGE_DRIVER" envDefault:"inmemory"`
+	}
+
+	// PG -.
+	PG struct {
+		PoolMax int    `env:"PG_POOL_MAX" envDefault:"2"`
+		URL     string `env:"PG_URL"`
+	}
+
+	// SQLite -.
+	SQLite struct {
+		Path string `env:"SQLITE_PATH" envDefault:"calendar.db"`
+	}
+
+	// InMemory - the journal is disabled when JournalDir is empty.
+	InMemory struct {
+		JournalDir    string `env:"INMEMORY_JOURNAL_DIR"`
+		SnapshotEvery int    `env:"INMEMORY_SNAPSHOT_EVERY" envDefault:"1000"`
+	}
+
+	// EventStore - Dir keeps the log of domain events and the checkpoint of its projections.
+	EventStore struct {
+		Dir             string `env:"EVENTSTORE_DIR" envDefault:"eventstore"`
+		CheckpointEvery int    `env:"EVENTSTORE_CHECKPOINT_EVERY" envDefault:"1000"`
+	}
+
+	// Idempotency - TTL is how long responses to requests with Idempotency-Key are replayed.
+	Idempotency struct {
+		TTL time.Duration `env:"IDEMPOTENCY_TTL" envDefault:"24h"`
+	}
+
+	// Trash - deleted events are purged Retention after deletion, the trash is checked every PurgeInterval.
+	Trash struct {
+		Retention     time.Duration `env:"TRASH_RETENTION" envDefault:"720h"`
+		PurgeInterval time.Duration `env:"TRASH_PURGE_INTERVAL" envDefault:"1h"`
+	}
+
+	// Webhooks - a delivery is attempted up to MaxAttempts times, retries wait Backoff, doubled after
+	// each failure. Requests time out after Timeout, due retries are checked every PollInterval.
+	// Up to QueueSize notifications wait to be stored as deliveries, the ones beyond are dropped.
+	Webhooks struct {
+		MaxAttempts  int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"8"`
+		Backoff      time.Duration `env:"WEBHOOK_BACKOFF" envDefault:"30s"`
+		Timeout      time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s"`
+		PollInterval time.Duration `env:"WEBHOOK_POLL_INTERVAL" envDefault:"5s"`
+		QueueSize    int           `env:"WEBHOOK_QUEUE_SIZE" envDefault:"10000"`
+	}
 )
 
 // New returns app config.
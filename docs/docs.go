@@ -17,7 +17,7 @@ const docTemplate = `{
     "paths": {
         "/v1/create_event": {
             "post": {
-                "description": "Creates event by user_id, date, text",
+                "description": "Creates event by user_id, text and either start (with optional end) or date for an all-day event.\ntz defaults to the user's time zone. A retry with the same Idempotency-Key replays the first response",
                 "consumes": [
                     "application/json"
                 ],
@@ -30,13 +30,88 @@ const docTemplate = `{
                 "summary": "Create",
                 "operationId": "create",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key to retry the request safely",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
                     {
                         "description": "Event",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.CreateRequest"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.CreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Response"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the event"
+                            },
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true for a replayed response"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/create_webhook": {
+            "post": {
+                "description": "Subscribes url to writes of events of the user: each write of the kinds in events\n(created, updated, deleted, all of them when empty) is POSTed to it as JSON signed with secret -\nX-Webhook-Signature: sha256= and hex of HMAC-SHA256 of the body. A failed delivery is retried with\nexponential backoff, after the last attempt it goes to the dead-letter list. secret is generated\nunless set, it is returned only here",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create webhook",
+                "operationId": "create-webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.CreateWebhookRequest"
                         }
                     }
                 ],
@@ -44,19 +119,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.WebhookResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     }
                 }
@@ -64,7 +139,7 @@ const docTemplate = `{
         },
         "/v1/delete_event": {
             "post": {
-                "description": "Deletes event",
+                "description": "Deletes event, a whole one goes to the trash, see /v1/trash.\nFor a recurring one scope tells which instances go:\nall (default), this or following the one starting at recurrence_id.\nIf-Match makes it fail with 412 unless the event (the series) has that version",
                 "consumes": [
                     "application/json"
                 ],
@@ -77,13 +152,19 @@ const docTemplate = `{
                 "summary": "Delete",
                 "operationId": "delete",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the expected version",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
                     {
                         "description": "Event",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.DeleteRequest"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.DeleteRequest"
                         }
                     }
                 ],
@@ -91,131 +172,343 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Response"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     }
                 }
             }
         },
-        "/v1/events_for_day": {
+        "/v1/delete_webhook": {
+            "post": {
+                "description": "Deletes the webhook with its deliveries, pending ones are not attempted any more",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete webhook",
+                "operationId": "delete-webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.DeleteWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/event": {
             "get": {
-                "description": "Get events for day by date",
+                "description": "Get event by uid, a series with its rrule and exdates",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "events"
                 ],
-                "summary": "Get events for day",
-                "operationId": "get-day",
+                "summary": "Get event",
+                "operationId": "get-event",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "string",
-                        "description": "Date",
-                        "name": "date",
-                        "in": "query"
+                        "description": "Event UID",
+                        "name": "uid",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Response"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the event"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Changes only the fields present in the body, a JSON Merge Patch (RFC 7396) of the update\nrequest fields: null removes a field, absent ones stay. date or start replace the timing\n(end and all_day go with them), removing rrule ends the series, removing tz sets the user's one.\nIf-Match makes it fail with 412 unless the event has that version, so does a write\nof the event between its read and the patched one",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Patch event",
+                "operationId": "patch-event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event UID",
+                        "name": "uid",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the expected version",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Merge patch",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Response"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the event"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     }
                 }
             }
         },
-        "/v1/events_for_month": {
+        "/v1/event/history": {
             "get": {
-                "description": "Get events for month by date",
+                "description": "Get revisions of the event, the first one first: who made each write (X-Actor header\nof the request, user:\u003cid\u003e without it), when, the changed fields and the event after it",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "events"
+                    "history"
                 ],
-                "summary": "Get events for month",
-                "operationId": "get-month",
+                "summary": "Get event history",
+                "operationId": "get-event-history",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "string",
-                        "description": "Date",
-                        "name": "date",
-                        "in": "query"
+                        "description": "Event UID",
+                        "name": "uid",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.HistoryResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     }
                 }
             }
         },
-        "/v1/events_for_week": {
+        "/v1/events": {
             "get": {
-                "description": "Get events for week by date",
+                "description": "Events of the user within [from, to), instances of series included, ordered by start\n(order=desc reverses it). Pages hold up to limit events, next_cursor of a page is passed\nas cursor to get the next one.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "events"
                 ],
-                "summary": "Get events for week",
-                "operationId": "get-week",
+                "summary": "Get events for range",
+                "operationId": "get-range",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "string",
-                        "description": "Date",
-                        "name": "date",
+                        "description": "Start of the range, YYYY-MM-DD (midnight in tz) or RFC 3339",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range, exclusive, YYYY-MM-DD (midnight in tz) or RFC 3339",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of date bounds, defaults to the user's one",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc (default) or desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1 to 500, 50 by default",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
                         "in": "query"
                     }
                 ],
@@ -223,33 +516,33 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.EventsPage"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     }
                 }
             }
         },
-        "/v1/update_event": {
+        "/v1/events:batch": {
             "post": {
-                "description": "Updates event",
+                "description": "Runs create, update and delete operations for the user in order and returns their results.\nOperations are validated like single requests, an invalid one fails the whole batch with 400.\nEach operation succeeds or fails alone, unless atomic: then the first failure is returned\nwith its status and none of the operations is applied",
                 "consumes": [
                     "application/json"
                 ],
@@ -259,16 +552,16 @@ const docTemplate = `{
                 "tags": [
                     "events"
                 ],
-                "summary": "Update",
-                "operationId": "update",
+                "summary": "Batch",
+                "operationId": "batch",
                 "parameters": [
                     {
-                        "description": "Event",
+                        "description": "Operations",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.UpdateRequest"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.BatchRequest"
                         }
                     }
                 ],
@@ -276,114 +569,2090 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.BatchResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Error"
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "date.Date": {
-            "type": "object",
-            "properties": {
-                "time.Time": {
-                    "type": "string"
+        },
+        "/v1/events_for_day": {
+            "get": {
+                "description": "Get events for day by date",
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get events for day",
+                "operationId": "get-day",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date, YYYY-MM-DD",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of period boundaries, defaults to the user's one",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
                 }
             }
         },
-        "request.CreateRequest": {
-            "type": "object",
-            "properties": {
-                "date": {
-                    "$ref": "#/definitions/date.Date"
-                },
-                "text": {
-                    "type": "string"
-                },
+        "/v1/events_for_month": {
+            "get": {
+                "description": "Get events for month by date",
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get events for month",
+                "operationId": "get-month",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date, YYYY-MM-DD",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of period boundaries, defaults to the user's one",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/events_for_week": {
+            "get": {
+                "description": "Get events for week by date",
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get events for week",
+                "operationId": "get-week",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date, YYYY-MM-DD",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of period boundaries, defaults to the user's one",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/restore_event": {
+            "post": {
+                "description": "Moves a deleted event back from the trash, with the next version.\nFails with 409 if an event was created under its uid after the deletion",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore",
+                "operationId": "restore",
+                "parameters": [
+                    {
+                        "description": "Event",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.RestoreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Response"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the event"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/revert_event": {
+            "post": {
+                "description": "Brings the event back to what it was after the revision, a write of its own in the history.\nReverting to a delete deletes the event, an event in the trash is to be restored first.\nIf-Match makes it fail with 412 unless the event has that version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "history"
+                ],
+                "summary": "Revert event",
+                "operationId": "revert-event",
+                "parameters": [
+                    {
+                        "description": "Revision",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.RevertRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected version of the event",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Response"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the event"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stream": {
+            "get": {
+                "description": "Server-sent events of the user created, updated or deleted from now on. Each message\nhas id, event - created, updated, deleted or reset - and data: the event as stored or,\nfor deleted, its uid. On reconnect the client passes the id of the last message it got in\nLast-Event-ID, the messages after it come first. If some of them are lost, reset comes\ninstead: the client has to reload events, e.g. by /v1/sync",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "stream"
+                ],
+                "summary": "Stream event changes",
+                "operationId": "stream",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last message got",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Same as Last-Event-ID, for clients that cant set headers",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Notification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/sync": {
+            "get": {
+                "description": "Events of the user created, updated or deleted since sync_token, in the order of their\nlatest change, without sync_token - all of them. Deleted events come as tombstones: uid and\ndeleted. Pages hold up to limit changes, sync_token of the response is passed to get the\nnext one or, once more is false, the changes made later. 410 means the token is no longer\nvalid and the client has to sync without it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sync"
+                ],
+                "summary": "Sync events",
+                "operationId": "sync",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "sync_token of the previous sync",
+                        "name": "sync_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1 to 500, 50 by default",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.SyncResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/trash": {
+            "get": {
+                "description": "Get deleted events of the user, the latest deleted first.\nThey are purged for good TRASH_RETENTION after the deletion",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Get trash",
+                "operationId": "get-trash",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.TrashResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/update_event": {
+            "post": {
+                "description": "Updates event. For a recurring one scope tells which instances change:\nall (default), this or following the one starting at recurrence_id.\nIf-Match makes it fail with 412 unless the event (the series) has that version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Update",
+                "operationId": "update",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the expected version",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Event",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.UpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Response"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the event"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/update_user": {
+            "post": {
+                "description": "Updates user settings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user",
+                "operationId": "update-user",
+                "parameters": [
+                    {
+                        "description": "User",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user": {
+            "get": {
+                "description": "Get user settings, tz is the default time zone of user's events and period queries",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user",
+                "operationId": "get-user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}/calendar.ics": {
+            "get": {
+                "description": "All events of the user as an iCalendar (RFC 5545) feed, for read-only subscriptions from calendar clients.\nSeries come with RRULE and EXDATE, changed instances as separate VEVENTs with RECURRENCE-ID.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Export calendar",
+                "operationId": "export-calendar",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "VCALENDAR",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}/import": {
+            "post": {
+                "description": "Creates events from an iCalendar (RFC 5545) file: all-day, timed and recurring VEVENTs,\nchanged and cancelled instances of a series with RECURRENCE-ID. The file is sent as the body\nor as the \"file\" field of a multipart form. Times without a zone are taken in tz, by default\nthe user's time zone. The report lists imported, skipped and failed VEVENTs with their lines,\nevents imported before are skipped, so a file can be imported again.",
+                "consumes": [
+                    "text/calendar",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Import calendar",
+                "operationId": "import-calendar",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of floating times, defaults to the user's one",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "iCalendar file",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/webhooks": {
+            "get": {
+                "description": "Get webhooks of the user, the oldest first. Secrets are not returned",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhooks",
+                "operationId": "get-webhooks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.WebhooksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/webhooks/deliveries": {
+            "get": {
+                "description": "Get deliveries of the webhook with their attempts, the newest first. status filters them:\npending, delivered or dead - the dead-letter list of deliveries whose attempts all failed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook deliveries",
+                "operationId": "get-webhook-deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, delivered or dead",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "1 to 500, 50 by default",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.DeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/users/{userID}/events": {
+            "get": {
+                "description": "Events of the user within [from, to), instances of series included, ordered by start\n(order=desc reverses it). Pages hold up to limit events, next_cursor of a page is passed\nas cursor to get the next one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events v2"
+                ],
+                "summary": "List events",
+                "operationId": "v2-list-events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the range, YYYY-MM-DD (midnight in tz) or RFC 3339",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range, exclusive, YYYY-MM-DD (midnight in tz) or RFC 3339",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of date bounds, defaults to the user's one",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc (default) or desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1 to 500, 50 by default",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.EventsPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates an event from text and either start (with optional end) or date for an all-day event.\ntz defaults to the user's time zone. Location holds the URL of the event.\nA retry with the same Idempotency-Key replays the first response",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events v2"
+                ],
+                "summary": "Create event",
+                "operationId": "v2-create-event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key to retry the request safely",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Event",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_request.Event"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Event"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the event"
+                            },
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true for a replayed response"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/users/{userID}/events/{uid}": {
+            "get": {
+                "description": "Returns the event, a series with its rrule and exdates",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events v2"
+                ],
+                "summary": "Get event",
+                "operationId": "v2-get-event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Event"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the event"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the event, creating it under uid if there is none. Changed instances\nof a series are kept as long as its instances stay where they were.\nIf-Match makes it fail with 412 unless the event exists and has that version,\nIf-None-Match: * unless there is no event",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events v2"
+                ],
+                "summary": "Put event",
+                "operationId": "v2-put-event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the expected version or *",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "* to only create the event",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Event",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_request.Event"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Event"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the event"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Event"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the event"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes the event, a whole one goes to the trash, see /v1/trash.\nFor a recurring one scope tells which instances go:\nall (default), this or following the one starting at recurrence_id.\nIf-Match makes it fail with 412 unless the event (the series) has that version",
+                "tags": [
+                    "events v2"
+                ],
+                "summary": "Delete event",
+                "operationId": "v2-delete-event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "all (default), this or following",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Original start of the instance, RFC 3339",
+                        "name": "recurrence_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the expected version",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Changes only the fields present in the body, a JSON Merge Patch (RFC 7396) of the event:\nnull removes a field, absent ones stay. date or start replace the timing (end and all_day\ngo with them), removing rrule ends the series, removing tz sets the user's one.\nIf-Match makes it fail with 412 unless the event has that version, so does a write\nof the event between its read and the patched one",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events v2"
+                ],
+                "summary": "Patch event",
+                "operationId": "v2-patch-event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event UID",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the expected version",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Merge patch",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_request.Event"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Event"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the event"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "date.Date": {
+            "type": "object",
+            "properties": {
+                "time.Time": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.BatchOperation": {
+            "type": "object",
+            "properties": {
+                "all_day": {
+                    "type": "boolean"
+                },
+                "date": {
+                    "$ref": "#/definitions/date.Date"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "exdates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "op": {
+                    "type": "string"
+                },
+                "rrule": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "tz": {
+                    "type": "string"
+                },
+                "uid": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.BatchRequest": {
+            "type": "object",
+            "properties": {
+                "atomic": {
+                    "type": "boolean"
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.BatchOperation"
+                    }
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.CreateRequest": {
+            "type": "object",
+            "properties": {
+                "all_day": {
+                    "type": "boolean"
+                },
+                "date": {
+                    "$ref": "#/definitions/date.Date"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "exdates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rrule": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "tz": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.CreateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.DeleteRequest": {
+            "type": "object",
+            "properties": {
+                "recurrence_id": {
+                    "type": "string"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "uid": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.DeleteWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.RestoreRequest": {
+            "type": "object",
+            "properties": {
+                "uid": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.RevertRequest": {
+            "type": "object",
+            "properties": {
+                "revision": {
+                    "type": "integer"
+                },
+                "uid": {
+                    "type": "string"
+                },
                 "user_id": {
                     "type": "integer"
                 }
             }
         },
-        "request.DeleteRequest": {
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.UpdateRequest": {
+            "type": "object",
+            "properties": {
+                "all_day": {
+                    "type": "boolean"
+                },
+                "date": {
+                    "$ref": "#/definitions/date.Date"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "exdates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "recurrence_id": {
+                    "type": "string"
+                },
+                "rrule": {
+                    "type": "string"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "tz": {
+                    "type": "string"
+                },
+                "uid": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_request.UpdateUserRequest": {
+            "type": "object",
+            "properties": {
+                "tz": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.BatchResponse": {
+            "type": "object",
+            "properties": {
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.BatchResult"
+                    }
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.BatchResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "event": {
+                    "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.ResultEvent"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "uid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Change": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "boolean"
+                },
+                "event": {
+                    "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.ResultEvent"
+                },
+                "uid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.DeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Delivery"
+                    }
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Delivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.DeliveryAttempt"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.DeliveryAttempt": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Error": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.EventsPage": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.ResultEvent"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.FieldChange": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "field": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.HistoryResponse": {
+            "type": "object",
+            "properties": {
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Revision"
+                    }
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.ImportEntry": {
+            "type": "object",
+            "properties": {
+                "ical_uid": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "uid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.ImportReport": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.ImportEntry"
+                    }
+                },
+                "imported": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.ImportEntry"
+                    }
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.ImportEntry"
+                    }
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Notification": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.ResultEvent"
+                },
+                "uid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Response": {
+            "type": "object",
+            "properties": {
+                "result": {
+                    "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.ResultEvent"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.ResultEvent": {
             "type": "object",
             "properties": {
+                "all_day": {
+                    "type": "boolean"
+                },
+                "date": {
+                    "$ref": "#/definitions/date.Date"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "exdates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "recurrence_id": {
+                    "type": "string"
+                },
+                "rrule": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "tz": {
+                    "type": "string"
+                },
                 "uid": {
                     "type": "string"
                 },
                 "user_id": {
                     "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Revision": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "at": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.FieldChange"
+                    }
+                },
+                "event": {
+                    "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.ResultEvent"
+                },
+                "op": {
+                    "type": "string"
+                },
+                "revision": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.SyncResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Change"
+                    }
+                },
+                "more": {
+                    "type": "boolean"
+                },
+                "sync_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.TrashResponse": {
+            "type": "object",
+            "properties": {
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.TrashedEvent"
+                    }
                 }
             }
         },
-        "request.UpdateRequest": {
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.TrashedEvent": {
             "type": "object",
             "properties": {
+                "all_day": {
+                    "type": "boolean"
+                },
                 "date": {
                     "$ref": "#/definitions/date.Date"
                 },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "exdates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "recurrence_id": {
+                    "type": "string"
+                },
+                "rrule": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
                 "text": {
                     "type": "string"
                 },
+                "tz": {
+                    "type": "string"
+                },
                 "uid": {
                     "type": "string"
                 },
                 "user_id": {
                     "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "response.Error": {
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.User": {
             "type": "object",
             "properties": {
-                "error": {
+                "tz": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
                     "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "result": {
+                    "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Webhook"
                 }
             }
         },
-        "response.Response": {
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.WebhooksResponse": {
             "type": "object",
             "properties": {
                 "result": {
-                    "$ref": "#/definitions/response.ResultEvent"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v1_response.Webhook"
+                    }
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v2_request.Event": {
+            "type": "object",
+            "properties": {
+                "all_day": {
+                    "type": "boolean"
+                },
+                "date": {
+                    "$ref": "#/definitions/date.Date"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "exdates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rrule": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "tz": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Error": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
                 }
             }
         },
-        "response.ResultEvent": {
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Event": {
             "type": "object",
             "properties": {
+                "all_day": {
+                    "type": "boolean"
+                },
                 "date": {
                     "$ref": "#/definitions/date.Date"
                 },
+                "end": {
+                    "type": "string"
+                },
+                "exdates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "recurrence_id": {
+                    "type": "string"
+                },
+                "rrule": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
                 "text": {
                     "type": "string"
                 },
+                "tz": {
+                    "type": "string"
+                },
                 "uid": {
                     "type": "string"
                 },
-                "user_id": {
+                "version": {
                     "type": "integer"
                 }
             }
+        },
+        "github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.EventsPage": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_andreyxaxa_calendar_internal_controller_restapi_v2_response.Event"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
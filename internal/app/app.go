@@ -1,17 +1,26 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/andreyxaxa/calendar/config"
+	"github.com/andreyxaxa/calendar/internal/controller/caldav"
 	"github.com/andreyxaxa/calendar/internal/controller/restapi"
-	"github.com/andreyxaxa/calendar/internal/repo/inmemory"
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo/history"
+	"github.com/andreyxaxa/calendar/internal/usecase/calendar"
 	"github.com/andreyxaxa/calendar/internal/usecase/events"
+	"github.com/andreyxaxa/calendar/internal/usecase/idempotency"
+	"github.com/andreyxaxa/calendar/internal/usecase/users"
+	"github.com/andreyxaxa/calendar/internal/usecase/webhooks"
 	"github.com/andreyxaxa/calendar/pkg/httpserver"
 	"github.com/andreyxaxa/calendar/pkg/logger"
+	"github.com/andreyxaxa/calendar/pkg/pubsub"
+	"github.com/andreyxaxa/calendar/pkg/webhook"
 )
 
 // Run -.
@@ -19,14 +28,57 @@ func Run(cfg *config.Config) {
 	l := logger.New(cfg.Log.Level)
 
 	// Repository
-	inmem := inmemory.New()
+	repos, err := newRepositories(cfg)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - newRepositories: %w", err))
+	}
+	defer repos.close()
+
+	// History
+	eventsRepo := history.New(repos.events)
+
+	// Notifications
+	notifications := pubsub.New[int, entity.Notification]()
 
 	// Use-Case
-	eventsUseCase := events.New(inmem)
+	webhooksUseCase := webhooks.New(
+		repos.webhooks,
+		webhook.New(webhook.Timeout(cfg.Webhooks.Timeout)),
+		cfg.Webhooks.MaxAttempts,
+		cfg.Webhooks.Backoff,
+		cfg.Webhooks.QueueSize,
+	)
+	eventsUseCase := events.New(eventsRepo, notifications, webhooksUseCase.Notify)
+	calendarUseCase := calendar.New(eventsRepo, eventsUseCase)
+	usersUseCase := users.New(repos.users)
+	idempotencyUseCase := idempotency.New(repos.idempotency, cfg.Idempotency.TTL)
+
+	// Trash purge
+	ctx, cancel := context.WithCancel(context.Background())
+	purged := make(chan struct{})
+
+	go func() {
+		defer close(purged)
+		purgeTrash(ctx, eventsUseCase, cfg.Trash.Retention, cfg.Trash.PurgeInterval, l)
+	}()
+
+	// Webhook deliveries
+	delivered := make(chan struct{})
+
+	go func() {
+		defer close(delivered)
+		deliverWebhooks(ctx, webhooksUseCase, cfg.Webhooks.PollInterval, l)
+	}()
 
 	// HTTP Server
-	httpServer := httpserver.New(httpserver.Port(cfg.HTTP.Port))
-	restapi.NewRouter(httpServer.App, cfg, eventsUseCase, l)
+	httpServer := httpserver.New(
+		httpserver.Port(cfg.HTTP.Port),
+		httpserver.BodyLimit(cfg.HTTP.BodyLimitMB*1024*1024),
+		httpserver.Methods(caldav.Methods...),
+	)
+	restapi.NewRouter(httpServer.App, cfg, eventsUseCase, calendarUseCase, usersUseCase, idempotencyUseCase,
+		webhooksUseCase, l)
+	caldav.NewRouter(httpServer.App, calendarUseCase, usersUseCase, l)
 
 	// Start server
 	httpServer.Start()
@@ -42,8 +94,16 @@ func Run(cfg *config.Config) {
 		l.Error(fmt.Errorf("app - Run - httpServer.Notify: %w", err))
 	}
 
-	err := httpServer.Shutdown()
+	// streams end first, the server would wait for them.
+	notifications.Close()
+
+	err = httpServer.Shutdown()
 	if err != nil {
 		l.Error(fmt.Errorf("app - Run - httpServer.Shutdown: %w", err))
 	}
+
+	// the repositories are closed after the purge and the deliveries stop.
+	cancel()
+	<-purged
+	<-delivered
 }
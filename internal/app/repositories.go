@@ -0,0 +1,146 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/andreyxaxa/calendar/config"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/repo/eventstore"
+	"github.com/andreyxaxa/calendar/internal/repo/inmemory"
+	pgrepo "github.com/andreyxaxa/calendar/internal/repo/postgres"
+	sqliterepo "github.com/andreyxaxa/calendar/internal/repo/sqlite"
+	"github.com/andreyxaxa/calendar/pkg/postgres"
+	"github.com/andreyxaxa/calendar/pkg/sqlite"
+)
+
+// repositories of the storage backend chosen by cfg.Storage.Driver.
+type repositories struct {
+	events      repo.EventsRepo
+	users       repo.UsersRepo
+	idempotency repo.IdempotencyRepo
+	webhooks    repo.WebhooksRepo
+	// close releases resources of the backend.
+	close func()
+}
+
+func newRepositories(cfg *config.Config) (*repositories, error) {
+	switch cfg.Storage.Driver {
+	case "inmemory":
+		if cfg.InMemory.JournalDir == "" {
+			r := inmemory.New()
+
+			return &repositories{
+				events:      r,
+				users:       inmemory.NewUsersRepo(r),
+				idempotency: inmemory.NewIdempotencyRepo(),
+				webhooks:    inmemory.NewWebhooksRepo(),
+				close:       func() {},
+			}, nil
+		}
+
+		r, err := inmemory.Open(cfg.InMemory.JournalDir, inmemory.SnapshotEvery(cfg.InMemory.SnapshotEvery))
+		if err != nil {
+			return nil, fmt.Errorf("inmemory.Open: %w", err)
+		}
+
+		idempotency, err := inmemory.OpenIdempotencyRepo(cfg.InMemory.JournalDir)
+		if err != nil {
+			r.Close()
+
+			return nil, fmt.Errorf("inmemory.OpenIdempotencyRepo: %w", err)
+		}
+
+		webhooks, err := inmemory.OpenWebhooksRepo(cfg.InMemory.JournalDir)
+		if err != nil {
+			r.Close()
+			idempotency.Close()
+
+			return nil, fmt.Errorf("inmemory.OpenWebhooksRepo: %w", err)
+		}
+
+		return &repositories{
+			events:      r,
+			users:       inmemory.NewUsersRepo(r),
+			idempotency: idempotency,
+			webhooks:    webhooks,
+			close: func() {
+				r.Close()
+				idempotency.Close()
+				webhooks.Close()
+			},
+		}, nil
+	case "sqlite":
+		s, err := sqlite.New(cfg.SQLite.Path)
+		if err != nil {
+			return nil, fmt.Errorf("sqlite.New: %w", err)
+		}
+
+		if err = sqliterepo.Migrate(s); err != nil {
+			s.Close()
+
+			return nil, fmt.Errorf("sqliterepo.Migrate: %w", err)
+		}
+
+		return &repositories{
+			events:      sqliterepo.New(s),
+			users:       sqliterepo.NewUsersRepo(s),
+			idempotency: sqliterepo.NewIdempotencyRepo(s),
+			webhooks:    sqliterepo.NewWebhooksRepo(s),
+			close:       s.Close,
+		}, nil
+	case "postgres":
+		pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(cfg.PG.PoolMax))
+		if err != nil {
+			return nil, fmt.Errorf("postgres.New: %w", err)
+		}
+
+		if err = pgrepo.Migrate(pg); err != nil {
+			pg.Close()
+
+			return nil, fmt.Errorf("pgrepo.Migrate: %w", err)
+		}
+
+		return &repositories{
+			events:      pgrepo.New(pg),
+			users:       pgrepo.NewUsersRepo(pg),
+			idempotency: pgrepo.NewIdempotencyRepo(pg),
+			webhooks:    pgrepo.NewWebhooksRepo(pg),
+			close:       pg.Close,
+		}, nil
+	case "eventstore":
+		r, err := eventstore.Open(cfg.EventStore.Dir, eventstore.CheckpointEvery(cfg.EventStore.CheckpointEvery))
+		if err != nil {
+			return nil, fmt.Errorf("eventstore.Open: %w", err)
+		}
+
+		// keys and webhooks are no domain events: they are journaled next to the log.
+		idempotency, err := inmemory.OpenIdempotencyRepo(cfg.EventStore.Dir)
+		if err != nil {
+			r.Close()
+
+			return nil, fmt.Errorf("inmemory.OpenIdempotencyRepo: %w", err)
+		}
+
+		webhooks, err := inmemory.OpenWebhooksRepo(cfg.EventStore.Dir)
+		if err != nil {
+			r.Close()
+			idempotency.Close()
+
+			return nil, fmt.Errorf("inmemory.OpenWebhooksRepo: %w", err)
+		}
+
+		return &repositories{
+			events:      r,
+			users:       eventstore.NewUsersRepo(r),
+			idempotency: idempotency,
+			webhooks:    webhooks,
+			close: func() {
+				r.Close()
+				idempotency.Close()
+				webhooks.Close()
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/usecase"
+	"github.com/andreyxaxa/calendar/pkg/logger"
+)
+
+// purgeTrash deletes events trashed more than retention ago, at start and then every interval,
+// until ctx is done.
+func purgeTrash(ctx context.Context, e usecase.Events, retention, interval time.Duration, l logger.Interface) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := e.PurgeTrash(ctx, time.Now().Add(-retention))
+		if err != nil {
+			l.Error(fmt.Errorf("app - purgeTrash - e.PurgeTrash: %w", err))
+		} else if purged > 0 {
+			l.Info("app - purgeTrash - purged %d events", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
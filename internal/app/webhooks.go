@@ -0,0 +1,37 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/usecase"
+	"github.com/andreyxaxa/calendar/pkg/logger"
+)
+
+// deliverWebhooks dispatches webhook deliveries at start, once notifications are queued and every
+// interval for due retries, until ctx is done. Notifications dropped meanwhile are logged.
+func deliverWebhooks(ctx context.Context, w usecase.Webhooks, interval time.Duration, l logger.Interface) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		dead, err := w.Dispatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			l.Error(fmt.Errorf("app - deliverWebhooks - w.Dispatch: %w", err))
+		} else if dead > 0 {
+			l.Warn("app - deliverWebhooks - %d deliveries moved to the dead-letter list", dead)
+		}
+
+		if dropped := w.Dropped(); dropped > 0 {
+			l.Warn("app - deliverWebhooks - %d notifications dropped, the queue is full", dropped)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.Queued():
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package caldav
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/usecase"
+	"github.com/andreyxaxa/calendar/pkg/logger"
+	"github.com/andreyxaxa/calendar/pkg/webdav"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	_root = "/dav/"
+	_ns   = "urn:ietf:params:xml:ns:caldav"
+	// _csNS - CalendarServer extensions, getctag is what older clients poll.
+	_csNS = "http://calendarserver.org/ns/"
+
+	_objectContentType = "text/calendar; charset=utf-8; component=VEVENT"
+)
+
+// CalDAV -.
+type CalDAV struct {
+	c usecase.Calendar
+	u usecase.Users
+	l logger.Interface
+}
+
+func davName(local string) xml.Name {
+	return xml.Name{Space: webdav.NS, Local: local}
+}
+
+func calName(local string) xml.Name {
+	return xml.Name{Space: _ns, Local: local}
+}
+
+func principalPath(userID int) string {
+	return _root + "users/" + strconv.Itoa(userID) + "/"
+}
+
+func calendarPath(userID int) string {
+	return principalPath(userID) + "calendar/"
+}
+
+func objectPath(userID int, uid uuid.UUID) string {
+	return calendarPath(userID) + uid.String() + ".ics"
+}
+
+// userID returns the user of the path, false for ids that cant be one.
+func userID(ctx *fiber.Ctx) (int, bool) {
+	u, err := ctx.ParamsInt("id")
+
+	return u, err == nil && u > 0
+}
+
+// objectUID maps a resource name such as {uid}.ics to the event UID.
+func objectUID(name string) (uuid.UUID, bool) {
+	name, err := url.PathUnescape(name)
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	name, ok := strings.CutSuffix(name, ".ics")
+	if !ok || name == "" {
+		return uuid.Nil, false
+	}
+
+	return entity.ImportUID(name), true
+}
+
+// depth returns the Depth header, infinity is taken as 1: the tree is two levels deep.
+func depth(ctx *fiber.Ctx) int {
+	if ctx.Get("Depth") == "0" {
+		return 0
+	}
+
+	return 1
+}
+
+// preconditions reads If-Match and If-None-Match, ETags there are quoted and may be weak.
+func preconditions(ctx *fiber.Ctx) entity.Preconditions {
+	return entity.Preconditions{
+		IfMatch:     etags(ctx.Get(fiber.HeaderIfMatch)),
+		IfNoneMatch: etags(ctx.Get(fiber.HeaderIfNoneMatch)),
+	}
+}
+
+func etags(header string) []string {
+	var tags []string
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		tag = strings.TrimPrefix(tag, "W/")
+		tags = append(tags, strings.Trim(tag, `"`))
+	}
+
+	return tags
+}
+
+// ctag changes with any object of the collection.
+func ctag(objects []entity.CalendarObject) string {
+	h := sha256.New()
+
+	for _, o := range objects {
+		h.Write(o.UID[:])
+		h.Write([]byte(o.ETag))
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+func principalProps(userID int) []webdav.Element {
+	self := webdav.Href(principalPath(userID))
+
+	return []webdav.Element{
+		webdav.NewElement(davName("resourcetype"), webdav.NewElement(davName("collection")), webdav.NewElement(davName("principal"))),
+		webdav.TextElement(davName("displayname"), "user "+strconv.Itoa(userID)),
+		webdav.NewElement(davName("current-user-principal"), self),
+		webdav.NewElement(davName("principal-URL"), self),
+		webdav.NewElement(calName("calendar-home-set"), self),
+	}
+}
+
+func calendarProps(userID int, tag string) []webdav.Element {
+	principal := webdav.Href(principalPath(userID))
+
+	report := func(name xml.Name) webdav.Element {
+		return webdav.NewElement(davName("supported-report"), webdav.NewElement(davName("report"), webdav.NewElement(name)))
+	}
+
+	privilege := func(local string) webdav.Element {
+		return webdav.NewElement(davName("privilege"), webdav.NewElement(davName(local)))
+	}
+
+	return []webdav.Element{
+		webdav.NewElement(davName("resourcetype"), webdav.NewElement(davName("collection")), webdav.NewElement(calName("calendar"))),
+		webdav.TextElement(davName("displayname"), "Calendar"),
+		webdav.NewElement(davName("current-user-principal"), principal),
+		webdav.NewElement(davName("owner"), principal),
+		webdav.NewElement(davName("supported-report-set"), report(calName("calendar-query")), report(calName("calendar-multiget"))),
+		webdav.NewElement(davName("current-user-privilege-set"),
+			privilege("read"), privilege("write"), privilege("write-content"), privilege("bind"), privilege("unbind"),
+		),
+		webdav.NewElement(calName("supported-calendar-component-set"), webdav.Element{
+			Name:  calName("comp"),
+			Attrs: []xml.Attr{{Name: xml.Name{Local: "name"}, Value: "VEVENT"}},
+		}),
+		webdav.NewElement(calName("supported-calendar-data"), webdav.Element{
+			Name: calName("calendar-data"),
+			Attrs: []xml.Attr{
+				{Name: xml.Name{Local: "content-type"}, Value: "text/calendar"},
+				{Name: xml.Name{Local: "version"}, Value: "2.0"},
+			},
+		}),
+		webdav.TextElement(xml.Name{Space: _csNS, Local: "getctag"}, tag),
+	}
+}
+
+// objectProps returns the properties of an object, calendar-data only if it has data.
+func objectProps(o entity.CalendarObject) []webdav.Element {
+	props := []webdav.Element{
+		webdav.NewElement(davName("resourcetype")),
+		webdav.TextElement(davName("getetag"), quote(o.ETag)),
+		webdav.TextElement(davName("getcontenttype"), _objectContentType),
+	}
+
+	if o.Data != nil {
+		props = append(props, webdav.TextElement(calName("calendar-data"), string(o.Data)))
+	}
+
+	return props
+}
+
+func quote(etag string) string {
+	return `"` + etag + `"`
+}
+
+func multistatus(ctx *fiber.Ctx, responses []webdav.Response) error {
+	var buf bytes.Buffer
+
+	if err := webdav.EncodeMultistatus(&buf, responses); err != nil {
+		return err
+	}
+
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+
+	return ctx.Status(http.StatusMultiStatus).Send(buf.Bytes())
+}
+
+// errorResponse answers with a DAV:error naming the failed precondition.
+func errorResponse(ctx *fiber.Ctx, code int, condition xml.Name) error {
+	var buf bytes.Buffer
+
+	if err := webdav.EncodeError(&buf, condition); err != nil {
+		return err
+	}
+
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+
+	return ctx.Status(code).Send(buf.Bytes())
+}
@@ -0,0 +1,119 @@
+package caldav
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/andreyxaxa/calendar/pkg/ical"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+func (r *CalDAV) getObject(ctx *fiber.Ctx) error {
+	u, ok := userID(ctx)
+	if !ok {
+		return ctx.SendStatus(http.StatusNotFound)
+	}
+
+	uid, ok := objectUID(ctx.Params("name"))
+	if !ok {
+		return ctx.SendStatus(http.StatusNotFound)
+	}
+
+	objects, err := r.c.Objects(ctx.UserContext(), u, []uuid.UUID{uid})
+	if err != nil {
+		r.l.Error(err, "caldav - getObject")
+
+		return ctx.SendStatus(http.StatusInternalServerError)
+	}
+
+	if len(objects) == 0 {
+		return ctx.SendStatus(http.StatusNotFound)
+	}
+
+	ctx.Set(fiber.HeaderContentType, _objectContentType)
+	ctx.Set(fiber.HeaderETag, quote(objects[0].ETag))
+
+	return ctx.Status(http.StatusOK).Send(objects[0].Data)
+}
+
+// putObject creates or replaces the event. The stored data is rewritten rather than kept,
+// so no ETag is sent back and clients fetch the object again (RFC 4791, 5.3.4).
+func (r *CalDAV) putObject(ctx *fiber.Ctx) error {
+	u, ok := userID(ctx)
+	if !ok {
+		return ctx.SendStatus(http.StatusNotFound)
+	}
+
+	uid, ok := objectUID(ctx.Params("name"))
+	if !ok {
+		return ctx.Status(http.StatusBadRequest).SendString("object name must end with .ics")
+	}
+
+	user, err := r.u.Get(ctx.UserContext(), u)
+	if err != nil {
+		r.l.Error(err, "caldav - putObject")
+
+		return ctx.SendStatus(http.StatusInternalServerError)
+	}
+
+	loc, err := time.LoadLocation(user.TimeZone)
+	if err != nil {
+		r.l.Error(err, "caldav - putObject")
+
+		return ctx.SendStatus(http.StatusInternalServerError)
+	}
+
+	created, err := r.c.PutObject(ctx.UserContext(), u, uid, bytes.NewReader(ctx.Body()), loc, preconditions(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrPreconditionFailed):
+			return ctx.SendStatus(http.StatusPreconditionFailed)
+		case errors.Is(err, ical.ErrInvalidData), errors.Is(err, errs.ErrInvalidCalendarData):
+			r.l.Debug(err, "caldav - putObject")
+
+			return errorResponse(ctx, http.StatusForbidden, calName("valid-calendar-data"))
+		}
+		r.l.Error(err, "caldav - putObject")
+
+		return ctx.SendStatus(http.StatusInternalServerError)
+	}
+
+	if created {
+		ctx.Location(objectPath(u, uid))
+
+		return ctx.SendStatus(http.StatusCreated)
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func (r *CalDAV) deleteObject(ctx *fiber.Ctx) error {
+	u, ok := userID(ctx)
+	if !ok {
+		return ctx.SendStatus(http.StatusNotFound)
+	}
+
+	uid, ok := objectUID(ctx.Params("name"))
+	if !ok {
+		return ctx.SendStatus(http.StatusNotFound)
+	}
+
+	err := r.c.DeleteObject(ctx.UserContext(), u, uid, preconditions(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrEventNotFound):
+			return ctx.SendStatus(http.StatusNotFound)
+		case errors.Is(err, errs.ErrPreconditionFailed):
+			return ctx.SendStatus(http.StatusPreconditionFailed)
+		}
+		r.l.Error(err, "caldav - deleteObject")
+
+		return ctx.SendStatus(http.StatusInternalServerError)
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
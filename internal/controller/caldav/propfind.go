@@ -0,0 +1,146 @@
+package caldav
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/andreyxaxa/calendar/pkg/webdav"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+func (r *CalDAV) options(ctx *fiber.Ctx) error {
+	ctx.Set("DAV", "1, 3, calendar-access")
+	ctx.Set(fiber.HeaderAllow, strings.Join(append([]string{
+		fiber.MethodOptions, fiber.MethodGet, fiber.MethodHead, fiber.MethodPut, fiber.MethodDelete,
+	}, Methods...), ", "))
+
+	return ctx.SendStatus(http.StatusOK)
+}
+
+// propfindRoot answers clients that start from the service root: without authentication
+// there is no current user, the calendar is found by the URL of the user.
+func (r *CalDAV) propfindRoot(ctx *fiber.Ctx) error {
+	p, err := webdav.DecodePropfind(bytes.NewReader(ctx.Body()))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+
+	props := []webdav.Element{
+		webdav.NewElement(davName("resourcetype"), webdav.NewElement(davName("collection"))),
+		webdav.NewElement(davName("current-user-principal"), webdav.NewElement(davName("unauthenticated"))),
+	}
+
+	return multistatus(ctx, []webdav.Response{response(p, _root, props)})
+}
+
+func (r *CalDAV) propfindPrincipal(ctx *fiber.Ctx) error {
+	u, ok := userID(ctx)
+	if !ok {
+		return ctx.SendStatus(http.StatusNotFound)
+	}
+
+	p, err := webdav.DecodePropfind(bytes.NewReader(ctx.Body()))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+
+	responses := []webdav.Response{response(p, principalPath(u), principalProps(u))}
+
+	if depth(ctx) > 0 {
+		objects, err := r.objectTags(ctx, u)
+		if err != nil {
+			r.l.Error(err, "caldav - propfindPrincipal")
+
+			return ctx.SendStatus(http.StatusInternalServerError)
+		}
+
+		responses = append(responses, response(p, calendarPath(u), calendarProps(u, ctag(objects))))
+	}
+
+	return multistatus(ctx, responses)
+}
+
+func (r *CalDAV) propfindCalendar(ctx *fiber.Ctx) error {
+	u, ok := userID(ctx)
+	if !ok {
+		return ctx.SendStatus(http.StatusNotFound)
+	}
+
+	p, err := webdav.DecodePropfind(bytes.NewReader(ctx.Body()))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+
+	objects, err := r.objectTags(ctx, u)
+	if err != nil {
+		r.l.Error(err, "caldav - propfindCalendar")
+
+		return ctx.SendStatus(http.StatusInternalServerError)
+	}
+
+	responses := []webdav.Response{response(p, calendarPath(u), calendarProps(u, ctag(objects)))}
+
+	if depth(ctx) > 0 {
+		for _, o := range objects {
+			responses = append(responses, response(p, objectPath(u, o.UID), objectProps(o)))
+		}
+	}
+
+	return multistatus(ctx, responses)
+}
+
+func (r *CalDAV) propfindObject(ctx *fiber.Ctx) error {
+	u, ok := userID(ctx)
+	if !ok {
+		return ctx.SendStatus(http.StatusNotFound)
+	}
+
+	uid, ok := objectUID(ctx.Params("name"))
+	if !ok {
+		return ctx.SendStatus(http.StatusNotFound)
+	}
+
+	p, err := webdav.DecodePropfind(bytes.NewReader(ctx.Body()))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+
+	objects, err := r.c.Objects(ctx.UserContext(), u, []uuid.UUID{uid})
+	if err != nil {
+		r.l.Error(err, "caldav - propfindObject")
+
+		return ctx.SendStatus(http.StatusInternalServerError)
+	}
+
+	if len(objects) == 0 {
+		return ctx.SendStatus(http.StatusNotFound)
+	}
+
+	// calendar-data is for REPORTs.
+	o := objects[0]
+	o.Data = nil
+
+	return multistatus(ctx, []webdav.Response{response(p, ctx.Path(), objectProps(o))})
+}
+
+// objectTags lists all objects of the user, a user without events has an empty calendar.
+func (r *CalDAV) objectTags(ctx *fiber.Ctx, userID int) ([]entity.CalendarObject, error) {
+	objects, err := r.c.ObjectTags(ctx.UserContext(), userID, time.Time{}, time.Time{})
+	if errors.Is(err, errs.ErrUserNotFound) {
+		return nil, nil
+	}
+
+	return objects, err
+}
+
+func response(p webdav.Propfind, href string, props []webdav.Element) webdav.Response {
+	found, missing := p.Select(props)
+
+	return webdav.Response{Href: href, Found: found, NotFound: missing}
+}
@@ -0,0 +1,228 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/ical"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/andreyxaxa/calendar/pkg/webdav"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+var errUnsupportedFilter = errors.New("unsupported filter")
+
+// report - a calendar-query or calendar-multiget body.
+type report struct {
+	XMLName xml.Name
+	Prop    webdav.PropNames `xml:"DAV: prop"`
+	Hrefs   []string         `xml:"DAV: href"`
+	Filter  *compFilter      `xml:"urn:ietf:params:xml:ns:caldav filter>comp-filter"`
+}
+
+type compFilter struct {
+	Name         string       `xml:"name,attr"`
+	IsNotDefined *struct{}    `xml:"urn:ietf:params:xml:ns:caldav is-not-defined"`
+	TimeRange    *timeRange   `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	PropFilters  []struct{}   `xml:"urn:ietf:params:xml:ns:caldav prop-filter"`
+	CompFilters  []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type timeRange struct {
+	Start string `xml:"start,attr"`
+	End   string `xml:"end,attr"`
+}
+
+func (r *CalDAV) report(ctx *fiber.Ctx) error {
+	u, ok := userID(ctx)
+	if !ok {
+		return ctx.SendStatus(http.StatusNotFound)
+	}
+
+	var rep report
+
+	if err := xml.Unmarshal(ctx.Body(), &rep); err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString("invalid report: " + err.Error())
+	}
+
+	// without prop the ETags are what a client needs.
+	if len(rep.Prop) == 0 {
+		rep.Prop = webdav.PropNames{davName("getetag")}
+	}
+
+	switch rep.XMLName {
+	case calName("calendar-query"):
+		return r.calendarQuery(ctx, u, rep)
+	case calName("calendar-multiget"):
+		return r.calendarMultiget(ctx, u, rep)
+	default:
+		return errorResponse(ctx, http.StatusForbidden, davName("supported-report"))
+	}
+}
+
+// calendarQuery lists objects matching the filter: VEVENTs, possibly within a time range.
+// Property and parameter filters are not supported.
+func (r *CalDAV) calendarQuery(ctx *fiber.Ctx, userID int, rep report) error {
+	from, to, match, err := eventFilter(rep.Filter)
+	if err != nil {
+		if errors.Is(err, errUnsupportedFilter) {
+			return errorResponse(ctx, http.StatusForbidden, calName("supported-filter"))
+		}
+
+		return errorResponse(ctx, http.StatusForbidden, calName("valid-filter"))
+	}
+
+	var objects []entity.CalendarObject
+
+	if match {
+		objects, err = r.c.ObjectTags(ctx.UserContext(), userID, from, to)
+		if err != nil && !errors.Is(err, errs.ErrUserNotFound) {
+			r.l.Error(err, "caldav - calendarQuery")
+
+			return ctx.SendStatus(http.StatusInternalServerError)
+		}
+	}
+
+	if wantsData(rep) && len(objects) > 0 {
+		uids := make([]uuid.UUID, len(objects))
+		for i, o := range objects {
+			uids[i] = o.UID
+		}
+
+		if objects, err = r.c.Objects(ctx.UserContext(), userID, uids); err != nil {
+			r.l.Error(err, "caldav - calendarQuery")
+
+			return ctx.SendStatus(http.StatusInternalServerError)
+		}
+	}
+
+	responses := make([]webdav.Response, 0, len(objects))
+
+	for _, o := range objects {
+		found, missing := webdav.SelectProps(rep.Prop, objectProps(o))
+		responses = append(responses, webdav.Response{Href: objectPath(userID, o.UID), Found: found, NotFound: missing})
+	}
+
+	return multistatus(ctx, responses)
+}
+
+// calendarMultiget returns the objects of the hrefs, echoing the hrefs as the client sent them.
+func (r *CalDAV) calendarMultiget(ctx *fiber.Ctx, userID int, rep report) error {
+	// targets are the event UIDs of the hrefs, valid tells hrefs outside the calendar.
+	targets := make([]uuid.UUID, len(rep.Hrefs))
+	valid := make([]bool, len(rep.Hrefs))
+	uids := make([]uuid.UUID, 0, len(rep.Hrefs))
+
+	for i, href := range rep.Hrefs {
+		if targets[i], valid[i] = hrefUID(userID, href); valid[i] {
+			uids = append(uids, targets[i])
+		}
+	}
+
+	objects, err := r.c.Objects(ctx.UserContext(), userID, uids)
+	if err != nil {
+		r.l.Error(err, "caldav - calendarMultiget")
+
+		return ctx.SendStatus(http.StatusInternalServerError)
+	}
+
+	byUID := make(map[uuid.UUID]entity.CalendarObject, len(objects))
+	for _, o := range objects {
+		byUID[o.UID] = o
+	}
+
+	responses := make([]webdav.Response, 0, len(rep.Hrefs))
+
+	for i, href := range rep.Hrefs {
+		o, ok := byUID[targets[i]]
+		if !valid[i] || !ok {
+			responses = append(responses, webdav.Response{Href: href, Status: http.StatusNotFound})
+
+			continue
+		}
+
+		found, missing := webdav.SelectProps(rep.Prop, objectProps(o))
+		responses = append(responses, webdav.Response{Href: href, Found: found, NotFound: missing})
+	}
+
+	return multistatus(ctx, responses)
+}
+
+// hrefUID maps an href of an object in the calendar of the user to the event UID.
+// Hrefs may be absolute URLs.
+func hrefUID(userID int, href string) (uuid.UUID, bool) {
+	u, err := url.Parse(href)
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	name, ok := strings.CutPrefix(u.EscapedPath(), calendarPath(userID))
+	if !ok || strings.Contains(name, "/") {
+		return uuid.Nil, false
+	}
+
+	return objectUID(name)
+}
+
+func wantsData(rep report) bool {
+	for _, name := range rep.Prop {
+		if name == calName("calendar-data") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// eventFilter reads the filter of a calendar-query: the time range of VEVENTs, zero bounds
+// are open. Match is false when the filter asks for other components, e.g. VTODOs.
+func eventFilter(f *compFilter) (from, to time.Time, match bool, err error) {
+	if f == nil {
+		return time.Time{}, time.Time{}, true, nil
+	}
+
+	if f.Name != "VCALENDAR" {
+		return time.Time{}, time.Time{}, false, errors.New("filter must start with VCALENDAR")
+	}
+
+	if f.IsNotDefined != nil || f.TimeRange != nil || len(f.PropFilters) > 0 || len(f.CompFilters) > 1 {
+		return time.Time{}, time.Time{}, false, errUnsupportedFilter
+	}
+
+	if len(f.CompFilters) == 0 {
+		return time.Time{}, time.Time{}, true, nil
+	}
+
+	event := f.CompFilters[0]
+	if event.Name != "VEVENT" {
+		return time.Time{}, time.Time{}, false, nil
+	}
+
+	if event.IsNotDefined != nil || len(event.PropFilters) > 0 || len(event.CompFilters) > 0 {
+		return time.Time{}, time.Time{}, false, errUnsupportedFilter
+	}
+
+	if event.TimeRange == nil {
+		return time.Time{}, time.Time{}, true, nil
+	}
+
+	if event.TimeRange.Start != "" {
+		if from, err = time.Parse(ical.UTCTimeFormat, event.TimeRange.Start); err != nil {
+			return time.Time{}, time.Time{}, false, err
+		}
+	}
+
+	if event.TimeRange.End != "" {
+		if to, err = time.Parse(ical.UTCTimeFormat, event.TimeRange.End); err != nil {
+			return time.Time{}, time.Time{}, false, err
+		}
+	}
+
+	return from, to, true, nil
+}
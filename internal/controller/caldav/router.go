@@ -0,0 +1,50 @@
+// Package caldav serves the events of each user as a CalDAV (RFC 4791) calendar collection,
+// next to the REST API. There is no authentication, as in the REST API: the user is in the path.
+//
+//	/dav/users/{id}/                   - principal of the user, the home of the calendar
+//	/dav/users/{id}/calendar/          - calendar collection
+//	/dav/users/{id}/calendar/{uid}.ics - an event with the changed instances of its series
+//
+// An object PUT under a name that is not an event UUID is stored as the event hashed from
+// the name (see entity.ImportUID) and listed as {uid}.ics from then on.
+package caldav
+
+import (
+	"net/http"
+
+	"github.com/andreyxaxa/calendar/internal/usecase"
+	"github.com/andreyxaxa/calendar/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Methods - WebDAV methods the server has to accept besides the standard ones.
+var Methods = []string{"PROPFIND", "REPORT"}
+
+// NewRouter -.
+func NewRouter(app *fiber.App, c usecase.Calendar, u usecase.Users, l logger.Interface) {
+	r := &CalDAV{
+		c: c,
+		u: u,
+		l: l,
+	}
+
+	// RFC 6764: clients look for the service here.
+	app.All("/.well-known/caldav", func(ctx *fiber.Ctx) error {
+		return ctx.Redirect(_root, http.StatusMovedPermanently)
+	})
+
+	davGroup := app.Group("/dav")
+	{
+		davGroup.Options("/*", r.options)
+
+		davGroup.Add("PROPFIND", "/", r.propfindRoot)
+		davGroup.Add("PROPFIND", "/users/:id", r.propfindPrincipal)
+		davGroup.Add("PROPFIND", "/users/:id/calendar", r.propfindCalendar)
+		davGroup.Add("REPORT", "/users/:id/calendar", r.report)
+
+		davGroup.Add("PROPFIND", "/users/:id/calendar/:name", r.propfindObject)
+		davGroup.Get("/users/:id/calendar/:name", r.getObject)
+		davGroup.Put("/users/:id/calendar/:name", r.putObject)
+		davGroup.Delete("/users/:id/calendar/:name", r.deleteObject)
+	}
+}
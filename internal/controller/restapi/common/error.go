@@ -0,0 +1,17 @@
+package common
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	ErrInvalidTimeZone = errors.New("invalid tz, expected IANA time zone name")
+	ErrInvalidIfMatch  = errors.New("invalid If-Match, expected a single etag")
+)
+
+// ErrorResponse - the body is response.Error of every version.
+func ErrorResponse(ctx *fiber.Ctx, code int, msg string) error {
+	return ctx.Status(code).JSON(fiber.Map{"error": msg})
+}
@@ -0,0 +1,116 @@
+package common
+
+import (
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/rrule"
+	"github.com/andreyxaxa/calendar/pkg/types/date"
+)
+
+// EventSpan resolves request timing into Start, End and AllDay of the event.
+// With all_day only calendar days of start and end in loc are used, end's day included.
+// A non-empty msg describes invalid timing.
+func EventSpan(day *date.Date, start, end *time.Time, allDay bool, loc *time.Location) (entity.Event, string) {
+	switch {
+	case start != nil:
+		until := *start
+		if end != nil {
+			until = *end
+		}
+
+		if until.Before(*start) {
+			return entity.Event{}, "end cant be before start"
+		}
+
+		if allDay {
+			return entity.Event{
+				Start:  date.Floating(start.In(loc)),
+				End:    date.Floating(until.In(loc)).AddDate(0, 0, 1),
+				AllDay: true,
+			}, ""
+		}
+
+		return entity.Event{Start: start.UTC(), End: until.UTC()}, ""
+	case day != nil:
+		return entity.Event{
+			Start:  date.Floating(day.Time),
+			End:    date.Floating(day.Time).AddDate(0, 0, 1),
+			AllDay: true,
+		}, ""
+	default:
+		return entity.Event{}, "date or start required"
+	}
+}
+
+// OccurrenceScope parses which instances of a series an update or delete applies to,
+// every scope but "all" needs recurrence_id.
+func OccurrenceScope(scope string, recurrenceID *time.Time) (entity.Scope, time.Time, string) {
+	var s entity.Scope
+
+	switch scope {
+	case "", "all":
+		return entity.ScopeAll, time.Time{}, ""
+	case "this":
+		s = entity.ScopeThis
+	case "following":
+		s = entity.ScopeFollowing
+	default:
+		return 0, time.Time{}, "invalid scope, expected: all, this or following"
+	}
+
+	if recurrenceID == nil {
+		return 0, time.Time{}, "recurrence_id required for scope " + scope
+	}
+
+	return s, recurrenceID.UTC(), ""
+}
+
+// Recurrence validates rule and exDates and sets them on event in canonical form.
+// Exdates of all-day events are floating days like their instances.
+func Recurrence(event *entity.Event, rule string, exDates []time.Time, loc *time.Location) string {
+	if rule == "" {
+		if len(exDates) > 0 {
+			return "exdates require rrule"
+		}
+
+		return ""
+	}
+
+	parsed, err := rrule.Parse(rule)
+	if err != nil {
+		return err.Error()
+	}
+
+	event.RRule = parsed.String()
+
+	for _, exDate := range exDates {
+		if event.AllDay {
+			exDate = date.Floating(exDate.In(loc))
+		}
+
+		event.ExDates = append(event.ExDates, exDate.UTC())
+	}
+
+	return ""
+}
+
+// InZone renders timed events in their own zone, all-day ones stay at UTC midnight.
+func InZone(event entity.Event) entity.Event {
+	loc := time.UTC
+
+	if l, err := LoadLocation(event.TimeZone); err == nil && !event.AllDay {
+		loc = l
+	}
+
+	event.Start, event.End = event.Start.In(loc), event.End.In(loc)
+
+	var exDates []time.Time
+	for _, exDate := range event.ExDates {
+		exDates = append(exDates, exDate.In(loc))
+	}
+
+	event.ExDates = exDates
+
+	return event
+}
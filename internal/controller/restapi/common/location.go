@@ -0,0 +1,50 @@
+package common
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/usecase"
+)
+
+// _locations caches loaded zones: time.LoadLocation reads tzdata on every call.
+var _locations sync.Map
+
+// Location resolves tz, an empty one falls back to the user's default time zone.
+func Location(ctx context.Context, u usecase.Users, userID int, tz string) (*time.Location, error) {
+	if tz == "" {
+		user, err := u.Get(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		tz = user.TimeZone
+	}
+
+	return LoadLocation(tz)
+}
+
+// LoadLocation accepts IANA names only: "" and "Local" would silently mean UTC or the server zone.
+func LoadLocation(tz string) (*time.Location, error) {
+	if tz == "" || tz == "Local" {
+		return nil, ErrInvalidTimeZone
+	}
+
+	// tz may point into a request buffer that fiber reuses, the cache keeps its own copy.
+	tz = strings.Clone(tz)
+
+	if loc, ok := _locations.Load(tz); ok {
+		return loc.(*time.Location), nil
+	}
+
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, ErrInvalidTimeZone
+	}
+
+	_locations.Store(tz, loc)
+
+	return loc, nil
+}
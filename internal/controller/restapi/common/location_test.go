@@ -0,0 +1,55 @@
+package common
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestLoadLocationKeepsName(t *testing.T) {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/", func(ctx *fiber.Ctx) error {
+		loc, err := LoadLocation(ctx.Query("tz"))
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendString(loc.String())
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	go app.Listener(ln)
+	defer app.Shutdown()
+
+	// requests of a connection share the buffer of the query.
+	for _, tz := range []string{"Asia/Vladivostok", "Europe/London"} {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/?tz=" + tz)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		b, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if string(b) != tz {
+			t.Fatalf("expected %s, got %s", tz, b)
+		}
+	}
+
+	// names of cached zones don't change with the requests.
+	_locations.Range(func(key, loc any) bool {
+		if _, err := time.LoadLocation(key.(string)); err != nil {
+			t.Errorf("zone %s is cached as %s", loc, key)
+		}
+
+		return true
+	})
+}
@@ -0,0 +1,90 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/google/uuid"
+)
+
+const (
+	_defaultLimit = 50
+	_maxLimit     = 500
+	// MaxRangeYears bounds a range query: series are expanded over the whole range.
+	MaxRangeYears = 10
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// cursor - JSON form of entity.Cursor, clients get it base64 encoded and opaque.
+type cursor struct {
+	Start        int64     `json:"s"`
+	UID          uuid.UUID `json:"u"`
+	RecurrenceID int64     `json:"r,omitempty"`
+	Desc         bool      `json:"d,omitempty"`
+}
+
+// EncodeCursor -.
+func EncodeCursor(c entity.Cursor, desc bool) string {
+	raw := cursor{Start: c.Start.UnixNano(), UID: c.UID, Desc: desc}
+	if !c.RecurrenceID.IsZero() {
+		raw.RecurrenceID = c.RecurrenceID.UnixNano()
+	}
+
+	// cursor holds nothing json cant encode.
+	b, _ := json.Marshal(raw)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a cursor of a page in the same order.
+func DecodeCursor(s string, desc bool) (*entity.Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	var raw cursor
+
+	if err = json.Unmarshal(b, &raw); err != nil || raw.Desc != desc {
+		return nil, errInvalidCursor
+	}
+
+	c := entity.Cursor{Start: time.Unix(0, raw.Start).UTC(), UID: raw.UID}
+	if raw.RecurrenceID != 0 {
+		c.RecurrenceID = time.Unix(0, raw.RecurrenceID).UTC()
+	}
+
+	return &c, nil
+}
+
+// PageLimit parses limit, _defaultLimit if it is empty.
+func PageLimit(s string) (int, error) {
+	if s == "" {
+		return _defaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(s)
+	if err != nil || limit < 1 || limit > _maxLimit {
+		return 0, errors.New("invalid limit, expected 1 to " + strconv.Itoa(_maxLimit))
+	}
+
+	return limit, nil
+}
+
+// RangeBound parses a range bound: a date means its midnight in loc.
+func RangeBound(s string, loc *time.Location) (time.Time, error) {
+	if d, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
+		return d, nil
+	}
+
+	return time.Parse(time.RFC3339, s)
+}
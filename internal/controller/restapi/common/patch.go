@@ -0,0 +1,130 @@
+package common
+
+import (
+	"mime"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/mergepatch"
+	"github.com/andreyxaxa/calendar/pkg/types/date"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PatchFields - members of an event merge patch, those of the v1 update request and of v2 request.Event.
+var PatchFields = []string{"date", "start", "end", "all_day", "tz", "text", "rrule", "exdates"}
+
+// IsMergePatch accepts application/json too: clients send merge patches with it.
+func IsMergePatch(ctx *fiber.Ctx) bool {
+	mediaType, _, err := mime.ParseMediaType(ctx.Get(fiber.HeaderContentType))
+
+	return err == nil && (mediaType == mergepatch.ContentType || mediaType == fiber.MIMEApplicationJSON)
+}
+
+// PatchTimeZone returns tz of the patched event: removing it falls back to the user's time zone.
+func PatchTimeZone(current entity.Event, p mergepatch.Patch) (string, string) {
+	if !p.Has("tz") {
+		return current.TimeZone, ""
+	}
+
+	var tz string
+
+	if err := p.Decode("tz", &tz); err != nil {
+		return "", err.Error()
+	}
+
+	return tz, ""
+}
+
+// EventPatch turns a merge patch of current into the changed fields and their mask, loc is
+// the zone of the patched event. Timing changes as a whole: end and all_day need date or start.
+// Removing rrule ends the series. The patch expects the version of current, which it depends on,
+// so a write since current was read fails it. A non-empty msg describes an invalid patch.
+func EventPatch(current entity.Event, p mergepatch.Patch, loc *time.Location) (entity.Event, entity.FieldMask, string) {
+	var (
+		patch entity.Event
+		mask  entity.FieldMask
+	)
+
+	if p.Has("text") {
+		if err := p.Decode("text", &patch.Text); err != nil {
+			return entity.Event{}, 0, err.Error()
+		}
+
+		if patch.Text == "" {
+			return entity.Event{}, 0, "text cant be removed"
+		}
+
+		mask |= entity.FieldText
+	}
+
+	if p.Has("tz") {
+		mask |= entity.FieldTimeZone
+	}
+
+	patch.TimeZone = loc.String()
+	allDay := current.AllDay
+
+	if p.HasAny("date", "start", "end", "all_day") {
+		var (
+			day        *date.Date
+			start, end *time.Time
+			wholeDays  bool
+		)
+
+		for name, v := range map[string]any{"date": &day, "start": &start, "end": &end, "all_day": &wholeDays} {
+			if err := p.Decode(name, v); err != nil {
+				return entity.Event{}, 0, err.Error()
+			}
+		}
+
+		if day == nil && start == nil {
+			return entity.Event{}, 0, "date or start required to change end or all_day"
+		}
+
+		span, msg := EventSpan(day, start, end, wholeDays, loc)
+		if msg != "" {
+			return entity.Event{}, 0, msg
+		}
+
+		patch.Start, patch.End, patch.AllDay = span.Start, span.End, span.AllDay
+		allDay = span.AllDay
+		mask |= entity.FieldTiming
+	}
+
+	if p.HasAny("rrule", "exdates") {
+		rule := current.RRule
+		if p.Has("rrule") {
+			rule = ""
+		}
+
+		var exDates []time.Time
+
+		if err := p.Decode("rrule", &rule); err != nil {
+			return entity.Event{}, 0, err.Error()
+		}
+
+		if err := p.Decode("exdates", &exDates); err != nil {
+			return entity.Event{}, 0, err.Error()
+		}
+
+		// exdates of all-day events float like their instances.
+		series := entity.Event{AllDay: allDay}
+		if msg := Recurrence(&series, rule, exDates, loc); msg != "" {
+			return entity.Event{}, 0, msg
+		}
+
+		if p.Has("rrule") {
+			patch.RRule = series.RRule
+			mask |= entity.FieldRRule
+		}
+
+		if p.Has("exdates") {
+			patch.ExDates = series.ExDates
+			mask |= entity.FieldExDates
+		}
+	}
+
+	patch.Version = current.Version
+
+	return patch, mask, ""
+}
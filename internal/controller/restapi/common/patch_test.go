@@ -0,0 +1,33 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/mergepatch"
+)
+
+func TestEventPatchExpectsReadVersion(t *testing.T) {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	current := entity.Event{Text: "stand-up", Start: start, End: start.Add(time.Hour), RRule: "FREQ=WEEKLY", Version: 3}
+
+	// the rule is kept from current: the write must fail if it changed since.
+	p, err := mergepatch.Parse([]byte(`{"exdates":["2026-01-12T09:00:00Z"]}`), PatchFields...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	patch, mask, msg := EventPatch(current, p, time.UTC)
+	if msg != "" {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+
+	if mask != entity.FieldExDates || len(patch.ExDates) != 1 {
+		t.Fatalf("expected exdates only, got %v %+v", mask, patch)
+	}
+
+	if patch.Version != current.Version {
+		t.Fatalf("expected version %d, got %d", current.Version, patch.Version)
+	}
+}
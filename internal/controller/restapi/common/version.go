@@ -0,0 +1,43 @@
+package common
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/gofiber/fiber/v2"
+)
+
+// IfMatch returns the version If-Match expects, 0 if there is no header or it is "*".
+// ETags are strong: a weak or foreign one matches no event and fails the precondition.
+func IfMatch(ctx *fiber.Ctx) (int64, error) {
+	header := strings.TrimSpace(ctx.Get(fiber.HeaderIfMatch))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	if strings.Contains(header, ",") || !strings.HasSuffix(header, `"`) {
+		return 0, ErrInvalidIfMatch
+	}
+
+	tag, ok := strings.CutPrefix(header, `"`)
+	if !ok {
+		if strings.HasPrefix(header, `W/"`) {
+			return 0, errs.ErrPreconditionFailed
+		}
+
+		return 0, ErrInvalidIfMatch
+	}
+
+	version, err := strconv.ParseInt(strings.TrimSuffix(tag, `"`), 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errs.ErrPreconditionFailed
+	}
+
+	return version, nil
+}
+
+// SetETag sends the version of the event as its ETag.
+func SetETag(ctx *fiber.Ctx, version int64) {
+	ctx.Set(fiber.HeaderETag, strconv.Quote(strconv.FormatInt(version, 10)))
+}
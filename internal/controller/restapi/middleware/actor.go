@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/common"
+	"github.com/andreyxaxa/calendar/pkg/actor"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	_headerActor = "X-Actor"
+	_maxActor    = 255
+)
+
+// Actor puts the X-Actor header, who makes the request, into its context: writes are recorded
+// in the history of events with it. Without the header they are recorded as made by the user.
+func Actor() func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		// the name outlives the request in revisions, its header buffer does not.
+		name := strings.Clone(ctx.Get(_headerActor))
+		if name == "" {
+			return ctx.Next()
+		}
+
+		if len(name) > _maxActor {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, "X-Actor is too long")
+		}
+
+		ctx.SetUserContext(actor.With(ctx.UserContext(), name))
+
+		return ctx.Next()
+	}
+}
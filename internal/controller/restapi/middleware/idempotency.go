@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/common"
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/usecase"
+	"github.com/andreyxaxa/calendar/pkg/logger"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	_headerIdempotencyKey = "Idempotency-Key"
+	// _headerReplayed marks a stored response sent again.
+	_headerReplayed    = "Idempotent-Replayed"
+	_maxIdempotencyKey = 255
+)
+
+// _replayedHeaders - headers stored with the response body.
+var _replayedHeaders = []string{fiber.HeaderContentType, fiber.HeaderETag, fiber.HeaderLocation}
+
+// Idempotency makes requests with an Idempotency-Key header safe to retry: a successful response
+// is stored and replayed to retries with the same method, path and body. Keys are of the user
+// and the endpoint: the same key of another user or sent to another endpoint is another key.
+// Failed requests release the key. Requests without the header pass through.
+func Idempotency(uc usecase.Idempotency, l logger.Interface) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		// the key outlives the request, its header buffer does not.
+		key := strings.Clone(ctx.Get(_headerIdempotencyKey))
+		if key == "" {
+			return ctx.Next()
+		}
+
+		if len(key) > _maxIdempotencyKey {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, "Idempotency-Key is too long")
+		}
+
+		key = scope(ctx) + key
+
+		stored, err := uc.Begin(ctx.UserContext(), key, fingerprint(ctx))
+		if err != nil {
+			if errors.Is(err, errs.ErrIdempotencyKeyReused) {
+				return common.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+			} else if errors.Is(err, errs.ErrRequestInProgress) {
+				return common.ErrorResponse(ctx, http.StatusConflict, err.Error())
+			}
+			l.Error(err, "restapi - middleware - idempotency")
+
+			return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+		}
+
+		if stored != nil {
+			for name, value := range stored.Header {
+				ctx.Set(name, value)
+			}
+
+			ctx.Set(_headerReplayed, "true")
+
+			return ctx.Status(stored.Status).Send(stored.Body)
+		}
+
+		err = ctx.Next()
+
+		status := ctx.Response().StatusCode()
+		if err != nil || status < http.StatusOK || status >= http.StatusMultipleChoices {
+			if rerr := uc.Release(ctx.UserContext(), key); rerr != nil {
+				l.Error(rerr, "restapi - middleware - idempotency")
+			}
+
+			return err
+		}
+
+		response := entity.StoredResponse{
+			Status: status,
+			Header: make(map[string]string),
+			Body:   append([]byte(nil), ctx.Response().Body()...),
+		}
+
+		for _, name := range _replayedHeaders {
+			if value := ctx.GetRespHeader(name); value != "" {
+				response.Header[name] = value
+			}
+		}
+
+		// the key stays in progress then: retries get 409 rather than create a duplicate.
+		if err = uc.Complete(ctx.UserContext(), key, response); err != nil {
+			l.Error(err, "restapi - middleware - idempotency")
+		}
+
+		return nil
+	}
+}
+
+// scope is the prefix of keys of the user of the request to its endpoint. v1 takes the user
+// in the body, a body without it leaves the request to fail in its handler.
+func scope(ctx *fiber.Ctx) string {
+	user := ctx.Params("userID")
+	if user == "" {
+		var body struct {
+			UserID json.Number `json:"user_id"`
+		}
+
+		_ = json.Unmarshal(ctx.Body(), &body)
+		user = body.UserID.String()
+	}
+
+	return ctx.Method() + " " + ctx.Route().Path + " " + user + " "
+}
+
+// fingerprint identifies the request a key came with.
+func fingerprint(ctx *fiber.Ctx) string {
+	h := sha256.New()
+
+	h.Write([]byte(ctx.Method() + " " + ctx.Path() + "\n"))
+	h.Write(ctx.Body())
+
+	return hex.EncodeToString(h.Sum(nil))
+}
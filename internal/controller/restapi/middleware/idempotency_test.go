@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/middleware"
+	"github.com/andreyxaxa/calendar/internal/repo/inmemory"
+	"github.com/andreyxaxa/calendar/internal/usecase/idempotency"
+	"github.com/andreyxaxa/calendar/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestIdempotencyKeysOfUsersAndEndpoints(t *testing.T) {
+	app := fiber.New()
+	created := 0
+
+	create := func(ctx *fiber.Ctx) error {
+		created++
+
+		return ctx.Status(http.StatusCreated).SendString(ctx.Path() + " " + string(ctx.Body()))
+	}
+
+	idempotent := middleware.Idempotency(idempotency.New(inmemory.NewIdempotencyRepo(), time.Hour), logger.New("error"))
+	app.Post("/v1/create_event", idempotent, create)
+	app.Post("/v2/users/:userID/events", idempotent, create)
+
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		replayed bool
+		// handled counts requests that reached the handler so far.
+		handled int
+	}{
+		{name: "first user", path: "/v1/create_event", body: `{"user_id":1,"event":"stand-up"}`, handled: 1},
+		{name: "another user", path: "/v1/create_event", body: `{"user_id":2,"event":"stand-up"}`, handled: 2},
+		{name: "retry", path: "/v1/create_event", body: `{"user_id":1,"event":"stand-up"}`, replayed: true, handled: 2},
+		{name: "another endpoint", path: "/v2/users/1/events", body: `{"text":"stand-up"}`, handled: 3},
+		{name: "another user of the path", path: "/v2/users/2/events", body: `{"text":"stand-up"}`, handled: 4},
+		{name: "retry of the path", path: "/v2/users/2/events", body: `{"text":"stand-up"}`, replayed: true, handled: 4},
+	}
+
+	for _, tc := range tests {
+		req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set("Idempotency-Key", "shared")
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated || string(body) != tc.path+" "+tc.body {
+			t.Fatalf("%s: expected its own response, got %d %s", tc.name, resp.StatusCode, body)
+		}
+
+		if replayed := resp.Header.Get("Idempotent-Replayed") == "true"; replayed != tc.replayed {
+			t.Fatalf("%s: expected replayed %v, got %v", tc.name, tc.replayed, replayed)
+		}
+
+		if created != tc.handled {
+			t.Fatalf("%s: expected %d requests handled, got %d", tc.name, tc.handled, created)
+		}
+	}
+}
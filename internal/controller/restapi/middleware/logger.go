@@ -19,7 +19,13 @@ func buildRequestMessage(ctx *fiber.Ctx) string {
 	result.WriteString(" - ")
 	result.WriteString(strconv.Itoa(ctx.Response().StatusCode()))
 	result.WriteString(" - ")
-	result.WriteString(strconv.Itoa(len(ctx.Response().Body())))
+
+	// a streamed body is not read: it would wait for the stream to end.
+	if ctx.Context().IsBodyStream() {
+		result.WriteString("stream")
+	} else {
+		result.WriteString(strconv.Itoa(len(ctx.Response().Body())))
+	}
 
 	return result.String()
 }
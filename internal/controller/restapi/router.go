@@ -5,6 +5,7 @@ import (
 	_ "github.com/andreyxaxa/calendar/docs" // Swagger docs.
 	"github.com/andreyxaxa/calendar/internal/controller/restapi/middleware"
 	v1 "github.com/andreyxaxa/calendar/internal/controller/restapi/v1"
+	v2 "github.com/andreyxaxa/calendar/internal/controller/restapi/v2"
 	"github.com/andreyxaxa/calendar/internal/usecase"
 	"github.com/andreyxaxa/calendar/pkg/logger"
 	"github.com/gofiber/fiber/v2"
@@ -16,7 +17,9 @@ import (
 // @version 1.0
 // @host localhost:8080
 // @BasePath /v1
-func NewRouter(app *fiber.App, cfg *config.Config, e usecase.Events, l logger.Interface) {
+func NewRouter(app *fiber.App, cfg *config.Config, e usecase.Events, c usecase.Calendar, u usecase.Users,
+	i usecase.Idempotency, w usecase.Webhooks, l logger.Interface,
+) {
 	// Swagger
 	if cfg.Swagger.Enabled {
 		app.Get("/swagger/*", swagger.HandlerDefault)
@@ -24,10 +27,23 @@ func NewRouter(app *fiber.App, cfg *config.Config, e usecase.Events, l logger.In
 
 	// Options
 	app.Use(middleware.Logger(l))
+	app.Use(middleware.Actor())
+
+	idempotency := middleware.Idempotency(i, l)
+	app.Post("/v1/create_event", idempotency)
+	app.Post("/v2/users/:userID/events", idempotency)
 
 	// Routers
 	apiV1Group := app.Group("/v1")
 	{
-		v1.NewEventsRoutes(apiV1Group, e, l)
+		v1.NewEventsRoutes(apiV1Group, e, u, l)
+		v1.NewCalendarRoutes(apiV1Group, c, u, l)
+		v1.NewUsersRoutes(apiV1Group, u, l)
+		v1.NewWebhooksRoutes(apiV1Group, w, l)
+	}
+
+	apiV2Group := app.Group("/v2")
+	{
+		v2.NewEventsRoutes(apiV2Group, e, u, l)
 	}
 }
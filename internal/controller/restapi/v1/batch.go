@@ -0,0 +1,192 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/common"
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/v1/request"
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/v1/response"
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// _maxBatchOperations bounds a batch, the whole of it is validated and run by one request.
+const _maxBatchOperations = 1000
+
+// @Summary Batch
+// @Description Runs create, update and delete operations for the user in order and returns their results.
+// @Description Operations are validated like single requests, an invalid one fails the whole batch with 400.
+// @Description Each operation succeeds or fails alone, unless atomic: then the first failure is returned
+// @Description with its status and none of the operations is applied
+// @ID batch
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param request body request.BatchRequest true "Operations"
+// @Success 200 {object} response.BatchResponse
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 412 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/events:batch [post]
+func (r *V1) batch(ctx *fiber.Ctx) error {
+	var body request.BatchRequest
+
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if body.UserID <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+	}
+
+	if len(body.Operations) == 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "operations required")
+	}
+
+	if len(body.Operations) > _maxBatchOperations {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, fmt.Sprintf("at most %d operations allowed", _maxBatchOperations))
+	}
+
+	ops := make([]entity.BatchOperation, len(body.Operations))
+
+	for i, op := range body.Operations {
+		var msg string
+
+		ops[i], msg, err = r.batchOperation(ctx.UserContext(), body.UserID, op)
+		if err != nil {
+			r.l.Error(err, "restapi - v1 - batch")
+
+			return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+		}
+
+		if msg != "" {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, fmt.Sprintf("operations[%d]: %s", i, msg))
+		}
+	}
+
+	results, err := r.e.Batch(ctx.UserContext(), body.UserID, ops, body.Atomic)
+	if err != nil {
+		var batchErr *entity.BatchError
+		if errors.As(err, &batchErr) {
+			code, msg := batchStatus(batchErr.Err)
+			if code == http.StatusInternalServerError {
+				r.l.Error(err, "restapi - v1 - batch")
+			}
+
+			return common.ErrorResponse(ctx, code, fmt.Sprintf("operations[%d]: %s", batchErr.Index, msg))
+		}
+		r.l.Error(err, "restapi - v1 - batch")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	resp := response.BatchResponse{Result: make([]response.BatchResult, 0, len(results))}
+
+	for _, res := range results {
+		result := response.BatchResult{Status: http.StatusOK, UID: res.UID.String()}
+
+		if res.Err != nil {
+			result.Status, result.Error = batchStatus(res.Err)
+			if result.Status == http.StatusInternalServerError {
+				r.l.Error(res.Err, "restapi - v1 - batch")
+			}
+		} else if res.Event.Version != 0 {
+			event := resultEvent(body.UserID, res.UID, res.Event)
+			result.Event = &event
+		}
+
+		resp.Result = append(resp.Result, result)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
+
+// batchOperation validates an operation of a batch as the single request would be,
+// msg tells what is wrong with it.
+func (r *V1) batchOperation(ctx context.Context, userID int, op request.BatchOperation) (entity.BatchOperation, string, error) {
+	var operation entity.BatchOperation
+
+	switch op.Op {
+	case "create":
+		operation.Op, operation.UID = entity.BatchCreate, uuid.New()
+	case "update":
+		operation.Op = entity.BatchUpdate
+	case "delete":
+		operation.Op = entity.BatchDelete
+	default:
+		return entity.BatchOperation{}, "op must be create, update or delete", nil
+	}
+
+	if operation.Op != entity.BatchCreate {
+		if op.EventUID == "" {
+			return entity.BatchOperation{}, "uid required", nil
+		}
+
+		uid, err := uuid.Parse(op.EventUID)
+		if err != nil {
+			return entity.BatchOperation{}, "invalid uid format", nil
+		}
+
+		if op.Version < 0 {
+			return entity.BatchOperation{}, "version cant be less than 0", nil
+		}
+
+		operation.UID = uid
+		operation.Event.Version = op.Version
+	}
+
+	if operation.Op == entity.BatchDelete {
+		return operation, "", nil
+	}
+
+	if op.Text == "" {
+		return entity.BatchOperation{}, "text required", nil
+	}
+
+	loc, err := common.Location(ctx, r.u, userID, op.TimeZone)
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidTimeZone) {
+			return entity.BatchOperation{}, err.Error(), nil
+		}
+
+		return entity.BatchOperation{}, "", err
+	}
+
+	event, msg := common.EventSpan(op.Date, op.Start, op.End, op.AllDay, loc)
+	if msg != "" {
+		return entity.BatchOperation{}, msg, nil
+	}
+
+	if msg = common.Recurrence(&event, op.RRule, op.ExDates, loc); msg != "" {
+		return entity.BatchOperation{}, msg, nil
+	}
+
+	event.TimeZone = loc.String()
+	event.Text = op.Text
+	event.Version = operation.Event.Version
+	operation.Event = event
+
+	return operation, "", nil
+}
+
+// batchStatus returns the status and message an operation failed with err would get as a single request.
+func batchStatus(err error) (int, string) {
+	if errors.Is(err, errs.ErrUserNotFound) {
+		return http.StatusNotFound, errs.ErrUserNotFound.Error()
+	} else if errors.Is(err, errs.ErrEventNotFound) {
+		return http.StatusNotFound, errs.ErrEventNotFound.Error()
+	} else if errors.Is(err, errs.ErrPreconditionFailed) {
+		return http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error()
+	} else if errors.Is(err, errs.ErrAlreadyExists) {
+		return http.StatusConflict, errs.ErrAlreadyExists.Error()
+	}
+
+	return http.StatusInternalServerError, "storage problems"
+}
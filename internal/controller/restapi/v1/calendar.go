@@ -0,0 +1,153 @@
+package v1
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/common"
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/v1/response"
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/ical"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// @Summary Export calendar
+// @Description All events of the user as an iCalendar (RFC 5545) feed, for read-only subscriptions from calendar clients.
+// @Description Series come with RRULE and EXDATE, changed instances as separate VEVENTs with RECURRENCE-ID.
+// @ID export-calendar
+// @Tags calendar
+// @Produce text/calendar
+// @Param id path int true "User ID"
+// @Success 200 {string} string "VCALENDAR"
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/users/{id}/calendar.ics [get]
+func (r *V1) exportCalendar(ctx *fiber.Ctx) error {
+	u, err := ctx.ParamsInt("id")
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid user id format")
+	}
+
+	if u <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user id cant be less than 1")
+	}
+
+	data, err := r.c.Export(ctx.UserContext(), u)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
+		}
+		r.l.Error(err, "restapi - v1 - exportCalendar")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	ctx.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+
+	return ctx.Status(http.StatusOK).Send(data)
+}
+
+// @Summary Import calendar
+// @Description Creates events from an iCalendar (RFC 5545) file: all-day, timed and recurring VEVENTs,
+// @Description changed and cancelled instances of a series with RECURRENCE-ID. The file is sent as the body
+// @Description or as the "file" field of a multipart form. Times without a zone are taken in tz, by default
+// @Description the user's time zone. The report lists imported, skipped and failed VEVENTs with their lines,
+// @Description events imported before are skipped, so a file can be imported again.
+// @ID import-calendar
+// @Tags calendar
+// @Accept text/calendar,multipart/form-data
+// @Produce json
+// @Param id path int true "User ID"
+// @Param tz query string false "IANA time zone of floating times, defaults to the user's one"
+// @Param file formData file false "iCalendar file"
+// @Success 200 {object} response.ImportReport
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/users/{id}/import [post]
+func (r *V1) importCalendar(ctx *fiber.Ctx) error {
+	u, err := ctx.ParamsInt("id")
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid user id format")
+	}
+
+	if u <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user id cant be less than 1")
+	}
+
+	loc, err := common.Location(ctx.UserContext(), r.u, u, ctx.Query("tz"))
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidTimeZone) {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		}
+		r.l.Error(err, "restapi - v1 - importCalendar")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	file, err := calendarFile(ctx)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+	defer file.Close()
+
+	report, err := r.c.Import(ctx.UserContext(), u, file, loc)
+	if err != nil {
+		var parseErr *ical.ParseError
+		if errors.As(err, &parseErr) {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, parseErr.Error())
+		}
+		r.l.Error(err, "restapi - v1 - importCalendar")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response.ImportReport{
+		Imported: importEntries(report.Imported),
+		Skipped:  importEntries(report.Skipped),
+		Failed:   importEntries(report.Failed),
+	})
+}
+
+// calendarFile returns the "file" field of a multipart form or the body.
+func calendarFile(ctx *fiber.Ctx) (io.ReadCloser, error) {
+	if !strings.HasPrefix(ctx.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		if len(ctx.Body()) == 0 {
+			return nil, errors.New("calendar file required")
+		}
+
+		return io.NopCloser(bytes.NewReader(ctx.Body())), nil
+	}
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, errors.New(`calendar file required in the "file" field`)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, errors.New("invalid calendar file")
+	}
+
+	return file, nil
+}
+
+func importEntries(entries []entity.ImportEntry) []response.ImportEntry {
+	res := make([]response.ImportEntry, 0, len(entries))
+
+	for _, e := range entries {
+		entry := response.ImportEntry{Line: e.Line, ICalUID: e.ICalUID, Reason: e.Reason}
+		if e.EventUID != uuid.Nil {
+			entry.UID = e.EventUID.String()
+		}
+
+		res = append(res, entry)
+	}
+
+	return res
+}
@@ -9,4 +9,7 @@ import (
 type V1 struct {
 	l logger.Interface
 	e usecase.Events
+	c usecase.Calendar
+	u usecase.Users
+	w usecase.Webhooks
 }
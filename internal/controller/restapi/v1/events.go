@@ -6,9 +6,11 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/common"
 	"github.com/andreyxaxa/calendar/internal/controller/restapi/v1/request"
 	"github.com/andreyxaxa/calendar/internal/controller/restapi/v1/response"
 	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/mergepatch"
 	"github.com/andreyxaxa/calendar/pkg/types/date"
 	"github.com/andreyxaxa/calendar/pkg/types/errs"
 	"github.com/gofiber/fiber/v2"
@@ -16,14 +18,20 @@ import (
 )
 
 // @Summary Create
-// @Description Creates event by user_id, date, text
+// @Description Creates event by user_id, text and either start (with optional end) or date for an all-day event.
+// @Description tz defaults to the user's time zone. A retry with the same Idempotency-Key replays the first response
 // @ID create
 // @Tags events
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Key to retry the request safely"
 // @Param request body request.CreateRequest true "Event"
 // @Success 200 {object} response.Response
+// @Header 200 {string} ETag "Version of the event"
+// @Header 200 {string} Idempotent-Replayed "true for a replayed response"
 // @Failure 400 {object} response.Error
+// @Failure 409 {object} response.Error
+// @Failure 422 {object} response.Error
 // @Failure 500 {object} response.Error
 // @Router /v1/create_event [post]
 func (r *V1) create(ctx *fiber.Ctx) error {
@@ -31,58 +39,73 @@ func (r *V1) create(ctx *fiber.Ctx) error {
 
 	err := ctx.BodyParser(&body)
 	if err != nil {
-		return errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
 	}
 
 	if body.UserID <= 0 {
-		return errorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
 	}
 
-	if body.Date == nil {
-		return errorResponse(ctx, http.StatusBadRequest, "date required")
+	if body.Text == "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "text required")
 	}
 
-	if body.Text == "" {
-		return errorResponse(ctx, http.StatusBadRequest, "text required")
+	loc, err := common.Location(ctx.UserContext(), r.u, body.UserID, body.TimeZone)
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidTimeZone) {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		}
+		r.l.Error(err, "restapi - v1 - create")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
 	}
 
-	event := entity.Event{
-		Date: body.Date.Time,
-		Text: body.Text,
+	event, msg := common.EventSpan(body.Date, body.Start, body.End, body.AllDay, loc)
+	if msg != "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, msg)
 	}
 
+	if msg = common.Recurrence(&event, body.RRule, body.ExDates, loc); msg != "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, msg)
+	}
+
+	event.TimeZone = loc.String()
+	event.Text = body.Text
+
 	eventUID := uuid.New()
 
-	err = r.e.Create(ctx.UserContext(), body.UserID, eventUID, event)
+	event, err = r.e.Create(ctx.UserContext(), body.UserID, eventUID, event)
 	if err != nil {
 		if errors.Is(err, errs.ErrAlreadyExists) {
-			return errorResponse(ctx, http.StatusInternalServerError, err.Error())
+			return common.ErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		}
 		r.l.Error(err, "restapi - v1 - create")
 
-		return errorResponse(ctx, http.StatusInternalServerError, "storage problems")
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
 	}
 
-	resp := response.Response{Result: response.ResultEvent{
-		UID:    eventUID.String(),
-		UserID: body.UserID,
-		Date:   date.Date{Time: event.Date},
-		Text:   event.Text,
-	}}
+	common.SetETag(ctx, event.Version)
+
+	resp := response.Response{Result: resultEvent(body.UserID, eventUID, event)}
 
 	return ctx.Status(http.StatusOK).JSON(resp)
 }
 
 // @Summary Update
-// @Description Updates event
+// @Description Updates event. For a recurring one scope tells which instances change:
+// @Description all (default), this or following the one starting at recurrence_id.
+// @Description If-Match makes it fail with 412 unless the event (the series) has that version
 // @ID update
 // @Tags events
 // @Accept json
 // @Produce json
+// @Param If-Match header string false "ETag of the expected version"
 // @Param request body request.UpdateRequest true "Event"
 // @Success 200 {object} response.Response
+// @Header 200 {string} ETag "Version of the event"
 // @Failure 400 {object} response.Error
 // @Failure 404 {object} response.Error
+// @Failure 412 {object} response.Error
 // @Failure 500 {object} response.Error
 // @Router /v1/update_event [post]
 func (r *V1) update(ctx *fiber.Ctx) error {
@@ -90,62 +113,107 @@ func (r *V1) update(ctx *fiber.Ctx) error {
 
 	err := ctx.BodyParser(&body)
 	if err != nil {
-		return errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
 	}
 
 	if body.UserID <= 0 {
-		return errorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
 	}
 
 	if body.EventUID == "" {
-		return errorResponse(ctx, http.StatusBadRequest, "uid required")
-	}
-
-	if body.Date == nil {
-		return errorResponse(ctx, http.StatusBadRequest, "date required")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "uid required")
 	}
 
 	if body.Text == "" {
-		return errorResponse(ctx, http.StatusBadRequest, "text required")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "text required")
 	}
 
 	uid, err := uuid.Parse(body.EventUID)
 	if err != nil {
-		return errorResponse(ctx, http.StatusBadRequest, "invalid uid format")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid uid format")
+	}
+
+	scope, recurrenceID, msg := common.OccurrenceScope(body.Scope, body.RecurrenceID)
+	if msg != "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, msg)
+	}
+
+	version, err := common.IfMatch(ctx)
+	if err != nil {
+		if errors.Is(err, errs.ErrPreconditionFailed) {
+			return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
+		}
+
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
 	}
 
-	err = r.e.Update(ctx.UserContext(), body.UserID, uid, body.Text, body.Date.Time)
+	loc, err := common.Location(ctx.UserContext(), r.u, body.UserID, body.TimeZone)
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidTimeZone) {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		}
+		r.l.Error(err, "restapi - v1 - update")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	event, msg := common.EventSpan(body.Date, body.Start, body.End, body.AllDay, loc)
+	if msg != "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, msg)
+	}
+
+	if msg = common.Recurrence(&event, body.RRule, body.ExDates, loc); msg != "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, msg)
+	}
+
+	if scope == entity.ScopeThis && event.Recurring() {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "rrule and exdates cant be set for a single occurrence")
+	}
+
+	event.TimeZone = loc.String()
+	event.Text = body.Text
+	event.Version = version
+
+	occurrence, err := r.e.UpdateOccurrence(ctx.UserContext(), body.UserID, uid, recurrenceID, scope, event)
 	if err != nil {
 		if errors.Is(err, errs.ErrUserNotFound) {
-			return errorResponse(ctx, http.StatusNotFound, err.Error())
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
 		} else if errors.Is(err, errs.ErrEventNotFound) {
-			return errorResponse(ctx, http.StatusNotFound, err.Error())
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, errs.ErrOccurrenceNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, errs.ErrNotRecurring) {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		} else if errors.Is(err, errs.ErrPreconditionFailed) {
+			return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
 		}
 		r.l.Error(err, "restapi - v1 - update")
 
-		return errorResponse(ctx, http.StatusInternalServerError, "storage problems")
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
 	}
 
-	resp := response.Response{Result: response.ResultEvent{
-		UID:    body.EventUID,
-		UserID: body.UserID,
-		Date:   date.Date{Time: body.Date.Time},
-		Text:   body.Text,
-	}}
+	common.SetETag(ctx, occurrence.Event.Version)
+
+	resp := response.Response{Result: resultOccurrence(body.UserID, occurrence)}
 
 	return ctx.Status(http.StatusOK).JSON(resp)
 }
 
 // @Summary Delete
-// @Description Deletes event
+// @Description Deletes event, a whole one goes to the trash, see /v1/trash.
+// @Description For a recurring one scope tells which instances go:
+// @Description all (default), this or following the one starting at recurrence_id.
+// @Description If-Match makes it fail with 412 unless the event (the series) has that version
 // @ID delete
 // @Tags events
 // @Accept json
 // @Produce json
+// @Param If-Match header string false "ETag of the expected version"
 // @Param request body request.DeleteRequest true "Event"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Error
 // @Failure 404 {object} response.Error
+// @Failure 412 {object} response.Error
 // @Failure 500 {object} response.Error
 // @Router /v1/delete_event [post]
 func (r *V1) delete(ctx *fiber.Ctx) error {
@@ -153,42 +221,231 @@ func (r *V1) delete(ctx *fiber.Ctx) error {
 
 	err := ctx.BodyParser(&body)
 	if err != nil {
-		return errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
 	}
 
 	if body.UserID <= 0 {
-		return errorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
 	}
 
 	if body.EventUID == "" {
-		return errorResponse(ctx, http.StatusBadRequest, "uid required")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "uid required")
 	}
 
 	uid, err := uuid.Parse(body.EventUID)
 	if err != nil {
-		return errorResponse(ctx, http.StatusBadRequest, "invalid uid format")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid uid format")
 	}
 
-	err = r.e.Delete(ctx.UserContext(), body.UserID, uid)
+	scope, recurrenceID, msg := common.OccurrenceScope(body.Scope, body.RecurrenceID)
+	if msg != "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, msg)
+	}
+
+	version, err := common.IfMatch(ctx)
+	if err != nil {
+		if errors.Is(err, errs.ErrPreconditionFailed) {
+			return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
+		}
+
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	err = r.e.DeleteOccurrence(ctx.UserContext(), body.UserID, uid, recurrenceID, scope, version)
 	if err != nil {
 		if errors.Is(err, errs.ErrUserNotFound) {
-			return errorResponse(ctx, http.StatusNotFound, err.Error())
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
 		} else if errors.Is(err, errs.ErrEventNotFound) {
-			return errorResponse(ctx, http.StatusNotFound, err.Error())
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, errs.ErrOccurrenceNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, errs.ErrNotRecurring) {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		} else if errors.Is(err, errs.ErrPreconditionFailed) {
+			return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
 		}
 		r.l.Error(err, "restapi - v1 - delete")
 
-		return errorResponse(ctx, http.StatusInternalServerError, "storage problems")
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
 	}
 
 	return ctx.SendStatus(http.StatusOK)
 }
 
+// @Summary Get event
+// @Description Get event by uid, a series with its rrule and exdates
+// @ID get-event
+// @Tags events
+// @Produce json
+// @Param user_id query int true "User ID"
+// @Param uid query string true "Event UID"
+// @Success 200 {object} response.Response
+// @Header 200 {string} ETag "Version of the event"
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/event [get]
+func (r *V1) getEvent(ctx *fiber.Ctx) error {
+	u, err := strconv.Atoi(ctx.Query("user_id"))
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid user_id format")
+	}
+
+	if u <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+	}
+
+	if ctx.Query("uid") == "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "uid required")
+	}
+
+	uid, err := uuid.Parse(ctx.Query("uid"))
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid uid format")
+	}
+
+	event, err := r.e.GetByUID(ctx.UserContext(), u, uid)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, errs.ErrEventNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
+		}
+		r.l.Error(err, "restapi - v1 - getEvent")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	common.SetETag(ctx, event.Version)
+
+	resp := response.Response{Result: resultEvent(u, uid, event)}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
+
+// @Summary Patch event
+// @Description Changes only the fields present in the body, a JSON Merge Patch (RFC 7396) of the update
+// @Description request fields: null removes a field, absent ones stay. date or start replace the timing
+// @Description (end and all_day go with them), removing rrule ends the series, removing tz sets the user's one.
+// @Description If-Match makes it fail with 412 unless the event has that version, so does a write
+// @Description of the event between its read and the patched one
+// @ID patch-event
+// @Tags events
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param user_id query int true "User ID"
+// @Param uid query string true "Event UID"
+// @Param If-Match header string false "ETag of the expected version"
+// @Param request body object true "Merge patch"
+// @Success 200 {object} response.Response
+// @Header 200 {string} ETag "Version of the event"
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 412 {object} response.Error
+// @Failure 415 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/event [patch]
+func (r *V1) patchEvent(ctx *fiber.Ctx) error {
+	u, err := strconv.Atoi(ctx.Query("user_id"))
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid user_id format")
+	}
+
+	if u <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+	}
+
+	if ctx.Query("uid") == "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "uid required")
+	}
+
+	uid, err := uuid.Parse(ctx.Query("uid"))
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid uid format")
+	}
+
+	if !common.IsMergePatch(ctx) {
+		return common.ErrorResponse(ctx, http.StatusUnsupportedMediaType, "expected "+mergepatch.ContentType+" body")
+	}
+
+	p, err := mergepatch.Parse(ctx.Body(), common.PatchFields...)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	version, err := common.IfMatch(ctx)
+	if err != nil {
+		if errors.Is(err, errs.ErrPreconditionFailed) {
+			return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
+		}
+
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	current, err := r.e.GetByUID(ctx.UserContext(), u, uid)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, errs.ErrEventNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
+		}
+		r.l.Error(err, "restapi - v1 - patchEvent")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	if version != 0 && version != current.Version {
+		return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
+	}
+
+	tz, msg := common.PatchTimeZone(current, p)
+	if msg != "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, msg)
+	}
+
+	loc, err := common.Location(ctx.UserContext(), r.u, u, tz)
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidTimeZone) {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		}
+		r.l.Error(err, "restapi - v1 - patchEvent")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	patch, mask, msg := common.EventPatch(current, p, loc)
+	if msg != "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, msg)
+	}
+
+	event, err := r.e.UpdateFields(ctx.UserContext(), u, uid, patch, mask)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, errs.ErrEventNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, errs.ErrPreconditionFailed) {
+			return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
+		}
+		r.l.Error(err, "restapi - v1 - patchEvent")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	common.SetETag(ctx, event.Version)
+
+	resp := response.Response{Result: resultEvent(u, uid, event)}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
+
 // @Summary Get events for day
 // @Description Get events for day by date
 // @ID get-day
 // @Tags events
-// @Param date query string false "Date"
+// @Param user_id query int true "User ID"
+// @Param date query string true "Date, YYYY-MM-DD"
+// @Param tz query string false "IANA time zone of period boundaries, defaults to the user's one"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Error
 // @Failure 404 {object} response.Error
@@ -200,26 +457,39 @@ func (r *V1) getEventsForDay(ctx *fiber.Ctx) error {
 
 	d, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
-		return errorResponse(ctx, http.StatusBadRequest, "invalid date format, expected: YYYY-MM-DD")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid date format, expected: YYYY-MM-DD")
 	}
 
 	u, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		return errorResponse(ctx, http.StatusBadRequest, "invalid user_id format")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid user_id format")
 	}
 
 	if u <= 0 {
-		return errorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
 	}
 
+	loc, err := common.Location(ctx.UserContext(), r.u, u, ctx.Query("tz"))
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidTimeZone) {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		}
+		r.l.Error(err, "restapi - v1 - getEventsForDay")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	// period boundaries are midnights in the requested zone.
+	d = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+
 	events, err := r.e.GetEventsForDay(ctx.UserContext(), u, d)
 	if err != nil {
 		if errors.Is(err, errs.ErrUserNotFound) {
-			return errorResponse(ctx, http.StatusNotFound, err.Error())
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
 		}
 		r.l.Error(err, "restapi - v1 - getEventsForDay")
 
-		return errorResponse(ctx, http.StatusInternalServerError, "storage problems")
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
 	}
 
 	resps := make([]response.Response, 0, len(events))
@@ -228,13 +498,8 @@ func (r *V1) getEventsForDay(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusOK).JSON(resps)
 	}
 
-	for uid, event := range events {
-		resps = append(resps, response.Response{Result: response.ResultEvent{
-			UID:    uid.String(),
-			UserID: u,
-			Date:   date.Date{Time: event.Date},
-			Text:   event.Text,
-		}})
+	for _, occurrence := range events {
+		resps = append(resps, response.Response{Result: resultOccurrence(u, occurrence)})
 	}
 
 	return ctx.Status(http.StatusOK).JSON(resps)
@@ -244,7 +509,9 @@ func (r *V1) getEventsForDay(ctx *fiber.Ctx) error {
 // @Description Get events for week by date
 // @ID get-week
 // @Tags events
-// @Param date query string false "Date"
+// @Param user_id query int true "User ID"
+// @Param date query string true "Date, YYYY-MM-DD"
+// @Param tz query string false "IANA time zone of period boundaries, defaults to the user's one"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Error
 // @Failure 404 {object} response.Error
@@ -256,26 +523,39 @@ func (r *V1) getEventsForWeek(ctx *fiber.Ctx) error {
 
 	d, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
-		return errorResponse(ctx, http.StatusBadRequest, "invalid date format, expected: YYYY-MM-DD")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid date format, expected: YYYY-MM-DD")
 	}
 
 	u, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		return errorResponse(ctx, http.StatusBadRequest, "invalid user_id")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid user_id")
 	}
 
 	if u <= 0 {
-		return errorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
 	}
 
+	loc, err := common.Location(ctx.UserContext(), r.u, u, ctx.Query("tz"))
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidTimeZone) {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		}
+		r.l.Error(err, "restapi - v1 - getEventsForWeek")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	// period boundaries are midnights in the requested zone.
+	d = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+
 	events, err := r.e.GetEventsForWeek(ctx.UserContext(), u, d)
 	if err != nil {
 		if errors.Is(err, errs.ErrUserNotFound) {
-			return errorResponse(ctx, http.StatusNotFound, err.Error())
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
 		}
 		r.l.Error(err, "restapi - v1 - getEventsForWeek")
 
-		return errorResponse(ctx, http.StatusInternalServerError, "storage problems")
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
 	}
 
 	resps := make([]response.Response, 0, len(events))
@@ -284,13 +564,8 @@ func (r *V1) getEventsForWeek(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusOK).JSON(resps)
 	}
 
-	for uid, event := range events {
-		resps = append(resps, response.Response{Result: response.ResultEvent{
-			UID:    uid.String(),
-			UserID: u,
-			Date:   date.Date{Time: event.Date},
-			Text:   event.Text,
-		}})
+	for _, occurrence := range events {
+		resps = append(resps, response.Response{Result: resultOccurrence(u, occurrence)})
 	}
 
 	return ctx.Status(http.StatusOK).JSON(resps)
@@ -300,7 +575,9 @@ func (r *V1) getEventsForWeek(ctx *fiber.Ctx) error {
 // @Description Get events for month by date
 // @ID get-month
 // @Tags events
-// @Param date query string false "Date"
+// @Param user_id query int true "User ID"
+// @Param date query string true "Date, YYYY-MM-DD"
+// @Param tz query string false "IANA time zone of period boundaries, defaults to the user's one"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Error
 // @Failure 404 {object} response.Error
@@ -312,26 +589,39 @@ func (r *V1) getEventsForMonth(ctx *fiber.Ctx) error {
 
 	d, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
-		return errorResponse(ctx, http.StatusBadRequest, "invalid date format, expected: YYYY-MM-DD")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid date format, expected: YYYY-MM-DD")
 	}
 
 	u, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		return errorResponse(ctx, http.StatusBadRequest, "invalid user_id")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid user_id")
 	}
 
 	if u <= 0 {
-		return errorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
 	}
 
+	loc, err := common.Location(ctx.UserContext(), r.u, u, ctx.Query("tz"))
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidTimeZone) {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		}
+		r.l.Error(err, "restapi - v1 - getEventsForMonth")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	// period boundaries are midnights in the requested zone.
+	d = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+
 	events, err := r.e.GetEventsForMonth(ctx.UserContext(), u, d)
 	if err != nil {
 		if errors.Is(err, errs.ErrUserNotFound) {
-			return errorResponse(ctx, http.StatusNotFound, err.Error())
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
 		}
 		r.l.Error(err, "restapi - v1 - getEventsForMonth")
 
-		return errorResponse(ctx, http.StatusInternalServerError, "storage problems")
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
 	}
 
 	resps := make([]response.Response, 0, len(events))
@@ -340,14 +630,140 @@ func (r *V1) getEventsForMonth(ctx *fiber.Ctx) error {
 		return ctx.Status(http.StatusOK).JSON(resps)
 	}
 
-	for uid, event := range events {
-		resps = append(resps, response.Response{Result: response.ResultEvent{
-			UID:    uid.String(),
-			UserID: u,
-			Date:   date.Date{Time: event.Date},
-			Text:   event.Text,
-		}})
+	for _, occurrence := range events {
+		resps = append(resps, response.Response{Result: resultOccurrence(u, occurrence)})
 	}
 
 	return ctx.Status(http.StatusOK).JSON(resps)
 }
+
+// @Summary Get events for range
+// @Description Events of the user within [from, to), instances of series included, ordered by start
+// @Description (order=desc reverses it). Pages hold up to limit events, next_cursor of a page is passed
+// @Description as cursor to get the next one.
+// @ID get-range
+// @Tags events
+// @Produce json
+// @Param user_id query int true "User ID"
+// @Param from query string true "Start of the range, YYYY-MM-DD (midnight in tz) or RFC 3339"
+// @Param to query string true "End of the range, exclusive, YYYY-MM-DD (midnight in tz) or RFC 3339"
+// @Param tz query string false "IANA time zone of date bounds, defaults to the user's one"
+// @Param order query string false "asc (default) or desc"
+// @Param limit query int false "Page size, 1 to 500, 50 by default"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} response.EventsPage
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/events [get]
+func (r *V1) getEvents(ctx *fiber.Ctx) error {
+	u, err := strconv.Atoi(ctx.Query("user_id"))
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid user_id")
+	}
+
+	if u <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+	}
+
+	loc, err := common.Location(ctx.UserContext(), r.u, u, ctx.Query("tz"))
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidTimeZone) {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		}
+		r.l.Error(err, "restapi - v1 - getEvents")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	from, err := common.RangeBound(ctx.Query("from"), loc)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid from format, expected: YYYY-MM-DD or RFC 3339")
+	}
+
+	to, err := common.RangeBound(ctx.Query("to"), loc)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid to format, expected: YYYY-MM-DD or RFC 3339")
+	}
+
+	if !to.After(from) {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "to must be after from")
+	}
+
+	if to.After(from.AddDate(common.MaxRangeYears, 0, 0)) {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "range cant be longer than "+strconv.Itoa(common.MaxRangeYears)+" years")
+	}
+
+	page := entity.PageRequest{}
+
+	switch ctx.Query("order") {
+	case "", "asc":
+	case "desc":
+		page.Desc = true
+	default:
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid order, expected: asc or desc")
+	}
+
+	if page.Limit, err = common.PageLimit(ctx.Query("limit")); err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	if page.After, err = common.DecodeCursor(ctx.Query("cursor"), page.Desc); err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	res, err := r.e.GetEventsForRange(ctx.UserContext(), u, from, to, page)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
+		}
+		r.l.Error(err, "restapi - v1 - getEvents")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	resp := response.EventsPage{
+		Events: make([]response.ResultEvent, 0, len(res.Occurrences)),
+	}
+
+	for _, occurrence := range res.Occurrences {
+		resp.Events = append(resp.Events, resultOccurrence(u, occurrence))
+	}
+
+	if res.Next != nil {
+		resp.NextCursor = common.EncodeCursor(*res.Next, page.Desc)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
+
+// resultEvent renders timed events in their own zone, all-day ones stay at UTC midnight.
+func resultEvent(userID int, uid uuid.UUID, event entity.Event) response.ResultEvent {
+	event = common.InZone(event)
+
+	return response.ResultEvent{
+		UID:      uid.String(),
+		UserID:   userID,
+		Date:     date.Date{Time: event.Start},
+		Start:    event.Start,
+		End:      event.End,
+		AllDay:   event.AllDay,
+		TimeZone: event.TimeZone,
+		Text:     event.Text,
+		RRule:    event.RRule,
+		ExDates:  event.ExDates,
+		Version:  event.Version,
+	}
+}
+
+// resultOccurrence is resultEvent for an instance, recurring ones carry their recurrence id.
+func resultOccurrence(userID int, occurrence entity.Occurrence) response.ResultEvent {
+	result := resultEvent(userID, occurrence.UID, occurrence.Event)
+
+	if !occurrence.RecurrenceID.IsZero() {
+		recurrenceID := occurrence.RecurrenceID.In(result.Start.Location())
+		result.RecurrenceID = &recurrenceID
+	}
+
+	return result
+}
@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/common"
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/v1/request"
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/v1/response"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// @Summary Get event history
+// @Description Get revisions of the event, the first one first: who made each write (X-Actor header
+// @Description of the request, user:<id> without it), when, the changed fields and the event after it
+// @ID get-event-history
+// @Tags history
+// @Produce json
+// @Param user_id query int true "User ID"
+// @Param uid query string true "Event UID"
+// @Success 200 {object} response.HistoryResponse
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/event/history [get]
+func (r *V1) getHistory(ctx *fiber.Ctx) error {
+	u, err := strconv.Atoi(ctx.Query("user_id"))
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid user_id format")
+	}
+
+	if u <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+	}
+
+	if ctx.Query("uid") == "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "uid required")
+	}
+
+	uid, err := uuid.Parse(ctx.Query("uid"))
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid uid format")
+	}
+
+	revisions, err := r.e.GetHistory(ctx.UserContext(), u, uid)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, errs.ErrUserNotFound.Error())
+		} else if errors.Is(err, errs.ErrEventNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, errs.ErrEventNotFound.Error())
+		}
+		r.l.Error(err, "restapi - v1 - getHistory")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	resp := response.HistoryResponse{Result: make([]response.Revision, 0, len(revisions))}
+
+	for _, revision := range revisions {
+		result := response.Revision{
+			Number:  revision.Number,
+			Op:      string(revision.Op),
+			Actor:   revision.Actor,
+			At:      revision.At,
+			Changes: []response.FieldChange{},
+		}
+
+		for _, change := range revision.Changes() {
+			result.Changes = append(result.Changes, response.FieldChange(change))
+		}
+
+		if revision.After != nil {
+			event := resultEvent(u, uid, *revision.After)
+			result.Event = &event
+		}
+
+		resp.Result = append(resp.Result, result)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
+
+// @Summary Revert event
+// @Description Brings the event back to what it was after the revision, a write of its own in the history.
+// @Description Reverting to a delete deletes the event, an event in the trash is to be restored first.
+// @Description If-Match makes it fail with 412 unless the event has that version
+// @ID revert-event
+// @Tags history
+// @Accept json
+// @Produce json
+// @Param request body request.RevertRequest true "Revision"
+// @Param If-Match header string false "Expected version of the event"
+// @Success 200 {object} response.Response
+// @Header 200 {string} ETag "Version of the event"
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 412 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/revert_event [post]
+func (r *V1) revert(ctx *fiber.Ctx) error {
+	var body request.RevertRequest
+
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if body.UserID <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+	}
+
+	if body.EventUID == "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "uid required")
+	}
+
+	uid, err := uuid.Parse(body.EventUID)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid uid format")
+	}
+
+	if body.Revision <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "revision required and cant be less than 1")
+	}
+
+	version, err := common.IfMatch(ctx)
+	if err != nil {
+		if errors.Is(err, errs.ErrPreconditionFailed) {
+			return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
+		}
+
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	event, err := r.e.Revert(ctx.UserContext(), body.UserID, uid, body.Revision, version)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, errs.ErrUserNotFound.Error())
+		} else if errors.Is(err, errs.ErrEventNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, errs.ErrEventNotFound.Error())
+		} else if errors.Is(err, errs.ErrRevisionNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, errs.ErrRevisionNotFound.Error())
+		} else if errors.Is(err, errs.ErrPreconditionFailed) {
+			return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
+		}
+		r.l.Error(err, "restapi - v1 - revert")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	// reverted to a delete.
+	if event.Version == 0 {
+		return ctx.SendStatus(http.StatusOK)
+	}
+
+	common.SetETag(ctx, event.Version)
+
+	resp := response.Response{Result: resultEvent(body.UserID, uid, event)}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
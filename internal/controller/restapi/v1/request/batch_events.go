@@ -0,0 +1,31 @@
+package request
+
+import (
+	"time"
+
+	"github.com/andreyxaxa/calendar/pkg/types/date"
+)
+
+// BatchRequest - operations run in order. If atomic, either all of them are applied or none.
+type BatchRequest struct {
+	UserID     int              `json:"user_id"`
+	Atomic     bool             `json:"atomic"`
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchOperation - op is create, update or delete. update and delete take uid and version,
+// the expected one as in If-Match, 0 skips the check. Event fields are those of CreateRequest,
+// update replaces the whole event.
+type BatchOperation struct {
+	Op       string      `json:"op"`
+	EventUID string      `json:"uid"`
+	Version  int64       `json:"version"`
+	Date     *date.Date  `json:"date"`
+	Start    *time.Time  `json:"start"`
+	End      *time.Time  `json:"end"`
+	AllDay   bool        `json:"all_day"`
+	TimeZone string      `json:"tz"`
+	Text     string      `json:"text"`
+	RRule    string      `json:"rrule"`
+	ExDates  []time.Time `json:"exdates"`
+}
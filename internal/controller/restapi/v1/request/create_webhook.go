@@ -0,0 +1,11 @@
+package request
+
+// CreateWebhookRequest - url (http or https) gets POSTs on writes of events of the user of
+// the kinds in events: created, updated, deleted, all of them when empty. secret signs them,
+// one is generated if it is empty.
+type CreateWebhookRequest struct {
+	UserID int      `json:"user_id"`
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
@@ -1,7 +1,13 @@
 package request
 
-// DeleteRequest -.
+import "time"
+
+// DeleteRequest - scope (all, this or following) with recurrence_id limits
+// the delete to some instances of a series.
 type DeleteRequest struct {
 	UserID   int    `json:"user_id"`
 	EventUID string `json:"uid"`
+
+	Scope        string     `json:"scope"`
+	RecurrenceID *time.Time `json:"recurrence_id"`
 }
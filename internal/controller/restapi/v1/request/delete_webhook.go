@@ -0,0 +1,7 @@
+package request
+
+// DeleteWebhookRequest -.
+type DeleteWebhookRequest struct {
+	UserID int    `json:"user_id"`
+	ID     string `json:"id"`
+}
@@ -0,0 +1,7 @@
+package request
+
+// RestoreRequest -.
+type RestoreRequest struct {
+	UserID   int    `json:"user_id"`
+	EventUID string `json:"uid"`
+}
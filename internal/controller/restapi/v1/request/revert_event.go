@@ -0,0 +1,8 @@
+package request
+
+// RevertRequest - revision is the number of the revision to bring the event back to.
+type RevertRequest struct {
+	UserID   int    `json:"user_id"`
+	EventUID string `json:"uid"`
+	Revision int64  `json:"revision"`
+}
@@ -1,11 +1,27 @@
 package request
 
-import "github.com/andreyxaxa/calendar/pkg/types/date"
+import (
+	"time"
 
-// UpdateRequest - .
+	"github.com/andreyxaxa/calendar/pkg/types/date"
+)
+
+// UpdateRequest - timing is either start (with optional end) or date for an all-day event,
+// tz defaults to the user's time zone. rrule (RFC 5545, e.g. FREQ=WEEKLY;BYDAY=MO) makes it
+// a series starting at the first instance, exdates are starts of cancelled instances.
+// scope (all, this or following) with recurrence_id limits the update to some instances of a series.
 type UpdateRequest struct {
-	UserID   int        `json:"user_id"`
-	EventUID string     `json:"uid"`
-	Date     *date.Date `json:"date"`
-	Text     string     `json:"text"`
+	UserID   int         `json:"user_id"`
+	EventUID string      `json:"uid"`
+	Date     *date.Date  `json:"date"`
+	Start    *time.Time  `json:"start"`
+	End      *time.Time  `json:"end"`
+	AllDay   bool        `json:"all_day"`
+	TimeZone string      `json:"tz"`
+	Text     string      `json:"text"`
+	RRule    string      `json:"rrule"`
+	ExDates  []time.Time `json:"exdates"`
+
+	Scope        string     `json:"scope"`
+	RecurrenceID *time.Time `json:"recurrence_id"`
 }
@@ -0,0 +1,7 @@
+package request
+
+// UpdateUserRequest -.
+type UpdateUserRequest struct {
+	UserID   int    `json:"user_id"`
+	TimeZone string `json:"tz"`
+}
@@ -0,0 +1,15 @@
+package response
+
+// BatchResponse - results of the operations, in their order.
+type BatchResponse struct {
+	Result []BatchResult `json:"result"`
+}
+
+// BatchResult - Status is the one the operation would get as a single request.
+// Event is the stored event of a create or update, Error tells why the operation failed.
+type BatchResult struct {
+	Status int          `json:"status"`
+	UID    string       `json:"uid"`
+	Event  *ResultEvent `json:"event,omitempty"`
+	Error  string       `json:"error,omitempty"`
+}
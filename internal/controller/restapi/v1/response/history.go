@@ -0,0 +1,28 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// HistoryResponse - revisions of the event, the first one first.
+type HistoryResponse struct {
+	Result []Revision `json:"result"`
+}
+
+// Revision - a write of the event by Actor at At. Event is the event after it, missing for a delete.
+type Revision struct {
+	Number  int64         `json:"revision"`
+	Op      string        `json:"op"`
+	Actor   string        `json:"actor"`
+	At      time.Time     `json:"at"`
+	Changes []FieldChange `json:"changes"`
+	Event   *ResultEvent  `json:"event,omitempty"`
+}
+
+// FieldChange - values of the field before and after the revision, null where there was no event.
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before" swaggertype:"object"`
+	After  json.RawMessage `json:"after" swaggertype:"object"`
+}
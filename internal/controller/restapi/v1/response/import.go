@@ -0,0 +1,17 @@
+package response
+
+// ImportReport - entries are ordered by line.
+type ImportReport struct {
+	Imported []ImportEntry `json:"imported"`
+	Skipped  []ImportEntry `json:"skipped"`
+	Failed   []ImportEntry `json:"failed"`
+}
+
+// ImportEntry - Line is where the VEVENT begins in the file, ICalUID is its UID
+// and UID the uid of the created event.
+type ImportEntry struct {
+	Line    int    `json:"line"`
+	ICalUID string `json:"ical_uid,omitempty"`
+	UID     string `json:"uid,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
@@ -0,0 +1,8 @@
+package response
+
+// Notification - data of a message of the stream: the event of the notification as it was stored,
+// only UID for a deleted one, nothing for a reset.
+type Notification struct {
+	UID   string       `json:"uid,omitempty"`
+	Event *ResultEvent `json:"event,omitempty"`
+}
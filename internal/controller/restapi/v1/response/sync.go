@@ -0,0 +1,16 @@
+package response
+
+// SyncResponse - changes of events since the sync token, in the order they were made.
+// SyncToken is passed as sync_token of the next sync, More is set if changes follow it already.
+type SyncResponse struct {
+	Changes   []Change `json:"changes"`
+	SyncToken string   `json:"sync_token"`
+	More      bool     `json:"more"`
+}
+
+// Change - Event is the event as it is now, a tombstone of a deleted one has Deleted instead.
+type Change struct {
+	UID     string       `json:"uid"`
+	Deleted bool         `json:"deleted,omitempty"`
+	Event   *ResultEvent `json:"event,omitempty"`
+}
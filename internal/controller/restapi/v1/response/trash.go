@@ -0,0 +1,14 @@
+package response
+
+import "time"
+
+// TrashResponse - trashed events, the latest deleted first.
+type TrashResponse struct {
+	Result []TrashedEvent `json:"result"`
+}
+
+// TrashedEvent - the event as it was when deleted at DeletedAt.
+type TrashedEvent struct {
+	ResultEvent
+	DeletedAt time.Time `json:"deleted_at"`
+}
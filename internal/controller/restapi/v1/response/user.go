@@ -0,0 +1,7 @@
+package response
+
+// User -.
+type User struct {
+	UserID   int    `json:"user_id"`
+	TimeZone string `json:"tz"`
+}
@@ -0,0 +1,50 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookResponse -.
+type WebhookResponse struct {
+	Result Webhook `json:"result"`
+}
+
+// WebhooksResponse - webhooks of the user, the oldest first.
+type WebhooksResponse struct {
+	Result []Webhook `json:"result"`
+}
+
+// Webhook - Secret is only returned when the webhook is created.
+type Webhook struct {
+	ID        string    `json:"id"`
+	UserID    int       `json:"user_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DeliveriesResponse - deliveries of the webhook, the newest first.
+type DeliveriesResponse struct {
+	Result []Delivery `json:"result"`
+}
+
+// Delivery - Payload is the body POSTed, NextAttemptAt is set while the delivery is pending.
+type Delivery struct {
+	ID            string            `json:"id"`
+	Event         string            `json:"event"`
+	Status        string            `json:"status"`
+	Payload       json.RawMessage   `json:"payload" swaggertype:"object"`
+	Attempts      []DeliveryAttempt `json:"attempts"`
+	NextAttemptAt *time.Time        `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// DeliveryAttempt - StatusCode is missing if no response came.
+type DeliveryAttempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+}
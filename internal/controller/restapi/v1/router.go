@@ -7,9 +7,10 @@ import (
 )
 
 // NewEventsRoutes -.
-func NewEventsRoutes(apiV1Group fiber.Router, e usecase.Events, l logger.Interface) {
+func NewEventsRoutes(apiV1Group fiber.Router, e usecase.Events, u usecase.Users, l logger.Interface) {
 	r := &V1{
 		e: e,
+		u: u,
 		l: l,
 	}
 
@@ -17,9 +18,62 @@ func NewEventsRoutes(apiV1Group fiber.Router, e usecase.Events, l logger.Interfa
 		apiV1Group.Post("/create_event", r.create)
 		apiV1Group.Post("/update_event", r.update)
 		apiV1Group.Post("/delete_event", r.delete)
+		apiV1Group.Post("/events\\:batch", r.batch)
+		apiV1Group.Post("/restore_event", r.restore)
+		apiV1Group.Post("/revert_event", r.revert)
 
+		apiV1Group.Get("/event", r.getEvent)
+		apiV1Group.Get("/event/history", r.getHistory)
+		apiV1Group.Patch("/event", r.patchEvent)
 		apiV1Group.Get("/events_for_day", r.getEventsForDay)
 		apiV1Group.Get("/events_for_week", r.getEventsForWeek)
 		apiV1Group.Get("/events_for_month", r.getEventsForMonth)
+		apiV1Group.Get("/events", r.getEvents)
+		apiV1Group.Get("/trash", r.getTrash)
+		apiV1Group.Get("/sync", r.sync)
+		apiV1Group.Get("/stream", r.stream)
+	}
+}
+
+// NewCalendarRoutes -.
+func NewCalendarRoutes(apiV1Group fiber.Router, c usecase.Calendar, u usecase.Users, l logger.Interface) {
+	r := &V1{
+		c: c,
+		u: u,
+		l: l,
+	}
+
+	{
+		apiV1Group.Get("/users/:id/calendar.ics", r.exportCalendar)
+		apiV1Group.Post("/users/:id/import", r.importCalendar)
+	}
+}
+
+// NewUsersRoutes -.
+func NewUsersRoutes(apiV1Group fiber.Router, u usecase.Users, l logger.Interface) {
+	r := &V1{
+		u: u,
+		l: l,
+	}
+
+	{
+		apiV1Group.Get("/user", r.getUser)
+		apiV1Group.Post("/update_user", r.updateUser)
+	}
+}
+
+// NewWebhooksRoutes -.
+func NewWebhooksRoutes(apiV1Group fiber.Router, w usecase.Webhooks, l logger.Interface) {
+	r := &V1{
+		w: w,
+		l: l,
+	}
+
+	{
+		apiV1Group.Post("/create_webhook", r.createWebhook)
+		apiV1Group.Post("/delete_webhook", r.deleteWebhook)
+
+		apiV1Group.Get("/webhooks", r.getWebhooks)
+		apiV1Group.Get("/webhooks/deliveries", r.getWebhookDeliveries)
 	}
 }
@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/common"
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/v1/response"
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// _streamHeartbeat - how often an idle stream gets a comment, so proxies keep it open.
+	_streamHeartbeat = 15 * time.Second
+	// _streamWriteTimeout - how long a write to the stream may take, the server one covers
+	// only the start of the response.
+	_streamWriteTimeout = 5 * time.Second
+	// _streamRetry - how long, in milliseconds, clients wait before reconnecting.
+	_streamRetry = 3000
+)
+
+// @Summary Stream event changes
+// @Description Server-sent events of the user created, updated or deleted from now on. Each message
+// @Description has id, event - created, updated, deleted or reset - and data: the event as stored or,
+// @Description for deleted, its uid. On reconnect the client passes the id of the last message it got in
+// @Description Last-Event-ID, the messages after it come first. If some of them are lost, reset comes
+// @Description instead: the client has to reload events, e.g. by /v1/sync
+// @ID stream
+// @Tags stream
+// @Produce text/event-stream
+// @Param user_id query int true "User ID"
+// @Param Last-Event-ID header string false "ID of the last message got"
+// @Param last_event_id query string false "Same as Last-Event-ID, for clients that cant set headers"
+// @Success 200 {object} response.Notification
+// @Failure 400 {object} response.Error
+// @Router /v1/stream [get]
+func (r *V1) stream(ctx *fiber.Ctx) error {
+	u, err := strconv.Atoi(ctx.Query("user_id"))
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid user_id format")
+	}
+
+	if u <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+	}
+
+	lastEventID := ctx.Get("Last-Event-ID", ctx.Query("last_event_id"))
+
+	var after uint64
+
+	if lastEventID != "" {
+		after, err = strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid Last-Event-ID format")
+		}
+	}
+
+	// the stream outlives the handler, it ends when the client goes away or the server closes
+	// the notifications.
+	subCtx, cancel := context.WithCancel(context.Background())
+	notifications := r.e.Subscribe(subCtx, u, after)
+	conn := ctx.Context().Conn()
+
+	ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Set("X-Accel-Buffering", "no")
+
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
+		fmt.Fprintf(w, "retry: %d\n\n", _streamRetry)
+
+		if err := flushStream(conn, w); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(_streamHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case n, ok := <-notifications:
+				if !ok {
+					return
+				}
+
+				if err := r.writeNotification(w, n); err != nil {
+					r.l.Error(err, "restapi - v1 - stream")
+
+					return
+				}
+			case <-heartbeat.C:
+				w.WriteString(": ping\n\n")
+			}
+
+			if err := flushStream(conn, w); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// writeNotification writes n as a message of the stream.
+func (r *V1) writeNotification(w *bufio.Writer, n entity.Notification) error {
+	var data response.Notification
+
+	switch n.Kind {
+	case entity.NotificationReset:
+	case entity.NotificationDeleted:
+		data.UID = n.UID.String()
+	default:
+		data.UID = n.UID.String()
+		event := resultEvent(n.UserID, n.UID, n.Event)
+		data.Event = &event
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", n.ID, n.Kind, b)
+
+	return nil
+}
+
+// flushStream sends what is written so far, a client that doesnt take it in _streamWriteTimeout is gone.
+func flushStream(conn net.Conn, w *bufio.Writer) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(_streamWriteTimeout)); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,117 @@
+package v1
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/common"
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/v1/response"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/gofiber/fiber/v2"
+)
+
+var errInvalidSyncToken = errors.New("invalid sync_token")
+
+// syncToken - JSON form of a position of the change sequence of the user, clients get it
+// base64 encoded and opaque.
+type syncToken struct {
+	UserID int   `json:"u"`
+	Seq    int64 `json:"s"`
+}
+
+func encodeSyncToken(userID int, seq int64) string {
+	// syncToken holds nothing json cant encode.
+	b, _ := json.Marshal(syncToken{UserID: userID, Seq: seq})
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeSyncToken parses a sync token of the user, an empty one is the start of the sequence.
+func decodeSyncToken(s string, userID int) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, errInvalidSyncToken
+	}
+
+	var raw syncToken
+
+	if err = json.Unmarshal(b, &raw); err != nil || raw.UserID != userID || raw.Seq < 0 {
+		return 0, errInvalidSyncToken
+	}
+
+	return raw.Seq, nil
+}
+
+// @Summary Sync events
+// @Description Events of the user created, updated or deleted since sync_token, in the order of their
+// @Description latest change, without sync_token - all of them. Deleted events come as tombstones: uid and
+// @Description deleted. Pages hold up to limit changes, sync_token of the response is passed to get the
+// @Description next one or, once more is false, the changes made later. 410 means the token is no longer
+// @Description valid and the client has to sync without it
+// @ID sync
+// @Tags sync
+// @Produce json
+// @Param user_id query int true "User ID"
+// @Param sync_token query string false "sync_token of the previous sync"
+// @Param limit query int false "Page size, 1 to 500, 50 by default"
+// @Success 200 {object} response.SyncResponse
+// @Failure 400 {object} response.Error
+// @Failure 410 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/sync [get]
+func (r *V1) sync(ctx *fiber.Ctx) error {
+	u, err := strconv.Atoi(ctx.Query("user_id"))
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid user_id format")
+	}
+
+	if u <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+	}
+
+	limit, err := common.PageLimit(ctx.Query("limit"))
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	after, err := decodeSyncToken(ctx.Query("sync_token"), u)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	page, err := r.e.Sync(ctx.UserContext(), u, after, limit)
+	if err != nil {
+		if errors.Is(err, errs.ErrSyncTokenExpired) {
+			return common.ErrorResponse(ctx, http.StatusGone, errs.ErrSyncTokenExpired.Error())
+		}
+		r.l.Error(err, "restapi - v1 - sync")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	resp := response.SyncResponse{
+		Changes:   make([]response.Change, 0, len(page.Changes)),
+		SyncToken: encodeSyncToken(u, page.Seq),
+		More:      page.More,
+	}
+
+	for _, change := range page.Changes {
+		result := response.Change{UID: change.UID.String(), Deleted: change.Deleted}
+
+		if !change.Deleted {
+			event := resultEvent(u, change.UID, change.Event)
+			result.Event = &event
+		}
+
+		resp.Changes = append(resp.Changes, result)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
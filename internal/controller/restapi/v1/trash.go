@@ -0,0 +1,115 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/common"
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/v1/request"
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/v1/response"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// @Summary Get trash
+// @Description Get deleted events of the user, the latest deleted first.
+// @Description They are purged for good TRASH_RETENTION after the deletion
+// @ID get-trash
+// @Tags trash
+// @Produce json
+// @Param user_id query int true "User ID"
+// @Success 200 {object} response.TrashResponse
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/trash [get]
+func (r *V1) getTrash(ctx *fiber.Ctx) error {
+	u, err := strconv.Atoi(ctx.Query("user_id"))
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid user_id format")
+	}
+
+	if u <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+	}
+
+	events, err := r.e.GetTrash(ctx.UserContext(), u)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, errs.ErrUserNotFound.Error())
+		}
+		r.l.Error(err, "restapi - v1 - getTrash")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	resp := response.TrashResponse{Result: make([]response.TrashedEvent, 0, len(events))}
+
+	for _, event := range events {
+		resp.Result = append(resp.Result, response.TrashedEvent{
+			ResultEvent: resultEvent(u, event.UID, event.Event),
+			DeletedAt:   event.DeletedAt,
+		})
+	}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
+
+// @Summary Restore
+// @Description Moves a deleted event back from the trash, with the next version.
+// @Description Fails with 409 if an event was created under its uid after the deletion
+// @ID restore
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param request body request.RestoreRequest true "Event"
+// @Success 200 {object} response.Response
+// @Header 200 {string} ETag "Version of the event"
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 409 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/restore_event [post]
+func (r *V1) restore(ctx *fiber.Ctx) error {
+	var body request.RestoreRequest
+
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if body.UserID <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+	}
+
+	if body.EventUID == "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "uid required")
+	}
+
+	uid, err := uuid.Parse(body.EventUID)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid uid format")
+	}
+
+	event, err := r.e.Restore(ctx.UserContext(), body.UserID, uid)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, errs.ErrUserNotFound.Error())
+		} else if errors.Is(err, errs.ErrEventNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, "event not found in trash")
+		} else if errors.Is(err, errs.ErrAlreadyExists) {
+			return common.ErrorResponse(ctx, http.StatusConflict, "event with this uid already exists")
+		}
+		r.l.Error(err, "restapi - v1 - restore")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	common.SetETag(ctx, event.Version)
+
+	resp := response.Response{Result: resultEvent(body.UserID, uid, event)}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/common"
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/v1/request"
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/v1/response"
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Summary Get user
+// @Description Get user settings, tz is the default time zone of user's events and period queries
+// @ID get-user
+// @Tags users
+// @Produce json
+// @Param user_id query int true "User ID"
+// @Success 200 {object} response.User
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/user [get]
+func (r *V1) getUser(ctx *fiber.Ctx) error {
+	u, err := strconv.Atoi(ctx.Query("user_id"))
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid user_id format")
+	}
+
+	if u <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+	}
+
+	user, err := r.u.Get(ctx.UserContext(), u)
+	if err != nil {
+		r.l.Error(err, "restapi - v1 - getUser")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response.User{
+		UserID:   user.ID,
+		TimeZone: user.TimeZone,
+	})
+}
+
+// @Summary Update user
+// @Description Updates user settings
+// @ID update-user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body request.UpdateUserRequest true "User"
+// @Success 200 {object} response.User
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/update_user [post]
+func (r *V1) updateUser(ctx *fiber.Ctx) error {
+	var body request.UpdateUserRequest
+
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if body.UserID <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+	}
+
+	loc, err := common.LoadLocation(body.TimeZone)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	user := entity.User{
+		ID:       body.UserID,
+		TimeZone: loc.String(),
+	}
+
+	err = r.u.Update(ctx.UserContext(), user)
+	if err != nil {
+		r.l.Error(err, "restapi - v1 - updateUser")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(response.User{
+		UserID:   user.ID,
+		TimeZone: user.TimeZone,
+	})
+}
@@ -0,0 +1,272 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/common"
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/v1/request"
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/v1/response"
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// _webhookEvents - notification kinds a webhook may subscribe to.
+var _webhookEvents = []entity.NotificationKind{
+	entity.NotificationCreated,
+	entity.NotificationUpdated,
+	entity.NotificationDeleted,
+}
+
+// @Summary Create webhook
+// @Description Subscribes url to writes of events of the user: each write of the kinds in events
+// @Description (created, updated, deleted, all of them when empty) is POSTed to it as JSON signed with secret -
+// @Description X-Webhook-Signature: sha256= and hex of HMAC-SHA256 of the body. A failed delivery is retried with
+// @Description exponential backoff, after the last attempt it goes to the dead-letter list. secret is generated
+// @Description unless set, it is returned only here
+// @ID create-webhook
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param request body request.CreateWebhookRequest true "Webhook"
+// @Success 200 {object} response.WebhookResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/create_webhook [post]
+func (r *V1) createWebhook(ctx *fiber.Ctx) error {
+	var body request.CreateWebhookRequest
+
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if body.UserID <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+	}
+
+	if body.URL == "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "url required")
+	}
+
+	u, err := url.Parse(body.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid url, want an absolute http or https one")
+	}
+
+	w := entity.Webhook{UserID: body.UserID, URL: body.URL, Secret: body.Secret}
+
+	for _, event := range body.Events {
+		kind := entity.NotificationKind(event)
+
+		if !slices.Contains(_webhookEvents, kind) {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid events, want created, updated or deleted")
+		}
+
+		if !slices.Contains(w.Kinds, kind) {
+			w.Kinds = append(w.Kinds, kind)
+		}
+	}
+
+	w, err = r.w.Create(ctx.UserContext(), w)
+	if err != nil {
+		r.l.Error(err, "restapi - v1 - createWebhook")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	resp := response.WebhookResponse{Result: resultWebhook(w)}
+	resp.Result.Secret = w.Secret
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
+
+// @Summary Get webhooks
+// @Description Get webhooks of the user, the oldest first. Secrets are not returned
+// @ID get-webhooks
+// @Tags webhooks
+// @Produce json
+// @Param user_id query int true "User ID"
+// @Success 200 {object} response.WebhooksResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/webhooks [get]
+func (r *V1) getWebhooks(ctx *fiber.Ctx) error {
+	u, err := strconv.Atoi(ctx.Query("user_id"))
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid user_id format")
+	}
+
+	if u <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+	}
+
+	webhooks, err := r.w.GetWebhooks(ctx.UserContext(), u)
+	if err != nil {
+		r.l.Error(err, "restapi - v1 - getWebhooks")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	resp := response.WebhooksResponse{Result: make([]response.Webhook, 0, len(webhooks))}
+
+	for _, w := range webhooks {
+		resp.Result = append(resp.Result, resultWebhook(w))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
+
+// @Summary Delete webhook
+// @Description Deletes the webhook with its deliveries, pending ones are not attempted any more
+// @ID delete-webhook
+// @Tags webhooks
+// @Accept json
+// @Param request body request.DeleteWebhookRequest true "Webhook"
+// @Success 200
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/delete_webhook [post]
+func (r *V1) deleteWebhook(ctx *fiber.Ctx) error {
+	var body request.DeleteWebhookRequest
+
+	err := ctx.BodyParser(&body)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if body.UserID <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+	}
+
+	if body.ID == "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "id required")
+	}
+
+	id, err := uuid.Parse(body.ID)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid id format")
+	}
+
+	err = r.w.Delete(ctx.UserContext(), body.UserID, id)
+	if err != nil {
+		if errors.Is(err, errs.ErrWebhookNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, errs.ErrWebhookNotFound.Error())
+		}
+		r.l.Error(err, "restapi - v1 - deleteWebhook")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	return ctx.SendStatus(http.StatusOK)
+}
+
+// @Summary Get webhook deliveries
+// @Description Get deliveries of the webhook with their attempts, the newest first. status filters them:
+// @Description pending, delivered or dead - the dead-letter list of deliveries whose attempts all failed
+// @ID get-webhook-deliveries
+// @Tags webhooks
+// @Produce json
+// @Param user_id query int true "User ID"
+// @Param webhook_id query string true "Webhook ID"
+// @Param status query string false "pending, delivered or dead"
+// @Param limit query int false "1 to 500, 50 by default"
+// @Success 200 {object} response.DeliveriesResponse
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v1/webhooks/deliveries [get]
+func (r *V1) getWebhookDeliveries(ctx *fiber.Ctx) error {
+	u, err := strconv.Atoi(ctx.Query("user_id"))
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid user_id format")
+	}
+
+	if u <= 0 {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "user_id required and cant be less than 1")
+	}
+
+	if ctx.Query("webhook_id") == "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "webhook_id required")
+	}
+
+	id, err := uuid.Parse(ctx.Query("webhook_id"))
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid webhook_id format")
+	}
+
+	status := entity.DeliveryStatus(ctx.Query("status"))
+
+	switch status {
+	case "", entity.DeliveryPending, entity.DeliveryDelivered, entity.DeliveryDead:
+	default:
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid status, want pending, delivered or dead")
+	}
+
+	limit, err := common.PageLimit(ctx.Query("limit"))
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	deliveries, err := r.w.GetDeliveries(ctx.UserContext(), u, id, status, limit)
+	if err != nil {
+		if errors.Is(err, errs.ErrWebhookNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, errs.ErrWebhookNotFound.Error())
+		}
+		r.l.Error(err, "restapi - v1 - getWebhookDeliveries")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	resp := response.DeliveriesResponse{Result: make([]response.Delivery, 0, len(deliveries))}
+
+	for _, d := range deliveries {
+		delivery := response.Delivery{
+			ID:        d.ID.String(),
+			Event:     string(d.Kind),
+			Status:    string(d.Status),
+			Payload:   d.Payload,
+			Attempts:  make([]response.DeliveryAttempt, 0, len(d.Attempts)),
+			CreatedAt: d.CreatedAt,
+		}
+
+		if d.Status == entity.DeliveryPending {
+			delivery.NextAttemptAt = &d.NextAttemptAt
+		}
+
+		for _, a := range d.Attempts {
+			delivery.Attempts = append(delivery.Attempts, response.DeliveryAttempt{
+				At:         a.At,
+				StatusCode: a.StatusCode,
+				Error:      a.Error,
+				DurationMS: a.Duration.Milliseconds(),
+			})
+		}
+
+		resp.Result = append(resp.Result, delivery)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
+
+// resultWebhook returns the webhook without its secret.
+func resultWebhook(w entity.Webhook) response.Webhook {
+	events := make([]string, 0, len(w.Kinds))
+	for _, kind := range w.Kinds {
+		events = append(events, string(kind))
+	}
+
+	return response.Webhook{
+		ID:        w.ID.String(),
+		UserID:    w.UserID,
+		URL:       w.URL,
+		Events:    events,
+		CreatedAt: w.CreatedAt,
+	}
+}
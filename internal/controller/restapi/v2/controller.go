@@ -0,0 +1,13 @@
+package v2
+
+import (
+	"github.com/andreyxaxa/calendar/internal/usecase"
+	"github.com/andreyxaxa/calendar/pkg/logger"
+)
+
+// V2 -.
+type V2 struct {
+	l logger.Interface
+	e usecase.Events
+	u usecase.Users
+}
@@ -0,0 +1,553 @@
+package v2
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/common"
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/v2/request"
+	"github.com/andreyxaxa/calendar/internal/controller/restapi/v2/response"
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/mergepatch"
+	"github.com/andreyxaxa/calendar/pkg/types/date"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// @Summary List events
+// @Description Events of the user within [from, to), instances of series included, ordered by start
+// @Description (order=desc reverses it). Pages hold up to limit events, next_cursor of a page is passed
+// @Description as cursor to get the next one.
+// @ID v2-list-events
+// @Tags events v2
+// @Produce json
+// @Param userID path int true "User ID"
+// @Param from query string true "Start of the range, YYYY-MM-DD (midnight in tz) or RFC 3339"
+// @Param to query string true "End of the range, exclusive, YYYY-MM-DD (midnight in tz) or RFC 3339"
+// @Param tz query string false "IANA time zone of date bounds, defaults to the user's one"
+// @Param order query string false "asc (default) or desc"
+// @Param limit query int false "Page size, 1 to 500, 50 by default"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} response.EventsPage
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v2/users/{userID}/events [get]
+func (r *V2) listEvents(ctx *fiber.Ctx) error {
+	u, ok := userID(ctx)
+	if !ok {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid userID")
+	}
+
+	loc, err := common.Location(ctx.UserContext(), r.u, u, ctx.Query("tz"))
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidTimeZone) {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		}
+		r.l.Error(err, "restapi - v2 - listEvents")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	from, err := common.RangeBound(ctx.Query("from"), loc)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid from format, expected: YYYY-MM-DD or RFC 3339")
+	}
+
+	to, err := common.RangeBound(ctx.Query("to"), loc)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid to format, expected: YYYY-MM-DD or RFC 3339")
+	}
+
+	if !to.After(from) {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "to must be after from")
+	}
+
+	if to.After(from.AddDate(common.MaxRangeYears, 0, 0)) {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "range cant be longer than "+strconv.Itoa(common.MaxRangeYears)+" years")
+	}
+
+	page := entity.PageRequest{}
+
+	switch ctx.Query("order") {
+	case "", "asc":
+	case "desc":
+		page.Desc = true
+	default:
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid order, expected: asc or desc")
+	}
+
+	if page.Limit, err = common.PageLimit(ctx.Query("limit")); err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	if page.After, err = common.DecodeCursor(ctx.Query("cursor"), page.Desc); err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	// a user without events has an empty collection.
+	res, err := r.e.GetEventsForRange(ctx.UserContext(), u, from, to, page)
+	if err != nil && !errors.Is(err, errs.ErrUserNotFound) {
+		r.l.Error(err, "restapi - v2 - listEvents")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	resp := response.EventsPage{
+		Events: make([]response.Event, 0, len(res.Occurrences)),
+	}
+
+	for _, occurrence := range res.Occurrences {
+		resp.Events = append(resp.Events, resultOccurrence(occurrence))
+	}
+
+	if res.Next != nil {
+		resp.NextCursor = common.EncodeCursor(*res.Next, page.Desc)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
+
+// @Summary Create event
+// @Description Creates an event from text and either start (with optional end) or date for an all-day event.
+// @Description tz defaults to the user's time zone. Location holds the URL of the event.
+// @Description A retry with the same Idempotency-Key replays the first response
+// @ID v2-create-event
+// @Tags events v2
+// @Accept json
+// @Produce json
+// @Param userID path int true "User ID"
+// @Param Idempotency-Key header string false "Key to retry the request safely"
+// @Param request body request.Event true "Event"
+// @Success 201 {object} response.Event
+// @Header 201 {string} ETag "Version of the event"
+// @Header 201 {string} Idempotent-Replayed "true for a replayed response"
+// @Failure 400 {object} response.Error
+// @Failure 409 {object} response.Error
+// @Failure 422 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v2/users/{userID}/events [post]
+func (r *V2) createEvent(ctx *fiber.Ctx) error {
+	u, ok := userID(ctx)
+	if !ok {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid userID")
+	}
+
+	var body request.Event
+
+	if err := ctx.BodyParser(&body); err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	event, status, msg := r.event(ctx, u, body)
+	if msg != "" {
+		return common.ErrorResponse(ctx, status, msg)
+	}
+
+	eventUID := uuid.New()
+
+	event, err := r.e.Create(ctx.UserContext(), u, eventUID, event)
+	if err != nil {
+		if errors.Is(err, errs.ErrAlreadyExists) {
+			return common.ErrorResponse(ctx, http.StatusConflict, err.Error())
+		}
+		r.l.Error(err, "restapi - v2 - createEvent")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	ctx.Location(eventPath(u, eventUID))
+	common.SetETag(ctx, event.Version)
+
+	return ctx.Status(http.StatusCreated).JSON(resultEvent(eventUID, event))
+}
+
+// @Summary Get event
+// @Description Returns the event, a series with its rrule and exdates
+// @ID v2-get-event
+// @Tags events v2
+// @Produce json
+// @Param userID path int true "User ID"
+// @Param uid path string true "Event UID"
+// @Success 200 {object} response.Event
+// @Header 200 {string} ETag "Version of the event"
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v2/users/{userID}/events/{uid} [get]
+func (r *V2) getEvent(ctx *fiber.Ctx) error {
+	u, uid, msg := eventID(ctx)
+	if msg != "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, msg)
+	}
+
+	event, err := r.e.GetByUID(ctx.UserContext(), u, uid)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) || errors.Is(err, errs.ErrEventNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, errs.ErrEventNotFound.Error())
+		}
+		r.l.Error(err, "restapi - v2 - getEvent")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	common.SetETag(ctx, event.Version)
+
+	return ctx.Status(http.StatusOK).JSON(resultEvent(uid, event))
+}
+
+// @Summary Put event
+// @Description Replaces the event, creating it under uid if there is none. Changed instances
+// @Description of a series are kept as long as its instances stay where they were.
+// @Description If-Match makes it fail with 412 unless the event exists and has that version,
+// @Description If-None-Match: * unless there is no event
+// @ID v2-put-event
+// @Tags events v2
+// @Accept json
+// @Produce json
+// @Param userID path int true "User ID"
+// @Param uid path string true "Event UID"
+// @Param If-Match header string false "ETag of the expected version or *"
+// @Param If-None-Match header string false "* to only create the event"
+// @Param request body request.Event true "Event"
+// @Success 200 {object} response.Event
+// @Success 201 {object} response.Event
+// @Header 200,201 {string} ETag "Version of the event"
+// @Failure 400 {object} response.Error
+// @Failure 409 {object} response.Error
+// @Failure 412 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v2/users/{userID}/events/{uid} [put]
+func (r *V2) putEvent(ctx *fiber.Ctx) error {
+	u, uid, msg := eventID(ctx)
+	if msg != "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, msg)
+	}
+
+	var body request.Event
+
+	if err := ctx.BodyParser(&body); err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	version, err := common.IfMatch(ctx)
+	if err != nil {
+		if errors.Is(err, errs.ErrPreconditionFailed) {
+			return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
+		}
+
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	noneMatch := ctx.Get(fiber.HeaderIfNoneMatch)
+	if noneMatch != "" && noneMatch != "*" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid If-None-Match, only * is supported")
+	}
+
+	event, status, msg := r.event(ctx, u, body)
+	if msg != "" {
+		return common.ErrorResponse(ctx, status, msg)
+	}
+
+	if noneMatch == "" {
+		event.Version = version
+
+		updated, err := r.e.Update(ctx.UserContext(), u, uid, event)
+		if err == nil {
+			common.SetETag(ctx, updated.Version)
+
+			return ctx.Status(http.StatusOK).JSON(resultEvent(uid, updated))
+		}
+
+		if errors.Is(err, errs.ErrPreconditionFailed) {
+			return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
+		}
+
+		if !errors.Is(err, errs.ErrUserNotFound) && !errors.Is(err, errs.ErrEventNotFound) {
+			r.l.Error(err, "restapi - v2 - putEvent")
+
+			return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+		}
+
+		// If-Match, even *, needs an existing event.
+		if ctx.Get(fiber.HeaderIfMatch) != "" {
+			return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
+		}
+
+		event.Version = 0
+	}
+
+	event, err = r.e.Create(ctx.UserContext(), u, uid, event)
+	if err != nil {
+		if errors.Is(err, errs.ErrAlreadyExists) {
+			if noneMatch != "" {
+				return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
+			}
+
+			// created by a concurrent request.
+			return common.ErrorResponse(ctx, http.StatusConflict, err.Error())
+		}
+		r.l.Error(err, "restapi - v2 - putEvent")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	ctx.Location(eventPath(u, uid))
+	common.SetETag(ctx, event.Version)
+
+	return ctx.Status(http.StatusCreated).JSON(resultEvent(uid, event))
+}
+
+// @Summary Patch event
+// @Description Changes only the fields present in the body, a JSON Merge Patch (RFC 7396) of the event:
+// @Description null removes a field, absent ones stay. date or start replace the timing (end and all_day
+// @Description go with them), removing rrule ends the series, removing tz sets the user's one.
+// @Description If-Match makes it fail with 412 unless the event has that version, so does a write
+// @Description of the event between its read and the patched one
+// @ID v2-patch-event
+// @Tags events v2
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param userID path int true "User ID"
+// @Param uid path string true "Event UID"
+// @Param If-Match header string false "ETag of the expected version"
+// @Param request body request.Event true "Merge patch"
+// @Success 200 {object} response.Event
+// @Header 200 {string} ETag "Version of the event"
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 412 {object} response.Error
+// @Failure 415 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v2/users/{userID}/events/{uid} [patch]
+func (r *V2) patchEvent(ctx *fiber.Ctx) error {
+	u, uid, msg := eventID(ctx)
+	if msg != "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, msg)
+	}
+
+	if !common.IsMergePatch(ctx) {
+		return common.ErrorResponse(ctx, http.StatusUnsupportedMediaType, "expected "+mergepatch.ContentType+" body")
+	}
+
+	p, err := mergepatch.Parse(ctx.Body(), common.PatchFields...)
+	if err != nil {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	version, err := common.IfMatch(ctx)
+	if err != nil {
+		if errors.Is(err, errs.ErrPreconditionFailed) {
+			return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
+		}
+
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	current, err := r.e.GetByUID(ctx.UserContext(), u, uid)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) || errors.Is(err, errs.ErrEventNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, errs.ErrEventNotFound.Error())
+		}
+		r.l.Error(err, "restapi - v2 - patchEvent")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	if version != 0 && version != current.Version {
+		return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
+	}
+
+	tz, msg := common.PatchTimeZone(current, p)
+	if msg != "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, msg)
+	}
+
+	loc, err := common.Location(ctx.UserContext(), r.u, u, tz)
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidTimeZone) {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		}
+		r.l.Error(err, "restapi - v2 - patchEvent")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	patch, mask, msg := common.EventPatch(current, p, loc)
+	if msg != "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, msg)
+	}
+
+	event, err := r.e.UpdateFields(ctx.UserContext(), u, uid, patch, mask)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) || errors.Is(err, errs.ErrEventNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, errs.ErrEventNotFound.Error())
+		} else if errors.Is(err, errs.ErrPreconditionFailed) {
+			return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
+		}
+		r.l.Error(err, "restapi - v2 - patchEvent")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	common.SetETag(ctx, event.Version)
+
+	return ctx.Status(http.StatusOK).JSON(resultEvent(uid, event))
+}
+
+// @Summary Delete event
+// @Description Deletes the event, a whole one goes to the trash, see /v1/trash.
+// @Description For a recurring one scope tells which instances go:
+// @Description all (default), this or following the one starting at recurrence_id.
+// @Description If-Match makes it fail with 412 unless the event (the series) has that version
+// @ID v2-delete-event
+// @Tags events v2
+// @Param userID path int true "User ID"
+// @Param uid path string true "Event UID"
+// @Param scope query string false "all (default), this or following"
+// @Param recurrence_id query string false "Original start of the instance, RFC 3339"
+// @Param If-Match header string false "ETag of the expected version"
+// @Success 204
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 412 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /v2/users/{userID}/events/{uid} [delete]
+func (r *V2) deleteEvent(ctx *fiber.Ctx) error {
+	u, uid, msg := eventID(ctx)
+	if msg != "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, msg)
+	}
+
+	var recurrenceID *time.Time
+
+	if s := ctx.Query("recurrence_id"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, "invalid recurrence_id format, expected RFC 3339")
+		}
+
+		recurrenceID = &t
+	}
+
+	scope, start, msg := common.OccurrenceScope(ctx.Query("scope"), recurrenceID)
+	if msg != "" {
+		return common.ErrorResponse(ctx, http.StatusBadRequest, msg)
+	}
+
+	version, err := common.IfMatch(ctx)
+	if err != nil {
+		if errors.Is(err, errs.ErrPreconditionFailed) {
+			return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
+		}
+
+		return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	err = r.e.DeleteOccurrence(ctx.UserContext(), u, uid, start, scope, version)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) || errors.Is(err, errs.ErrEventNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, errs.ErrEventNotFound.Error())
+		} else if errors.Is(err, errs.ErrOccurrenceNotFound) {
+			return common.ErrorResponse(ctx, http.StatusNotFound, err.Error())
+		} else if errors.Is(err, errs.ErrNotRecurring) {
+			return common.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		} else if errors.Is(err, errs.ErrPreconditionFailed) {
+			return common.ErrorResponse(ctx, http.StatusPreconditionFailed, errs.ErrPreconditionFailed.Error())
+		}
+		r.l.Error(err, "restapi - v2 - deleteEvent")
+
+		return common.ErrorResponse(ctx, http.StatusInternalServerError, "storage problems")
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+// event validates a full representation of the event, a non-empty msg goes with its status.
+func (r *V2) event(ctx *fiber.Ctx, userID int, body request.Event) (entity.Event, int, string) {
+	if body.Text == "" {
+		return entity.Event{}, http.StatusBadRequest, "text required"
+	}
+
+	loc, err := common.Location(ctx.UserContext(), r.u, userID, body.TimeZone)
+	if err != nil {
+		if errors.Is(err, common.ErrInvalidTimeZone) {
+			return entity.Event{}, http.StatusBadRequest, err.Error()
+		}
+		r.l.Error(err, "restapi - v2 - event")
+
+		return entity.Event{}, http.StatusInternalServerError, "storage problems"
+	}
+
+	event, msg := common.EventSpan(body.Date, body.Start, body.End, body.AllDay, loc)
+	if msg != "" {
+		return entity.Event{}, http.StatusBadRequest, msg
+	}
+
+	if msg = common.Recurrence(&event, body.RRule, body.ExDates, loc); msg != "" {
+		return entity.Event{}, http.StatusBadRequest, msg
+	}
+
+	event.TimeZone = loc.String()
+	event.Text = body.Text
+
+	return event, 0, ""
+}
+
+// resultEvent renders timed events in their own zone, all-day ones stay at UTC midnight.
+func resultEvent(uid uuid.UUID, event entity.Event) response.Event {
+	event = common.InZone(event)
+
+	return response.Event{
+		UID:      uid.String(),
+		Date:     date.Date{Time: event.Start},
+		Start:    event.Start,
+		End:      event.End,
+		AllDay:   event.AllDay,
+		TimeZone: event.TimeZone,
+		Text:     event.Text,
+		RRule:    event.RRule,
+		ExDates:  event.ExDates,
+		Version:  event.Version,
+	}
+}
+
+// resultOccurrence is resultEvent for an instance, recurring ones carry their recurrence id.
+func resultOccurrence(occurrence entity.Occurrence) response.Event {
+	result := resultEvent(occurrence.UID, occurrence.Event)
+
+	if !occurrence.RecurrenceID.IsZero() {
+		recurrenceID := occurrence.RecurrenceID.In(result.Start.Location())
+		result.RecurrenceID = &recurrenceID
+	}
+
+	return result
+}
+
+// userID returns the user of the path, false for ids that cant be one.
+func userID(ctx *fiber.Ctx) (int, bool) {
+	u, err := ctx.ParamsInt("userID")
+
+	return u, err == nil && u > 0
+}
+
+// eventID returns the user and the event of the path, a non-empty msg describes invalid ones.
+func eventID(ctx *fiber.Ctx) (int, uuid.UUID, string) {
+	u, ok := userID(ctx)
+	if !ok {
+		return 0, uuid.Nil, "invalid userID"
+	}
+
+	uid, err := uuid.Parse(ctx.Params("uid"))
+	if err != nil {
+		return 0, uuid.Nil, "invalid uid format"
+	}
+
+	return u, uid, ""
+}
+
+func eventPath(userID int, uid uuid.UUID) string {
+	return "/v2/users/" + strconv.Itoa(userID) + "/events/" + uid.String()
+}
@@ -0,0 +1,21 @@
+package request
+
+import (
+	"time"
+
+	"github.com/andreyxaxa/calendar/pkg/types/date"
+)
+
+// Event - timing is either start (with optional end) or date for an all-day event,
+// tz defaults to the user's time zone. rrule (RFC 5545, e.g. FREQ=WEEKLY;BYDAY=MO) makes it
+// a series starting at the first instance, exdates are starts of cancelled instances.
+type Event struct {
+	Date     *date.Date  `json:"date"`
+	Start    *time.Time  `json:"start"`
+	End      *time.Time  `json:"end"`
+	AllDay   bool        `json:"all_day"`
+	TimeZone string      `json:"tz"`
+	Text     string      `json:"text"`
+	RRule    string      `json:"rrule"`
+	ExDates  []time.Time `json:"exdates"`
+}
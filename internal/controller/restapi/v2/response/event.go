@@ -0,0 +1,31 @@
+package response
+
+import (
+	"time"
+
+	"github.com/andreyxaxa/calendar/pkg/types/date"
+)
+
+// Event - Start and End are in the event's time zone, Date is the day of Start.
+// End of an all-day event is the day after the last one.
+// Instances of a series share its UID and have RecurrenceID, their original start.
+// Version is the one of the event, of the series for instances, and its ETag.
+type Event struct {
+	UID          string      `json:"uid"`
+	Date         date.Date   `json:"date"`
+	Start        time.Time   `json:"start"`
+	End          time.Time   `json:"end"`
+	AllDay       bool        `json:"all_day"`
+	TimeZone     string      `json:"tz"`
+	Text         string      `json:"text"`
+	RRule        string      `json:"rrule,omitempty"`
+	ExDates      []time.Time `json:"exdates,omitempty"`
+	RecurrenceID *time.Time  `json:"recurrence_id,omitempty"`
+	Version      int64       `json:"version,omitempty"`
+}
+
+// EventsPage - a page of events ordered by start, NextCursor is set when more follow.
+type EventsPage struct {
+	Events     []Event `json:"events"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
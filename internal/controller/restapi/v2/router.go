@@ -0,0 +1,29 @@
+// Package v2 is the resource-oriented REST API: events are addressed as
+// /v2/users/{userID}/events/{uid}. It lives next to the RPC-style v1.
+package v2
+
+import (
+	"github.com/andreyxaxa/calendar/internal/usecase"
+	"github.com/andreyxaxa/calendar/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewEventsRoutes -.
+func NewEventsRoutes(apiV2Group fiber.Router, e usecase.Events, u usecase.Users, l logger.Interface) {
+	r := &V2{
+		e: e,
+		u: u,
+		l: l,
+	}
+
+	eventsGroup := apiV2Group.Group("/users/:userID/events")
+	{
+		eventsGroup.Get("/", r.listEvents)
+		eventsGroup.Post("/", r.createEvent)
+
+		eventsGroup.Get("/:uid", r.getEvent)
+		eventsGroup.Put("/:uid", r.putEvent)
+		eventsGroup.Patch("/:uid", r.patchEvent)
+		eventsGroup.Delete("/:uid", r.deleteEvent)
+	}
+}
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// BatchOp - what an operation of a batch does.
+type BatchOp int
+
+// BatchOps -.
+const (
+	BatchCreate BatchOp = iota
+	BatchUpdate
+	BatchDelete
+)
+
+// BatchOperation - creates Event under UID, replaces the event UID with Event or deletes it.
+// Version of Event is the expected one for update and delete.
+type BatchOperation struct {
+	Op    BatchOp
+	UID   uuid.UUID
+	Event Event
+}
+
+// BatchResult - Event is the stored one for a create or update, Err tells why the operation failed.
+type BatchResult struct {
+	UID   uuid.UUID
+	Event Event
+	Err   error
+}
+
+// BatchError - the operation at Index failed an all-or-nothing batch, none was applied.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+// Error -.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+}
+
+// Unwrap -.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
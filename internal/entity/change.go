@@ -0,0 +1,20 @@
+package entity
+
+import "github.com/google/uuid"
+
+// Change - the latest write of the event UID, numbered Seq in the change sequence of its user.
+// A deleted change is the tombstone of an event moved to the trash, its Event is zero.
+type Change struct {
+	UID     uuid.UUID
+	Seq     int64
+	Deleted bool
+	Event   Event
+}
+
+// ChangesPage - changes following a position of the change sequence, in its order.
+// Seq is the position the next sync goes on from, More is set if changes follow it already.
+type ChangesPage struct {
+	Changes []Change
+	Seq     int64
+	More    bool
+}
@@ -1,9 +1,61 @@
 package entity
 
-import "time"
+import (
+	"time"
 
-// Event -.
+	"github.com/andreyxaxa/calendar/pkg/types/date"
+	"github.com/google/uuid"
+)
+
+// Event - occupies [Start, End). A timed event may have End == Start.
+// All-day events have Start and End at UTC midnight (see date.Floating),
+// End being the day after the last one. TimeZone is the IANA zone the event
+// was planned in.
+//
+// A recurring event (RRule is set) is a series: Start and End are those of the
+// first instance, ExDates lists starts of the cancelled ones and Overrides the
+// changed ones.
+//
+// Version is 1 for a new event and grows with every write, see repo.EventsRepo.
 type Event struct {
-	Date time.Time `json:"date"`
-	Text string    `json:"text"`
+	Start     time.Time   `json:"start"`
+	End       time.Time   `json:"end"`
+	AllDay    bool        `json:"all_day"`
+	TimeZone  string      `json:"tz"`
+	Text      string      `json:"text"`
+	RRule     string      `json:"rrule,omitempty"`
+	ExDates   []time.Time `json:"exdates,omitempty"`
+	Overrides []Override  `json:"overrides,omitempty"`
+	Version   int64       `json:"version,omitempty"`
+}
+
+// Occurrence - a single instance of an event. For a recurring event UID is the
+// series UID and RecurrenceID is the original start of the instance, zero otherwise.
+type Occurrence struct {
+	UID          uuid.UUID
+	RecurrenceID time.Time
+	Event
+}
+
+// Overlaps reports whether the event intersects [from, to).
+// All-day events are compared by calendar days of from and to in their location.
+// For a recurring event only the first instance is checked, see Occurrences.
+func (e Event) Overlaps(from, to time.Time) bool {
+	if e.AllDay {
+		from, to = date.Floating(from), date.FloatingCeil(to)
+	}
+
+	if !e.Start.Before(to) {
+		return false
+	}
+
+	return e.End.After(from) || !e.Start.Before(from)
+}
+
+// Instance returns the instance of the event starting at start.
+func (e Event) Instance(start time.Time) Event {
+	e.End = start.Add(e.End.Sub(e.Start))
+	e.Start = start
+
+	return e
 }
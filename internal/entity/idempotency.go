@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// IdempotencyKey - a key a client sends with a request it may retry. Fingerprint identifies
+// the request, Response is nil while it is being handled.
+type IdempotencyKey struct {
+	Key         string
+	Fingerprint string
+	Response    *StoredResponse
+	ExpiresAt   time.Time
+}
+
+// StoredResponse - the response to a request with an idempotency key, replayed on its retries.
+type StoredResponse struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   []byte            `json:"body"`
+}
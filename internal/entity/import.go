@@ -0,0 +1,32 @@
+package entity
+
+import "github.com/google/uuid"
+
+// ImportEntry - a VEVENT of an imported iCalendar file: Line is where it begins,
+// ICalUID is its UID. EventUID is set for imported events, Reason for the rest.
+type ImportEntry struct {
+	Line     int
+	ICalUID  string
+	EventUID uuid.UUID
+	Reason   string
+}
+
+// ImportReport -.
+type ImportReport struct {
+	Imported []ImportEntry
+	Skipped  []ImportEntry
+	Failed   []ImportEntry
+}
+
+// _importNamespace derives event UIDs from iCalendar UIDs that are not UUIDs.
+var _importNamespace = uuid.MustParse("0f5bbd8e-2d4c-4a53-9a9e-7c1d6f0b8f3e")
+
+// ImportUID returns the event UID for an iCalendar UID or a CalDAV resource name:
+// UUIDs are kept, others hashed, so the same one always maps to the same event.
+func ImportUID(icalUID string) uuid.UUID {
+	if uid, err := uuid.Parse(icalUID); err == nil {
+		return uid
+	}
+
+	return uuid.NewSHA1(_importNamespace, []byte(icalUID))
+}
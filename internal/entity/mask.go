@@ -0,0 +1,57 @@
+package entity
+
+// FieldMask - fields of an Event a partial update changes, the others keep their stored values.
+type FieldMask uint
+
+const (
+	// FieldText -.
+	FieldText FieldMask = 1 << iota
+	// FieldTiming - Start, End and AllDay, they only change together.
+	FieldTiming
+	// FieldTimeZone -.
+	FieldTimeZone
+	// FieldRRule -.
+	FieldRRule
+	// FieldExDates -.
+	FieldExDates
+)
+
+// Has reports whether m includes all of fields.
+func (m FieldMask) Has(fields FieldMask) bool {
+	return m&fields == fields
+}
+
+// Merge returns e with the fields of mask taken from patch. Overrides are kept while
+// the series stays the same, an event that is no longer recurring loses its exdates too.
+func (e Event) Merge(patch Event, mask FieldMask) Event {
+	merged := e
+
+	if mask.Has(FieldText) {
+		merged.Text = patch.Text
+	}
+
+	if mask.Has(FieldTiming) {
+		merged.Start, merged.End, merged.AllDay = patch.Start, patch.End, patch.AllDay
+	}
+
+	if mask.Has(FieldTimeZone) {
+		merged.TimeZone = patch.TimeZone
+	}
+
+	if mask.Has(FieldRRule) {
+		merged.RRule = patch.RRule
+	}
+
+	if mask.Has(FieldExDates) {
+		merged.ExDates = patch.ExDates
+	}
+
+	switch {
+	case !merged.Recurring():
+		merged.ExDates, merged.Overrides = nil, nil
+	case !e.SameSeries(merged):
+		merged.Overrides = nil
+	}
+
+	return merged
+}
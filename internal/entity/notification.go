@@ -0,0 +1,28 @@
+package entity
+
+import "github.com/google/uuid"
+
+// NotificationKind - what a write did to an event.
+type NotificationKind string
+
+// NotificationKinds -.
+const (
+	// NotificationCreated - the event was created or restored from the trash.
+	NotificationCreated NotificationKind = "created"
+	NotificationUpdated NotificationKind = "updated"
+	// NotificationDeleted - the event was moved to the trash, Event is zero.
+	NotificationDeleted NotificationKind = "deleted"
+	// NotificationReset - notifications following the one a subscriber resumed after are lost,
+	// it has to reload the events. UID and Event are zero.
+	NotificationReset NotificationKind = "reset"
+)
+
+// Notification - a committed write of the event UID of the user, Event is the stored event.
+// ID numbers notifications in the order they were published.
+type Notification struct {
+	ID     uint64
+	Kind   NotificationKind
+	UserID int
+	UID    uuid.UUID
+	Event  Event
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// CalendarObject - an event as a CalDAV resource: its iCalendar data and an ETag that
+// changes with the event. Data is nil where only ETags are listed.
+type CalendarObject struct {
+	UID  uuid.UUID
+	ETag string
+	Data []byte
+}
+
+// Preconditions - If-Match and If-None-Match of a write: ETags or "*", empty ones are not checked.
+type Preconditions struct {
+	IfMatch     []string
+	IfNoneMatch []string
+}
+
+// Hold reports whether the preconditions hold for a resource with etag, "" if there is none.
+func (p Preconditions) Hold(etag string) bool {
+	matches := func(tags []string) bool {
+		return etag != "" && (slices.Contains(tags, "*") || slices.Contains(tags, etag))
+	}
+
+	if len(p.IfMatch) > 0 && !matches(p.IfMatch) {
+		return false
+	}
+
+	return len(p.IfNoneMatch) == 0 || !matches(p.IfNoneMatch)
+}
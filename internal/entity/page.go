@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cursor - position of an occurrence in the order of occurrences: by Start, then UID,
+// then RecurrenceID. It stays valid while events change, a page goes on after it.
+type Cursor struct {
+	Start        time.Time
+	UID          uuid.UUID
+	RecurrenceID time.Time
+}
+
+// Compare -.
+func (c Cursor) Compare(other Cursor) int {
+	if cmp := c.Start.Compare(other.Start); cmp != 0 {
+		return cmp
+	}
+
+	if cmp := bytes.Compare(c.UID[:], other.UID[:]); cmp != 0 {
+		return cmp
+	}
+
+	return c.RecurrenceID.Compare(other.RecurrenceID)
+}
+
+// Cursor returns the position of the occurrence.
+func (o Occurrence) Cursor() Cursor {
+	return Cursor{Start: o.Start, UID: o.UID, RecurrenceID: o.RecurrenceID}
+}
+
+// PageRequest - up to Limit occurrences following After, from the first one if After is nil.
+// Desc reverses the order.
+type PageRequest struct {
+	Limit int
+	After *Cursor
+	Desc  bool
+}
+
+// OccurrencesPage - Next is set if more follow.
+type OccurrencesPage struct {
+	Occurrences []Occurrence
+	Next        *Cursor
+}
+
+// EventsPage - a page of the events of a range: Events are not series, in the order of their
+// cursors, Series may have instances anywhere in the range.
+type EventsPage struct {
+	Events []Occurrence
+	Series map[uuid.UUID]Event
+}
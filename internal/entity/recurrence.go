@@ -0,0 +1,244 @@
+package entity
+
+import (
+	"slices"
+	"time"
+
+	"github.com/andreyxaxa/calendar/pkg/rrule"
+	"github.com/andreyxaxa/calendar/pkg/types/date"
+	"github.com/google/uuid"
+)
+
+// Scope - which instances of a series an edit applies to.
+type Scope int
+
+// Scopes -.
+const (
+	ScopeAll Scope = iota
+	ScopeThis
+	ScopeFollowing
+)
+
+// Override - a changed instance of a series (RECURRENCE-ID in RFC 5545),
+// RecurrenceID is the instance's original start.
+type Override struct {
+	RecurrenceID time.Time `json:"recurrence_id"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	AllDay       bool      `json:"all_day"`
+	Text         string    `json:"text"`
+}
+
+// Recurring -.
+func (e Event) Recurring() bool {
+	return e.RRule != ""
+}
+
+// Recurrence returns the series of a recurring event. Timed events repeat on the
+// wall clock of their time zone, all-day ones on floating days.
+func (e Event) Recurrence() (rrule.Set, error) {
+	rule, err := rrule.Parse(e.RRule)
+	if err != nil {
+		return rrule.Set{}, err
+	}
+
+	start := e.Start
+
+	if !e.AllDay {
+		loc, err := time.LoadLocation(e.TimeZone)
+		if err != nil {
+			return rrule.Set{}, err
+		}
+
+		start = start.In(loc)
+	}
+
+	return rrule.Set{Rule: rule, DTStart: start, ExDates: e.ExDates}, nil
+}
+
+// HasInstance reports whether the series has a (not cancelled) instance starting at recurrenceID.
+func (e Event) HasInstance(recurrenceID time.Time) (bool, error) {
+	if !e.Recurring() {
+		return false, nil
+	}
+
+	set, err := e.Recurrence()
+	if err != nil {
+		return false, err
+	}
+
+	return len(set.Between(recurrenceID, recurrenceID.Add(time.Nanosecond))) > 0, nil
+}
+
+// SeriesStart returns when the earliest instance starts, overrides may move it before Start.
+func (e Event) SeriesStart() time.Time {
+	start := e.Start
+
+	for _, o := range e.Overrides {
+		if o.Start.Before(start) {
+			start = o.Start
+		}
+	}
+
+	return start
+}
+
+// SeriesEnd returns when the last instance ends, zero time if the series never ends.
+func (e Event) SeriesEnd() (time.Time, error) {
+	if !e.Recurring() {
+		return e.End, nil
+	}
+
+	set, err := e.Recurrence()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	last, ok := set.Last()
+	if !ok {
+		return time.Time{}, nil
+	}
+
+	end := e.Start
+	if !last.IsZero() {
+		end = e.Instance(last.UTC()).End
+	}
+
+	for _, o := range e.Overrides {
+		if o.End.After(end) {
+			end = o.End
+		}
+	}
+
+	return end, nil
+}
+
+// Occurrences returns the instances overlapping [from, to), in order of their original start.
+func (e Event) Occurrences(uid uuid.UUID, from, to time.Time) ([]Occurrence, error) {
+	if !e.Recurring() {
+		if e.Overlaps(from, to) {
+			return []Occurrence{{UID: uid, Event: e}}, nil
+		}
+
+		return nil, nil
+	}
+
+	set, err := e.Recurrence()
+	if err != nil {
+		return nil, err
+	}
+
+	lower, upper := from, to
+	if e.AllDay {
+		lower, upper = date.Floating(from), date.FloatingCeil(to)
+	}
+
+	var occurrences []Occurrence
+
+	for _, start := range set.Between(lower.Add(-e.End.Sub(e.Start)), upper) {
+		start = start.UTC()
+
+		if e.override(start) >= 0 {
+			continue
+		}
+
+		if instance := e.Instance(start); instance.Overlaps(from, to) {
+			occurrences = append(occurrences, Occurrence{UID: uid, RecurrenceID: start, Event: instance})
+		}
+	}
+
+	// an override may move its instance anywhere, so all of them are checked.
+	for _, o := range e.Overrides {
+		if slices.ContainsFunc(e.ExDates, o.RecurrenceID.Equal) {
+			continue
+		}
+
+		instance := e
+		instance.Start, instance.End, instance.AllDay, instance.Text = o.Start, o.End, o.AllDay, o.Text
+
+		if instance.Overlaps(from, to) {
+			occurrences = append(occurrences, Occurrence{UID: uid, RecurrenceID: o.RecurrenceID, Event: instance})
+		}
+	}
+
+	slices.SortFunc(occurrences, func(a, b Occurrence) int {
+		return a.RecurrenceID.Compare(b.RecurrenceID)
+	})
+
+	return occurrences, nil
+}
+
+// Clone returns a copy of e not sharing ExDates and Overrides with it.
+func (e Event) Clone() Event {
+	e.ExDates, e.Overrides = slices.Clone(e.ExDates), slices.Clone(e.Overrides)
+
+	return e
+}
+
+// Override sets o as the instance starting at o.RecurrenceID, replacing an earlier override.
+func (e *Event) Override(o Override) {
+	e.Overrides = slices.Clone(e.Overrides)
+
+	if i := e.override(o.RecurrenceID); i >= 0 {
+		e.Overrides[i] = o
+
+		return
+	}
+
+	e.Overrides = append(e.Overrides, o)
+}
+
+// Cancel removes the instance starting at recurrenceID from the series.
+func (e *Event) Cancel(recurrenceID time.Time) {
+	e.ExDates, e.Overrides = slices.Clone(e.ExDates), slices.Clone(e.Overrides)
+
+	if i := e.override(recurrenceID); i >= 0 {
+		e.Overrides = slices.Delete(e.Overrides, i, i+1)
+	}
+
+	if !slices.ContainsFunc(e.ExDates, recurrenceID.Equal) {
+		e.ExDates = append(e.ExDates, recurrenceID)
+	}
+}
+
+// Split cuts the series before the instance starting at recurrenceID. head keeps the
+// earlier instances with their exdates and overrides, tail is the rule of the rest:
+// with COUNT reduced by the instances in head, UNTIL unchanged.
+func (e Event) Split(recurrenceID time.Time) (head Event, tail rrule.Rule, err error) {
+	set, err := e.Recurrence()
+	if err != nil {
+		return Event{}, rrule.Rule{}, err
+	}
+
+	tail = set.Rule
+
+	if tail.Count > 0 {
+		// cancelled instances count too.
+		set.ExDates = nil
+		tail.Count -= len(set.Between(set.DTStart, recurrenceID))
+	}
+
+	head = e
+	head.RRule = set.Rule.EndBefore(recurrenceID).String()
+	head.ExDates = slices.DeleteFunc(slices.Clone(e.ExDates), func(t time.Time) bool {
+		return !t.Before(recurrenceID)
+	})
+	head.Overrides = slices.DeleteFunc(slices.Clone(e.Overrides), func(o Override) bool {
+		return !o.RecurrenceID.Before(recurrenceID)
+	})
+
+	return head, tail, nil
+}
+
+// SameSeries reports whether e and other produce instances at the same starts,
+// so exdates and overrides of one apply to the other.
+func (e Event) SameSeries(other Event) bool {
+	return e.Start.Equal(other.Start) && e.AllDay == other.AllDay &&
+		e.TimeZone == other.TimeZone && e.RRule == other.RRule
+}
+
+func (e Event) override(recurrenceID time.Time) int {
+	return slices.IndexFunc(e.Overrides, func(o Override) bool {
+		return o.RecurrenceID.Equal(recurrenceID)
+	})
+}
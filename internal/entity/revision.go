@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// RevisionOp - the write a revision records.
+type RevisionOp string
+
+// RevisionOps -.
+const (
+	RevisionCreate  RevisionOp = "create"
+	RevisionUpdate  RevisionOp = "update"
+	RevisionDelete  RevisionOp = "delete"
+	RevisionRestore RevisionOp = "restore"
+)
+
+// Revision - an immutable record of a write of an event made by Actor at At.
+// Revisions of an event are numbered from 1. Before is nil for a created or restored event,
+// After for a deleted one.
+type Revision struct {
+	Number int64      `json:"number"`
+	Op     RevisionOp `json:"op"`
+	Actor  string     `json:"actor"`
+	At     time.Time  `json:"at"`
+	Before *Event     `json:"before,omitempty"`
+	After  *Event     `json:"after,omitempty"`
+}
+
+// FieldChange - JSON values of an event field before and after a revision, null where the event was missing.
+type FieldChange struct {
+	Field  string
+	Before json.RawMessage
+	After  json.RawMessage
+}
+
+// _revisionFields are JSON names of the fields of Event a revision may change, Version changes with every one.
+var _revisionFields = func() []string {
+	var fields []string
+
+	t := reflect.TypeFor[Event]()
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" && name != "version" {
+			fields = append(fields, name)
+		}
+	}
+
+	return fields
+}()
+
+var _null = json.RawMessage("null")
+
+// Changes returns the fields the revision changed, in the order of Event fields.
+func (r Revision) Changes() []FieldChange {
+	before, after := eventFields(r.Before), eventFields(r.After)
+
+	var changes []FieldChange
+
+	for _, field := range _revisionFields {
+		b, a := fieldValue(before, field), fieldValue(after, field)
+		if !bytes.Equal(b, a) {
+			changes = append(changes, FieldChange{Field: field, Before: b, After: a})
+		}
+	}
+
+	return changes
+}
+
+func eventFields(event *Event) map[string]json.RawMessage {
+	if event == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+
+	return fields
+}
+
+// fieldValue returns the value of the field, null if the event is missing or omits the field as empty.
+func fieldValue(fields map[string]json.RawMessage, field string) json.RawMessage {
+	if fields == nil {
+		return _null
+	}
+
+	if value, ok := fields[field]; ok {
+		return value
+	}
+
+	return _null
+}
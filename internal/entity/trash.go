@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrashedEvent - an event deleted at DeletedAt. It stays in the trash of the user
+// and can be restored until the trash older than the retention period is purged.
+type TrashedEvent struct {
+	UID       uuid.UUID
+	DeletedAt time.Time
+	Event
+}
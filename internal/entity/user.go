@@ -0,0 +1,10 @@
+package entity
+
+// DefaultTimeZone - zone of users who have not chosen one.
+const DefaultTimeZone = "UTC"
+
+// User -.
+type User struct {
+	ID       int    `json:"user_id"`
+	TimeZone string `json:"tz"`
+}
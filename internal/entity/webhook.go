@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook - a subscription of a tool of the user to writes of events: notifications of Kinds,
+// all but NotificationReset when empty, are POSTed to URL signed with Secret.
+type Webhook struct {
+	ID        uuid.UUID
+	UserID    int
+	URL       string
+	Secret    string
+	Kinds     []NotificationKind
+	CreatedAt time.Time
+}
+
+// Wants reports whether notifications of kind are sent to the webhook.
+func (w Webhook) Wants(kind NotificationKind) bool {
+	if kind == NotificationReset {
+		return false
+	}
+
+	return len(w.Kinds) == 0 || slices.Contains(w.Kinds, kind)
+}
+
+// DeliveryStatus -.
+type DeliveryStatus string
+
+// DeliveryStatuses -.
+const (
+	// DeliveryPending - the delivery is to be attempted at NextAttemptAt.
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryDead - all attempts failed, the delivery is kept in the dead-letter list.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// Delivery - Payload of a notification to be POSTed to the webhook, with the attempts made so far,
+// the oldest first.
+type Delivery struct {
+	ID            uuid.UUID
+	WebhookID     uuid.UUID
+	UserID        int
+	Kind          NotificationKind
+	Payload       []byte
+	Status        DeliveryStatus
+	Attempts      []DeliveryAttempt
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+}
+
+// DeliveryAttempt - StatusCode is 0 if no response came, Error tells why the attempt failed.
+type DeliveryAttempt struct {
+	At         time.Time
+	StatusCode int
+	Error      string
+	Duration   time.Duration
+}
+
+// DueDelivery - a pending delivery with URL and Secret of its webhook.
+type DueDelivery struct {
+	Delivery
+	URL    string
+	Secret string
+}
@@ -9,13 +9,94 @@ import (
 )
 
 type (
-	// EventsRepo - interface of repository
+	// EventsRepo - interface of repository.
+	// Period queries compute boundaries in the location of date, ranges are [from, to).
+	//
+	// Create stores version 1 of the event, each write increments it. Writes take the expected
+	// version (Version of the event, the version argument of Delete): unless it is 0, the write
+	// fails with errs.ErrPreconditionFailed if the stored event has another one.
+	//
+	// Delete moves the event to the trash of the user, queries other than GetTrash don't see it.
+	// Trashed events are kept by uid: deleting an event again replaces the one trashed under its uid.
+	//
+	// Writes of events are numbered in a change sequence of the user, from 1. The sequence keeps the
+	// latest change of each event ever written, tombstones of deleted ones included, purged or not.
 	EventsRepo interface {
+		UnitOfWork
 		Create(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) error
-		Update(ctx context.Context, userID int, eventUID uuid.UUID, text string, date time.Time) error
-		Delete(ctx context.Context, userID int, eventUID uuid.UUID) error
+		// Update returns the new version.
+		Update(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) (int64, error)
+		// UpdateFields atomically merges the fields of mask into the stored event, see entity.Event.Merge.
+		UpdateFields(ctx context.Context, userID int, eventUID uuid.UUID, patch entity.Event,
+			mask entity.FieldMask) (entity.Event, error)
+		Delete(ctx context.Context, userID int, eventUID uuid.UUID, version int64, deletedAt time.Time) error
+		// Restore moves the event back from the trash and returns it with the next version.
+		// It fails with errs.ErrAlreadyExists if an event was created under its uid meanwhile.
+		Restore(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error)
+		// GetTrash returns trashed events of the user in no particular order.
+		GetTrash(ctx context.Context, userID int) ([]entity.TrashedEvent, error)
+		// PurgeTrash deletes events of all users trashed before the time and returns their number.
+		PurgeTrash(ctx context.Context, before time.Time) (int64, error)
+		// AddRevision appends the revision of the event, numbered next to its last one.
+		AddRevision(ctx context.Context, userID int, eventUID uuid.UUID, revision entity.Revision) error
+		// GetRevisions returns revisions of the event, the first one first, none for an event never written.
+		GetRevisions(ctx context.Context, userID int, eventUID uuid.UUID) ([]entity.Revision, error)
+		// GetChanges returns up to limit changes of the user numbered after the position, in their order,
+		// and the number of the last change of the user, 0 if there is none - as for a user who never wrote.
+		GetChanges(ctx context.Context, userID int, after int64, limit int) ([]entity.Change, int64, error)
+		GetByUID(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error)
+		GetAll(ctx context.Context, userID int) (map[uuid.UUID]entity.Event, error)
 		GetEventsForDay(ctx context.Context, userID int, date time.Time) (map[uuid.UUID]entity.Event, error)
 		GetEventsForWeek(ctx context.Context, userID int, date time.Time) (map[uuid.UUID]entity.Event, error)
 		GetEventsForMonth(ctx context.Context, userID int, date time.Time) (map[uuid.UUID]entity.Event, error)
+		// GetEventsForRange returns up to page.Limit events overlapping [from, to) that are not series,
+		// following page.After in the order of entity.Cursor (reversed if page.Desc), and every series
+		// that may have instances there, see entity.Event.Occurrences.
+		GetEventsForRange(ctx context.Context, userID int, from, to time.Time,
+			page entity.PageRequest) (entity.EventsPage, error)
+	}
+
+	// UnitOfWork - WithinTx runs fn against a repo whose writes are kept only if fn returns nil,
+	// all together, and returns the error of fn as it is. Inside fn only tx may be used.
+	// WithinTx of tx nests: its failure undoes just what its fn did.
+	UnitOfWork interface {
+		WithinTx(ctx context.Context, fn func(tx EventsRepo) error) error
+	}
+
+	// UsersRepo - interface of users repository
+	UsersRepo interface {
+		Get(ctx context.Context, userID int) (entity.User, error)
+		Save(ctx context.Context, user entity.User) error
+	}
+
+	// IdempotencyRepo - interface of idempotency keys repository.
+	// Keys expired by now are treated as missing.
+	IdempotencyRepo interface {
+		// Reserve stores key unless there is a live one, which is returned with errs.ErrAlreadyExists.
+		Reserve(ctx context.Context, key entity.IdempotencyKey, now time.Time) (entity.IdempotencyKey, error)
+		// Complete stores the response of the reserved key, nothing if it was released or expired meanwhile.
+		Complete(ctx context.Context, key string, response entity.StoredResponse) error
+		// Release deletes the key, so the request can be retried.
+		Release(ctx context.Context, key string) error
+	}
+
+	// WebhooksRepo - interface of webhooks repository.
+	// Deliveries are kept with their attempts, deleting a webhook deletes its deliveries.
+	WebhooksRepo interface {
+		CreateWebhook(ctx context.Context, webhook entity.Webhook) error
+		// GetWebhooks returns webhooks of the user, the oldest first.
+		GetWebhooks(ctx context.Context, userID int) ([]entity.Webhook, error)
+		// DeleteWebhook fails with errs.ErrWebhookNotFound if the user has no such webhook.
+		DeleteWebhook(ctx context.Context, userID int, id uuid.UUID) error
+		AddDeliveries(ctx context.Context, deliveries []entity.Delivery) error
+		// GetDueDeliveries returns up to limit pending deliveries to attempt by now, the earliest first.
+		GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.DueDelivery, error)
+		// AddAttempt appends attempt to the delivery and stores its Status and NextAttemptAt.
+		AddAttempt(ctx context.Context, delivery entity.Delivery, attempt entity.DeliveryAttempt) error
+		// GetDeliveries returns up to limit deliveries of the webhook of the user in status,
+		// of any if it is empty, the newest first. It fails with errs.ErrWebhookNotFound
+		// if the user has no such webhook.
+		GetDeliveries(ctx context.Context, userID int, webhookID uuid.UUID, status entity.DeliveryStatus,
+			limit int) ([]entity.Delivery, error)
 	}
 )
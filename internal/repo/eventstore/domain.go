@@ -0,0 +1,48 @@
+// Package eventstore implements repositories where an append-only log of domain events is
+// the source of truth. Events, trash, history and the day, week and month read models of
+// each user are projections of the log, rebuilt from it on start (from the last checkpoint)
+// or as a whole by Rebuild.
+package eventstore
+
+import (
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/google/uuid"
+)
+
+// Kind - what a domain event records.
+type Kind string
+
+// Kinds -.
+const (
+	// EventCreated - Event was created under UID, with version 1.
+	EventCreated Kind = "event_created"
+	// EventUpdated - Event is the new state of the event UID, Version included.
+	EventUpdated Kind = "event_updated"
+	// EventDeleted - the event UID was moved to the trash at At.
+	EventDeleted Kind = "event_deleted"
+	// EventRestored - the event UID was moved back from the trash as Event.
+	EventRestored Kind = "event_restored"
+	// TrashPurged - events of all users trashed before Before were deleted for good.
+	TrashPurged Kind = "trash_purged"
+	// RevisionAdded - Revision was appended to the history of the event UID.
+	RevisionAdded Kind = "revision_added"
+	// UserSaved - User was saved.
+	UserSaved Kind = "user_saved"
+)
+
+// DomainEvent - an immutable fact of the log. Seq numbers them from 1 in the order of the log,
+// At is when the fact happened.
+type DomainEvent struct {
+	Seq    int64     `json:"seq"`
+	Kind   Kind      `json:"kind"`
+	At     time.Time `json:"at"`
+	UserID int       `json:"user_id,omitempty"`
+	UID    uuid.UUID `json:"uid,omitzero"`
+
+	Event    *entity.Event    `json:"event,omitempty"`
+	Revision *entity.Revision `json:"revision,omitempty"`
+	User     *entity.User     `json:"user,omitempty"`
+	Before   time.Time        `json:"before,omitzero"`
+}
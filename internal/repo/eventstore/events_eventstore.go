@@ -0,0 +1,461 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/pkg/types/date"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+const _defaultCheckpointEvery = 1000
+
+// EventsRepo - writes are checked against the projections, recorded as domain events
+// and projected. Reads are served by the projections.
+type EventsRepo struct {
+	storage map[int]*calendar
+	mu      sync.RWMutex
+
+	// log is nil unless the repo was opened with Open.
+	log *eventLog
+	dir string
+	// seq is Seq of the last domain event, checkpointed the one the last checkpoint was taken at.
+	seq, checkpointed int64
+	checkpointEvery   int64
+
+	// tx is set for the repo WithinTx passes to its function.
+	tx *tx
+}
+
+// tx - a transaction projects to the storage in place, undo holds what puts its projections back,
+// in their order, if it fails. Domain events are kept to be committed together.
+type tx struct {
+	events []DomainEvent
+	undo   []func()
+}
+
+// New returns new EventsRepo(struct) keeping the log in memory only.
+func New() *EventsRepo {
+	return &EventsRepo{
+		storage: make(map[int]*calendar),
+	}
+}
+
+// Open returns EventsRepo with the log in dir: the projections are restored from the last
+// checkpoint and the domain events appended after it.
+func Open(dir string, opts ...Option) (*EventsRepo, error) {
+	r := &EventsRepo{
+		dir:             dir,
+		checkpointEvery: _defaultCheckpointEvery,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	l, err := openLog(dir)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - Open - openLog: %w", err)
+	}
+
+	cp, err := loadCheckpoint(dir)
+	if err != nil {
+		l.close()
+
+		return nil, fmt.Errorf("EventsRepo - Open - loadCheckpoint: %w", err)
+	}
+
+	r.storage = cp.Users
+
+	r.seq, err = l.replay(func(e DomainEvent) {
+		if e.Seq > cp.Seq {
+			r.project(e)
+		}
+	})
+	if err != nil {
+		l.close()
+
+		return nil, fmt.Errorf("EventsRepo - Open - l.replay: %w", err)
+	}
+
+	if r.seq < cp.Seq {
+		l.close()
+
+		return nil, fmt.Errorf("EventsRepo - Open: checkpoint at seq %d is ahead of the log at %d, rebuild it",
+			cp.Seq, r.seq)
+	}
+
+	r.log = l
+	r.checkpointed = cp.Seq
+
+	return r, nil
+}
+
+// Close checkpoints the projections and closes the log.
+func (r *EventsRepo) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.log == nil {
+		return nil
+	}
+
+	if r.seq > r.checkpointed {
+		if err := saveCheckpoint(r.dir, checkpoint{Seq: r.seq, Users: r.storage}); err != nil {
+			r.log.close()
+
+			return fmt.Errorf("EventsRepo - Close - saveCheckpoint: %w", err)
+		}
+	}
+
+	return r.log.close()
+}
+
+// WithinTx runs fn against the projections, undoing what it projected unless it succeeds: a write keeps
+// only what puts back the entries of the event it changed, so it costs no more than outside of
+// a transaction. Domain events of fn are committed to the log together. Other writes wait for the transaction.
+func (r *EventsRepo) WithinTx(ctx context.Context, fn func(tx repo.EventsRepo) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the checkpoint is taken before fn projects, it must not hold what may be undone.
+	if r.tx == nil {
+		if err := r.checkpoint(); err != nil {
+			return fmt.Errorf("EventsRepo - WithinTx - r.checkpoint: %w", err)
+		}
+	}
+
+	view := &EventsRepo{
+		storage: r.storage,
+		tx:      &tx{},
+	}
+
+	committed := false
+
+	defer func() {
+		if !committed {
+			view.tx.rollback()
+		}
+	}()
+
+	if err := fn(view); err != nil {
+		return err
+	}
+
+	if len(view.tx.events) > 0 {
+		if err := r.append(view.tx.events); err != nil {
+			return fmt.Errorf("EventsRepo - WithinTx - r.append: %w", err)
+		}
+
+		if r.tx != nil {
+			r.tx.undo = append(r.tx.undo, view.tx.undo...)
+		}
+	}
+
+	committed = true
+
+	return nil
+}
+
+// rollback undoes the projections of t, the last one first.
+func (t *tx) rollback() {
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		t.undo[i]()
+	}
+}
+
+// emit commits e and projects it. Must be called with r.mu held.
+func (r *EventsRepo) emit(e DomainEvent) error {
+	if err := r.commit([]DomainEvent{e}); err != nil {
+		return err
+	}
+
+	r.project(e)
+
+	return nil
+}
+
+// commit appends the domain events to the log, checkpointing the projections first if enough were
+// appended since the last checkpoint. In a transaction they are kept for its commit.
+// Must be called with r.mu held, before the events are projected.
+func (r *EventsRepo) commit(events []DomainEvent) error {
+	if r.tx == nil {
+		if err := r.checkpoint(); err != nil {
+			return err
+		}
+	}
+
+	return r.append(events)
+}
+
+// checkpoint saves the projections if enough domain events were appended since the last checkpoint.
+// Must be called with r.mu held, outside of a transaction.
+func (r *EventsRepo) checkpoint() error {
+	if r.log == nil || r.seq-r.checkpointed < r.checkpointEvery {
+		return nil
+	}
+
+	if err := saveCheckpoint(r.dir, checkpoint{Seq: r.seq, Users: r.storage}); err != nil {
+		return fmt.Errorf("saveCheckpoint: %w", err)
+	}
+
+	r.checkpointed = r.seq
+
+	return nil
+}
+
+// append numbers the domain events and appends them to the log. In a transaction they are kept
+// for its commit. Must be called with r.mu held.
+func (r *EventsRepo) append(events []DomainEvent) error {
+	if r.tx != nil {
+		r.tx.events = append(r.tx.events, events...)
+
+		return nil
+	}
+
+	for i := range events {
+		events[i].Seq = r.seq + int64(i) + 1
+	}
+
+	if r.log != nil {
+		if err := r.log.append(events); err != nil {
+			return fmt.Errorf("r.log.append: %w", err)
+		}
+	}
+
+	r.seq += int64(len(events))
+
+	return nil
+}
+
+// project applies e to the projections of the users it concerns. In a transaction what puts
+// them back is kept first.
+func (r *EventsRepo) project(e DomainEvent) {
+	if e.Kind == TrashPurged {
+		for _, user := range r.storage {
+			if user.trashedBefore(e.Before) {
+				if r.tx != nil {
+					r.tx.undo = append(r.tx.undo, user.savedTrash())
+				}
+
+				user.apply(e)
+			}
+		}
+
+		return
+	}
+
+	user, ok := r.storage[e.UserID]
+
+	switch {
+	case !ok:
+		user = newCalendar()
+		r.storage[e.UserID] = user
+
+		if r.tx != nil {
+			r.tx.undo = append(r.tx.undo, func() { delete(r.storage, e.UserID) })
+		}
+	case r.tx != nil:
+		r.tx.undo = append(r.tx.undo, user.saved(e.UID))
+	}
+
+	user.apply(e)
+}
+
+// Create -.
+func (r *EventsRepo) Create(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if user, ok := r.storage[userID]; ok {
+		if _, ok = user.events[eventUID]; ok {
+			return errs.ErrAlreadyExists
+		}
+	}
+
+	event.Version = 1
+
+	err := r.emit(DomainEvent{Kind: EventCreated, At: time.Now(), UserID: userID, UID: eventUID, Event: &event})
+	if err != nil {
+		return fmt.Errorf("EventsRepo - Create - r.emit: %w", err)
+	}
+
+	return nil
+}
+
+// Update -.
+func (r *EventsRepo) Update(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, err := r.current(userID, eventUID, event.Version)
+	if err != nil {
+		return 0, err
+	}
+
+	event.Version = current.Version + 1
+
+	err = r.emit(DomainEvent{Kind: EventUpdated, At: time.Now(), UserID: userID, UID: eventUID, Event: &event})
+	if err != nil {
+		return 0, fmt.Errorf("EventsRepo - Update - r.emit: %w", err)
+	}
+
+	return event.Version, nil
+}
+
+// UpdateFields -.
+func (r *EventsRepo) UpdateFields(ctx context.Context, userID int, eventUID uuid.UUID, patch entity.Event,
+	mask entity.FieldMask,
+) (entity.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, err := r.current(userID, eventUID, patch.Version)
+	if err != nil {
+		return entity.Event{}, err
+	}
+
+	event := current.Merge(patch, mask)
+	event.Version = current.Version + 1
+
+	err = r.emit(DomainEvent{Kind: EventUpdated, At: time.Now(), UserID: userID, UID: eventUID, Event: &event})
+	if err != nil {
+		return entity.Event{}, fmt.Errorf("EventsRepo - UpdateFields - r.emit: %w", err)
+	}
+
+	return event.Clone(), nil
+}
+
+// Delete -.
+func (r *EventsRepo) Delete(ctx context.Context, userID int, eventUID uuid.UUID, version int64,
+	deletedAt time.Time,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.current(userID, eventUID, version); err != nil {
+		return err
+	}
+
+	err := r.emit(DomainEvent{Kind: EventDeleted, At: deletedAt, UserID: userID, UID: eventUID})
+	if err != nil {
+		return fmt.Errorf("EventsRepo - Delete - r.emit: %w", err)
+	}
+
+	return nil
+}
+
+// current returns the stored event expected to have the version, see repo.EventsRepo.
+func (r *EventsRepo) current(userID int, eventUID uuid.UUID, version int64) (entity.Event, error) {
+	user, ok := r.storage[userID]
+	if !ok {
+		return entity.Event{}, errs.ErrUserNotFound
+	}
+
+	current, ok := user.events[eventUID]
+	if !ok {
+		return entity.Event{}, errs.ErrEventNotFound
+	}
+
+	if version != 0 && version != current.Version {
+		return entity.Event{}, errs.ErrPreconditionFailed
+	}
+
+	return current.Clone(), nil
+}
+
+// GetChanges -.
+func (r *EventsRepo) GetChanges(ctx context.Context, userID int, after int64, limit int,
+) ([]entity.Change, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.storage[userID]
+	if !ok {
+		return []entity.Change{}, 0, nil
+	}
+
+	return user.changesAfter(after, limit), user.seq, nil
+}
+
+// GetByUID -.
+func (r *EventsRepo) GetByUID(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.current(userID, eventUID, 0)
+}
+
+// GetAll -.
+func (r *EventsRepo) GetAll(ctx context.Context, userID int) (map[uuid.UUID]entity.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.storage[userID]
+	if !ok {
+		return nil, errs.ErrUserNotFound
+	}
+
+	events := make(map[uuid.UUID]entity.Event, len(user.events))
+
+	for uid, event := range user.events {
+		events[uid] = event.Clone()
+	}
+
+	return events, nil
+}
+
+// GetEventsForDay -.
+func (r *EventsRepo) GetEventsForDay(ctx context.Context, userID int, d time.Time) (map[uuid.UUID]entity.Event, error) {
+	from, to := date.DayRange(d)
+
+	return r.getEvents(userID, func(c *calendar) *periods { return c.days }, from, to)
+}
+
+// GetEventsForWeek -.
+func (r *EventsRepo) GetEventsForWeek(ctx context.Context, userID int, d time.Time) (map[uuid.UUID]entity.Event, error) {
+	from, to := date.WeekRange(d)
+
+	return r.getEvents(userID, func(c *calendar) *periods { return c.weeks }, from, to)
+}
+
+// GetEventsForMonth -.
+func (r *EventsRepo) GetEventsForMonth(ctx context.Context, userID int, d time.Time) (map[uuid.UUID]entity.Event, error) {
+	from, to := date.MonthRange(d)
+
+	return r.getEvents(userID, func(c *calendar) *periods { return c.months }, from, to)
+}
+
+// GetEventsForRange uses the read model of events by start.
+func (r *EventsRepo) GetEventsForRange(ctx context.Context, userID int, from, to time.Time,
+	page entity.PageRequest,
+) (entity.EventsPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.storage[userID]
+	if !ok {
+		return entity.EventsPage{}, errs.ErrUserNotFound
+	}
+
+	return user.page(from, to, page), nil
+}
+
+// getEvents returns events of the user overlapping [from, to) found in the read model of model.
+func (r *EventsRepo) getEvents(userID int, model func(c *calendar) *periods, from, to time.Time,
+) (map[uuid.UUID]entity.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.storage[userID]
+	if !ok {
+		return nil, errs.ErrUserNotFound
+	}
+
+	return user.between(model(user), from, to), nil
+}
@@ -0,0 +1,15 @@
+package eventstore_test
+
+import (
+	"testing"
+
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/repo/eventstore"
+	"github.com/andreyxaxa/calendar/internal/repo/repotest"
+)
+
+func BenchmarkWrites(b *testing.B) {
+	repotest.BenchmarkWrites(b, func(b *testing.B) repo.EventsRepo {
+		return eventstore.New()
+	})
+}
@@ -0,0 +1,30 @@
+package eventstore_test
+
+import (
+	"testing"
+
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/repo/eventstore"
+	"github.com/andreyxaxa/calendar/internal/repo/repotest"
+)
+
+func TestEventsRepo(t *testing.T) {
+	repotest.Events(t, func(t *testing.T) repo.EventsRepo {
+		return eventstore.New()
+	})
+}
+
+func TestEventsRepoLogged(t *testing.T) {
+	repotest.Events(t, func(t *testing.T) repo.EventsRepo {
+		r := openRepo(t, t.TempDir())
+		t.Cleanup(func() { r.Close() })
+
+		return r
+	})
+}
+
+func TestEventsRepoWrites(t *testing.T) {
+	repotest.Writes(t, func(t *testing.T) repo.EventsRepo {
+		return eventstore.New()
+	})
+}
@@ -0,0 +1,187 @@
+package eventstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	_logFile        = "events.log"
+	_checkpointFile = "checkpoint.json"
+)
+
+// eventLog - the append-only log of domain events, a line of JSON per commit: the domain
+// events of a write, or of a transaction, are kept or lost together.
+type eventLog struct {
+	file *os.File
+	// size is the length of the valid part of file.
+	size int64
+}
+
+// checkpoint - projections of the log up to Seq, so the log is replayed only after it.
+type checkpoint struct {
+	Seq   int64             `json:"seq"`
+	Users map[int]*calendar `json:"users"`
+}
+
+// openLog opens the log in dir. A torn last commit is truncated, other damage fails it:
+// the log is the source of truth.
+func openLog(dir string) (*eventLog, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("os.MkdirAll: %w", err)
+	}
+
+	file, err := os.OpenFile(filepath.Join(dir, _logFile), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("os.OpenFile: %w", err)
+	}
+
+	return &eventLog{
+		file: file,
+	}, nil
+}
+
+// replay calls fn with the domain events of the log in order and leaves the file
+// positioned at the end of the last commit. It returns Seq of the last domain event.
+func (l *eventLog) replay(fn func(e DomainEvent)) (int64, error) {
+	if _, err := l.file.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("l.file.Seek: %w", err)
+	}
+
+	var (
+		reader = bufio.NewReader(l.file)
+		seq    int64
+		size   int64
+	)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// a commit without its newline was torn by a crash.
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("reader.ReadBytes: %w", err)
+		}
+
+		var commit []DomainEvent
+		if err = json.Unmarshal(line, &commit); err != nil {
+			return 0, fmt.Errorf("commit at offset %d: %w", size, err)
+		}
+
+		for _, e := range commit {
+			if e.Seq != seq+1 {
+				return 0, fmt.Errorf("commit at offset %d: seq %d follows %d", size, e.Seq, seq)
+			}
+
+			seq = e.Seq
+			fn(e)
+		}
+
+		size += int64(len(line))
+	}
+
+	l.size = size
+
+	return seq, l.truncate()
+}
+
+// append durably writes the commit. On failure the log is truncated back to its
+// previous size, so a torn commit never precedes valid ones.
+func (l *eventLog) append(commit []DomainEvent) error {
+	line, err := json.Marshal(commit)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	line = append(line, '\n')
+
+	if _, err = l.file.Write(line); err != nil {
+		return errors.Join(fmt.Errorf("l.file.Write: %w", err), l.truncate())
+	}
+
+	if err = l.file.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("l.file.Sync: %w", err), l.truncate())
+	}
+
+	l.size += int64(len(line))
+
+	return nil
+}
+
+// truncate drops everything past the valid part of the log.
+func (l *eventLog) truncate() error {
+	if err := l.file.Truncate(l.size); err != nil {
+		return fmt.Errorf("l.file.Truncate: %w", err)
+	}
+
+	if _, err := l.file.Seek(l.size, io.SeekStart); err != nil {
+		return fmt.Errorf("l.file.Seek: %w", err)
+	}
+
+	return nil
+}
+
+func (l *eventLog) close() error {
+	return l.file.Close()
+}
+
+// loadCheckpoint returns the checkpoint of dir, an empty one if there is none.
+func loadCheckpoint(dir string) (checkpoint, error) {
+	cp := checkpoint{Users: make(map[int]*calendar)}
+
+	data, err := os.ReadFile(filepath.Join(dir, _checkpointFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return cp, err
+	}
+
+	if err = json.Unmarshal(data, &cp); err != nil {
+		return cp, err
+	}
+
+	return cp, nil
+}
+
+// saveCheckpoint atomically replaces the checkpoint of dir.
+func saveCheckpoint(dir string, cp checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, _checkpointFile+".*")
+	if err != nil {
+		return fmt.Errorf("os.CreateTemp: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("tmp.Write: %w", err)
+	}
+
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("tmp.Sync: %w", err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("tmp.Close: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), filepath.Join(dir, _checkpointFile)); err != nil {
+		return fmt.Errorf("os.Rename: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,380 @@
+package eventstore_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	eventsrepo "github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/repo/eventstore"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+func openRepo(t *testing.T, dir string, opts ...eventstore.Option) *eventstore.EventsRepo {
+	t.Helper()
+
+	repo, err := eventstore.Open(dir, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return repo
+}
+
+func TestLogReplay(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	userID := 1
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	kept, updated, deleted := uuid.New(), uuid.New(), uuid.New()
+
+	repo := openRepo(t, dir)
+
+	for _, uid := range []uuid.UUID{kept, updated, deleted} {
+		if err := repo.Create(ctx, userID, uid, entity.Event{Text: "old", Start: date, End: date}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := repo.Update(ctx, userID, updated, entity.Event{Text: "new", Start: date, End: date}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.Delete(ctx, userID, deleted, 0, date); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := repo.AddRevision(ctx, userID, kept, entity.Revision{Op: entity.RevisionCreate, Actor: "alice", At: date})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = eventstore.NewUsersRepo(repo).Save(ctx, entity.User{ID: userID, TimeZone: "Europe/Moscow"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err = repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// without the checkpoint everything is replayed from the log.
+	if err = os.Remove(filepath.Join(dir, "checkpoint.json")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo = openRepo(t, dir)
+	defer repo.Close()
+
+	events, err := repo.GetEventsForDay(ctx, userID, date)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(events) != 2 || events[kept].Text != "old" || events[updated].Text != "new" ||
+		events[updated].Version != 2 {
+		t.Fatalf("expected the kept and updated events, got %+v", events)
+	}
+
+	trash, err := repo.GetTrash(ctx, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(trash) != 1 || trash[0].UID != deleted || !trash[0].DeletedAt.Equal(date) {
+		t.Fatalf("unexpected trash %+v", trash)
+	}
+
+	revisions, err := repo.GetRevisions(ctx, userID, kept)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(revisions) != 1 || revisions[0].Number != 1 || revisions[0].Actor != "alice" {
+		t.Fatalf("unexpected revisions %+v", revisions)
+	}
+
+	user, err := eventstore.NewUsersRepo(repo).Get(ctx, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if user.TimeZone != "Europe/Moscow" {
+		t.Fatalf("expected %q, got %q", "Europe/Moscow", user.TimeZone)
+	}
+}
+
+func TestLogTx(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	userID := 1
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	committed, rolledBack := uuid.New(), uuid.New()
+
+	repo := openRepo(t, dir)
+
+	err := repo.WithinTx(ctx, func(tx eventsrepo.EventsRepo) error {
+		if err := tx.Create(ctx, userID, committed, entity.Event{Text: "created", Start: date, End: date}); err != nil {
+			return err
+		}
+
+		_, err := tx.Update(ctx, userID, committed, entity.Event{Text: "committed", Start: date, End: date})
+
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	errAbort := errors.New("abort")
+
+	err = repo.WithinTx(ctx, func(tx eventsrepo.EventsRepo) error {
+		if err := tx.Create(ctx, userID, rolledBack, entity.Event{Text: "rolled back", Start: date, End: date}); err != nil {
+			return err
+		}
+
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected errAbort, got %v", err)
+	}
+
+	if err = repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the transaction is a single commit of the log.
+	data, err := os.ReadFile(filepath.Join(dir, "events.log"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if lines := strings.Count(string(data), "\n"); lines != 1 {
+		t.Fatalf("expected 1 commit, got %d", lines)
+	}
+
+	repo = openRepo(t, dir)
+	defer repo.Close()
+
+	events, err := repo.GetEventsForDay(ctx, userID, date)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(events) != 1 || events[committed].Text != "committed" || events[committed].Version != 2 {
+		t.Fatalf("expected only the committed event, got %+v", events)
+	}
+}
+
+func TestLogCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	userID := 1
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	repo := openRepo(t, dir, eventstore.CheckpointEvery(3))
+
+	for range 10 {
+		if err := repo.Create(ctx, userID, uuid.New(), entity.Event{Text: "event", Start: date, End: date}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// a crash: the checkpoint lags behind the log.
+	if _, err := os.Stat(filepath.Join(dir, "checkpoint.json")); err != nil {
+		t.Fatalf("expected checkpoint: %v", err)
+	}
+
+	repo = openRepo(t, dir)
+	defer repo.Close()
+
+	events, err := repo.GetEventsForWeek(ctx, userID, date)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(events) != 10 {
+		t.Fatalf("expected 10 events, got %d", len(events))
+	}
+}
+
+func TestLogChanges(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	userID := 1
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	event := entity.Event{Text: "event", Start: date, End: date}
+	// the checkpoint is taken after the creates.
+	updated, deleted, kept := uuid.New(), uuid.New(), uuid.New()
+
+	repo := openRepo(t, dir, eventstore.CheckpointEvery(3))
+
+	for _, uid := range []uuid.UUID{updated, deleted, kept} {
+		if err := repo.Create(ctx, userID, uid, event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := repo.Update(ctx, userID, updated, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.Delete(ctx, userID, deleted, 0, date); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo = openRepo(t, dir)
+	defer repo.Close()
+
+	changes, seq, err := repo.GetChanges(ctx, userID, 0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if seq != 5 || len(changes) != 3 {
+		t.Fatalf("expected 3 changes at 5, got %+v at %d", changes, seq)
+	}
+
+	for i, want := range []entity.Change{{UID: kept, Seq: 3}, {UID: updated, Seq: 4}, {UID: deleted, Seq: 5, Deleted: true}} {
+		if got := changes[i]; got.UID != want.UID || got.Seq != want.Seq || got.Deleted != want.Deleted {
+			t.Fatalf("expected %+v, got %+v", want, got)
+		}
+	}
+}
+
+func TestRebuild(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	userID := 1
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	uid := uuid.New()
+
+	repo := openRepo(t, dir)
+
+	if err := repo.Create(ctx, userID, uid, entity.Event{Text: "event", Start: date, End: date}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.Delete(ctx, userID, uid, 0, date); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// a checkpoint ahead of the log is refused.
+	if err := os.Truncate(filepath.Join(dir, "events.log"), 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := eventstore.Open(dir); err == nil {
+		t.Fatal("expected error")
+	}
+
+	seq, err := eventstore.Rebuild(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if seq != 0 {
+		t.Fatalf("expected seq 0, got %d", seq)
+	}
+
+	repo = openRepo(t, dir)
+
+	// the log was emptied, so is the rebuilt checkpoint.
+	if _, err = repo.GetTrash(ctx, userID); !errors.Is(err, errs.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	if err = repo.Create(ctx, userID, uid, entity.Event{Text: "event", Start: date, End: date}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err = repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if seq, err = eventstore.Rebuild(dir); err != nil || seq != 1 {
+		t.Fatalf("expected seq 1, got %d, %v", seq, err)
+	}
+
+	repo = openRepo(t, dir)
+	defer repo.Close()
+
+	if _, err = repo.GetByUID(ctx, userID, uid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLogTornTail(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	userID := 1
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	first, second := uuid.New(), uuid.New()
+	path := filepath.Join(dir, "events.log")
+
+	repo := openRepo(t, dir)
+
+	if err := repo.Create(ctx, userID, first, entity.Event{Text: "first", Start: date, End: date}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	validSize := info.Size()
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = file.WriteString(`[{"seq":2,"kind":"event_cre`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	file.Close()
+
+	repo = openRepo(t, dir)
+	defer repo.Close()
+
+	info, err = os.Stat(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Size() != validSize {
+		t.Fatalf("expected log truncated to %d bytes, got %d", validSize, info.Size())
+	}
+
+	// writes continue after the truncated tail.
+	if err = repo.Create(ctx, userID, second, entity.Event{Text: "second", Start: date, End: date}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events, err := repo.GetEventsForDay(ctx, userID, date)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+}
@@ -0,0 +1,11 @@
+package eventstore
+
+// Option -.
+type Option func(*EventsRepo)
+
+// CheckpointEvery sets how many domain events appended to the log trigger a checkpoint of the projections.
+func CheckpointEvery(events int) Option {
+	return func(r *EventsRepo) {
+		r.checkpointEvery = int64(events)
+	}
+}
@@ -0,0 +1,506 @@
+package eventstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/date"
+	"github.com/google/btree"
+	"github.com/google/uuid"
+)
+
+const (
+	// _floatingSlack widens the periods an event is projected to, so all-day events, stored at UTC
+	// midnight, and periods of any time zone, bounded in UTC, are found.
+	_floatingSlack = 24 * time.Hour
+	// _btreeDegree is the degree of the trees of events by start and of the change sequence.
+	_btreeDegree = 32
+)
+
+// calendar - projection of the log for a single user.
+// Recurring events are kept out of the read models: a series may overlap any period
+// after its start, so they are checked one by one against their series end.
+type calendar struct {
+	timeZone string
+
+	events map[uuid.UUID]entity.Event
+	// days, weeks and months are read models of events by the periods they overlap.
+	days, weeks, months *periods
+	// byStart is the read model of ranges, events ordered by start. maxSpan is the longest
+	// duration ever projected: events starting up to maxSpan before a range may still overlap it.
+	byStart *btree.BTreeG[startKey]
+	maxSpan time.Duration
+	// series maps recurring events to the time they span.
+	series map[uuid.UUID]seriesSpan
+
+	trash     map[uuid.UUID]trashedEvent
+	revisions map[uuid.UUID][]entity.Revision
+
+	// changes is the change sequence, changeSeq maps events to their latest change
+	// and seq is the number of the last one.
+	changes   *btree.BTreeG[change]
+	changeSeq map[uuid.UUID]int64
+	seq       int64
+}
+
+// startKey orders events by start, uid breaks ties.
+type startKey struct {
+	start time.Time
+	uid   uuid.UUID
+}
+
+func lessStartKey(a, b startKey) bool {
+	if !a.start.Equal(b.start) {
+		return a.start.Before(b.start)
+	}
+
+	return bytes.Compare(a.uid[:], b.uid[:]) < 0
+}
+
+// change - the latest change of the event uid, numbered seq.
+type change struct {
+	seq     int64
+	uid     uuid.UUID
+	deleted bool
+}
+
+func lessChange(a, b change) bool {
+	return a.seq < b.seq
+}
+
+// trashedEvent - an event deleted at DeletedAt.
+type trashedEvent struct {
+	DeletedAt time.Time    `json:"deleted_at"`
+	Event     entity.Event `json:"event"`
+}
+
+// seriesSpan - from the start of the earliest instance to the end of the last,
+// end is zero for endless series.
+type seriesSpan struct {
+	start, end time.Time
+}
+
+func newCalendar() *calendar {
+	return &calendar{
+		timeZone:  entity.DefaultTimeZone,
+		events:    make(map[uuid.UUID]entity.Event),
+		days:      newPeriods(date.DayRange),
+		weeks:     newPeriods(date.WeekRange),
+		months:    newPeriods(date.MonthRange),
+		byStart:   btree.NewG(_btreeDegree, lessStartKey),
+		series:    make(map[uuid.UUID]seriesSpan),
+		trash:     make(map[uuid.UUID]trashedEvent),
+		revisions: make(map[uuid.UUID][]entity.Revision),
+		changes:   btree.NewG(_btreeDegree, lessChange),
+		changeSeq: make(map[uuid.UUID]int64),
+	}
+}
+
+// saved returns a function putting the entries of the event uid back as they are now, with the
+// time zone and counter of c. A failed transaction undoes what it projected with it.
+func (c *calendar) saved(uid uuid.UUID) func() {
+	event, stored := c.events[uid]
+	trashed, inTrash := c.trash[uid]
+	revisions, revised := c.revisions[uid]
+	seq, changed := c.changeSeq[uid]
+	latest, _ := c.changes.Get(change{seq: seq})
+	timeZone, lastSeq, maxSpan := c.timeZone, c.seq, c.maxSpan
+
+	return func() {
+		c.remove(uid)
+
+		if current, ok := c.changeSeq[uid]; ok {
+			c.changes.Delete(change{seq: current})
+			delete(c.changeSeq, uid)
+		}
+
+		if stored {
+			c.events[uid] = event
+			c.index(uid, event)
+		}
+
+		if changed {
+			c.changeSeq[uid] = seq
+			c.changes.ReplaceOrInsert(latest)
+		}
+
+		restore(c.trash, uid, trashed, inTrash)
+		restore(c.revisions, uid, revisions, revised)
+		c.timeZone, c.seq, c.maxSpan = timeZone, lastSeq, maxSpan
+	}
+}
+
+// savedTrash returns a function putting the trash back as it is now.
+func (c *calendar) savedTrash() func() {
+	trash := maps.Clone(c.trash)
+
+	return func() {
+		c.trash = trash
+	}
+}
+
+// restore sets m[k] to v if ok, deletes it otherwise.
+func restore[K comparable, V any](m map[K]V, k K, v V, ok bool) {
+	if ok {
+		m[k] = v
+	} else {
+		delete(m, k)
+	}
+}
+
+// apply projects the domain event of the user.
+func (c *calendar) apply(e DomainEvent) {
+	switch e.Kind {
+	case EventCreated, EventUpdated:
+		c.put(e.UID, *e.Event)
+	case EventDeleted:
+		if event, ok := c.events[e.UID]; ok {
+			c.remove(e.UID)
+			c.trash[e.UID] = trashedEvent{DeletedAt: e.At, Event: event}
+			c.touch(e.UID, true)
+		}
+	case EventRestored:
+		delete(c.trash, e.UID)
+		c.put(e.UID, *e.Event)
+	case TrashPurged:
+		c.purge(e.Before)
+	case RevisionAdded:
+		revisions := c.revisions[e.UID]
+		revision := *e.Revision
+		revision.Number = int64(len(revisions)) + 1
+
+		// clipped, so a saved history never shares the appended revision.
+		c.revisions[e.UID] = append(slices.Clip(revisions), revision)
+	case UserSaved:
+		c.timeZone = e.User.TimeZone
+	}
+}
+
+// put inserts or replaces the event in the read models.
+func (c *calendar) put(uid uuid.UUID, event entity.Event) {
+	c.remove(uid)
+	c.events[uid] = event.Clone()
+	c.touch(uid, false)
+	c.index(uid, event)
+}
+
+// index adds the stored event to the read models, or a series to series.
+func (c *calendar) index(uid uuid.UUID, event entity.Event) {
+	if event.Recurring() {
+		// an invalid rule leaves the series endless, the usecase reports it on expansion.
+		end, _ := event.SeriesEnd()
+		c.series[uid] = seriesSpan{start: event.SeriesStart(), end: end}
+
+		return
+	}
+
+	for _, p := range []*periods{c.days, c.weeks, c.months} {
+		p.add(uid, event)
+	}
+
+	c.byStart.ReplaceOrInsert(startKey{start: event.Start, uid: uid})
+	c.maxSpan = max(c.maxSpan, event.End.Sub(event.Start))
+}
+
+func (c *calendar) remove(uid uuid.UUID) {
+	old, ok := c.events[uid]
+	if !ok {
+		return
+	}
+
+	for _, p := range []*periods{c.days, c.weeks, c.months} {
+		p.remove(uid, old)
+	}
+
+	c.byStart.Delete(startKey{start: old.Start, uid: uid})
+
+	delete(c.series, uid)
+	delete(c.events, uid)
+}
+
+// touch numbers the next change, the write of the event, replacing its previous one.
+func (c *calendar) touch(uid uuid.UUID, deleted bool) {
+	if seq, ok := c.changeSeq[uid]; ok {
+		c.changes.Delete(change{seq: seq})
+	}
+
+	c.seq++
+	c.changeSeq[uid] = c.seq
+	c.changes.ReplaceOrInsert(change{seq: c.seq, uid: uid, deleted: deleted})
+}
+
+// changesAfter returns up to limit changes numbered after the position.
+func (c *calendar) changesAfter(after int64, limit int) []entity.Change {
+	changes := make([]entity.Change, 0)
+
+	c.changes.AscendGreaterOrEqual(change{seq: after + 1}, func(ch change) bool {
+		if len(changes) == limit {
+			return false
+		}
+
+		next := entity.Change{UID: ch.uid, Seq: ch.seq, Deleted: ch.deleted}
+		if !ch.deleted {
+			next.Event = c.events[ch.uid].Clone()
+		}
+
+		changes = append(changes, next)
+
+		return true
+	})
+
+	return changes
+}
+
+// purge deletes events trashed before the time and returns their number.
+func (c *calendar) purge(before time.Time) int64 {
+	var n int64
+
+	for uid, trashed := range c.trash {
+		if trashed.DeletedAt.Before(before) {
+			delete(c.trash, uid)
+			n++
+		}
+	}
+
+	return n
+}
+
+// trashedBefore reports whether the trash holds events deleted before the time.
+func (c *calendar) trashedBefore(before time.Time) bool {
+	for _, trashed := range c.trash {
+		if trashed.DeletedAt.Before(before) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// between returns events overlapping [from, to) found in the read model p
+// and series that may have instances there, see entity.Event.Occurrences.
+func (c *calendar) between(p *periods, from, to time.Time) map[uuid.UUID]entity.Event {
+	events := c.seriesBetween(from, to)
+
+	for uid := range p.between(from, to) {
+		if event := c.events[uid]; event.Overlaps(from, to) {
+			events[uid] = event.Clone()
+		}
+	}
+
+	return events
+}
+
+// seriesBetween returns series that may have instances in [from, to).
+func (c *calendar) seriesBetween(from, to time.Time) map[uuid.UUID]entity.Event {
+	series := make(map[uuid.UUID]entity.Event)
+
+	for uid, span := range c.series {
+		if !span.start.Before(to.Add(_floatingSlack)) {
+			continue
+		}
+
+		if span.end.IsZero() || span.end.After(from.Add(-_floatingSlack)) {
+			series[uid] = c.events[uid].Clone()
+		}
+	}
+
+	return series
+}
+
+// page returns up to page.Limit events overlapping [from, to) following page.After, as they are ordered
+// in byStart, and series that may have instances there.
+func (c *calendar) page(from, to time.Time, page entity.PageRequest) entity.EventsPage {
+	res := entity.EventsPage{
+		Events: make([]entity.Occurrence, 0, page.Limit),
+		Series: c.seriesBetween(from, to),
+	}
+
+	lower := startKey{start: from.Add(-c.maxSpan - _floatingSlack)}
+	upper := startKey{start: to.Add(_floatingSlack)}
+
+	var after *startKey
+	if page.After != nil {
+		after = &startKey{start: page.After.Start, uid: page.After.UID}
+	}
+
+	collect := func(k startKey) bool {
+		if len(res.Events) == page.Limit {
+			return false
+		}
+
+		if after != nil && k == *after {
+			return true
+		}
+
+		if event := c.events[k.uid]; event.Overlaps(from, to) {
+			res.Events = append(res.Events, entity.Occurrence{UID: k.uid, Event: event.Clone()})
+		}
+
+		return true
+	}
+
+	if page.Desc {
+		if after != nil && lessStartKey(*after, upper) {
+			upper = *after
+		}
+
+		c.byStart.DescendRange(upper, lower, collect)
+
+		return res
+	}
+
+	if after != nil && lessStartKey(lower, *after) {
+		lower = *after
+	}
+
+	c.byStart.AscendRange(lower, upper, collect)
+
+	return res
+}
+
+// periods - a read model of events by the periods (of UTC) they overlap, with _floatingSlack
+// on both sides. Periods are keyed by their start in unix seconds.
+type periods struct {
+	// bounds returns [from, to) of the period containing t.
+	bounds func(t time.Time) (time.Time, time.Time)
+	events map[int64]map[uuid.UUID]struct{}
+}
+
+func newPeriods(bounds func(t time.Time) (time.Time, time.Time)) *periods {
+	return &periods{
+		bounds: bounds,
+		events: make(map[int64]map[uuid.UUID]struct{}),
+	}
+}
+
+func (p *periods) add(uid uuid.UUID, event entity.Event) {
+	p.each(event.Start.Add(-_floatingSlack), event.End.Add(_floatingSlack), func(key int64) {
+		p.writable(key)[uid] = struct{}{}
+	})
+}
+
+func (p *periods) remove(uid uuid.UUID, event entity.Event) {
+	p.each(event.Start.Add(-_floatingSlack), event.End.Add(_floatingSlack), func(key int64) {
+		uids := p.writable(key)
+		delete(uids, uid)
+
+		if len(uids) == 0 {
+			delete(p.events, key)
+		}
+	})
+}
+
+// writable returns events of the period to change.
+func (p *periods) writable(key int64) map[uuid.UUID]struct{} {
+	uids, ok := p.events[key]
+	if !ok {
+		uids = make(map[uuid.UUID]struct{})
+		p.events[key] = uids
+	}
+
+	return uids
+}
+
+// between returns uids of events projected to the periods overlapping [from, to).
+func (p *periods) between(from, to time.Time) map[uuid.UUID]struct{} {
+	uids := make(map[uuid.UUID]struct{})
+
+	p.each(from, to, func(key int64) {
+		for uid := range p.events[key] {
+			uids[uid] = struct{}{}
+		}
+	})
+
+	return uids
+}
+
+// each calls fn with keys of the periods overlapping [from, to), at least the one of from.
+func (p *periods) each(from, to time.Time, fn func(key int64)) {
+	for start, end := p.bounds(from.UTC()); ; start, end = p.bounds(end) {
+		fn(start.Unix())
+
+		if !end.Before(to) {
+			return
+		}
+	}
+}
+
+// calendarSnapshot is how calendar is stored in checkpoints, the read models are rebuilt on load.
+type calendarSnapshot struct {
+	TimeZone  string                          `json:"tz"`
+	Events    map[uuid.UUID]entity.Event      `json:"events"`
+	Trash     map[uuid.UUID]trashedEvent      `json:"trash,omitempty"`
+	Revisions map[uuid.UUID][]entity.Revision `json:"revisions,omitempty"`
+	Changes   map[uuid.UUID]changeSnapshot    `json:"changes,omitempty"`
+	Seq       int64                           `json:"seq,omitempty"`
+}
+
+// changeSnapshot is how the latest change of an event is stored in checkpoints.
+type changeSnapshot struct {
+	Seq     int64 `json:"seq"`
+	Deleted bool  `json:"deleted,omitempty"`
+}
+
+// MarshalJSON -.
+func (c *calendar) MarshalJSON() ([]byte, error) {
+	changes := make(map[uuid.UUID]changeSnapshot, c.changes.Len())
+
+	c.changes.Ascend(func(ch change) bool {
+		changes[ch.uid] = changeSnapshot{Seq: ch.seq, Deleted: ch.deleted}
+
+		return true
+	})
+
+	return json.Marshal(calendarSnapshot{
+		TimeZone:  c.timeZone,
+		Events:    c.events,
+		Trash:     c.trash,
+		Revisions: c.revisions,
+		Changes:   changes,
+		Seq:       c.seq,
+	})
+}
+
+// UnmarshalJSON -.
+func (c *calendar) UnmarshalJSON(b []byte) error {
+	var snapshot calendarSnapshot
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return err
+	}
+
+	*c = *newCalendar()
+	c.timeZone = snapshot.TimeZone
+
+	for uid, event := range snapshot.Events {
+		c.put(uid, event)
+	}
+
+	maps.Copy(c.trash, snapshot.Trash)
+	maps.Copy(c.revisions, snapshot.Revisions)
+
+	// checkpoints taken before the change sequence number the events and tombstones of the trash anew.
+	if snapshot.Changes == nil {
+		for uid := range snapshot.Trash {
+			if _, ok := c.events[uid]; !ok {
+				c.touch(uid, true)
+			}
+		}
+
+		return nil
+	}
+
+	c.changes.Clear(false)
+	clear(c.changeSeq)
+	c.seq = snapshot.Seq
+
+	for uid, ch := range snapshot.Changes {
+		c.changeSeq[uid] = ch.Seq
+		c.changes.ReplaceOrInsert(change{seq: ch.Seq, uid: uid, deleted: ch.Deleted})
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package eventstore
+
+import (
+	"fmt"
+)
+
+// Rebuild replays the whole log in dir into new projections and replaces the checkpoint with them,
+// e.g. after the projections changed or the checkpoint was lost. It returns Seq of the last
+// domain event. The log must not be open meanwhile.
+func Rebuild(dir string) (int64, error) {
+	l, err := openLog(dir)
+	if err != nil {
+		return 0, fmt.Errorf("eventstore - Rebuild - openLog: %w", err)
+	}
+	defer l.close()
+
+	r := New()
+
+	seq, err := l.replay(r.project)
+	if err != nil {
+		return 0, fmt.Errorf("eventstore - Rebuild - l.replay: %w", err)
+	}
+
+	if err = saveCheckpoint(dir, checkpoint{Seq: seq, Users: r.storage}); err != nil {
+		return 0, fmt.Errorf("eventstore - Rebuild - saveCheckpoint: %w", err)
+	}
+
+	return seq, nil
+}
@@ -0,0 +1,55 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+// AddRevision -.
+func (r *EventsRepo) AddRevision(ctx context.Context, userID int, eventUID uuid.UUID, revision entity.Revision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	revision.Before, revision.After = cloneEvent(revision.Before), cloneEvent(revision.After)
+
+	err := r.emit(DomainEvent{Kind: RevisionAdded, At: revision.At, UserID: userID, UID: eventUID, Revision: &revision})
+	if err != nil {
+		return fmt.Errorf("EventsRepo - AddRevision - r.emit: %w", err)
+	}
+
+	return nil
+}
+
+// GetRevisions -.
+func (r *EventsRepo) GetRevisions(ctx context.Context, userID int, eventUID uuid.UUID) ([]entity.Revision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.storage[userID]
+	if !ok {
+		return nil, errs.ErrUserNotFound
+	}
+
+	revisions := slices.Clone(user.revisions[eventUID])
+
+	for i, revision := range revisions {
+		revisions[i].Before, revisions[i].After = cloneEvent(revision.Before), cloneEvent(revision.After)
+	}
+
+	return revisions, nil
+}
+
+func cloneEvent(event *entity.Event) *entity.Event {
+	if event == nil {
+		return nil
+	}
+
+	clone := event.Clone()
+
+	return &clone
+}
@@ -0,0 +1,91 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+// Restore -.
+func (r *EventsRepo) Restore(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.storage[userID]
+	if !ok {
+		return entity.Event{}, errs.ErrUserNotFound
+	}
+
+	trashed, ok := user.trash[eventUID]
+	if !ok {
+		return entity.Event{}, errs.ErrEventNotFound
+	}
+
+	if _, ok = user.events[eventUID]; ok {
+		return entity.Event{}, errs.ErrAlreadyExists
+	}
+
+	event := trashed.Event.Clone()
+	event.Version++
+
+	err := r.emit(DomainEvent{Kind: EventRestored, At: time.Now(), UserID: userID, UID: eventUID, Event: &event})
+	if err != nil {
+		return entity.Event{}, fmt.Errorf("EventsRepo - Restore - r.emit: %w", err)
+	}
+
+	return event.Clone(), nil
+}
+
+// GetTrash -.
+func (r *EventsRepo) GetTrash(ctx context.Context, userID int) ([]entity.TrashedEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.storage[userID]
+	if !ok {
+		return nil, errs.ErrUserNotFound
+	}
+
+	events := make([]entity.TrashedEvent, 0, len(user.trash))
+
+	for uid, trashed := range user.trash {
+		events = append(events, entity.TrashedEvent{
+			UID:       uid,
+			DeletedAt: trashed.DeletedAt,
+			Event:     trashed.Event.Clone(),
+		})
+	}
+
+	return events, nil
+}
+
+// PurgeTrash -.
+func (r *EventsRepo) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+
+	for _, user := range r.storage {
+		for _, trashed := range user.trash {
+			if trashed.DeletedAt.Before(before) {
+				purged++
+			}
+		}
+	}
+
+	if purged == 0 {
+		return 0, nil
+	}
+
+	err := r.emit(DomainEvent{Kind: TrashPurged, At: time.Now(), Before: before})
+	if err != nil {
+		return 0, fmt.Errorf("EventsRepo - PurgeTrash - r.emit: %w", err)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,51 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+)
+
+// UsersRepo - users are projected from the same log as their events.
+type UsersRepo struct {
+	events *EventsRepo
+}
+
+// NewUsersRepo returns new UsersRepo(struct) on top of events log
+func NewUsersRepo(events *EventsRepo) *UsersRepo {
+	return &UsersRepo{
+		events: events,
+	}
+}
+
+// Get -.
+func (r *UsersRepo) Get(ctx context.Context, userID int) (entity.User, error) {
+	r.events.mu.RLock()
+	defer r.events.mu.RUnlock()
+
+	user, ok := r.events.storage[userID]
+	if !ok {
+		return entity.User{}, errs.ErrUserNotFound
+	}
+
+	return entity.User{
+		ID:       userID,
+		TimeZone: user.timeZone,
+	}, nil
+}
+
+// Save -.
+func (r *UsersRepo) Save(ctx context.Context, user entity.User) error {
+	r.events.mu.Lock()
+	defer r.events.mu.Unlock()
+
+	err := r.events.emit(DomainEvent{Kind: UserSaved, At: time.Now(), UserID: user.ID, User: &user})
+	if err != nil {
+		return fmt.Errorf("UsersRepo - Save - r.events.emit: %w", err)
+	}
+
+	return nil
+}
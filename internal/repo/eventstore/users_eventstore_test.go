@@ -0,0 +1,15 @@
+package eventstore_test
+
+import (
+	"testing"
+
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/repo/eventstore"
+	"github.com/andreyxaxa/calendar/internal/repo/repotest"
+)
+
+func TestUsersRepo(t *testing.T) {
+	repotest.Users(t, func(t *testing.T) repo.UsersRepo {
+		return eventstore.NewUsersRepo(eventstore.New())
+	})
+}
@@ -0,0 +1,186 @@
+// Package history records every write of an events repository as a revision of the event,
+// see entity.Revision. The actor is taken from the context of the write, see pkg/actor.
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/pkg/actor"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+// EventsRepo - repo.EventsRepo adding the revision of each write in the transaction of the write,
+// so a write is never kept without its revision.
+type EventsRepo struct {
+	repo.EventsRepo
+}
+
+// New returns new EventsRepo(struct) recording writes to r.
+func New(r repo.EventsRepo) *EventsRepo {
+	return &EventsRepo{
+		EventsRepo: r,
+	}
+}
+
+// WithinTx -.
+func (r *EventsRepo) WithinTx(ctx context.Context, fn func(tx repo.EventsRepo) error) error {
+	return r.EventsRepo.WithinTx(ctx, func(tx repo.EventsRepo) error {
+		return fn(New(tx))
+	})
+}
+
+// Create -.
+func (r *EventsRepo) Create(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) error {
+	return r.EventsRepo.WithinTx(ctx, func(tx repo.EventsRepo) error {
+		if err := tx.Create(ctx, userID, eventUID, event); err != nil {
+			return err
+		}
+
+		event.Version = 1
+
+		return addRevision(ctx, tx, "Create", userID, eventUID, entity.Revision{
+			Op:    entity.RevisionCreate,
+			At:    time.Now(),
+			After: &event,
+		})
+	})
+}
+
+// Update -.
+func (r *EventsRepo) Update(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) (int64, error) {
+	err := r.EventsRepo.WithinTx(ctx, func(tx repo.EventsRepo) error {
+		before, err := current(ctx, tx, "Update", userID, eventUID)
+		if err != nil {
+			return err
+		}
+
+		if event.Version, err = tx.Update(ctx, userID, eventUID, event); err != nil {
+			return err
+		}
+
+		return addRevision(ctx, tx, "Update", userID, eventUID, entity.Revision{
+			Op:     entity.RevisionUpdate,
+			At:     time.Now(),
+			Before: before,
+			After:  &event,
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return event.Version, nil
+}
+
+// UpdateFields -.
+func (r *EventsRepo) UpdateFields(ctx context.Context, userID int, eventUID uuid.UUID, patch entity.Event,
+	mask entity.FieldMask,
+) (entity.Event, error) {
+	var event entity.Event
+
+	err := r.EventsRepo.WithinTx(ctx, func(tx repo.EventsRepo) error {
+		before, err := current(ctx, tx, "UpdateFields", userID, eventUID)
+		if err != nil {
+			return err
+		}
+
+		if event, err = tx.UpdateFields(ctx, userID, eventUID, patch, mask); err != nil {
+			return err
+		}
+
+		return addRevision(ctx, tx, "UpdateFields", userID, eventUID, entity.Revision{
+			Op:     entity.RevisionUpdate,
+			At:     time.Now(),
+			Before: before,
+			After:  &event,
+		})
+	})
+	if err != nil {
+		return entity.Event{}, err
+	}
+
+	return event, nil
+}
+
+// Delete -.
+func (r *EventsRepo) Delete(ctx context.Context, userID int, eventUID uuid.UUID, version int64,
+	deletedAt time.Time,
+) error {
+	return r.EventsRepo.WithinTx(ctx, func(tx repo.EventsRepo) error {
+		before, err := current(ctx, tx, "Delete", userID, eventUID)
+		if err != nil {
+			return err
+		}
+
+		if err = tx.Delete(ctx, userID, eventUID, version, deletedAt); err != nil {
+			return err
+		}
+
+		return addRevision(ctx, tx, "Delete", userID, eventUID, entity.Revision{
+			Op:     entity.RevisionDelete,
+			At:     deletedAt,
+			Before: before,
+		})
+	})
+}
+
+// Restore -.
+func (r *EventsRepo) Restore(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error) {
+	var event entity.Event
+
+	err := r.EventsRepo.WithinTx(ctx, func(tx repo.EventsRepo) error {
+		var err error
+
+		if event, err = tx.Restore(ctx, userID, eventUID); err != nil {
+			return err
+		}
+
+		return addRevision(ctx, tx, "Restore", userID, eventUID, entity.Revision{
+			Op:    entity.RevisionRestore,
+			At:    time.Now(),
+			After: &event,
+		})
+	})
+	if err != nil {
+		return entity.Event{}, err
+	}
+
+	return event, nil
+}
+
+// current returns the stored event, nil if there is none: the write then fails on its own.
+func current(ctx context.Context, tx repo.EventsRepo, op string, userID int, eventUID uuid.UUID) (*entity.Event, error) {
+	event, err := tx.GetByUID(ctx, userID, eventUID)
+	if errors.Is(err, errs.ErrEventNotFound) || errors.Is(err, errs.ErrUserNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - %s - tx.GetByUID: %w", op, err)
+	}
+
+	return &event, nil
+}
+
+// addRevision records the write by the actor of ctx, the owner of the calendar if there is none.
+func addRevision(ctx context.Context, tx repo.EventsRepo, op string, userID int, eventUID uuid.UUID,
+	revision entity.Revision,
+) error {
+	revision.Actor = actor.From(ctx)
+	if revision.Actor == "" {
+		revision.Actor = "user:" + strconv.Itoa(userID)
+	}
+
+	if err := tx.AddRevision(ctx, userID, eventUID, revision); err != nil {
+		return fmt.Errorf("EventsRepo - %s - tx.AddRevision: %w", op, err)
+	}
+
+	return nil
+}
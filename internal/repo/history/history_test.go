@@ -0,0 +1,121 @@
+package history_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/repo/history"
+	"github.com/andreyxaxa/calendar/internal/repo/inmemory"
+	"github.com/andreyxaxa/calendar/pkg/actor"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+func TestHistory(t *testing.T) {
+	r := history.New(inmemory.New())
+
+	ctx := context.Background()
+	userID := 1
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	deletedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	uid := uuid.New()
+
+	event := entity.Event{
+		Start:    start,
+		End:      start.Add(time.Hour),
+		TimeZone: "UTC",
+		Text:     "stand-up",
+	}
+
+	if err := r.Create(ctx, userID, uid, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	alice := actor.With(ctx, "alice")
+
+	updated := event
+	updated.Text, updated.Version = "retro", 1
+
+	if _, err := r.Update(alice, userID, uid, updated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// failed writes are not recorded.
+	if _, err := r.Update(alice, userID, uid, updated); !errors.Is(err, errs.ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+
+	if err := r.Create(ctx, userID, uid, event); !errors.Is(err, errs.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+
+	if err := r.Delete(alice, userID, uid, 2, deletedAt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := r.Restore(ctx, userID, uid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// writes in a transaction are recorded in it.
+	err := r.WithinTx(ctx, func(tx repo.EventsRepo) error {
+		patch := entity.Event{Text: "planning"}
+
+		if _, err := tx.UpdateFields(ctx, userID, uid, patch, entity.FieldText); err != nil {
+			return err
+		}
+
+		return errors.New("rollback")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	revisions, err := r.GetRevisions(ctx, userID, uid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		op            entity.RevisionOp
+		actor         string
+		before, after string
+	}{
+		{entity.RevisionCreate, "user:1", "", "stand-up"},
+		{entity.RevisionUpdate, "alice", "stand-up", "retro"},
+		{entity.RevisionDelete, "alice", "retro", ""},
+		{entity.RevisionRestore, "user:1", "", "retro"},
+	}
+
+	if len(revisions) != len(want) {
+		t.Fatalf("expected %d revisions, got %+v", len(want), revisions)
+	}
+
+	for i, revision := range revisions {
+		if revision.Number != int64(i+1) || revision.Op != want[i].op || revision.Actor != want[i].actor ||
+			text(revision.Before) != want[i].before || text(revision.After) != want[i].after {
+			t.Fatalf("unexpected revision %d: %+v", i+1, revision)
+		}
+	}
+
+	if !revisions[2].At.Equal(deletedAt) {
+		t.Fatalf("expected the delete at %v, got %v", deletedAt, revisions[2].At)
+	}
+
+	if revisions[1].After.Version != 2 || revisions[3].After.Version != 3 {
+		t.Fatalf("expected versions of the stored events, got %d and %d",
+			revisions[1].After.Version, revisions[3].After.Version)
+	}
+}
+
+func text(event *entity.Event) string {
+	if event == nil {
+		return ""
+	}
+
+	return event.Text
+}
@@ -2,24 +2,208 @@ package inmemory
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/pkg/types/date"
 	"github.com/andreyxaxa/calendar/pkg/types/errs"
 	"github.com/google/uuid"
 )
 
+const _defaultSnapshotEvery = 1000
+
 // EventsRepo -.
 type EventsRepo struct {
-	storage map[int]map[uuid.UUID]entity.Event
+	storage map[int]*userEvents
 	mu      sync.RWMutex
+
+	// journal is nil unless the repo was opened with Open.
+	journal       *journal
+	snapshotEvery int
+
+	// tx is set for the repo WithinTx passes to its function.
+	tx *tx
+}
+
+// tx - a transaction writes the storage in place, undo holds what puts its writes back, in their
+// order, if it fails. Records are kept to be journaled together on commit.
+type tx struct {
+	records []record
+	undo    []func()
 }
 
 // New returns new EventsRepo(struct)
 func New() *EventsRepo {
 	return &EventsRepo{
-		storage: make(map[int]map[uuid.UUID]entity.Event),
+		storage: make(map[int]*userEvents),
+	}
+}
+
+// Open returns EventsRepo persisted to dir: every write is journaled before it
+// is applied and state is restored from the snapshot and journal on open.
+func Open(dir string, opts ...Option) (*EventsRepo, error) {
+	r := &EventsRepo{
+		snapshotEvery: _defaultSnapshotEvery,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	j, storage, err := openJournal(dir)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - Open - openJournal: %w", err)
+	}
+
+	r.journal = j
+	r.storage = storage
+
+	return r, nil
+}
+
+// Close -.
+func (r *EventsRepo) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.journal == nil {
+		return nil
+	}
+
+	return r.journal.close()
+}
+
+// WithinTx runs fn against the storage, undoing its writes unless it succeeds: a write keeps only
+// what puts back the entries of the event it changed, so it costs no more than outside of
+// a transaction. Writes of fn are journaled as a single record, so they are restored all or none.
+// Other writes wait for the transaction.
+func (r *EventsRepo) WithinTx(ctx context.Context, fn func(tx repo.EventsRepo) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the journal is compacted before fn writes, the snapshot must not hold what it may undo.
+	if r.tx == nil && r.journal != nil {
+		if err := r.journal.compact(r.storage, r.snapshotEvery); err != nil {
+			return fmt.Errorf("EventsRepo - WithinTx - r.journal.compact: %w", err)
+		}
+	}
+
+	view := &EventsRepo{
+		storage: r.storage,
+		tx:      &tx{},
+	}
+
+	committed := false
+
+	defer func() {
+		if !committed {
+			view.tx.rollback()
+		}
+	}()
+
+	if err := fn(view); err != nil {
+		return err
+	}
+
+	if len(view.tx.records) > 0 {
+		if err := r.commit(view.tx); err != nil {
+			return fmt.Errorf("EventsRepo - WithinTx - r.commit: %w", err)
+		}
+	}
+
+	committed = true
+
+	return nil
+}
+
+// commit journals the records of t as a single one, in a transaction t becomes part of it.
+// Must be called with r.mu held.
+func (r *EventsRepo) commit(t *tx) error {
+	rec := record{Op: opTx, Records: t.records}
+
+	if r.tx != nil {
+		r.tx.records = append(r.tx.records, rec)
+		r.tx.undo = append(r.tx.undo, t.undo...)
+
+		return nil
+	}
+
+	if r.journal == nil {
+		return nil
+	}
+
+	if err := r.journal.append(rec); err != nil {
+		return fmt.Errorf("r.journal.append: %w", err)
+	}
+
+	return nil
+}
+
+// rollback undoes the writes of t, the last one first.
+func (t *tx) rollback() {
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		t.undo[i]()
+	}
+}
+
+// persist journals rec, compacting the journal first if it grew too long.
+// In a transaction rec is kept for the commit.
+// Must be called with r.mu held, before rec is applied to r.storage.
+func (r *EventsRepo) persist(rec record) error {
+	user, _ := r.user(rec.UserID)
+	number(user, &rec)
+
+	if r.tx != nil {
+		r.tx.records = append(r.tx.records, rec)
+
+		return nil
+	}
+
+	if r.journal == nil {
+		return nil
+	}
+
+	if err := r.journal.write(rec, r.storage, r.snapshotEvery); err != nil {
+		return fmt.Errorf("r.journal.write: %w", err)
+	}
+
+	return nil
+}
+
+// user returns events of the user.
+func (r *EventsRepo) user(userID int) (*userEvents, bool) {
+	user, ok := r.storage[userID]
+
+	return user, ok
+}
+
+// writable returns events of the user to change the event uid of, they are created if there are none.
+// In a transaction what puts the event back is kept first.
+func (r *EventsRepo) writable(userID int, uid uuid.UUID) *userEvents {
+	user, ok := r.storage[userID]
+	if !ok {
+		user = newUserEvents()
+		r.storage[userID] = user
+
+		r.onRollback(func() { delete(r.storage, userID) })
+
+		return user
+	}
+
+	if r.tx != nil {
+		r.onRollback(user.saved(uid))
+	}
+
+	return user
+}
+
+// onRollback keeps undo to be called if the transaction fails, outside of one it is dropped.
+func (r *EventsRepo) onRollback(undo func()) {
+	if r.tx != nil {
+		r.tx.undo = append(r.tx.undo, undo)
 	}
 }
 
@@ -28,122 +212,211 @@ func (r *EventsRepo) Create(ctx context.Context, userID int, eventUID uuid.UUID,
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, ok := r.storage[userID]; !ok {
-		r.storage[userID] = make(map[uuid.UUID]entity.Event)
+	user, ok := r.user(userID)
+	if ok {
+		if _, ok = user.get(eventUID); ok {
+			return errs.ErrAlreadyExists
+		}
 	}
 
-	if _, ok := r.storage[userID][eventUID]; ok {
-		return errs.ErrAlreadyExists
+	event.Version = 1
+
+	err := r.persist(record{Op: opPut, UserID: userID, UID: eventUID, Event: event})
+	if err != nil {
+		return fmt.Errorf("EventsRepo - Create - r.persist: %w", err)
 	}
 
-	r.storage[userID][eventUID] = event
+	r.writable(userID, eventUID).put(eventUID, event)
+
 	return nil
 }
 
 // Update -.
-func (r *EventsRepo) Update(ctx context.Context, userID int, eventUID uuid.UUID, text string, date time.Time) error {
+func (r *EventsRepo) Update(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) (int64, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, ok := r.storage[userID]; !ok {
-		return errs.ErrUserNotFound
+	user, ok := r.user(userID)
+	if !ok {
+		return 0, errs.ErrUserNotFound
 	}
 
-	event, ok := r.storage[userID][eventUID]
+	current, ok := user.get(eventUID)
 	if !ok {
-		return errs.ErrEventNotFound
+		return 0, errs.ErrEventNotFound
 	}
 
-	event.Text = text
-	event.Date = date
-	r.storage[userID][eventUID] = event
+	if event.Version != 0 && event.Version != current.Version {
+		return 0, errs.ErrPreconditionFailed
+	}
 
-	return nil
+	event.Version = current.Version + 1
+
+	err := r.persist(record{Op: opPut, UserID: userID, UID: eventUID, Event: event})
+	if err != nil {
+		return 0, fmt.Errorf("EventsRepo - Update - r.persist: %w", err)
+	}
+
+	r.writable(userID, eventUID).put(eventUID, event)
+
+	return event.Version, nil
+}
+
+// UpdateFields -.
+func (r *EventsRepo) UpdateFields(ctx context.Context, userID int, eventUID uuid.UUID, patch entity.Event,
+	mask entity.FieldMask,
+) (entity.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.user(userID)
+	if !ok {
+		return entity.Event{}, errs.ErrUserNotFound
+	}
+
+	current, ok := user.get(eventUID)
+	if !ok {
+		return entity.Event{}, errs.ErrEventNotFound
+	}
+
+	if patch.Version != 0 && patch.Version != current.Version {
+		return entity.Event{}, errs.ErrPreconditionFailed
+	}
+
+	event := current.Merge(patch, mask)
+	event.Version = current.Version + 1
+
+	err := r.persist(record{Op: opPut, UserID: userID, UID: eventUID, Event: event})
+	if err != nil {
+		return entity.Event{}, fmt.Errorf("EventsRepo - UpdateFields - r.persist: %w", err)
+	}
+
+	r.writable(userID, eventUID).put(eventUID, event)
+
+	return event, nil
 }
 
 // Delete -.
-func (r *EventsRepo) Delete(ctx context.Context, userID int, eventUID uuid.UUID) error {
+func (r *EventsRepo) Delete(ctx context.Context, userID int, eventUID uuid.UUID, version int64,
+	deletedAt time.Time,
+) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, ok := r.storage[userID]; !ok {
+	user, ok := r.user(userID)
+	if !ok {
 		return errs.ErrUserNotFound
 	}
 
-	_, ok := r.storage[userID][eventUID]
+	current, ok := user.get(eventUID)
 	if !ok {
 		return errs.ErrEventNotFound
 	}
 
-	delete(r.storage[userID], eventUID)
+	if version != 0 && version != current.Version {
+		return errs.ErrPreconditionFailed
+	}
+
+	err := r.persist(record{Op: opTrash, UserID: userID, UID: eventUID, Time: deletedAt})
+	if err != nil {
+		return fmt.Errorf("EventsRepo - Delete - r.persist: %w", err)
+	}
+
+	r.writable(userID, eventUID).moveToTrash(eventUID, deletedAt)
 
 	return nil
 }
 
-// GetEventsForDay -.
-func (r *EventsRepo) GetEventsForDay(ctx context.Context, userID int, date time.Time) (map[uuid.UUID]entity.Event, error) {
-	events := make(map[uuid.UUID]entity.Event)
+// GetChanges -.
+func (r *EventsRepo) GetChanges(ctx context.Context, userID int, after int64, limit int,
+) ([]entity.Change, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.user(userID)
+	if !ok {
+		return []entity.Change{}, 0, nil
+	}
 
+	return user.changesAfter(after, limit), user.seq, nil
+}
+
+// GetByUID -.
+func (r *EventsRepo) GetByUID(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error) {
 	r.mu.RLock()
-	userEvents, ok := r.storage[userID]
+	defer r.mu.RUnlock()
+
+	user, ok := r.user(userID)
 	if !ok {
-		return nil, errs.ErrUserNotFound
+		return entity.Event{}, errs.ErrUserNotFound
 	}
 
-	for uid, event := range userEvents {
-		if event.Date.Equal(date) {
-			events[uid] = event
-		}
+	event, ok := user.get(eventUID)
+	if !ok {
+		return entity.Event{}, errs.ErrEventNotFound
 	}
-	r.mu.RUnlock()
 
-	return events, nil
+	return event, nil
 }
 
-// GetEventsForWeek -.
-func (r *EventsRepo) GetEventsForWeek(ctx context.Context, userID int, date time.Time) (map[uuid.UUID]entity.Event, error) {
-	events := make(map[uuid.UUID]entity.Event)
-
+// GetAll -.
+func (r *EventsRepo) GetAll(ctx context.Context, userID int) (map[uuid.UUID]entity.Event, error) {
 	r.mu.RLock()
-	userEvents, ok := r.storage[userID]
+	defer r.mu.RUnlock()
+
+	user, ok := r.user(userID)
 	if !ok {
 		return nil, errs.ErrUserNotFound
 	}
 
-	targetYear, targetWeek := date.ISOWeek()
+	return user.all(), nil
+}
 
-	for uid, event := range userEvents {
-		actualYear, actualWeek := event.Date.ISOWeek()
+// GetEventsForDay -.
+func (r *EventsRepo) GetEventsForDay(ctx context.Context, userID int, d time.Time) (map[uuid.UUID]entity.Event, error) {
+	from, to := date.DayRange(d)
 
-		if actualYear == targetYear && actualWeek == targetWeek {
-			events[uid] = event
-		}
-	}
-	r.mu.RUnlock()
+	return r.getEvents(userID, from, to)
+}
+
+// GetEventsForWeek -.
+func (r *EventsRepo) GetEventsForWeek(ctx context.Context, userID int, d time.Time) (map[uuid.UUID]entity.Event, error) {
+	from, to := date.WeekRange(d)
 
-	return events, nil
+	return r.getEvents(userID, from, to)
 }
 
 // GetEventsForMonth -.
-func (r *EventsRepo) GetEventsForMonth(ctx context.Context, userID int, date time.Time) (map[uuid.UUID]entity.Event, error) {
-	events := make(map[uuid.UUID]entity.Event)
+func (r *EventsRepo) GetEventsForMonth(ctx context.Context, userID int, d time.Time) (map[uuid.UUID]entity.Event, error) {
+	from, to := date.MonthRange(d)
 
+	return r.getEvents(userID, from, to)
+}
+
+// GetEventsForRange -.
+func (r *EventsRepo) GetEventsForRange(ctx context.Context, userID int, from, to time.Time,
+	page entity.PageRequest,
+) (entity.EventsPage, error) {
 	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	userEvents, ok := r.storage[userID]
+	user, ok := r.user(userID)
 	if !ok {
-		return nil, errs.ErrUserNotFound
+		return entity.EventsPage{}, errs.ErrUserNotFound
 	}
 
-	targetYear, targetMonth, _ := date.Date()
+	return user.page(from, to, page), nil
+}
 
-	for uid, event := range userEvents {
-		actualYear, actualMonth, _ := event.Date.Date()
-		if actualYear == targetYear && actualMonth == targetMonth {
-			events[uid] = event
-		}
+// getEvents returns events of the user overlapping [from, to).
+func (r *EventsRepo) getEvents(userID int, from, to time.Time) (map[uuid.UUID]entity.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.user(userID)
+	if !ok {
+		return nil, errs.ErrUserNotFound
 	}
-	r.mu.RUnlock()
 
-	return events, nil
+	return user.between(from, to), nil
 }
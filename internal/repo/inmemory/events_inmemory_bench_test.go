@@ -0,0 +1,100 @@
+package inmemory_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/repo/inmemory"
+	"github.com/andreyxaxa/calendar/internal/repo/repotest"
+	"github.com/andreyxaxa/calendar/pkg/types/date"
+	"github.com/google/uuid"
+)
+
+// scanEvents is the previous implementation: every query scans all events of the user.
+type scanEvents map[uuid.UUID]entity.Event
+
+func (s scanEvents) between(from, to time.Time) map[uuid.UUID]entity.Event {
+	events := make(map[uuid.UUID]entity.Event)
+
+	for uid, event := range s {
+		if event.Overlaps(from, to) {
+			events[uid] = event
+		}
+	}
+
+	return events
+}
+
+// benchEvents spreads n events over three years.
+func benchEvents(n int) scanEvents {
+	rnd := rand.New(rand.NewSource(1))
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := make(scanEvents, n)
+
+	for range n {
+		day := start.AddDate(0, 0, rnd.Intn(3*365))
+		events[uuid.New()] = entity.Event{
+			Start: day,
+			End:   day.Add(time.Hour),
+			Text:  "event",
+		}
+	}
+
+	return events
+}
+
+func BenchmarkGetEvents(b *testing.B) {
+	ctx := context.Background()
+	userID := 1
+	d := time.Date(2026, 6, 17, 0, 0, 0, 0, time.UTC)
+
+	periods := []struct {
+		name  string
+		bound func(time.Time) (time.Time, time.Time)
+		query func(*inmemory.EventsRepo, context.Context, int, time.Time) (map[uuid.UUID]entity.Event, error)
+	}{
+		{"day", date.DayRange, (*inmemory.EventsRepo).GetEventsForDay},
+		{"week", date.WeekRange, (*inmemory.EventsRepo).GetEventsForWeek},
+		{"month", date.MonthRange, (*inmemory.EventsRepo).GetEventsForMonth},
+	}
+
+	for _, n := range []int{10_000, 100_000} {
+		events := benchEvents(n)
+
+		repo := inmemory.New()
+		for uid, event := range events {
+			if err := repo.Create(ctx, userID, uid, event); err != nil {
+				b.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		for _, p := range periods {
+			b.Run(fmt.Sprintf("%s/scan/%d", p.name, n), func(b *testing.B) {
+				from, to := p.bound(d)
+
+				for b.Loop() {
+					events.between(from, to)
+				}
+			})
+
+			b.Run(fmt.Sprintf("%s/index/%d", p.name, n), func(b *testing.B) {
+				for b.Loop() {
+					if _, err := p.query(repo, ctx, userID, d); err != nil {
+						b.Fatalf("unexpected error: %v", err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkWrites(b *testing.B) {
+	repotest.BenchmarkWrites(b, func(b *testing.B) repo.EventsRepo {
+		return inmemory.New()
+	})
+}
@@ -0,0 +1,157 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+)
+
+const (
+	_idempotencyJournalFile  = "idempotency.log"
+	_idempotencySnapshotFile = "idempotency.json"
+)
+
+// IdempotencyRepo -.
+type IdempotencyRepo struct {
+	keys map[string]entity.IdempotencyKey
+	// sweepAt - when keys nobody retried are dropped next, they expire on lookup until then.
+	sweepAt time.Time
+	mu      sync.Mutex
+
+	// journal is nil unless the repo was opened with OpenIdempotencyRepo.
+	journal *journal
+}
+
+// NewIdempotencyRepo returns new IdempotencyRepo(struct)
+func NewIdempotencyRepo() *IdempotencyRepo {
+	return &IdempotencyRepo{
+		keys: make(map[string]entity.IdempotencyKey),
+	}
+}
+
+// OpenIdempotencyRepo returns IdempotencyRepo persisted to dir, the way Open persists events:
+// its journal and snapshot have their own files there.
+func OpenIdempotencyRepo(dir string) (*IdempotencyRepo, error) {
+	r := NewIdempotencyRepo()
+
+	j, err := openLog(dir, _idempotencyJournalFile, _idempotencySnapshotFile, &r.keys, r.apply)
+	if err != nil {
+		return nil, fmt.Errorf("IdempotencyRepo - OpenIdempotencyRepo - openLog: %w", err)
+	}
+
+	r.journal = j
+
+	return r, nil
+}
+
+// Close -.
+func (r *IdempotencyRepo) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.journal == nil {
+		return nil
+	}
+
+	return r.journal.close()
+}
+
+// Reserve -.
+func (r *IdempotencyRepo) Reserve(ctx context.Context, key entity.IdempotencyKey, now time.Time) (entity.IdempotencyKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, ok := r.keys[key.Key]; ok && stored.ExpiresAt.After(now) {
+		return stored, errs.ErrAlreadyExists
+	}
+
+	// a sweep per TTL rather than per request: keys of this one expire by then.
+	if !now.Before(r.sweepAt) {
+		for k, stored := range r.keys {
+			if !stored.ExpiresAt.After(now) {
+				delete(r.keys, k)
+			}
+		}
+
+		r.sweepAt = key.ExpiresAt
+	}
+
+	key.Response = nil
+	rec := record{Op: opKey, IdempotencyKey: &key}
+
+	if err := r.persist(rec); err != nil {
+		return entity.IdempotencyKey{}, fmt.Errorf("IdempotencyRepo - Reserve - r.persist: %w", err)
+	}
+
+	r.apply(rec)
+
+	return key, nil
+}
+
+// Complete -.
+func (r *IdempotencyRepo) Complete(ctx context.Context, key string, response entity.StoredResponse) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.keys[key]
+	if !ok {
+		return nil
+	}
+
+	stored.Response = &response
+	rec := record{Op: opKey, IdempotencyKey: &stored}
+
+	if err := r.persist(rec); err != nil {
+		return fmt.Errorf("IdempotencyRepo - Complete - r.persist: %w", err)
+	}
+
+	r.apply(rec)
+
+	return nil
+}
+
+// Release -.
+func (r *IdempotencyRepo) Release(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.keys[key]; !ok {
+		return nil
+	}
+
+	rec := record{Op: opRelease, IdempotencyKey: &entity.IdempotencyKey{Key: key}}
+
+	if err := r.persist(rec); err != nil {
+		return fmt.Errorf("IdempotencyRepo - Release - r.persist: %w", err)
+	}
+
+	r.apply(rec)
+
+	return nil
+}
+
+// persist journals rec. Must be called with r.mu held, before rec is applied.
+func (r *IdempotencyRepo) persist(rec record) error {
+	if r.journal == nil {
+		return nil
+	}
+
+	if err := r.journal.write(rec, r.keys, _defaultSnapshotEvery); err != nil {
+		return fmt.Errorf("r.journal.write: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepo) apply(rec record) {
+	switch rec.Op {
+	case opKey:
+		r.keys[rec.IdempotencyKey.Key] = *rec.IdempotencyKey
+	case opRelease:
+		delete(r.keys, rec.IdempotencyKey.Key)
+	}
+}
@@ -0,0 +1,84 @@
+package inmemory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/repo/inmemory"
+	"github.com/andreyxaxa/calendar/internal/repo/repotest"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+)
+
+func TestIdempotencyRepo(t *testing.T) {
+	repotest.Idempotency(t, func(t *testing.T) repo.IdempotencyRepo {
+		return inmemory.NewIdempotencyRepo()
+	})
+}
+
+func TestIdempotencyRepoJournaled(t *testing.T) {
+	repotest.Idempotency(t, func(t *testing.T) repo.IdempotencyRepo {
+		r, err := inmemory.OpenIdempotencyRepo(t.TempDir())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		t.Cleanup(func() { r.Close() })
+
+		return r
+	})
+}
+
+func TestIdempotencyKeysJournal(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	now := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	completed := entity.IdempotencyKey{Key: "retry-1", Fingerprint: "create", ExpiresAt: now.Add(time.Hour)}
+	released := entity.IdempotencyKey{Key: "retry-2", Fingerprint: "create", ExpiresAt: now.Add(time.Hour)}
+	response := entity.StoredResponse{Status: 201, Body: []byte(`{}`)}
+
+	repo, err := inmemory.OpenIdempotencyRepo(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, key := range []entity.IdempotencyKey{completed, released} {
+		if _, err = repo.Reserve(ctx, key, now); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err = repo.Complete(ctx, completed.Key, response); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err = repo.Release(ctx, released.Key); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err = repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo, err = inmemory.OpenIdempotencyRepo(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer repo.Close()
+
+	stored, err := repo.Reserve(ctx, completed, now.Add(time.Minute))
+	if !errors.Is(err, errs.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+
+	if stored.Response == nil || stored.Response.Status != response.Status {
+		t.Fatalf("expected the stored response, got %+v", stored.Response)
+	}
+
+	if _, err = repo.Reserve(ctx, released, now.Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,465 @@
+package inmemory
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/google/btree"
+	"github.com/google/uuid"
+)
+
+const (
+	_btreeDegree = 32
+	// _floatingSlack widens index scans so all-day events, stored at UTC
+	// midnight, are found for periods in any time zone.
+	_floatingSlack = 24 * time.Hour
+)
+
+// dateKey orders index entries by start, uid breaks ties.
+type dateKey struct {
+	date time.Time
+	uid  uuid.UUID
+}
+
+// change - the latest change of the event uid, numbered seq.
+type change struct {
+	seq     int64
+	uid     uuid.UUID
+	deleted bool
+}
+
+func lessChange(a, b change) bool {
+	return a.seq < b.seq
+}
+
+func lessDateKey(a, b dateKey) bool {
+	if !a.date.Equal(b.date) {
+		return a.date.Before(b.date)
+	}
+
+	return bytes.Compare(a.uid[:], b.uid[:]) < 0
+}
+
+// userEvents keeps events of a single user together with a B-tree ordered by
+// start, so period queries cost O(log n + k) instead of a full scan.
+// Recurring events are kept out of the tree: a series may overlap any period
+// after its start, so they are checked one by one against their series end.
+type userEvents struct {
+	timeZone string
+
+	byUID  map[uuid.UUID]entity.Event
+	byDate *btree.BTreeG[dateKey]
+	// maxSpan is the longest duration ever stored: events starting up to
+	// maxSpan before a period may still overlap it.
+	maxSpan time.Duration
+	// recurring maps series to the time they span.
+	recurring map[uuid.UUID]seriesSpan
+
+	// trash keeps deleted events out of the index until they are restored or purged.
+	trash map[uuid.UUID]trashedEvent
+	// revisions of events, appended only.
+	revisions map[uuid.UUID][]entity.Revision
+
+	// changes is the change sequence, changeSeq maps events to their latest change
+	// and seq is the number of the last one.
+	changes   *btree.BTreeG[change]
+	changeSeq map[uuid.UUID]int64
+	seq       int64
+}
+
+// trashedEvent - an event deleted at DeletedAt.
+type trashedEvent struct {
+	DeletedAt time.Time    `json:"deleted_at"`
+	Event     entity.Event `json:"event"`
+}
+
+// seriesSpan - from the start of the earliest instance to the end of the last,
+// end is zero for endless series.
+type seriesSpan struct {
+	start, end time.Time
+}
+
+func newUserEvents() *userEvents {
+	return &userEvents{
+		timeZone:  entity.DefaultTimeZone,
+		byUID:     make(map[uuid.UUID]entity.Event),
+		byDate:    btree.NewG(_btreeDegree, lessDateKey),
+		recurring: make(map[uuid.UUID]seriesSpan),
+		trash:     make(map[uuid.UUID]trashedEvent),
+		revisions: make(map[uuid.UUID][]entity.Revision),
+		changes:   btree.NewG(_btreeDegree, lessChange),
+		changeSeq: make(map[uuid.UUID]int64),
+	}
+}
+
+// saved returns a function putting the entries of the event uid back as they are now, with the
+// counters of u. A failed transaction undoes its writes with it.
+func (u *userEvents) saved(uid uuid.UUID) func() {
+	event, stored := u.byUID[uid]
+	trashed, inTrash := u.trash[uid]
+	revisions, revised := u.revisions[uid]
+	seq, changed := u.changeSeq[uid]
+	latest, _ := u.changes.Get(change{seq: seq})
+	lastSeq, maxSpan := u.seq, u.maxSpan
+
+	return func() {
+		u.unindex(uid)
+		delete(u.byUID, uid)
+
+		if current, ok := u.changeSeq[uid]; ok {
+			u.changes.Delete(change{seq: current})
+			delete(u.changeSeq, uid)
+		}
+
+		if stored {
+			u.byUID[uid] = event
+			u.index(uid, event)
+		}
+
+		if changed {
+			u.changeSeq[uid] = seq
+			u.changes.ReplaceOrInsert(latest)
+		}
+
+		restore(u.trash, uid, trashed, inTrash)
+		restore(u.revisions, uid, revisions, revised)
+		u.seq, u.maxSpan = lastSeq, maxSpan
+	}
+}
+
+// savedTrash returns a function putting the trash back as it is now.
+func (u *userEvents) savedTrash() func() {
+	trash := maps.Clone(u.trash)
+
+	return func() {
+		u.trash = trash
+	}
+}
+
+// restore sets m[k] to v if ok, deletes it otherwise.
+func restore[K comparable, V any](m map[K]V, k K, v V, ok bool) {
+	if ok {
+		m[k] = v
+	} else {
+		delete(m, k)
+	}
+}
+
+func (u *userEvents) get(uid uuid.UUID) (entity.Event, bool) {
+	event, ok := u.byUID[uid]
+
+	return event.Clone(), ok
+}
+
+// put inserts or replaces the event, moving its index entry if the start changed.
+func (u *userEvents) put(uid uuid.UUID, event entity.Event) {
+	u.unindex(uid)
+	u.byUID[uid] = event.Clone()
+	u.touch(uid, false)
+	u.index(uid, event)
+}
+
+// index adds the stored event to the tree, or a series to recurring.
+func (u *userEvents) index(uid uuid.UUID, event entity.Event) {
+	if event.Recurring() {
+		// an invalid rule leaves the series endless, the usecase reports it on expansion.
+		end, _ := event.SeriesEnd()
+		u.recurring[uid] = seriesSpan{start: event.SeriesStart(), end: end}
+
+		return
+	}
+
+	u.byDate.ReplaceOrInsert(dateKey{date: event.Start, uid: uid})
+	u.maxSpan = max(u.maxSpan, event.End.Sub(event.Start))
+}
+
+// remove deletes the event, leaving its tombstone in the change sequence.
+func (u *userEvents) remove(uid uuid.UUID) {
+	if _, ok := u.byUID[uid]; !ok {
+		return
+	}
+
+	u.unindex(uid)
+	delete(u.byUID, uid)
+	u.touch(uid, true)
+}
+
+// touch numbers the next change, the write of the event, replacing its previous one.
+func (u *userEvents) touch(uid uuid.UUID, deleted bool) {
+	if seq, ok := u.changeSeq[uid]; ok {
+		u.changes.Delete(change{seq: seq})
+	}
+
+	u.seq++
+	u.changeSeq[uid] = u.seq
+	u.changes.ReplaceOrInsert(change{seq: u.seq, uid: uid, deleted: deleted})
+}
+
+// changesAfter returns up to limit changes numbered after the position.
+func (u *userEvents) changesAfter(after int64, limit int) []entity.Change {
+	changes := make([]entity.Change, 0)
+
+	u.changes.AscendGreaterOrEqual(change{seq: after + 1}, func(c change) bool {
+		if len(changes) == limit {
+			return false
+		}
+
+		ch := entity.Change{UID: c.uid, Seq: c.seq, Deleted: c.deleted}
+		if !c.deleted {
+			ch.Event = u.byUID[c.uid].Clone()
+		}
+
+		changes = append(changes, ch)
+
+		return true
+	})
+
+	return changes
+}
+
+// moveToTrash removes the event, keeping it in the trash.
+func (u *userEvents) moveToTrash(uid uuid.UUID, deletedAt time.Time) {
+	event, ok := u.byUID[uid]
+	if !ok {
+		return
+	}
+
+	u.remove(uid)
+	u.trash[uid] = trashedEvent{DeletedAt: deletedAt, Event: event}
+}
+
+// restore puts the event back in place of the trashed one.
+func (u *userEvents) restore(uid uuid.UUID, event entity.Event) {
+	delete(u.trash, uid)
+	u.put(uid, event)
+}
+
+// purge deletes events trashed before the time and returns their number.
+func (u *userEvents) purge(before time.Time) int64 {
+	var n int64
+
+	for uid, trashed := range u.trash {
+		if trashed.DeletedAt.Before(before) {
+			delete(u.trash, uid)
+			n++
+		}
+	}
+
+	return n
+}
+
+func (u *userEvents) trashed() []entity.TrashedEvent {
+	events := make([]entity.TrashedEvent, 0, len(u.trash))
+
+	for uid, trashed := range u.trash {
+		events = append(events, entity.TrashedEvent{
+			UID:       uid,
+			DeletedAt: trashed.DeletedAt,
+			Event:     trashed.Event.Clone(),
+		})
+	}
+
+	return events
+}
+
+// addRevision appends the revision, numbering it next to the last one of the event.
+func (u *userEvents) addRevision(uid uuid.UUID, revision entity.Revision) {
+	revisions := u.revisions[uid]
+	revision.Number = int64(len(revisions)) + 1
+	revision.Before, revision.After = cloneEvent(revision.Before), cloneEvent(revision.After)
+
+	// clipped, so a saved history never shares the appended revision.
+	u.revisions[uid] = append(slices.Clip(revisions), revision)
+}
+
+func (u *userEvents) unindex(uid uuid.UUID) {
+	old, ok := u.byUID[uid]
+	if !ok {
+		return
+	}
+
+	u.byDate.Delete(dateKey{date: old.Start, uid: uid})
+	delete(u.recurring, uid)
+}
+
+func (u *userEvents) all() map[uuid.UUID]entity.Event {
+	events := make(map[uuid.UUID]entity.Event, len(u.byUID))
+
+	for uid, event := range u.byUID {
+		events[uid] = event.Clone()
+	}
+
+	return events
+}
+
+// between returns events overlapping [from, to) and series that may have
+// instances there, see entity.Event.Occurrences.
+func (u *userEvents) between(from, to time.Time) map[uuid.UUID]entity.Event {
+	events := u.series(from, to)
+
+	lower := dateKey{date: from.Add(-u.maxSpan - _floatingSlack)}
+	upper := dateKey{date: to.Add(_floatingSlack)}
+
+	u.byDate.AscendRange(lower, upper, func(k dateKey) bool {
+		if event := u.byUID[k.uid]; event.Overlaps(from, to) {
+			events[k.uid] = event.Clone()
+		}
+
+		return true
+	})
+
+	return events
+}
+
+// series returns series that may have instances in [from, to).
+func (u *userEvents) series(from, to time.Time) map[uuid.UUID]entity.Event {
+	series := make(map[uuid.UUID]entity.Event)
+
+	for uid, span := range u.recurring {
+		if !span.start.Before(to.Add(_floatingSlack)) {
+			continue
+		}
+
+		if span.end.IsZero() || span.end.After(from.Add(-_floatingSlack)) {
+			series[uid] = u.byUID[uid].Clone()
+		}
+	}
+
+	return series
+}
+
+// page returns up to page.Limit events overlapping [from, to) following page.After, as they are ordered
+// in the tree, and series that may have instances there.
+func (u *userEvents) page(from, to time.Time, page entity.PageRequest) entity.EventsPage {
+	res := entity.EventsPage{
+		Events: make([]entity.Occurrence, 0, page.Limit),
+		Series: u.series(from, to),
+	}
+
+	lower := dateKey{date: from.Add(-u.maxSpan - _floatingSlack)}
+	upper := dateKey{date: to.Add(_floatingSlack)}
+
+	var after *dateKey
+	if page.After != nil {
+		after = &dateKey{date: page.After.Start, uid: page.After.UID}
+	}
+
+	collect := func(k dateKey) bool {
+		if len(res.Events) == page.Limit {
+			return false
+		}
+
+		if after != nil && k == *after {
+			return true
+		}
+
+		if event := u.byUID[k.uid]; event.Overlaps(from, to) {
+			res.Events = append(res.Events, entity.Occurrence{UID: k.uid, Event: event.Clone()})
+		}
+
+		return true
+	}
+
+	if page.Desc {
+		if after != nil && lessDateKey(*after, upper) {
+			upper = *after
+		}
+
+		u.byDate.DescendRange(upper, lower, collect)
+
+		return res
+	}
+
+	if after != nil && lessDateKey(lower, *after) {
+		lower = *after
+	}
+
+	u.byDate.AscendRange(lower, upper, collect)
+
+	return res
+}
+
+// userSnapshot is how userEvents is stored in snapshots, the index is rebuilt on load.
+type userSnapshot struct {
+	TimeZone  string                          `json:"tz"`
+	Events    map[uuid.UUID]entity.Event      `json:"events"`
+	Trash     map[uuid.UUID]trashedEvent      `json:"trash,omitempty"`
+	Revisions map[uuid.UUID][]entity.Revision `json:"revisions,omitempty"`
+	Changes   map[uuid.UUID]changeSnapshot    `json:"changes,omitempty"`
+	Seq       int64                           `json:"seq,omitempty"`
+}
+
+// changeSnapshot is how the latest change of an event is stored in snapshots.
+type changeSnapshot struct {
+	Seq     int64 `json:"seq"`
+	Deleted bool  `json:"deleted,omitempty"`
+}
+
+// MarshalJSON -.
+func (u *userEvents) MarshalJSON() ([]byte, error) {
+	changes := make(map[uuid.UUID]changeSnapshot, u.changes.Len())
+
+	u.changes.Ascend(func(c change) bool {
+		changes[c.uid] = changeSnapshot{Seq: c.seq, Deleted: c.deleted}
+
+		return true
+	})
+
+	return json.Marshal(userSnapshot{
+		TimeZone:  u.timeZone,
+		Events:    u.byUID,
+		Trash:     u.trash,
+		Revisions: u.revisions,
+		Changes:   changes,
+		Seq:       u.seq,
+	})
+}
+
+// UnmarshalJSON -.
+func (u *userEvents) UnmarshalJSON(b []byte) error {
+	var snapshot userSnapshot
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return err
+	}
+
+	*u = *newUserEvents()
+	u.timeZone = snapshot.TimeZone
+
+	for uid, event := range snapshot.Events {
+		u.put(uid, event)
+	}
+
+	for uid, trashed := range snapshot.Trash {
+		u.trash[uid] = trashed
+	}
+
+	for uid, revisions := range snapshot.Revisions {
+		u.revisions[uid] = revisions
+	}
+
+	// snapshots taken before the change sequence number the events and tombstones of the trash anew.
+	if snapshot.Changes == nil {
+		for uid := range snapshot.Trash {
+			if _, ok := u.byUID[uid]; !ok {
+				u.touch(uid, true)
+			}
+		}
+
+		return nil
+	}
+
+	u.changes.Clear(false)
+	clear(u.changeSeq)
+	u.seq = snapshot.Seq
+
+	for uid, c := range snapshot.Changes {
+		u.changeSeq[uid] = c.Seq
+		u.changes.ReplaceOrInsert(change{seq: c.Seq, uid: uid, deleted: c.Deleted})
+	}
+
+	return nil
+}
@@ -0,0 +1,408 @@
+package inmemory
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/google/uuid"
+)
+
+const (
+	_journalFile  = "journal.log"
+	_snapshotFile = "snapshot.json"
+
+	// _headerSize is length(uint32) + crc32(uint32) in front of every record.
+	_headerSize = 8
+	// _maxRecordSize guards replay against garbage lengths in a damaged header.
+	_maxRecordSize = 16 << 20
+)
+
+type op string
+
+const (
+	opPut op = "put"
+	// opDelete is replayed from journals written before events were moved to the trash.
+	opDelete op = "delete"
+	opUser   op = "user"
+	// opTrash moves the event to the trash at Time, opRestore puts Event back from it.
+	opTrash   op = "trash"
+	opRestore op = "restore"
+	// opPurge deletes events of all users trashed before Time.
+	opPurge op = "purge"
+	// opRevision appends Revision to the history of the event.
+	opRevision op = "revision"
+	// opTx holds the records of a transaction, applied all at once.
+	opTx op = "tx"
+
+	// opKey puts IdempotencyKey, reserved or completed, opRelease deletes it.
+	opKey     op = "key"
+	opRelease op = "release"
+
+	// opWebhook creates Webhook, opDeleteWebhook deletes it with its deliveries.
+	opWebhook       op = "webhook"
+	opDeleteWebhook op = "delete_webhook"
+	// opDeliveries adds Deliveries, opAttempt adds Attempt, numbered by Seq, to the only one of them.
+	opDeliveries op = "deliveries"
+	opAttempt    op = "attempt"
+)
+
+// record is a single journaled mutation. Create and Update are both
+// journaled as a put of the resulting event. Seq is the change of the event the
+// record makes and Revision is numbered, so replay skips what the snapshot holds.
+type record struct {
+	Op     op           `json:"op"`
+	UserID int          `json:"user_id"`
+	UID    uuid.UUID    `json:"uid"`
+	Event  entity.Event `json:"event"`
+	User   entity.User  `json:"user"`
+	Time   time.Time    `json:"time,omitzero"`
+	Seq    int64        `json:"seq,omitempty"`
+
+	Revision *entity.Revision `json:"revision,omitempty"`
+
+	IdempotencyKey *entity.IdempotencyKey `json:"idempotency_key,omitempty"`
+
+	Webhook    *entity.Webhook         `json:"webhook,omitempty"`
+	Deliveries []entity.Delivery       `json:"deliveries,omitempty"`
+	Attempt    *entity.DeliveryAttempt `json:"attempt,omitempty"`
+
+	Records []record `json:"records,omitempty"`
+}
+
+var _crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// journal is an append-only log of mutations plus a snapshot compacting it.
+type journal struct {
+	dir          string
+	snapshotFile string
+	file         *os.File
+	// size is the length of the valid part of file.
+	size int64
+	// records counts records appended since the last snapshot.
+	records int
+}
+
+// openJournal restores storage from the snapshot and journal in dir.
+func openJournal(dir string) (*journal, map[int]*userEvents, error) {
+	storage := make(map[int]*userEvents)
+
+	j, err := openLog(dir, _journalFile, _snapshotFile, &storage, func(rec record) {
+		apply(storage, rec)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return j, storage, nil
+}
+
+// openLog restores state, a pointer to what snapshots hold, from the snapshot and journal in dir:
+// fn applies the journaled records. A damaged tail of the journal (torn write, checksum mismatch)
+// is truncated.
+func openLog(dir, journalFile, snapshotFile string, state any, fn func(rec record)) (*journal, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("os.MkdirAll: %w", err)
+	}
+
+	if err := loadSnapshot(filepath.Join(dir, snapshotFile), state); err != nil {
+		return nil, fmt.Errorf("loadSnapshot: %w", err)
+	}
+
+	file, err := os.OpenFile(filepath.Join(dir, journalFile), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("os.OpenFile: %w", err)
+	}
+
+	j := &journal{
+		dir:          dir,
+		snapshotFile: snapshotFile,
+		file:         file,
+	}
+
+	if err = j.replay(fn); err != nil {
+		file.Close()
+
+		return nil, fmt.Errorf("j.replay: %w", err)
+	}
+
+	return j, nil
+}
+
+func loadSnapshot(path string, state any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, state)
+}
+
+// replay applies all valid records with fn and leaves the file positioned
+// at the end of the last one.
+func (j *journal) replay(fn func(rec record)) error {
+	reader := bufio.NewReader(j.file)
+	header := make([]byte, _headerSize)
+
+	for {
+		rec, n, ok := readRecord(reader, header)
+		if !ok {
+			break
+		}
+
+		fn(rec)
+
+		j.size += n
+		j.records++
+	}
+
+	return j.truncate()
+}
+
+// readRecord returns false on EOF as well as on a damaged record.
+func readRecord(r io.Reader, header []byte) (record, int64, bool) {
+	var rec record
+
+	if _, err := io.ReadFull(r, header); err != nil {
+		return rec, 0, false
+	}
+
+	length := binary.BigEndian.Uint32(header[:4])
+	sum := binary.BigEndian.Uint32(header[4:])
+
+	if length > _maxRecordSize {
+		return rec, 0, false
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return rec, 0, false
+	}
+
+	if crc32.Checksum(payload, _crcTable) != sum {
+		return rec, 0, false
+	}
+
+	if err := json.Unmarshal(payload, &rec); err != nil {
+		return rec, 0, false
+	}
+
+	return rec, int64(_headerSize + length), true
+}
+
+// write journals rec, replacing the journal with a snapshot of state first once it holds every records.
+func (j *journal) write(rec record, state any, every int) error {
+	if err := j.compact(state, every); err != nil {
+		return err
+	}
+
+	if err := j.append(rec); err != nil {
+		return fmt.Errorf("j.append: %w", err)
+	}
+
+	return nil
+}
+
+// compact replaces the journal with a snapshot of state once it holds every records.
+func (j *journal) compact(state any, every int) error {
+	if j.records < every {
+		return nil
+	}
+
+	if err := j.snapshot(state); err != nil {
+		return fmt.Errorf("j.snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// append durably writes rec. On failure the journal is truncated back to its
+// previous size, so a torn record never precedes valid ones.
+func (j *journal) append(rec record) error {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	buf := make([]byte, _headerSize+len(payload))
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:_headerSize], crc32.Checksum(payload, _crcTable))
+	copy(buf[_headerSize:], payload)
+
+	if _, err = j.file.Write(buf); err != nil {
+		return errors.Join(fmt.Errorf("j.file.Write: %w", err), j.truncate())
+	}
+
+	if err = j.file.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("j.file.Sync: %w", err), j.truncate())
+	}
+
+	j.size += int64(len(buf))
+	j.records++
+
+	return nil
+}
+
+// truncate drops everything past the valid part of the journal.
+func (j *journal) truncate() error {
+	if err := j.file.Truncate(j.size); err != nil {
+		return fmt.Errorf("j.file.Truncate: %w", err)
+	}
+
+	if _, err := j.file.Seek(j.size, io.SeekStart); err != nil {
+		return fmt.Errorf("j.file.Seek: %w", err)
+	}
+
+	return nil
+}
+
+// snapshot atomically replaces the snapshot with state and empties the journal.
+// A crash in between means the journal is replayed over the new snapshot: records of
+// the events are numbered, so those it holds are skipped.
+func (j *journal) snapshot(state any) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(j.dir, j.snapshotFile+".*")
+	if err != nil {
+		return fmt.Errorf("os.CreateTemp: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("tmp.Write: %w", err)
+	}
+
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("tmp.Sync: %w", err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("tmp.Close: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), filepath.Join(j.dir, j.snapshotFile)); err != nil {
+		return fmt.Errorf("os.Rename: %w", err)
+	}
+
+	if err = syncDir(j.dir); err != nil {
+		return fmt.Errorf("syncDir: %w", err)
+	}
+
+	j.size = 0
+	j.records = 0
+
+	return j.truncate()
+}
+
+func (j *journal) close() error {
+	return j.file.Close()
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
+
+// number sets Seq and the revision number rec makes once applied to events of its user, nil if there are none.
+func number(user *userEvents, rec *record) {
+	var seq, revisions int64
+
+	if user != nil {
+		seq, revisions = user.seq, int64(len(user.revisions[rec.UID]))
+	}
+
+	switch rec.Op {
+	case opPut, opTrash, opRestore:
+		rec.Seq = seq + 1
+	case opRevision:
+		rec.Revision.Number = revisions + 1
+	}
+}
+
+// applied tells if storage already holds rec. Records of journals written before
+// they were numbered never are.
+func applied(storage map[int]*userEvents, rec record) bool {
+	user, ok := storage[rec.UserID]
+	if !ok {
+		return false
+	}
+
+	switch rec.Op {
+	case opPut, opDelete, opTrash, opRestore:
+		return rec.Seq != 0 && rec.Seq <= user.seq
+	case opRevision:
+		return rec.Revision.Number != 0 && rec.Revision.Number <= int64(len(user.revisions[rec.UID]))
+	default:
+		return false
+	}
+}
+
+func apply(storage map[int]*userEvents, rec record) {
+	if applied(storage, rec) {
+		return
+	}
+
+	switch rec.Op {
+	case opPut:
+		if _, ok := storage[rec.UserID]; !ok {
+			storage[rec.UserID] = newUserEvents()
+		}
+
+		storage[rec.UserID].put(rec.UID, rec.Event)
+	case opDelete:
+		if user, ok := storage[rec.UserID]; ok {
+			user.remove(rec.UID)
+		}
+	case opUser:
+		if _, ok := storage[rec.UserID]; !ok {
+			storage[rec.UserID] = newUserEvents()
+		}
+
+		storage[rec.UserID].timeZone = rec.User.TimeZone
+	case opTrash:
+		if user, ok := storage[rec.UserID]; ok {
+			user.moveToTrash(rec.UID, rec.Time)
+		}
+	case opRestore:
+		if user, ok := storage[rec.UserID]; ok {
+			user.restore(rec.UID, rec.Event)
+		}
+	case opPurge:
+		for _, user := range storage {
+			user.purge(rec.Time)
+		}
+	case opRevision:
+		if _, ok := storage[rec.UserID]; !ok {
+			storage[rec.UserID] = newUserEvents()
+		}
+
+		storage[rec.UserID].addRevision(rec.UID, *rec.Revision)
+	case opTx:
+		for _, r := range rec.Records {
+			apply(storage, r)
+		}
+	}
+}
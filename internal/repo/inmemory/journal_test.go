@@ -0,0 +1,463 @@
+package inmemory_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	eventsrepo "github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/repo/inmemory"
+	"github.com/google/uuid"
+)
+
+func openRepo(t *testing.T, dir string, opts ...inmemory.Option) *inmemory.EventsRepo {
+	t.Helper()
+
+	repo, err := inmemory.Open(dir, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return repo
+}
+
+func TestJournalReplay(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	userID := 1
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	kept, updated, deleted := uuid.New(), uuid.New(), uuid.New()
+
+	repo := openRepo(t, dir)
+
+	for _, uid := range []uuid.UUID{kept, updated, deleted} {
+		if err := repo.Create(ctx, userID, uid, entity.Event{Text: "old", Start: date, End: date}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := repo.Update(ctx, userID, updated, entity.Event{Text: "new", Start: date, End: date}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.Delete(ctx, userID, deleted, 0, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo = openRepo(t, dir)
+	defer repo.Close()
+
+	events, err := repo.GetEventsForDay(ctx, userID, date)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	if events[kept].Text != "old" {
+		t.Fatalf("expected %q, got %q", "old", events[kept].Text)
+	}
+
+	if events[updated].Text != "new" {
+		t.Fatalf("expected %q, got %q", "new", events[updated].Text)
+	}
+	if events[updated].Version != 2 {
+		t.Fatalf("expected version 2, got %d", events[updated].Version)
+	}
+}
+
+func TestJournalTx(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	userID := 1
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	committed, rolledBack := uuid.New(), uuid.New()
+
+	repo := openRepo(t, dir)
+
+	err := repo.WithinTx(ctx, func(tx eventsrepo.EventsRepo) error {
+		return tx.Create(ctx, userID, committed, entity.Event{Text: "committed", Start: date, End: date})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	errAbort := errors.New("abort")
+
+	err = repo.WithinTx(ctx, func(tx eventsrepo.EventsRepo) error {
+		if err := tx.Create(ctx, userID, rolledBack, entity.Event{Text: "rolled back", Start: date, End: date}); err != nil {
+			return err
+		}
+
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected errAbort, got %v", err)
+	}
+
+	if err = repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo = openRepo(t, dir)
+	defer repo.Close()
+
+	events, err := repo.GetEventsForDay(ctx, userID, date)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(events) != 1 || events[committed].Text != "committed" {
+		t.Fatalf("expected only the committed event, got %+v", events)
+	}
+}
+
+func TestJournalSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	userID := 1
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	repo := openRepo(t, dir, inmemory.SnapshotEvery(3))
+
+	for range 10 {
+		if err := repo.Create(ctx, userID, uuid.New(), entity.Event{Text: "event", Start: date, End: date}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "snapshot.json")); err != nil {
+		t.Fatalf("expected snapshot: %v", err)
+	}
+
+	repo = openRepo(t, dir)
+	defer repo.Close()
+
+	events, err := repo.GetEventsForDay(ctx, userID, date)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(events) != 10 {
+		t.Fatalf("expected 10 events, got %d", len(events))
+	}
+}
+
+func TestJournalTrash(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	userID := 1
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// deleted in this order an hour apart, the snapshot is taken before the last delete.
+	purged, trashed, restored := uuid.New(), uuid.New(), uuid.New()
+
+	repo := openRepo(t, dir, inmemory.SnapshotEvery(5))
+
+	for _, uid := range []uuid.UUID{purged, trashed, restored} {
+		if err := repo.Create(ctx, userID, uid, entity.Event{Text: "event", Start: date, End: date}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for i, uid := range []uuid.UUID{purged, trashed, restored} {
+		if err := repo.Delete(ctx, userID, uid, 0, date.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := repo.Restore(ctx, userID, restored); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := repo.PurgeTrash(ctx, date.Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo = openRepo(t, dir)
+	defer repo.Close()
+
+	events, err := repo.GetEventsForDay(ctx, userID, date)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := events[restored]; !ok || len(events) != 1 {
+		t.Fatalf("expected the restored event, got %v", events)
+	}
+
+	trash, err := repo.GetTrash(ctx, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(trash) != 1 || trash[0].UID != trashed || !trash[0].DeletedAt.Equal(date.Add(time.Hour)) {
+		t.Fatalf("unexpected trash %+v", trash)
+	}
+}
+
+func TestJournalRevisions(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	userID := 1
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	event := entity.Event{Text: "event", Start: date, End: date, Version: 1}
+	// the snapshot is taken after the first two revisions.
+	uid := uuid.New()
+
+	repo := openRepo(t, dir, inmemory.SnapshotEvery(3))
+
+	if err := repo.Create(ctx, userID, uid, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, revision := range []entity.Revision{
+		{Op: entity.RevisionCreate, Actor: "user:1", At: date, After: &event},
+		{Op: entity.RevisionUpdate, Actor: "alice", At: date, Before: &event, After: &event},
+		{Op: entity.RevisionDelete, Actor: "alice", At: date, Before: &event},
+	} {
+		if err := repo.AddRevision(ctx, userID, uid, revision); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo = openRepo(t, dir)
+	defer repo.Close()
+
+	revisions, err := repo.GetRevisions(ctx, userID, uid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(revisions) != 3 || revisions[1].Actor != "alice" || revisions[2].Number != 3 ||
+		revisions[2].After != nil || revisions[0].After.Text != event.Text {
+		t.Fatalf("unexpected revisions %+v", revisions)
+	}
+}
+
+func TestJournalChanges(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	userID := 1
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	event := entity.Event{Text: "event", Start: date, End: date}
+	// the snapshot is taken after the creates.
+	updated, deleted, kept := uuid.New(), uuid.New(), uuid.New()
+
+	repo := openRepo(t, dir, inmemory.SnapshotEvery(3))
+
+	for _, uid := range []uuid.UUID{updated, deleted, kept} {
+		if err := repo.Create(ctx, userID, uid, event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := repo.Update(ctx, userID, updated, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.Delete(ctx, userID, deleted, 0, date); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo = openRepo(t, dir)
+	defer repo.Close()
+
+	changes, seq, err := repo.GetChanges(ctx, userID, 0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if seq != 5 || len(changes) != 3 {
+		t.Fatalf("expected 3 changes at 5, got %+v at %d", changes, seq)
+	}
+
+	for i, want := range []entity.Change{{UID: kept, Seq: 3}, {UID: updated, Seq: 4}, {UID: deleted, Seq: 5, Deleted: true}} {
+		if got := changes[i]; got.UID != want.UID || got.Seq != want.Seq || got.Deleted != want.Deleted {
+			t.Fatalf("expected %+v, got %+v", want, got)
+		}
+	}
+}
+
+// TestJournalReplayedOverSnapshot - a crash after the snapshot replaced the old one leaves the
+// journal it compacted in place, which is replayed over it on every open until the next snapshot.
+func TestJournalReplayedOverSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	userID := 1
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	event := entity.Event{Text: "event", Start: date, End: date}
+	updated, deleted := uuid.New(), uuid.New()
+	path := filepath.Join(dir, "journal.log")
+
+	repo := openRepo(t, dir)
+
+	for _, uid := range []uuid.UUID{updated, deleted} {
+		if err := repo.Create(ctx, userID, uid, event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := repo.Update(ctx, userID, updated, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.AddRevision(ctx, userID, updated, entity.Revision{Op: entity.RevisionUpdate, At: date}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.Delete(ctx, userID, deleted, 0, date); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	compacted, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the next write snapshots the five records.
+	repo = openRepo(t, dir, inmemory.SnapshotEvery(5))
+
+	if err = repo.Create(ctx, userID, uuid.New(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err = repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err = os.WriteFile(path, compacted, 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for range 2 {
+		repo = openRepo(t, dir)
+
+		changes, seq, err := repo.GetChanges(ctx, userID, 0, 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if seq != 4 || len(changes) != 2 || changes[0].UID != updated || changes[1].UID != deleted {
+			t.Fatalf("expected 2 changes at 4, got %+v at %d", changes, seq)
+		}
+
+		revisions, err := repo.GetRevisions(ctx, userID, updated)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(revisions) != 1 {
+			t.Fatalf("expected 1 revision, got %+v", revisions)
+		}
+
+		trash, err := repo.GetTrash(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(trash) != 1 || trash[0].UID != deleted {
+			t.Fatalf("unexpected trash %+v", trash)
+		}
+
+		if err = repo.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestJournalCorruptedTail(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	userID := 1
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	first, second := uuid.New(), uuid.New()
+	path := filepath.Join(dir, "journal.log")
+
+	repo := openRepo(t, dir)
+
+	if err := repo.Create(ctx, userID, first, entity.Event{Text: "first", Start: date, End: date}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	validSize := info.Size()
+
+	if err = repo.Create(ctx, userID, second, entity.Event{Text: "second", Start: date, End: date}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err = repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// flip the last byte of the second record and append a torn header.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data[len(data)-1] ^= 0xff
+	data = append(data, 0, 0, 1)
+
+	if err = os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo = openRepo(t, dir)
+	defer repo.Close()
+
+	events, err := repo.GetEventsForDay(ctx, userID, date)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(events) != 1 || events[first].Text != "first" {
+		t.Fatalf("expected only the first event, got %v", events)
+	}
+
+	info, err = os.Stat(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Size() != validSize {
+		t.Fatalf("expected journal truncated to %d bytes, got %d", validSize, info.Size())
+	}
+
+	// writes continue after the truncated tail.
+	if err = repo.Create(ctx, userID, second, entity.Event{Text: "second", Start: date, End: date}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
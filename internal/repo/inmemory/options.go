@@ -0,0 +1,11 @@
+package inmemory
+
+// Option -.
+type Option func(*EventsRepo)
+
+// SnapshotEvery sets how many journal records trigger a snapshot compacting the journal.
+func SnapshotEvery(records int) Option {
+	return func(r *EventsRepo) {
+		r.snapshotEvery = records
+	}
+}
@@ -0,0 +1,96 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+// Restore -.
+func (r *EventsRepo) Restore(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.user(userID)
+	if !ok {
+		return entity.Event{}, errs.ErrUserNotFound
+	}
+
+	trashed, ok := user.trash[eventUID]
+	if !ok {
+		return entity.Event{}, errs.ErrEventNotFound
+	}
+
+	if _, ok = user.get(eventUID); ok {
+		return entity.Event{}, errs.ErrAlreadyExists
+	}
+
+	event := trashed.Event.Clone()
+	event.Version++
+
+	err := r.persist(record{Op: opRestore, UserID: userID, UID: eventUID, Event: event})
+	if err != nil {
+		return entity.Event{}, fmt.Errorf("EventsRepo - Restore - r.persist: %w", err)
+	}
+
+	r.writable(userID, eventUID).restore(eventUID, event)
+
+	return event, nil
+}
+
+// GetTrash -.
+func (r *EventsRepo) GetTrash(ctx context.Context, userID int) ([]entity.TrashedEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.user(userID)
+	if !ok {
+		return nil, errs.ErrUserNotFound
+	}
+
+	return user.trashed(), nil
+}
+
+// PurgeTrash -.
+func (r *EventsRepo) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var users []int
+
+	for userID, user := range r.storage {
+		for _, trashed := range user.trash {
+			if trashed.DeletedAt.Before(before) {
+				users = append(users, userID)
+
+				break
+			}
+		}
+	}
+
+	if len(users) == 0 {
+		return 0, nil
+	}
+
+	err := r.persist(record{Op: opPurge, Time: before})
+	if err != nil {
+		return 0, fmt.Errorf("EventsRepo - PurgeTrash - r.persist: %w", err)
+	}
+
+	var purged int64
+
+	for _, userID := range users {
+		user := r.storage[userID]
+		if r.tx != nil {
+			r.onRollback(user.savedTrash())
+		}
+
+		purged += user.purge(before)
+	}
+
+	return purged, nil
+}
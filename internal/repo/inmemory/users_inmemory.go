@@ -0,0 +1,53 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+)
+
+// UsersRepo - users share storage (and journal) with their events.
+type UsersRepo struct {
+	events *EventsRepo
+}
+
+// NewUsersRepo returns new UsersRepo(struct) on top of events storage
+func NewUsersRepo(events *EventsRepo) *UsersRepo {
+	return &UsersRepo{
+		events: events,
+	}
+}
+
+// Get -.
+func (r *UsersRepo) Get(ctx context.Context, userID int) (entity.User, error) {
+	r.events.mu.RLock()
+	defer r.events.mu.RUnlock()
+
+	user, ok := r.events.storage[userID]
+	if !ok {
+		return entity.User{}, errs.ErrUserNotFound
+	}
+
+	return entity.User{
+		ID:       userID,
+		TimeZone: user.timeZone,
+	}, nil
+}
+
+// Save -.
+func (r *UsersRepo) Save(ctx context.Context, user entity.User) error {
+	r.events.mu.Lock()
+	defer r.events.mu.Unlock()
+
+	rec := record{Op: opUser, UserID: user.ID, User: user}
+
+	if err := r.events.persist(rec); err != nil {
+		return fmt.Errorf("UsersRepo - Save - r.events.persist: %w", err)
+	}
+
+	apply(r.events.storage, rec)
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package inmemory_test
+
+import (
+	"testing"
+
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/repo/inmemory"
+	"github.com/andreyxaxa/calendar/internal/repo/repotest"
+)
+
+func TestUsersRepo(t *testing.T) {
+	repotest.Users(t, func(t *testing.T) repo.UsersRepo {
+		return inmemory.NewUsersRepo(inmemory.New())
+	})
+}
@@ -0,0 +1,287 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+const (
+	_webhooksJournalFile  = "webhooks.log"
+	_webhooksSnapshotFile = "webhooks.json"
+)
+
+// webhooksState is what snapshots of WebhooksRepo hold.
+type webhooksState struct {
+	Webhooks []entity.Webhook `json:"webhooks"`
+	// Deliveries are in the order they were added.
+	Deliveries []entity.Delivery `json:"deliveries"`
+}
+
+// WebhooksRepo -.
+type WebhooksRepo struct {
+	state webhooksState
+	mu    sync.Mutex
+
+	// journal is nil unless the repo was opened with OpenWebhooksRepo.
+	journal *journal
+}
+
+// NewWebhooksRepo returns new WebhooksRepo(struct)
+func NewWebhooksRepo() *WebhooksRepo {
+	return &WebhooksRepo{}
+}
+
+// OpenWebhooksRepo returns WebhooksRepo persisted to dir, the way OpenIdempotencyRepo persists keys.
+func OpenWebhooksRepo(dir string) (*WebhooksRepo, error) {
+	r := NewWebhooksRepo()
+
+	j, err := openLog(dir, _webhooksJournalFile, _webhooksSnapshotFile, &r.state, r.apply)
+	if err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - OpenWebhooksRepo - openLog: %w", err)
+	}
+
+	r.journal = j
+
+	return r, nil
+}
+
+// Close -.
+func (r *WebhooksRepo) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.journal == nil {
+		return nil
+	}
+
+	return r.journal.close()
+}
+
+// CreateWebhook -.
+func (r *WebhooksRepo) CreateWebhook(ctx context.Context, webhook entity.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook.Kinds = slices.Clone(webhook.Kinds)
+	rec := record{Op: opWebhook, Webhook: &webhook}
+
+	if err := r.persist(rec); err != nil {
+		return fmt.Errorf("WebhooksRepo - CreateWebhook - r.persist: %w", err)
+	}
+
+	r.apply(rec)
+
+	return nil
+}
+
+// GetWebhooks -.
+func (r *WebhooksRepo) GetWebhooks(ctx context.Context, userID int) ([]entity.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhooks := make([]entity.Webhook, 0)
+
+	for _, w := range r.state.Webhooks {
+		if w.UserID == userID {
+			w.Kinds = slices.Clone(w.Kinds)
+			webhooks = append(webhooks, w)
+		}
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook -.
+func (r *WebhooksRepo) DeleteWebhook(ctx context.Context, userID int, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.webhook(userID, id) < 0 {
+		return errs.ErrWebhookNotFound
+	}
+
+	rec := record{Op: opDeleteWebhook, Webhook: &entity.Webhook{ID: id, UserID: userID}}
+
+	if err := r.persist(rec); err != nil {
+		return fmt.Errorf("WebhooksRepo - DeleteWebhook - r.persist: %w", err)
+	}
+
+	r.apply(rec)
+
+	return nil
+}
+
+// AddDeliveries -.
+func (r *WebhooksRepo) AddDeliveries(ctx context.Context, deliveries []entity.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec := record{Op: opDeliveries, Deliveries: make([]entity.Delivery, 0, len(deliveries))}
+
+	for _, d := range deliveries {
+		rec.Deliveries = append(rec.Deliveries, cloneDelivery(d))
+	}
+
+	if err := r.persist(rec); err != nil {
+		return fmt.Errorf("WebhooksRepo - AddDeliveries - r.persist: %w", err)
+	}
+
+	r.apply(rec)
+
+	return nil
+}
+
+// GetDueDeliveries -.
+func (r *WebhooksRepo) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.DueDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	due := make([]entity.DueDelivery, 0)
+
+	for _, d := range r.state.Deliveries {
+		if d.Status != entity.DeliveryPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+
+		w := r.state.Webhooks[r.webhook(d.UserID, d.WebhookID)]
+		due = append(due, entity.DueDelivery{Delivery: cloneDelivery(d), URL: w.URL, Secret: w.Secret})
+	}
+
+	slices.SortStableFunc(due, func(a, b entity.DueDelivery) int {
+		return a.NextAttemptAt.Compare(b.NextAttemptAt)
+	})
+
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	return due, nil
+}
+
+// AddAttempt -.
+func (r *WebhooksRepo) AddAttempt(ctx context.Context, delivery entity.Delivery, attempt entity.DeliveryAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.delivery(delivery.ID)
+	// the webhook was deleted meanwhile.
+	if i < 0 {
+		return nil
+	}
+
+	rec := record{
+		Op: opAttempt,
+		// only the state the attempt leaves the delivery in is journaled.
+		Deliveries: []entity.Delivery{{ID: delivery.ID, Status: delivery.Status, NextAttemptAt: delivery.NextAttemptAt}},
+		Attempt:    &attempt,
+		Seq:        int64(len(r.state.Deliveries[i].Attempts) + 1),
+	}
+
+	if err := r.persist(rec); err != nil {
+		return fmt.Errorf("WebhooksRepo - AddAttempt - r.persist: %w", err)
+	}
+
+	r.apply(rec)
+
+	return nil
+}
+
+// GetDeliveries -.
+func (r *WebhooksRepo) GetDeliveries(ctx context.Context, userID int, webhookID uuid.UUID,
+	status entity.DeliveryStatus, limit int,
+) ([]entity.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.webhook(userID, webhookID) < 0 {
+		return nil, errs.ErrWebhookNotFound
+	}
+
+	deliveries := make([]entity.Delivery, 0)
+
+	for i := len(r.state.Deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		d := r.state.Deliveries[i]
+
+		if d.WebhookID == webhookID && (status == "" || d.Status == status) {
+			deliveries = append(deliveries, cloneDelivery(d))
+		}
+	}
+
+	return deliveries, nil
+}
+
+// webhook returns the index of the webhook of the user, -1 if there is none. Must be called with r.mu held.
+func (r *WebhooksRepo) webhook(userID int, id uuid.UUID) int {
+	return slices.IndexFunc(r.state.Webhooks, func(w entity.Webhook) bool {
+		return w.UserID == userID && w.ID == id
+	})
+}
+
+// delivery returns the index of the delivery, -1 if there is none. Must be called with r.mu held.
+func (r *WebhooksRepo) delivery(id uuid.UUID) int {
+	return slices.IndexFunc(r.state.Deliveries, func(d entity.Delivery) bool {
+		return d.ID == id
+	})
+}
+
+// persist journals rec. Must be called with r.mu held, before rec is applied.
+func (r *WebhooksRepo) persist(rec record) error {
+	if r.journal == nil {
+		return nil
+	}
+
+	if err := r.journal.write(rec, r.state, _defaultSnapshotEvery); err != nil {
+		return fmt.Errorf("r.journal.write: %w", err)
+	}
+
+	return nil
+}
+
+// apply skips what the state already holds: the journal is replayed over the snapshot
+// if a crash interrupted compaction.
+func (r *WebhooksRepo) apply(rec record) {
+	switch rec.Op {
+	case opWebhook:
+		if r.webhook(rec.Webhook.UserID, rec.Webhook.ID) < 0 {
+			r.state.Webhooks = append(r.state.Webhooks, *rec.Webhook)
+		}
+	case opDeleteWebhook:
+		if i := r.webhook(rec.Webhook.UserID, rec.Webhook.ID); i >= 0 {
+			r.state.Webhooks = slices.Delete(r.state.Webhooks, i, i+1)
+		}
+
+		r.state.Deliveries = slices.DeleteFunc(r.state.Deliveries, func(d entity.Delivery) bool {
+			return d.WebhookID == rec.Webhook.ID
+		})
+	case opDeliveries:
+		for _, d := range rec.Deliveries {
+			if r.delivery(d.ID) < 0 {
+				r.state.Deliveries = append(r.state.Deliveries, d)
+			}
+		}
+	case opAttempt:
+		i := r.delivery(rec.Deliveries[0].ID)
+		if i < 0 || rec.Seq <= int64(len(r.state.Deliveries[i].Attempts)) {
+			return
+		}
+
+		d := &r.state.Deliveries[i]
+		d.Status = rec.Deliveries[0].Status
+		d.NextAttemptAt = rec.Deliveries[0].NextAttemptAt
+		d.Attempts = append(d.Attempts, *rec.Attempt)
+	}
+}
+
+func cloneDelivery(d entity.Delivery) entity.Delivery {
+	d.Payload = slices.Clone(d.Payload)
+	d.Attempts = slices.Clone(d.Attempts)
+
+	return d
+}
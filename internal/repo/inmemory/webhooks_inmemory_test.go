@@ -0,0 +1,131 @@
+package inmemory_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/repo/inmemory"
+	"github.com/andreyxaxa/calendar/internal/repo/repotest"
+	"github.com/google/uuid"
+)
+
+func TestWebhooksRepo(t *testing.T) {
+	repotest.Webhooks(t, func(t *testing.T) repo.WebhooksRepo {
+		return inmemory.NewWebhooksRepo()
+	})
+}
+
+func TestWebhooksRepoJournaled(t *testing.T) {
+	repotest.Webhooks(t, func(t *testing.T) repo.WebhooksRepo {
+		r, err := inmemory.OpenWebhooksRepo(t.TempDir())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		t.Cleanup(func() { r.Close() })
+
+		return r
+	})
+}
+
+func TestWebhooksJournal(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	now := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	kept := entity.Webhook{ID: uuid.New(), UserID: 1, URL: "http://kept", Secret: "s1", CreatedAt: now}
+	deleted := entity.Webhook{ID: uuid.New(), UserID: 1, URL: "http://deleted", Secret: "s2", CreatedAt: now}
+	delivery := entity.Delivery{
+		ID:            uuid.New(),
+		WebhookID:     kept.ID,
+		UserID:        1,
+		Kind:          entity.NotificationCreated,
+		Payload:       []byte(`{"id":1}`),
+		Status:        entity.DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+
+	repo, err := inmemory.OpenWebhooksRepo(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, w := range []entity.Webhook{kept, deleted} {
+		if err = repo.CreateWebhook(ctx, w); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	orphan := delivery
+	orphan.ID, orphan.WebhookID = uuid.New(), deleted.ID
+
+	if err = repo.AddDeliveries(ctx, []entity.Delivery{delivery, orphan}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	failed := delivery
+	failed.NextAttemptAt = now.Add(time.Minute)
+
+	if err = repo.AddAttempt(ctx, failed, entity.DeliveryAttempt{At: now, StatusCode: 503}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err = repo.DeleteWebhook(ctx, 1, deleted.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err = repo.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the second time the journal is replayed over a snapshot already holding it,
+	// as after a crash while compacting.
+	for i := range 2 {
+		repo, err = inmemory.OpenWebhooksRepo(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		webhooks, err := repo.GetWebhooks(ctx, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(webhooks) != 1 || webhooks[0].ID != kept.ID || webhooks[0].Secret != kept.Secret {
+			t.Fatalf("expected only the kept webhook, got %+v", webhooks)
+		}
+
+		due, err := repo.GetDueDeliveries(ctx, now.Add(time.Hour), 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(due) != 1 || due[0].ID != delivery.ID || string(due[0].Payload) != string(delivery.Payload) ||
+			len(due[0].Attempts) != 1 || !due[0].NextAttemptAt.Equal(failed.NextAttemptAt) || due[0].URL != kept.URL {
+			t.Fatalf("expected the failed delivery, got %+v", due)
+		}
+
+		if err = repo.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if i > 0 {
+			break
+		}
+
+		snapshot, err := json.Marshal(map[string]any{"webhooks": webhooks, "deliveries": []entity.Delivery{due[0].Delivery}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err = os.WriteFile(filepath.Join(dir, "webhooks.json"), snapshot, 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,635 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/pkg/postgres"
+	"github.com/andreyxaxa/calendar/pkg/types/date"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	_uniqueViolation = "23505"
+	// _floatingSlack widens the series bounds check so all-day series, stored
+	// at UTC midnight, are found for periods in any time zone.
+	_floatingSlack = 24 * time.Hour
+
+	_eventColumns = `uid, start_at, end_at, all_day, time_zone, text, rrule, exdates, overrides, version`
+	// _trashColumns are the columns events and trash share besides user_id, uid and version.
+	_trashColumns = `start_at, end_at, all_day, time_zone, text, rrule, exdates, overrides, series_start, series_end`
+)
+
+// querier - the pool or a transaction.
+type querier interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// EventsRepo - queries go to db: the pool, or the transaction in WithinTx.
+type EventsRepo struct {
+	*postgres.Postgres
+
+	db querier
+}
+
+// New returns new EventsRepo(struct)
+func New(pg *postgres.Postgres) *EventsRepo {
+	return &EventsRepo{Postgres: pg, db: pg.Pool}
+}
+
+// WithinTx -. Nested calls run in savepoints.
+func (r *EventsRepo) WithinTx(ctx context.Context, fn func(tx repo.EventsRepo) error) error {
+	return r.atomic(ctx, "WithinTx", func(tx pgx.Tx) error {
+		return fn(&EventsRepo{Postgres: r.Postgres, db: tx})
+	})
+}
+
+// atomic runs fn in a transaction, in WithinTx - in a savepoint of its one,
+// so a failed statement does not abort it. Errors of fn are returned as they are.
+func (r *EventsRepo) atomic(ctx context.Context, op string, fn func(tx pgx.Tx) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("EventsRepo - %s - r.db.Begin: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("EventsRepo - %s - tx.Commit: %w", op, err)
+	}
+
+	return nil
+}
+
+// Create -.
+func (r *EventsRepo) Create(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) error {
+	end, err := seriesEnd(event)
+	if err != nil {
+		return fmt.Errorf("EventsRepo - Create - seriesEnd: %w", err)
+	}
+
+	return r.atomic(ctx, "Create", func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `INSERT INTO users (id) VALUES ($1) ON CONFLICT DO NOTHING`, userID)
+		if err != nil {
+			return fmt.Errorf("EventsRepo - Create - tx.Exec users: %w", err)
+		}
+
+		_, err = tx.Exec(ctx,
+			`INSERT INTO events (user_id, uid, start_at, end_at, all_day, time_zone, text,
+				rrule, exdates, overrides, series_start, series_end, version)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, 1)`,
+			userID, eventUID, event.Start, event.End, event.AllDay, event.TimeZone, event.Text,
+			event.RRule, exDates(event), overrides(event), event.SeriesStart(), end,
+		)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == _uniqueViolation {
+				return errs.ErrAlreadyExists
+			}
+
+			return fmt.Errorf("EventsRepo - Create - tx.Exec events: %w", err)
+		}
+
+		if err = change(ctx, tx, userID, eventUID, false); err != nil {
+			return fmt.Errorf("EventsRepo - Create - change: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Update -.
+func (r *EventsRepo) Update(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) (int64, error) {
+	var version int64
+
+	err := r.atomic(ctx, "Update", func(tx pgx.Tx) error {
+		var err error
+
+		version, err = update(ctx, tx, userID, eventUID, event)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return missed(ctx, tx, userID, eventUID, "Update")
+			}
+
+			return fmt.Errorf("EventsRepo - Update - update: %w", err)
+		}
+
+		if err = change(ctx, tx, userID, eventUID, false); err != nil {
+			return fmt.Errorf("EventsRepo - Update - change: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// UpdateFields -.
+func (r *EventsRepo) UpdateFields(ctx context.Context, userID int, eventUID uuid.UUID, patch entity.Event,
+	mask entity.FieldMask,
+) (entity.Event, error) {
+	var event entity.Event
+
+	err := r.atomic(ctx, "UpdateFields", func(tx pgx.Tx) error {
+		row := tx.QueryRow(ctx,
+			`SELECT `+_eventColumns+` FROM events WHERE user_id = $1 AND uid = $2 FOR UPDATE`,
+			userID, eventUID,
+		)
+
+		_, current, err := scanEvent(row)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return notFound(ctx, tx, userID, "UpdateFields")
+			}
+
+			return fmt.Errorf("EventsRepo - UpdateFields - scanEvent: %w", err)
+		}
+
+		if patch.Version != 0 && patch.Version != current.Version {
+			return errs.ErrPreconditionFailed
+		}
+
+		event = current.Merge(patch, mask)
+
+		if event.Version, err = update(ctx, tx, userID, eventUID, event); err != nil {
+			return fmt.Errorf("EventsRepo - UpdateFields - update: %w", err)
+		}
+
+		if err = change(ctx, tx, userID, eventUID, false); err != nil {
+			return fmt.Errorf("EventsRepo - UpdateFields - change: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.Event{}, err
+	}
+
+	return event, nil
+}
+
+// update writes all columns of the event if it has the expected version and returns the new one.
+// pgx.ErrNoRows means the event is missing or has another version.
+func update(ctx context.Context, db querier, userID int, eventUID uuid.UUID, event entity.Event) (int64, error) {
+	end, err := seriesEnd(event)
+	if err != nil {
+		return 0, fmt.Errorf("seriesEnd: %w", err)
+	}
+
+	var version int64
+
+	err = db.QueryRow(ctx,
+		`UPDATE events SET start_at = $3, end_at = $4, all_day = $5, time_zone = $6, text = $7,
+			rrule = $8, exdates = $9, overrides = $10, series_start = $11, series_end = $12, version = version + 1
+		WHERE user_id = $1 AND uid = $2 AND ($13::bigint = 0 OR version = $13)
+		RETURNING version`,
+		userID, eventUID, event.Start, event.End, event.AllDay, event.TimeZone, event.Text,
+		event.RRule, exDates(event), overrides(event), event.SeriesStart(), end, event.Version,
+	).Scan(&version)
+
+	return version, err
+}
+
+// change numbers the write of the event as the next change of the user, see repo.EventsRepo.
+// The row of the user stays locked until the transaction ends, so changes are committed in their order.
+func change(ctx context.Context, db querier, userID int, eventUID uuid.UUID, deleted bool) error {
+	var seq int64
+
+	err := db.QueryRow(ctx,
+		`UPDATE users SET change_seq = change_seq + 1 WHERE id = $1 RETURNING change_seq`, userID,
+	).Scan(&seq)
+	if err != nil {
+		return fmt.Errorf("db.QueryRow users: %w", err)
+	}
+
+	_, err = db.Exec(ctx,
+		`INSERT INTO changes (user_id, uid, seq, deleted) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, uid) DO UPDATE SET seq = excluded.seq, deleted = excluded.deleted`,
+		userID, eventUID, seq, deleted,
+	)
+	if err != nil {
+		return fmt.Errorf("db.Exec changes: %w", err)
+	}
+
+	return nil
+}
+
+// Delete -.
+func (r *EventsRepo) Delete(ctx context.Context, userID int, eventUID uuid.UUID, version int64,
+	deletedAt time.Time,
+) error {
+	return r.atomic(ctx, "Delete", func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `DELETE FROM trash WHERE user_id = $1 AND uid = $2`, userID, eventUID)
+		if err != nil {
+			return fmt.Errorf("EventsRepo - Delete - tx.Exec trash: %w", err)
+		}
+
+		tag, err := tx.Exec(ctx,
+			`WITH deleted AS (
+				DELETE FROM events WHERE user_id = $1 AND uid = $2 AND ($3::bigint = 0 OR version = $3)
+				RETURNING *
+			)
+			INSERT INTO trash (user_id, uid, `+_trashColumns+`, version, deleted_at)
+			SELECT user_id, uid, `+_trashColumns+`, version, $4 FROM deleted`,
+			userID, eventUID, version, deletedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("EventsRepo - Delete - tx.Exec events: %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return missed(ctx, tx, userID, eventUID, "Delete")
+		}
+
+		if err = change(ctx, tx, userID, eventUID, true); err != nil {
+			return fmt.Errorf("EventsRepo - Delete - change: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetChanges -.
+func (r *EventsRepo) GetChanges(ctx context.Context, userID int, after int64, limit int,
+) ([]entity.Change, int64, error) {
+	var (
+		changes []entity.Change
+		seq     int64
+	)
+
+	err := r.atomic(ctx, "GetChanges", func(tx pgx.Tx) error {
+		// the lock keeps writes of the user out until the changes and their events are read.
+		err := tx.QueryRow(ctx, `SELECT change_seq FROM users WHERE id = $1 FOR SHARE`, userID).Scan(&seq)
+		if err != nil {
+			// the user never wrote: there are no changes yet.
+			if errors.Is(err, pgx.ErrNoRows) {
+				changes = []entity.Change{}
+
+				return nil
+			}
+
+			return fmt.Errorf("EventsRepo - GetChanges - tx.QueryRow: %w", err)
+		}
+
+		if changes, err = queryChanges(ctx, tx, userID, after, limit); err != nil {
+			return fmt.Errorf("EventsRepo - GetChanges - queryChanges: %w", err)
+		}
+
+		if len(changes) == 0 {
+			return nil
+		}
+
+		view := &EventsRepo{Postgres: r.Postgres, db: tx}
+
+		events, err := view.queryEvents(ctx, "GetChanges",
+			`SELECT `+_eventColumns+` FROM events WHERE user_id = $1 AND uid IN (
+				SELECT uid FROM changes WHERE user_id = $1 AND seq > $2 AND seq <= $3 AND NOT deleted
+			)`,
+			userID, after, changes[len(changes)-1].Seq,
+		)
+		if err != nil {
+			return err
+		}
+
+		for i, ch := range changes {
+			if !ch.Deleted {
+				changes[i].Event = events[ch.UID]
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return changes, seq, nil
+}
+
+// queryChanges returns up to limit changes of the user numbered after the position, without events.
+func queryChanges(ctx context.Context, db querier, userID int, after int64, limit int) ([]entity.Change, error) {
+	rows, err := db.Query(ctx,
+		`SELECT uid, seq, deleted FROM changes WHERE user_id = $1 AND seq > $2 ORDER BY seq LIMIT $3`,
+		userID, after, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	changes := make([]entity.Change, 0)
+
+	for rows.Next() {
+		var ch entity.Change
+
+		if err = rows.Scan(&ch.UID, &ch.Seq, &ch.Deleted); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		changes = append(changes, ch)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return changes, nil
+}
+
+// GetByUID -.
+func (r *EventsRepo) GetByUID(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error) {
+	row := r.db.QueryRow(ctx,
+		`SELECT `+_eventColumns+` FROM events WHERE user_id = $1 AND uid = $2`,
+		userID, eventUID,
+	)
+
+	_, event, err := scanEvent(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Event{}, notFound(ctx, r.db, userID, "GetByUID")
+		}
+
+		return entity.Event{}, fmt.Errorf("EventsRepo - GetByUID - scanEvent: %w", err)
+	}
+
+	return event, nil
+}
+
+// GetAll -.
+func (r *EventsRepo) GetAll(ctx context.Context, userID int) (map[uuid.UUID]entity.Event, error) {
+	exists, err := userExists(ctx, r.db, userID)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - GetAll - userExists: %w", err)
+	}
+
+	if !exists {
+		return nil, errs.ErrUserNotFound
+	}
+
+	return r.queryEvents(ctx, "GetAll", `SELECT `+_eventColumns+` FROM events WHERE user_id = $1`, userID)
+}
+
+// GetEventsForDay -.
+func (r *EventsRepo) GetEventsForDay(ctx context.Context, userID int, d time.Time) (map[uuid.UUID]entity.Event, error) {
+	from, to := date.DayRange(d)
+
+	return r.getEvents(ctx, userID, from, to, "GetEventsForDay")
+}
+
+// GetEventsForWeek -.
+func (r *EventsRepo) GetEventsForWeek(ctx context.Context, userID int, d time.Time) (map[uuid.UUID]entity.Event, error) {
+	from, to := date.WeekRange(d)
+
+	return r.getEvents(ctx, userID, from, to, "GetEventsForWeek")
+}
+
+// GetEventsForMonth -.
+func (r *EventsRepo) GetEventsForMonth(ctx context.Context, userID int, d time.Time) (map[uuid.UUID]entity.Event, error) {
+	from, to := date.MonthRange(d)
+
+	return r.getEvents(ctx, userID, from, to, "GetEventsForMonth")
+}
+
+// GetEventsForRange reads the page from the index of events by start.
+func (r *EventsRepo) GetEventsForRange(ctx context.Context, userID int, from, to time.Time,
+	page entity.PageRequest,
+) (entity.EventsPage, error) {
+	exists, err := userExists(ctx, r.db, userID)
+	if err != nil {
+		return entity.EventsPage{}, fmt.Errorf("EventsRepo - GetEventsForRange - userExists: %w", err)
+	}
+
+	if !exists {
+		return entity.EventsPage{}, errs.ErrUserNotFound
+	}
+
+	series, err := r.queryEvents(ctx, "GetEventsForRange",
+		`SELECT `+_eventColumns+` FROM events
+		WHERE user_id = $1 AND rrule <> '' AND series_start < $3 AND (series_end IS NULL OR series_end > $2)`,
+		userID, from.Add(-_floatingSlack), to.Add(_floatingSlack),
+	)
+	if err != nil {
+		return entity.EventsPage{}, err
+	}
+
+	order, after := "ASC", ">"
+	if page.Desc {
+		order, after = "DESC", "<"
+	}
+
+	query := `SELECT ` + _eventColumns + ` FROM events
+		WHERE user_id = $1 AND rrule = '' AND (
+			(NOT all_day AND start_at < $3 AND (end_at > $2 OR start_at >= $2))
+			OR (all_day AND start_at < $5 AND end_at > $4)
+		)`
+	args := []any{userID, from, to, date.Floating(from), date.FloatingCeil(to), page.Limit}
+
+	if page.After != nil {
+		query += ` AND (start_at ` + after + ` $7 OR (start_at = $7 AND uid ` + after + ` $8))`
+		args = append(args, page.After.Start, page.After.UID)
+	}
+
+	query += ` ORDER BY start_at ` + order + `, uid ` + order + ` LIMIT $6`
+
+	events, err := r.queryOccurrences(ctx, "GetEventsForRange", query, args...)
+	if err != nil {
+		return entity.EventsPage{}, err
+	}
+
+	return entity.EventsPage{Events: events, Series: series}, nil
+}
+
+// queryOccurrences runs a query selecting _eventColumns, keeping the order of its rows.
+func (r *EventsRepo) queryOccurrences(ctx context.Context, op, query string, args ...any) ([]entity.Occurrence, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - %s - r.db.Query: %w", op, err)
+	}
+	defer rows.Close()
+
+	occurrences := make([]entity.Occurrence, 0)
+
+	for rows.Next() {
+		uid, event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("EventsRepo - %s - scanEvent: %w", op, err)
+		}
+
+		occurrences = append(occurrences, entity.Occurrence{UID: uid, Event: event})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("EventsRepo - %s - rows.Err: %w", op, err)
+	}
+
+	return occurrences, nil
+}
+
+// getEvents returns events of the user overlapping [from, to), see entity.Event.Overlaps,
+// and series that may have instances there.
+func (r *EventsRepo) getEvents(ctx context.Context, userID int, from, to time.Time, op string) (map[uuid.UUID]entity.Event, error) {
+	exists, err := userExists(ctx, r.db, userID)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - %s - userExists: %w", op, err)
+	}
+
+	if !exists {
+		return nil, errs.ErrUserNotFound
+	}
+
+	return r.queryEvents(ctx, op,
+		`SELECT `+_eventColumns+` FROM events
+		WHERE user_id = $1 AND (
+			(rrule = '' AND NOT all_day AND start_at < $3 AND (end_at > $2 OR start_at >= $2))
+			OR (rrule = '' AND all_day AND start_at < $5 AND end_at > $4)
+			OR (rrule <> '' AND series_start < $7 AND (series_end IS NULL OR series_end > $6))
+		)`,
+		userID, from, to, date.Floating(from), date.FloatingCeil(to),
+		from.Add(-_floatingSlack), to.Add(_floatingSlack),
+	)
+}
+
+// queryEvents runs a query selecting _eventColumns.
+func (r *EventsRepo) queryEvents(ctx context.Context, op, query string, args ...any) (map[uuid.UUID]entity.Event, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - %s - r.db.Query: %w", op, err)
+	}
+	defer rows.Close()
+
+	events := make(map[uuid.UUID]entity.Event)
+
+	for rows.Next() {
+		uid, event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("EventsRepo - %s - scanEvent: %w", op, err)
+		}
+
+		events[uid] = event
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("EventsRepo - %s - rows.Err: %w", op, err)
+	}
+
+	return events, nil
+}
+
+// scanEvent scans a row of _eventColumns, followed by columns scanned into extra.
+func scanEvent(row pgx.Row, extra ...any) (uuid.UUID, entity.Event, error) {
+	var (
+		uid   uuid.UUID
+		event entity.Event
+	)
+
+	dest := []any{&uid, &event.Start, &event.End, &event.AllDay, &event.TimeZone, &event.Text,
+		&event.RRule, &event.ExDates, &event.Overrides, &event.Version}
+
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return uuid.UUID{}, entity.Event{}, err
+	}
+
+	event.Start, event.End = event.Start.UTC(), event.End.UTC()
+
+	if len(event.ExDates) == 0 {
+		event.ExDates = nil
+	}
+
+	for i := range event.ExDates {
+		event.ExDates[i] = event.ExDates[i].UTC()
+	}
+
+	if len(event.Overrides) == 0 {
+		event.Overrides = nil
+	}
+
+	return uid, event, nil
+}
+
+// missed resolves which of ErrPreconditionFailed/ErrUserNotFound/ErrEventNotFound caused an empty write.
+func missed(ctx context.Context, db querier, userID int, eventUID uuid.UUID, op string) error {
+	var exists bool
+
+	err := db.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM events WHERE user_id = $1 AND uid = $2)`, userID, eventUID,
+	).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("EventsRepo - %s - db.QueryRow: %w", op, err)
+	}
+
+	if exists {
+		return errs.ErrPreconditionFailed
+	}
+
+	return notFound(ctx, db, userID, op)
+}
+
+// notFound resolves which of ErrUserNotFound/ErrEventNotFound caused an empty write.
+func notFound(ctx context.Context, db querier, userID int, op string) error {
+	exists, err := userExists(ctx, db, userID)
+	if err != nil {
+		return fmt.Errorf("EventsRepo - %s - userExists: %w", op, err)
+	}
+
+	if !exists {
+		return errs.ErrUserNotFound
+	}
+
+	return errs.ErrEventNotFound
+}
+
+func userExists(ctx context.Context, db querier, userID int) (bool, error) {
+	var exists bool
+
+	err := db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, userID).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// seriesEnd returns the series_end column value, nil for endless series.
+func seriesEnd(event entity.Event) (*time.Time, error) {
+	end, err := event.SeriesEnd()
+	if err != nil || end.IsZero() {
+		return nil, err
+	}
+
+	return &end, nil
+}
+
+// overrides never returns nil, overrides column is NOT NULL.
+func overrides(event entity.Event) []entity.Override {
+	if event.Overrides == nil {
+		return []entity.Override{}
+	}
+
+	return event.Overrides
+}
+
+// exDates never returns nil, exdates column is NOT NULL.
+func exDates(event entity.Event) []time.Time {
+	if event.ExDates == nil {
+		return []time.Time{}
+	}
+
+	return event.ExDates
+}
@@ -0,0 +1,46 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/andreyxaxa/calendar/internal/repo"
+	pgrepo "github.com/andreyxaxa/calendar/internal/repo/postgres"
+	"github.com/andreyxaxa/calendar/internal/repo/repotest"
+	"github.com/andreyxaxa/calendar/pkg/postgres"
+)
+
+// eventsRepo connects to the database from PG_URL, e.g. one started by
+// `docker run -e POSTGRES_PASSWORD=pass -p 5432:5432 postgres`.
+func eventsRepo(t *testing.T) *pgrepo.EventsRepo {
+	t.Helper()
+
+	url := os.Getenv("PG_URL")
+	if url == "" {
+		t.Skip("PG_URL is not set")
+	}
+
+	pg, err := postgres.New(url, postgres.ConnAttempts(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(pg.Close)
+
+	if err = pgrepo.Migrate(pg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = pg.Pool.Exec(context.Background(), `TRUNCATE events, trash, revisions, changes, users, idempotency_keys,
+		webhooks, deliveries, delivery_attempts`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return pgrepo.New(pg)
+}
+
+func TestEventsRepo(t *testing.T) {
+	repotest.Events(t, func(t *testing.T) repo.EventsRepo {
+		return eventsRepo(t)
+	})
+}
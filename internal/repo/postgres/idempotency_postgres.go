@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/postgres"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/jackc/pgx/v5"
+)
+
+// IdempotencyRepo -.
+type IdempotencyRepo struct {
+	*postgres.Postgres
+}
+
+// NewIdempotencyRepo returns new IdempotencyRepo(struct)
+func NewIdempotencyRepo(pg *postgres.Postgres) *IdempotencyRepo {
+	return &IdempotencyRepo{pg}
+}
+
+// Reserve -.
+func (r *IdempotencyRepo) Reserve(ctx context.Context, key entity.IdempotencyKey, now time.Time) (entity.IdempotencyKey, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return entity.IdempotencyKey{}, fmt.Errorf("IdempotencyRepo - Reserve - r.Pool.Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		return entity.IdempotencyKey{}, fmt.Errorf("IdempotencyRepo - Reserve - tx.Exec delete: %w", err)
+	}
+
+	// a concurrent insert of the key makes this one wait for it.
+	tag, err := tx.Exec(ctx,
+		`INSERT INTO idempotency_keys (key, fingerprint, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO NOTHING`,
+		key.Key, key.Fingerprint, key.ExpiresAt,
+	)
+	if err != nil {
+		return entity.IdempotencyKey{}, fmt.Errorf("IdempotencyRepo - Reserve - tx.Exec insert: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		var stored entity.IdempotencyKey
+
+		err = tx.QueryRow(ctx,
+			`SELECT key, fingerprint, response, expires_at FROM idempotency_keys WHERE key = $1`, key.Key,
+		).Scan(&stored.Key, &stored.Fingerprint, &stored.Response, &stored.ExpiresAt)
+		if err != nil {
+			// released right after the conflict: the request was being handled.
+			if errors.Is(err, pgx.ErrNoRows) {
+				key.Response = nil
+
+				return key, errs.ErrAlreadyExists
+			}
+
+			return entity.IdempotencyKey{}, fmt.Errorf("IdempotencyRepo - Reserve - tx.QueryRow: %w", err)
+		}
+
+		stored.ExpiresAt = stored.ExpiresAt.UTC()
+
+		return stored, errs.ErrAlreadyExists
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return entity.IdempotencyKey{}, fmt.Errorf("IdempotencyRepo - Reserve - tx.Commit: %w", err)
+	}
+
+	key.Response = nil
+
+	return key, nil
+}
+
+// Complete -.
+func (r *IdempotencyRepo) Complete(ctx context.Context, key string, response entity.StoredResponse) error {
+	_, err := r.Pool.Exec(ctx, `UPDATE idempotency_keys SET response = $2 WHERE key = $1`, key, response)
+	if err != nil {
+		return fmt.Errorf("IdempotencyRepo - Complete - r.Pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Release -.
+func (r *IdempotencyRepo) Release(ctx context.Context, key string) error {
+	_, err := r.Pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE key = $1`, key)
+	if err != nil {
+		return fmt.Errorf("IdempotencyRepo - Release - r.Pool.Exec: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/andreyxaxa/calendar/internal/repo"
+	pgrepo "github.com/andreyxaxa/calendar/internal/repo/postgres"
+	"github.com/andreyxaxa/calendar/internal/repo/repotest"
+)
+
+func TestIdempotencyRepo(t *testing.T) {
+	repotest.Idempotency(t, func(t *testing.T) repo.IdempotencyRepo {
+		return pgrepo.NewIdempotencyRepo(eventsRepo(t).Postgres)
+	})
+}
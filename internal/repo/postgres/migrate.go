@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+
+	"github.com/andreyxaxa/calendar/pkg/postgres"
+	"github.com/golang-migrate/migrate/v4"
+	pgxmigrate "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrate applies embedded migrations up to the latest version.
+func Migrate(pg *postgres.Postgres) error {
+	src, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return fmt.Errorf("postgres - Migrate - iofs.New: %w", err)
+	}
+
+	// closing the driver closes db, the pool behind it stays open.
+	db := stdlib.OpenDBFromPool(pg.Pool)
+
+	driver, err := pgxmigrate.WithInstance(db, &pgxmigrate.Config{})
+	if err != nil {
+		db.Close()
+
+		return fmt.Errorf("postgres - Migrate - pgxmigrate.WithInstance: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "pgx5", driver)
+	if err != nil {
+		driver.Close()
+
+		return fmt.Errorf("postgres - Migrate - migrate.NewWithInstance: %w", err)
+	}
+	defer m.Close()
+
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("postgres - Migrate - m.Up: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+ALTER TABLE events ADD COLUMN date DATE;
+
+UPDATE events SET date = (start_at AT TIME ZONE 'UTC')::date;
+
+ALTER TABLE events ALTER COLUMN date SET NOT NULL;
+
+DROP INDEX IF EXISTS events_user_id_start_at_idx;
+
+ALTER TABLE events
+    DROP COLUMN start_at,
+    DROP COLUMN end_at,
+    DROP COLUMN all_day;
+
+CREATE INDEX IF NOT EXISTS events_user_id_date_idx ON events (user_id, date);
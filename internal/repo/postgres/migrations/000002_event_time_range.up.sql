@@ -0,0 +1,20 @@
+ALTER TABLE events
+    ADD COLUMN start_at TIMESTAMPTZ,
+    ADD COLUMN end_at   TIMESTAMPTZ,
+    ADD COLUMN all_day  BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- events created before were whole days.
+UPDATE events
+SET start_at = date::timestamp AT TIME ZONE 'UTC',
+    end_at   = (date + 1)::timestamp AT TIME ZONE 'UTC',
+    all_day  = TRUE;
+
+ALTER TABLE events
+    ALTER COLUMN start_at SET NOT NULL,
+    ALTER COLUMN end_at SET NOT NULL;
+
+DROP INDEX IF EXISTS events_user_id_date_idx;
+
+ALTER TABLE events DROP COLUMN date;
+
+CREATE INDEX IF NOT EXISTS events_user_id_start_at_idx ON events (user_id, start_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS events_user_id_recurring_idx;
+
+ALTER TABLE events
+    DROP COLUMN series_end,
+    DROP COLUMN exdates,
+    DROP COLUMN rrule;
@@ -0,0 +1,9 @@
+ALTER TABLE events
+    ADD COLUMN rrule      TEXT NOT NULL DEFAULT '',
+    ADD COLUMN exdates    TIMESTAMPTZ[] NOT NULL DEFAULT '{}',
+    -- end of the last instance, NULL for endless series.
+    ADD COLUMN series_end TIMESTAMPTZ;
+
+UPDATE events SET series_end = end_at;
+
+CREATE INDEX IF NOT EXISTS events_user_id_recurring_idx ON events (user_id) WHERE rrule <> '';
@@ -0,0 +1,3 @@
+ALTER TABLE events
+    DROP COLUMN series_start,
+    DROP COLUMN overrides;
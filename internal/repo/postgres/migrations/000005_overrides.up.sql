@@ -0,0 +1,8 @@
+ALTER TABLE events
+    ADD COLUMN overrides    JSONB NOT NULL DEFAULT '[]',
+    -- start of the earliest instance, overrides may move it before start_at.
+    ADD COLUMN series_start TIMESTAMPTZ;
+
+UPDATE events SET series_start = start_at;
+
+ALTER TABLE events ALTER COLUMN series_start SET NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE events
+    -- grows with every write of the event, see repo.EventsRepo.
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
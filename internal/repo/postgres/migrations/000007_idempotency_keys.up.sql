@@ -0,0 +1,9 @@
+-- response is the one replayed on retries, NULL while the request is handled.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key         TEXT        PRIMARY KEY,
+    fingerprint TEXT        NOT NULL,
+    response    JSONB,
+    expires_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,20 @@
+-- trash keeps deleted events with the columns of events.
+CREATE TABLE IF NOT EXISTS trash (
+    user_id      INTEGER       NOT NULL REFERENCES users (id),
+    uid          UUID          NOT NULL,
+    start_at     TIMESTAMPTZ   NOT NULL,
+    end_at       TIMESTAMPTZ   NOT NULL,
+    all_day      BOOLEAN       NOT NULL,
+    time_zone    TEXT          NOT NULL,
+    text         TEXT          NOT NULL,
+    rrule        TEXT          NOT NULL,
+    exdates      TIMESTAMPTZ[] NOT NULL,
+    overrides    JSONB         NOT NULL,
+    series_start TIMESTAMPTZ   NOT NULL,
+    series_end   TIMESTAMPTZ,
+    version      BIGINT        NOT NULL,
+    deleted_at   TIMESTAMPTZ   NOT NULL,
+    PRIMARY KEY (user_id, uid)
+);
+
+CREATE INDEX IF NOT EXISTS trash_deleted_at_idx ON trash (deleted_at);
@@ -0,0 +1,12 @@
+-- revisions are appended only, before and after are the event, NULL where it was missing.
+CREATE TABLE IF NOT EXISTS revisions (
+    user_id INTEGER     NOT NULL REFERENCES users (id),
+    uid     UUID        NOT NULL,
+    number  BIGINT      NOT NULL,
+    op      TEXT        NOT NULL,
+    actor   TEXT        NOT NULL,
+    at      TIMESTAMPTZ NOT NULL,
+    before  JSONB,
+    after   JSONB,
+    PRIMARY KEY (user_id, uid, number)
+);
@@ -0,0 +1,3 @@
+-- pages of a range are read in the order of start_at, then uid.
+DROP INDEX IF EXISTS events_user_id_start_at_idx;
+CREATE INDEX IF NOT EXISTS events_user_id_start_at_uid_idx ON events (user_id, start_at, uid);
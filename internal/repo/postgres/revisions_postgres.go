@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+// AddRevision -.
+func (r *EventsRepo) AddRevision(ctx context.Context, userID int, eventUID uuid.UUID, revision entity.Revision) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO revisions (user_id, uid, number, op, actor, at, before, after)
+		SELECT $1, $2, COALESCE(MAX(number), 0) + 1, $3, $4, $5, $6, $7 FROM revisions
+		WHERE user_id = $1 AND uid = $2`,
+		userID, eventUID, string(revision.Op), revision.Actor, revision.At, revision.Before, revision.After,
+	)
+	if err != nil {
+		return fmt.Errorf("EventsRepo - AddRevision - r.db.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// GetRevisions -.
+func (r *EventsRepo) GetRevisions(ctx context.Context, userID int, eventUID uuid.UUID) ([]entity.Revision, error) {
+	exists, err := userExists(ctx, r.db, userID)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - GetRevisions - userExists: %w", err)
+	}
+
+	if !exists {
+		return nil, errs.ErrUserNotFound
+	}
+
+	rows, err := r.db.Query(ctx,
+		`SELECT number, op, actor, at, before, after FROM revisions
+		WHERE user_id = $1 AND uid = $2 ORDER BY number`,
+		userID, eventUID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - GetRevisions - r.db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []entity.Revision
+
+	for rows.Next() {
+		var (
+			revision entity.Revision
+			op       string
+		)
+
+		err = rows.Scan(&revision.Number, &op, &revision.Actor, &revision.At, &revision.Before, &revision.After)
+		if err != nil {
+			return nil, fmt.Errorf("EventsRepo - GetRevisions - rows.Scan: %w", err)
+		}
+
+		revision.Op = entity.RevisionOp(op)
+		revision.At = revision.At.UTC()
+
+		revisions = append(revisions, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("EventsRepo - GetRevisions - rows.Err: %w", err)
+	}
+
+	return revisions, nil
+}
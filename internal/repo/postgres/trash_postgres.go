@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Restore -.
+func (r *EventsRepo) Restore(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error) {
+	var event entity.Event
+
+	err := r.atomic(ctx, "Restore", func(tx pgx.Tx) error {
+		row := tx.QueryRow(ctx,
+			`WITH restored AS (
+				DELETE FROM trash WHERE user_id = $1 AND uid = $2
+				RETURNING *
+			)
+			INSERT INTO events (user_id, uid, `+_trashColumns+`, version)
+			SELECT user_id, uid, `+_trashColumns+`, version + 1 FROM restored
+			RETURNING `+_eventColumns,
+			userID, eventUID,
+		)
+
+		_, restored, err := scanEvent(row)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == _uniqueViolation {
+				return errs.ErrAlreadyExists
+			}
+
+			if errors.Is(err, pgx.ErrNoRows) {
+				return notFound(ctx, tx, userID, "Restore")
+			}
+
+			return fmt.Errorf("EventsRepo - Restore - scanEvent: %w", err)
+		}
+
+		event = restored
+
+		if err = change(ctx, tx, userID, eventUID, false); err != nil {
+			return fmt.Errorf("EventsRepo - Restore - change: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.Event{}, err
+	}
+
+	return event, nil
+}
+
+// GetTrash -.
+func (r *EventsRepo) GetTrash(ctx context.Context, userID int) ([]entity.TrashedEvent, error) {
+	exists, err := userExists(ctx, r.db, userID)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - GetTrash - userExists: %w", err)
+	}
+
+	if !exists {
+		return nil, errs.ErrUserNotFound
+	}
+
+	rows, err := r.db.Query(ctx,
+		`SELECT `+_eventColumns+`, deleted_at FROM trash WHERE user_id = $1`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - GetTrash - r.db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	var events []entity.TrashedEvent
+
+	for rows.Next() {
+		var deletedAt time.Time
+
+		uid, event, err := scanEvent(rows, &deletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("EventsRepo - GetTrash - scanEvent: %w", err)
+		}
+
+		events = append(events, entity.TrashedEvent{
+			UID:       uid,
+			DeletedAt: deletedAt.UTC(),
+			Event:     event,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("EventsRepo - GetTrash - rows.Err: %w", err)
+	}
+
+	return events, nil
+}
+
+// PurgeTrash -.
+func (r *EventsRepo) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM trash WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("EventsRepo - PurgeTrash - r.db.Exec: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
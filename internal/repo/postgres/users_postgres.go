@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/postgres"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/jackc/pgx/v5"
+)
+
+// UsersRepo -.
+type UsersRepo struct {
+	*postgres.Postgres
+}
+
+// NewUsersRepo returns new UsersRepo(struct)
+func NewUsersRepo(pg *postgres.Postgres) *UsersRepo {
+	return &UsersRepo{pg}
+}
+
+// Get -.
+func (r *UsersRepo) Get(ctx context.Context, userID int) (entity.User, error) {
+	user := entity.User{ID: userID}
+
+	err := r.Pool.QueryRow(ctx, `SELECT time_zone FROM users WHERE id = $1`, userID).Scan(&user.TimeZone)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.User{}, errs.ErrUserNotFound
+		}
+
+		return entity.User{}, fmt.Errorf("UsersRepo - Get - r.Pool.QueryRow: %w", err)
+	}
+
+	return user, nil
+}
+
+// Save -.
+func (r *UsersRepo) Save(ctx context.Context, user entity.User) error {
+	_, err := r.Pool.Exec(ctx,
+		`INSERT INTO users (id, time_zone) VALUES ($1, $2)
+		ON CONFLICT (id) DO UPDATE SET time_zone = EXCLUDED.time_zone`,
+		user.ID, user.TimeZone,
+	)
+	if err != nil {
+		return fmt.Errorf("UsersRepo - Save - r.Pool.Exec: %w", err)
+	}
+
+	return nil
+}
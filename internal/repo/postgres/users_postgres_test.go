@@ -0,0 +1,15 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/andreyxaxa/calendar/internal/repo"
+	pgrepo "github.com/andreyxaxa/calendar/internal/repo/postgres"
+	"github.com/andreyxaxa/calendar/internal/repo/repotest"
+)
+
+func TestUsersRepo(t *testing.T) {
+	repotest.Users(t, func(t *testing.T) repo.UsersRepo {
+		return pgrepo.NewUsersRepo(eventsRepo(t).Postgres)
+	})
+}
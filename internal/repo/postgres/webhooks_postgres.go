@@ -0,0 +1,317 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/postgres"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const _deliveryColumns = `d.id, d.webhook_id, d.user_id, d.kind, d.payload, d.status, d.next_attempt_at, d.created_at`
+
+// WebhooksRepo -.
+type WebhooksRepo struct {
+	*postgres.Postgres
+}
+
+// NewWebhooksRepo returns new WebhooksRepo(struct)
+func NewWebhooksRepo(pg *postgres.Postgres) *WebhooksRepo {
+	return &WebhooksRepo{pg}
+}
+
+// CreateWebhook -.
+func (r *WebhooksRepo) CreateWebhook(ctx context.Context, webhook entity.Webhook) error {
+	kinds := webhook.Kinds
+	if kinds == nil {
+		kinds = []entity.NotificationKind{}
+	}
+
+	_, err := r.Pool.Exec(ctx,
+		`INSERT INTO webhooks (id, user_id, url, secret, kinds, created_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		webhook.ID, webhook.UserID, webhook.URL, webhook.Secret, kinds, webhook.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("WebhooksRepo - CreateWebhook - r.Pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// GetWebhooks -.
+func (r *WebhooksRepo) GetWebhooks(ctx context.Context, userID int) ([]entity.Webhook, error) {
+	rows, err := r.Pool.Query(ctx,
+		`SELECT id, user_id, url, secret, kinds, created_at FROM webhooks
+		WHERE user_id = $1 ORDER BY created_at, id`, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - GetWebhooks - r.Pool.Query: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]entity.Webhook, 0)
+
+	for rows.Next() {
+		var w entity.Webhook
+
+		if err = rows.Scan(&w.ID, &w.UserID, &w.URL, &w.Secret, &w.Kinds, &w.CreatedAt); err != nil {
+			return nil, fmt.Errorf("WebhooksRepo - GetWebhooks - rows.Scan: %w", err)
+		}
+
+		if len(w.Kinds) == 0 {
+			w.Kinds = nil
+		}
+
+		w.CreatedAt = w.CreatedAt.UTC()
+		webhooks = append(webhooks, w)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - GetWebhooks - rows.Err: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook -.
+func (r *WebhooksRepo) DeleteWebhook(ctx context.Context, userID int, id uuid.UUID) error {
+	tag, err := r.Pool.Exec(ctx, `DELETE FROM webhooks WHERE user_id = $1 AND id = $2`, userID, id)
+	if err != nil {
+		return fmt.Errorf("WebhooksRepo - DeleteWebhook - r.Pool.Exec: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errs.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// AddDeliveries -.
+func (r *WebhooksRepo) AddDeliveries(ctx context.Context, deliveries []entity.Delivery) error {
+	batch := &pgx.Batch{}
+
+	for _, d := range deliveries {
+		batch.Queue(
+			`INSERT INTO deliveries (id, webhook_id, user_id, kind, payload, status, next_attempt_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			d.ID, d.WebhookID, d.UserID, string(d.Kind), string(d.Payload), string(d.Status), d.NextAttemptAt, d.CreatedAt,
+		)
+	}
+
+	// a batch runs in an implicit transaction.
+	if err := r.Pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("WebhooksRepo - AddDeliveries - r.Pool.SendBatch: %w", err)
+	}
+
+	return nil
+}
+
+// GetDueDeliveries -.
+func (r *WebhooksRepo) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.DueDelivery, error) {
+	rows, err := r.Pool.Query(ctx,
+		`SELECT `+_deliveryColumns+`, w.url, w.secret FROM deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = $1 AND d.next_attempt_at <= $2
+		ORDER BY d.next_attempt_at, d.seq LIMIT $3`,
+		string(entity.DeliveryPending), now, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - GetDueDeliveries - r.Pool.Query: %w", err)
+	}
+	defer rows.Close()
+
+	due := make([]entity.DueDelivery, 0)
+
+	for rows.Next() {
+		var d entity.DueDelivery
+
+		if d.Delivery, err = scanDelivery(rows, &d.URL, &d.Secret); err != nil {
+			return nil, fmt.Errorf("WebhooksRepo - GetDueDeliveries - scanDelivery: %w", err)
+		}
+
+		due = append(due, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - GetDueDeliveries - rows.Err: %w", err)
+	}
+
+	deliveries := make([]*entity.Delivery, len(due))
+	for i := range due {
+		deliveries[i] = &due[i].Delivery
+	}
+
+	if err = r.queryAttempts(ctx, deliveries); err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - GetDueDeliveries - r.queryAttempts: %w", err)
+	}
+
+	return due, nil
+}
+
+// AddAttempt -.
+func (r *WebhooksRepo) AddAttempt(ctx context.Context, delivery entity.Delivery, attempt entity.DeliveryAttempt) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("WebhooksRepo - AddAttempt - r.Pool.Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE deliveries SET status = $1, next_attempt_at = $2 WHERE id = $3`,
+		string(delivery.Status), delivery.NextAttemptAt, delivery.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("WebhooksRepo - AddAttempt - tx.Exec update: %w", err)
+	}
+
+	// the webhook was deleted meanwhile.
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO delivery_attempts (delivery_id, number, at, status_code, error, duration)
+		SELECT $1, COALESCE(MAX(number), 0) + 1, $2, $3, $4, $5 FROM delivery_attempts WHERE delivery_id = $1`,
+		delivery.ID, attempt.At, attempt.StatusCode, attempt.Error, attempt.Duration.Microseconds(),
+	)
+	if err != nil {
+		return fmt.Errorf("WebhooksRepo - AddAttempt - tx.Exec insert: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("WebhooksRepo - AddAttempt - tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeliveries -.
+func (r *WebhooksRepo) GetDeliveries(ctx context.Context, userID int, webhookID uuid.UUID,
+	status entity.DeliveryStatus, limit int,
+) ([]entity.Delivery, error) {
+	var exists bool
+
+	err := r.Pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM webhooks WHERE user_id = $1 AND id = $2)`, userID, webhookID,
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - GetDeliveries - r.Pool.QueryRow: %w", err)
+	}
+
+	if !exists {
+		return nil, errs.ErrWebhookNotFound
+	}
+
+	rows, err := r.Pool.Query(ctx,
+		`SELECT `+_deliveryColumns+` FROM deliveries d
+		WHERE d.webhook_id = $1 AND ($2 = '' OR d.status = $2)
+		ORDER BY d.seq DESC LIMIT $3`,
+		webhookID, string(status), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - GetDeliveries - r.Pool.Query: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]entity.Delivery, 0)
+
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("WebhooksRepo - GetDeliveries - scanDelivery: %w", err)
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - GetDeliveries - rows.Err: %w", err)
+	}
+
+	refs := make([]*entity.Delivery, len(deliveries))
+	for i := range deliveries {
+		refs[i] = &deliveries[i]
+	}
+
+	if err = r.queryAttempts(ctx, refs); err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - GetDeliveries - r.queryAttempts: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// queryAttempts sets attempts of the deliveries, the oldest first.
+func (r *WebhooksRepo) queryAttempts(ctx context.Context, deliveries []*entity.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*entity.Delivery, len(deliveries))
+	ids := make([]uuid.UUID, 0, len(deliveries))
+
+	for _, d := range deliveries {
+		byID[d.ID] = d
+		ids = append(ids, d.ID)
+	}
+
+	rows, err := r.Pool.Query(ctx,
+		`SELECT delivery_id, at, status_code, error, duration FROM delivery_attempts
+		WHERE delivery_id = ANY($1) ORDER BY delivery_id, number`, ids,
+	)
+	if err != nil {
+		return fmt.Errorf("r.Pool.Query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id       uuid.UUID
+			a        entity.DeliveryAttempt
+			duration int64
+		)
+
+		if err = rows.Scan(&id, &a.At, &a.StatusCode, &a.Error, &duration); err != nil {
+			return fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		a.At = a.At.UTC()
+		a.Duration = time.Duration(duration) * time.Microsecond
+
+		d := byID[id]
+		d.Attempts = append(d.Attempts, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return nil
+}
+
+// scanDelivery scans a row of _deliveryColumns, followed by columns scanned into extra.
+func scanDelivery(row pgx.Row, extra ...any) (entity.Delivery, error) {
+	var (
+		d                     entity.Delivery
+		kind, payload, status string
+	)
+
+	dest := append([]any{&d.ID, &d.WebhookID, &d.UserID, &kind, &payload, &status, &d.NextAttemptAt, &d.CreatedAt},
+		extra...)
+
+	if err := row.Scan(dest...); err != nil {
+		return entity.Delivery{}, err
+	}
+
+	d.Kind = entity.NotificationKind(kind)
+	d.Payload = []byte(payload)
+	d.Status = entity.DeliveryStatus(status)
+	d.NextAttemptAt = d.NextAttemptAt.UTC()
+	d.CreatedAt = d.CreatedAt.UTC()
+
+	return d, nil
+}
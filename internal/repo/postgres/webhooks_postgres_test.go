@@ -0,0 +1,15 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/andreyxaxa/calendar/internal/repo"
+	pgrepo "github.com/andreyxaxa/calendar/internal/repo/postgres"
+	"github.com/andreyxaxa/calendar/internal/repo/repotest"
+)
+
+func TestWebhooksRepo(t *testing.T) {
+	repotest.Webhooks(t, func(t *testing.T) repo.WebhooksRepo {
+		return pgrepo.NewWebhooksRepo(eventsRepo(t).Postgres)
+	})
+}
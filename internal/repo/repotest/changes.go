@@ -0,0 +1,113 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/google/uuid"
+)
+
+func testChanges(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	events := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	event := entity.Event{Text: "stand-up", Start: start, End: start.Add(15 * time.Minute), TimeZone: "UTC"}
+	updated, deleted, restored := uuid.New(), uuid.New(), uuid.New()
+
+	// a user who never wrote has no changes yet.
+	changes, seq, err := events.GetChanges(ctx, userID, 0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(changes) != 0 || seq != 0 {
+		t.Fatalf("expected no changes, got %d up to %d", len(changes), seq)
+	}
+
+	for _, uid := range []uuid.UUID{updated, deleted, restored} {
+		if err := events.Create(ctx, userID, uid, event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := events.Update(ctx, userID, updated, entity.Event{Text: "retro", Start: start, End: start}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, uid := range []uuid.UUID{deleted, restored} {
+		if err := events.Delete(ctx, userID, uid, 0, start); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if _, err := events.Restore(ctx, userID, restored); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// tombstones outlive the trash.
+	if _, err := events.PurgeTrash(ctx, start.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// a failed transaction numbers nothing.
+	err = events.WithinTx(ctx, func(tx repo.EventsRepo) error {
+		if err := tx.Create(ctx, userID, uuid.New(), event); err != nil {
+			return err
+		}
+
+		return errors.New("abort")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	changes, seq, err = events.GetChanges(ctx, userID, 0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if seq != 7 {
+		t.Fatalf("expected seq 7, got %d", seq)
+	}
+
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", changes)
+	}
+
+	if changes[0].UID != updated || changes[0].Seq != 4 || changes[0].Deleted || changes[0].Event.Text != "retro" ||
+		changes[0].Event.Version != 2 {
+		t.Fatalf("unexpected change of the updated event %+v", changes[0])
+	}
+
+	if changes[1].UID != deleted || changes[1].Seq != 5 || !changes[1].Deleted {
+		t.Fatalf("unexpected tombstone %+v", changes[1])
+	}
+
+	if changes[2].UID != restored || changes[2].Seq != 7 || changes[2].Deleted || changes[2].Event.Text != "stand-up" {
+		t.Fatalf("unexpected change of the restored event %+v", changes[2])
+	}
+
+	changes, seq, err = events.GetChanges(ctx, userID, 4, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if seq != 7 || len(changes) != 1 || changes[0].UID != deleted {
+		t.Fatalf("expected the tombstone only, got %+v at %d", changes, seq)
+	}
+
+	changes, _, err = events.GetChanges(ctx, userID, 7, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(changes) != 0 {
+		t.Fatalf("expected no changes, got %+v", changes)
+	}
+}
@@ -0,0 +1,1005 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+func testCreateAndGetForDay(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	repo := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	date := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	uid := uuid.New()
+
+	event := entity.Event{
+		Text:  "meeting with friend",
+		Start: date,
+		End:   date,
+	}
+
+	err := repo.Create(ctx, userID, uid, event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events, err := repo.GetEventsForDay(ctx, userID, date)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+
+	expected := event.Text
+	actual := events[uid].Text
+
+	if actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+}
+
+func testCreateAndUpdate(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	repo := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	uid := uuid.New()
+	date := time.Now()
+
+	newDate := date.Add(24 * time.Hour)
+
+	err := repo.Create(ctx, userID, uid, entity.Event{
+		Text:  "old",
+		Start: date,
+		End:   date,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = repo.Update(ctx, userID, uid, entity.Event{Text: "new", Start: newDate, End: newDate})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// запрашиваю со старой датой
+	events, err := repo.GetEventsForDay(ctx, userID, date)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// ожидаю пустой ответ
+	if len(events) != 0 {
+		t.Fatalf("expected 0 events, got %d", len(events))
+	}
+
+	// запрашиваю с новой датой
+	events, err = repo.GetEventsForDay(ctx, userID, newDate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if events[uid].Text != "new" {
+		t.Fatalf("expected updated text, got old")
+	}
+}
+
+func testCreateAndDelete(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	repo := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	uid := uuid.New()
+	date := time.Now()
+
+	err := repo.Create(ctx, userID, uid, entity.Event{
+		Text:  "meeting with friend",
+		Start: date,
+		End:   date,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = repo.Delete(ctx, userID, uid, 0, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events, err := repo.GetEventsForDay(ctx, userID, date)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected 0 events, got %d", len(events))
+	}
+}
+
+func testEventsSpanningSeveralDays(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	repo := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	overnight, vacation := uuid.New(), uuid.New()
+
+	err := repo.Create(ctx, userID, overnight, entity.Event{
+		Text:  "overnight deploy",
+		Start: time.Date(2026, 1, 1, 23, 0, 0, 0, time.UTC),
+		End:   time.Date(2026, 1, 2, 1, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = repo.Create(ctx, userID, vacation, entity.Event{
+		Text:   "vacation",
+		Start:  time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC),
+		End:    time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC),
+		AllDay: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		day      time.Time
+		expected []uuid.UUID
+	}{
+		{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), []uuid.UUID{overnight}},
+		{time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), []uuid.UUID{overnight}},
+		{time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC), nil},
+		{time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC), nil},
+		{time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), []uuid.UUID{vacation}},
+		{time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC), []uuid.UUID{vacation}},
+		{time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC), nil},
+		// all-day events keep their days in any time zone.
+		{time.Date(2026, 1, 7, 0, 0, 0, 0, la), []uuid.UUID{vacation}},
+		{time.Date(2026, 1, 8, 0, 0, 0, 0, la), nil},
+	}
+
+	for _, tt := range tests {
+		events, err := repo.GetEventsForDay(ctx, userID, tt.day)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(events) != len(tt.expected) {
+			t.Fatalf("%s: expected %d events, got %d", tt.day, len(tt.expected), len(events))
+		}
+
+		for _, uid := range tt.expected {
+			if _, ok := events[uid]; !ok {
+				t.Fatalf("%s: expected event %s", tt.day, uid)
+			}
+		}
+	}
+
+	events, err := repo.GetEventsForWeek(ctx, userID, time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+
+	events, err = repo.GetEventsForMonth(ctx, userID, time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+}
+
+func testDayBoundariesInTimeZone(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	repo := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	uid := uuid.New()
+
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 2026-03-08 is 23 hours long in Los Angeles: clocks jump from 02:00 to 03:00.
+	start := time.Date(2026, 3, 8, 23, 30, 0, 0, la)
+
+	err = repo.Create(ctx, userID, uid, entity.Event{
+		Text:     "late call",
+		Start:    start.UTC(),
+		End:      start.Add(15 * time.Minute).UTC(),
+		TimeZone: la.String(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		day      time.Time
+		expected int
+	}{
+		{time.Date(2026, 3, 8, 0, 0, 0, 0, la), 1},
+		{time.Date(2026, 3, 9, 0, 0, 0, 0, la), 0},
+		// it is already the next day in UTC.
+		{time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), 0},
+		{time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), 1},
+	}
+
+	for _, tt := range tests {
+		events, err := repo.GetEventsForDay(ctx, userID, tt.day)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(events) != tt.expected {
+			t.Fatalf("%s: expected %d events, got %d", tt.day, tt.expected, len(events))
+		}
+	}
+
+	events, err := repo.GetEventsForDay(ctx, userID, time.Date(2026, 3, 8, 0, 0, 0, 0, la))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if events[uid].TimeZone != la.String() {
+		t.Fatalf("expected %q, got %q", la.String(), events[uid].TimeZone)
+	}
+}
+
+func testRecurringSeries(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	repo := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	endless, finished := uuid.New(), uuid.New()
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+
+	series := map[uuid.UUID]entity.Event{
+		endless: {
+			Text:     "stand-up",
+			Start:    start,
+			End:      start.Add(15 * time.Minute),
+			TimeZone: "UTC",
+			RRule:    "FREQ=WEEKLY;BYDAY=MO",
+			ExDates:  []time.Time{start.AddDate(0, 0, 7)},
+		},
+		finished: {
+			Text:     "course",
+			Start:    start,
+			End:      start.Add(time.Hour),
+			TimeZone: "UTC",
+			RRule:    "FREQ=DAILY;COUNT=3",
+		},
+	}
+
+	for uid, event := range series {
+		if err := repo.Create(ctx, userID, uid, event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	events, err := repo.GetEventsForMonth(ctx, userID, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("expected only the endless series, got %d events", len(events))
+	}
+
+	got := events[endless]
+	if got.RRule != series[endless].RRule || len(got.ExDates) != 1 || !got.ExDates[0].Equal(series[endless].ExDates[0]) {
+		t.Fatalf("expected %+v, got %+v", series[endless], got)
+	}
+
+	events, err = repo.GetEventsForDay(ctx, userID, start.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected both series, got %d events", len(events))
+	}
+
+	events, err = repo.GetEventsForDay(ctx, userID, start.AddDate(0, 0, -2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(events) != 0 {
+		t.Fatalf("expected no series before their start, got %d events", len(events))
+	}
+}
+
+func testGetByUID(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	repo := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	uid := uuid.New()
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	// the first instance moves to the previous week.
+	event := entity.Event{
+		Text:     "stand-up",
+		Start:    start,
+		End:      start.Add(15 * time.Minute),
+		TimeZone: "UTC",
+		RRule:    "FREQ=WEEKLY;COUNT=3",
+		ExDates:  []time.Time{start.AddDate(0, 0, 14)},
+		Overrides: []entity.Override{{
+			RecurrenceID: start,
+			Start:        start.AddDate(0, 0, -3),
+			End:          start.AddDate(0, 0, -3).Add(time.Hour),
+			Text:         "moved",
+		}},
+	}
+
+	_, err := repo.GetByUID(ctx, userID, uid)
+	if !errors.Is(err, errs.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	if err = repo.Create(ctx, userID, uid, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := repo.GetByUID(ctx, userID, uid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Text != event.Text || !got.Start.Equal(event.Start) || got.RRule != event.RRule ||
+		len(got.ExDates) != 1 || len(got.Overrides) != 1 || !got.Overrides[0].Start.Equal(event.Overrides[0].Start) {
+		t.Fatalf("expected %+v, got %+v", event, got)
+	}
+
+	_, err = repo.GetByUID(ctx, userID, uuid.New())
+	if !errors.Is(err, errs.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+
+	// found through the override, before the series start.
+	events, err := repo.GetEventsForDay(ctx, userID, start.AddDate(0, 0, -3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("expected the series, got %d events", len(events))
+	}
+}
+
+func testGetAll(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	repo := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	_, err := repo.GetAll(ctx, userID)
+	if !errors.Is(err, errs.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	events := map[uuid.UUID]entity.Event{
+		uuid.New(): {Text: "past", Start: start.AddDate(-3, 0, 0), End: start.AddDate(-3, 0, 0).Add(time.Hour), TimeZone: "UTC"},
+		uuid.New(): {Text: "future", Start: start.AddDate(3, 0, 0), End: start.AddDate(3, 0, 0).Add(time.Hour), TimeZone: "UTC"},
+		uuid.New(): {Text: "series", Start: start, End: start.Add(time.Hour), TimeZone: "UTC", RRule: "FREQ=DAILY"},
+	}
+
+	for uid, event := range events {
+		if err = repo.Create(ctx, userID, uid, event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	other := entity.Event{Text: "other user", Start: start, End: start.Add(time.Hour), TimeZone: "UTC"}
+	if err = repo.Create(ctx, userID+1, uuid.New(), other); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := repo.GetAll(ctx, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != len(events) {
+		t.Fatalf("expected %d events, got %d", len(events), len(got))
+	}
+
+	for uid, event := range events {
+		if got[uid].Text != event.Text || !got[uid].Start.Equal(event.Start) {
+			t.Fatalf("expected %+v, got %+v", event, got[uid])
+		}
+	}
+}
+
+func testGetEventsForRange(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	repo := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	call, vacation, series := uuid.New(), uuid.New(), uuid.New()
+
+	_, err := repo.GetEventsForRange(ctx, userID, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		entity.PageRequest{Limit: 10})
+	if !errors.Is(err, errs.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	events := map[uuid.UUID]entity.Event{
+		call: {
+			Text: "call", TimeZone: "UTC",
+			Start: time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC), End: time.Date(2026, 1, 10, 10, 0, 0, 0, time.UTC),
+		},
+		vacation: {
+			Text: "vacation", TimeZone: "UTC", AllDay: true,
+			Start: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC),
+		},
+		series: {
+			Text: "monthly", TimeZone: "UTC", RRule: "FREQ=MONTHLY;COUNT=3",
+			Start: time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC), End: time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for uid, event := range events {
+		if err = repo.Create(ctx, userID, uid, event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		expected []uuid.UUID
+	}{
+		{
+			"january",
+			time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			[]uuid.UUID{call, vacation, series},
+		},
+		{
+			"to is exclusive",
+			time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC),
+			nil,
+		},
+		{
+			"inside the vacation",
+			time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC),
+			[]uuid.UUID{vacation},
+		},
+		{
+			"after the series",
+			time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		page, err := repo.GetEventsForRange(ctx, userID, tt.from, tt.to, entity.PageRequest{Limit: 10})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+
+		got := maps.Clone(page.Series)
+		if got == nil {
+			got = make(map[uuid.UUID]entity.Event)
+		}
+
+		for _, o := range page.Events {
+			got[o.UID] = o.Event
+		}
+
+		if len(got) != len(tt.expected) {
+			t.Fatalf("%s: expected %d events, got %d", tt.name, len(tt.expected), len(got))
+		}
+
+		for _, uid := range tt.expected {
+			if _, ok := got[uid]; !ok {
+				t.Fatalf("%s: expected event %s", tt.name, uid)
+			}
+		}
+	}
+}
+
+func testGetEventsForRangePages(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	repo := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	from, to := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	series := uuid.New()
+
+	err := repo.Create(ctx, userID, series, entity.Event{
+		Text: "daily", TimeZone: "UTC", RRule: "FREQ=DAILY",
+		Start: time.Date(2025, 12, 1, 8, 0, 0, 0, time.UTC), End: time.Date(2025, 12, 1, 8, 30, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// two events share each start, so they are ordered by uid
+	var cursors []entity.Cursor
+
+	for day := 1; day <= 4; day++ {
+		start := time.Date(2026, 1, day*5, 9, 0, 0, 0, time.UTC)
+
+		for range 2 {
+			uid := uuid.New()
+
+			event := entity.Event{Text: "call", TimeZone: "UTC", Start: start, End: start.Add(time.Hour)}
+			if err = repo.Create(ctx, userID, uid, event); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			cursors = append(cursors, entity.Cursor{Start: start, UID: uid})
+		}
+	}
+
+	slices.SortFunc(cursors, entity.Cursor.Compare)
+
+	for _, desc := range []bool{false, true} {
+		expected := slices.Clone(cursors)
+		if desc {
+			slices.Reverse(expected)
+		}
+
+		var (
+			got   []entity.Cursor
+			after *entity.Cursor
+		)
+
+		for {
+			page, err := repo.GetEventsForRange(ctx, userID, from, to, entity.PageRequest{Limit: 3, After: after, Desc: desc})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if _, ok := page.Series[series]; !ok {
+				t.Fatalf("desc %v: expected the series", desc)
+			}
+
+			if len(page.Events) > 3 {
+				t.Fatalf("desc %v: expected at most 3 events, got %d", desc, len(page.Events))
+			}
+
+			for _, o := range page.Events {
+				if o.UID == series {
+					t.Fatalf("desc %v: expected the series only among the series", desc)
+				}
+
+				got = append(got, entity.Cursor{Start: o.Start, UID: o.UID})
+			}
+
+			if len(page.Events) < 3 {
+				break
+			}
+
+			after = &got[len(got)-1]
+		}
+
+		if len(got) != len(expected) {
+			t.Fatalf("desc %v: expected %d events, got %d", desc, len(expected), len(got))
+		}
+
+		for i := range expected {
+			if !got[i].Start.Equal(expected[i].Start) || got[i].UID != expected[i].UID {
+				t.Fatalf("desc %v: expected %v at %d, got %v", desc, expected[i], i, got[i])
+			}
+		}
+	}
+}
+
+func testUpdateFields(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	repo := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	uid := uuid.New()
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	event := entity.Event{
+		Text:     "stand-up",
+		Start:    start,
+		End:      start.Add(15 * time.Minute),
+		TimeZone: "UTC",
+		RRule:    "FREQ=DAILY;COUNT=5",
+		ExDates:  []time.Time{start.AddDate(0, 0, 1)},
+		Overrides: []entity.Override{{
+			RecurrenceID: start.AddDate(0, 0, 2),
+			Start:        start.AddDate(0, 0, 2).Add(time.Hour),
+			End:          start.AddDate(0, 0, 2).Add(2 * time.Hour),
+			Text:         "later",
+		}},
+	}
+
+	_, err := repo.UpdateFields(ctx, userID, uid, entity.Event{Text: "x"}, entity.FieldText)
+	if !errors.Is(err, errs.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	if err = repo.Create(ctx, userID, uid, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// fields out of the mask keep their values, the series and its overrides stay.
+	got, err := repo.UpdateFields(ctx, userID, uid, entity.Event{Text: "daily", Start: start.Add(time.Hour)}, entity.FieldText)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, err := repo.GetByUID(ctx, userID, uid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, e := range []entity.Event{got, stored} {
+		if e.Text != "daily" || !e.Start.Equal(start) || e.RRule != event.RRule || len(e.ExDates) != 1 || len(e.Overrides) != 1 {
+			t.Fatalf("expected only text to change, got %+v", e)
+		}
+	}
+
+	// ending the recurrence drops exdates and overrides.
+	if _, err = repo.UpdateFields(ctx, userID, uid, entity.Event{}, entity.FieldRRule); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stored, err = repo.GetByUID(ctx, userID, uid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stored.RRule != "" || len(stored.ExDates) != 0 || len(stored.Overrides) != 0 || stored.Text != "daily" {
+		t.Fatalf("expected a single event, got %+v", stored)
+	}
+
+	_, err = repo.UpdateFields(ctx, userID, uuid.New(), entity.Event{Text: "x"}, entity.FieldText)
+	if !errors.Is(err, errs.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+}
+
+func testVersions(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	repo := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	uid := uuid.New()
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	event := entity.Event{Text: "stand-up", Start: start, End: start.Add(15 * time.Minute), TimeZone: "UTC"}
+
+	if err := repo.Create(ctx, userID, uid, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, err := repo.GetByUID(ctx, userID, uid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stored.Version != 1 {
+		t.Fatalf("expected version 1, got %d", stored.Version)
+	}
+
+	event.Version = 1
+
+	version, err := repo.Update(ctx, userID, uid, event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if version != 2 {
+		t.Fatalf("expected version 2, got %d", version)
+	}
+
+	// writes expecting a stale version fail and change nothing.
+	if _, err = repo.Update(ctx, userID, uid, event); !errors.Is(err, errs.ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+
+	_, err = repo.UpdateFields(ctx, userID, uid, entity.Event{Text: "x", Version: 1}, entity.FieldText)
+	if !errors.Is(err, errs.ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+
+	if err = repo.Delete(ctx, userID, uid, 1, time.Now()); !errors.Is(err, errs.ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+
+	// version 0 expects any.
+	got, err := repo.UpdateFields(ctx, userID, uid, entity.Event{Text: "daily"}, entity.FieldText)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Version != 3 {
+		t.Fatalf("expected version 3, got %d", got.Version)
+	}
+
+	if stored, err = repo.GetByUID(ctx, userID, uid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stored.Version != 3 || stored.Text != "daily" {
+		t.Fatalf("expected version 3 of the patched event, got %+v", stored)
+	}
+
+	// a missing event is not a failed precondition.
+	if _, err = repo.Update(ctx, userID, uuid.New(), event); !errors.Is(err, errs.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+
+	if err = repo.Delete(ctx, userID, uid, 3, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = repo.GetByUID(ctx, userID, uid); !errors.Is(err, errs.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+}
+
+func testWithinTx(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	events := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	event := entity.Event{Text: "stand-up", Start: start, End: start.Add(15 * time.Minute), TimeZone: "UTC"}
+	kept, moved, dropped, other := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	for _, uid := range []uuid.UUID{kept, moved} {
+		if err := events.Create(ctx, userID, uid, event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// a user the transactions only read.
+	if err := events.Create(ctx, userID+1, other, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	errAbort := errors.New("abort")
+
+	// a failed transaction changes nothing.
+	err := events.WithinTx(ctx, func(tx repo.EventsRepo) error {
+		if err := tx.Create(ctx, userID, dropped, event); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(ctx, userID, kept, 0, time.Now()); err != nil {
+			return err
+		}
+
+		if _, err := tx.GetByUID(ctx, userID, dropped); err != nil {
+			return err
+		}
+
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected errAbort, got %v", err)
+	}
+
+	if _, err = events.GetByUID(ctx, userID, dropped); !errors.Is(err, errs.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+
+	if _, err = events.GetByUID(ctx, userID, kept); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// a failed write or nested transaction leaves the rest of it.
+	err = events.WithinTx(ctx, func(tx repo.EventsRepo) error {
+		if err := tx.Create(ctx, userID, kept, event); !errors.Is(err, errs.ErrAlreadyExists) {
+			t.Fatalf("expected ErrAlreadyExists, got %v", err)
+		}
+
+		err := tx.WithinTx(ctx, func(nested repo.EventsRepo) error {
+			if err := nested.Create(ctx, userID, dropped, event); err != nil {
+				return err
+			}
+
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("expected errAbort, got %v", err)
+		}
+
+		moving := event
+		moving.Start, moving.End = start.Add(time.Hour), start.Add(2*time.Hour)
+
+		if _, err = tx.Update(ctx, userID, moved, moving); err != nil {
+			return err
+		}
+
+		return tx.WithinTx(ctx, func(nested repo.EventsRepo) error {
+			if _, err := nested.GetByUID(ctx, userID+1, other); err != nil {
+				return err
+			}
+
+			return nested.Delete(ctx, userID, kept, 1, time.Now())
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = events.GetByUID(ctx, userID+1, other); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := events.GetEventsForDay(ctx, userID, start)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != 1 || !got[moved].Start.Equal(start.Add(time.Hour)) || got[moved].Version != 2 {
+		t.Fatalf("expected only the moved event, got %+v", got)
+	}
+}
+
+// txState - what a repo holds of a user, compared before and after a failed transaction.
+type txState struct {
+	All       map[uuid.UUID]entity.Event
+	Week      map[uuid.UUID]entity.Event
+	Trash     []entity.TrashedEvent
+	Revisions []entity.Revision
+	Changes   []entity.Change
+	Seq       int64
+}
+
+func stateOf(t *testing.T, events repo.EventsRepo, userID int, revised uuid.UUID, week time.Time) txState {
+	t.Helper()
+
+	ctx := context.Background()
+
+	var (
+		state txState
+		err   error
+	)
+
+	if state.All, err = events.GetAll(ctx, userID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if state.Week, err = events.GetEventsForWeek(ctx, userID, week); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if state.Trash, err = events.GetTrash(ctx, userID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	slices.SortFunc(state.Trash, func(a, b entity.TrashedEvent) int {
+		return strings.Compare(a.UID.String(), b.UID.String())
+	})
+
+	if state.Revisions, err = events.GetRevisions(ctx, userID, revised); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if state.Changes, state.Seq, err = events.GetChanges(ctx, userID, 0, 100); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return state
+}
+
+func testWithinTxRollback(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	events := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	event := entity.Event{Text: "stand-up", Start: start, End: start.Add(15 * time.Minute), TimeZone: "UTC"}
+	series := entity.Event{Text: "gym", Start: start, End: start.Add(time.Hour), TimeZone: "UTC", RRule: "FREQ=DAILY"}
+	kept, restored, trashed, recurring, created := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	for _, uid := range []uuid.UUID{kept, restored, trashed} {
+		if err := events.Create(ctx, userID, uid, event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := events.Create(ctx, userID, recurring, series); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := events.AddRevision(ctx, userID, kept, entity.Revision{Op: entity.RevisionCreate, At: start}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := events.Delete(ctx, userID, restored, 0, start); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	before := stateOf(t, events, userID, kept, start)
+	errAbort := errors.New("abort")
+
+	// every kind of write of a failed transaction is undone, those of a nested one it kept too.
+	err := events.WithinTx(ctx, func(tx repo.EventsRepo) error {
+		if _, err := tx.Restore(ctx, userID, restored); err != nil {
+			return err
+		}
+
+		moving := event
+		moving.Start, moving.End = start.AddDate(0, 0, 14), start.AddDate(0, 0, 14).Add(time.Hour)
+
+		if _, err := tx.Update(ctx, userID, kept, moving); err != nil {
+			return err
+		}
+
+		if _, err := tx.UpdateFields(ctx, userID, recurring, entity.Event{Text: "pool"}, entity.FieldText); err != nil {
+			return err
+		}
+
+		if err := tx.AddRevision(ctx, userID, kept, entity.Revision{Op: entity.RevisionUpdate, At: start}); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(ctx, userID, trashed, 0, start); err != nil {
+			return err
+		}
+
+		if _, err := tx.PurgeTrash(ctx, start.Add(time.Hour)); err != nil {
+			return err
+		}
+
+		if err := tx.Create(ctx, userID+1, created, event); err != nil {
+			return err
+		}
+
+		err := tx.WithinTx(ctx, func(nested repo.EventsRepo) error {
+			return nested.Create(ctx, userID, created, event)
+		})
+		if err != nil {
+			return err
+		}
+
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected errAbort, got %v", err)
+	}
+
+	if after := stateOf(t, events, userID, kept, start); !reflect.DeepEqual(before, after) {
+		t.Fatalf("expected the state kept\nbefore %+v\nafter  %+v", before, after)
+	}
+
+	if _, err = events.GetAll(ctx, userID+1); !errors.Is(err, errs.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	// the repo is written as before.
+	if err = events.Create(ctx, userID, created, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, seq, err := events.GetChanges(ctx, userID, 0, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if seq != before.Seq+1 {
+		t.Fatalf("expected change %d, got %d", before.Seq+1, seq)
+	}
+}
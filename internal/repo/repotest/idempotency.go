@@ -0,0 +1,84 @@
+package repotest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+)
+
+// Idempotency runs the tests every repo.IdempotencyRepo passes, newRepo returns an empty one.
+func Idempotency(t *testing.T, newRepo func(t *testing.T) repo.IdempotencyRepo) {
+	repo := newRepo(t)
+
+	ctx := context.Background()
+	now := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	key := entity.IdempotencyKey{Key: "retry-1", Fingerprint: "create", ExpiresAt: now.Add(time.Hour)}
+
+	if _, err := repo.Reserve(ctx, key, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the request is being handled.
+	other := entity.IdempotencyKey{Key: key.Key, Fingerprint: "other", ExpiresAt: now.Add(2 * time.Hour)}
+
+	stored, err := repo.Reserve(ctx, other, now)
+	if !errors.Is(err, errs.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+
+	if stored.Fingerprint != key.Fingerprint || stored.Response != nil || !stored.ExpiresAt.Equal(key.ExpiresAt) {
+		t.Fatalf("expected the key in progress, got %+v", stored)
+	}
+
+	response := entity.StoredResponse{
+		Status: 201,
+		Header: map[string]string{"ETag": `"1"`},
+		Body:   []byte(`{"uid":"dff704a8-5532-47e2-aea4-e4a13b2f75ae"}`),
+	}
+
+	if err = repo.Complete(ctx, key.Key, response); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, err = repo.Reserve(ctx, key, now.Add(time.Minute))
+	if !errors.Is(err, errs.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+
+	if stored.Response == nil || stored.Response.Status != response.Status ||
+		stored.Response.Header["ETag"] != `"1"` || !bytes.Equal(stored.Response.Body, response.Body) {
+		t.Fatalf("expected the stored response, got %+v", stored.Response)
+	}
+
+	// an expired key is reserved anew.
+	if _, err = repo.Reserve(ctx, other, now.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err = repo.Release(ctx, key.Key); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = repo.Reserve(ctx, key, now.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// completing a released key stores nothing.
+	if err = repo.Release(ctx, key.Key); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err = repo.Complete(ctx, key.Key, response); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = repo.Reserve(ctx, key, now.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+// Package repotest - tests every backend of the repositories passes, run from the package of each.
+package repotest
+
+import (
+	"testing"
+
+	"github.com/andreyxaxa/calendar/internal/repo"
+)
+
+// Events runs the tests every repo.EventsRepo passes, newRepo returns an empty one.
+func Events(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	for _, test := range []struct {
+		name string
+		run  func(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo)
+	}{
+		{"CreateAndGetForDay", testCreateAndGetForDay},
+		{"CreateAndUpdate", testCreateAndUpdate},
+		{"CreateAndDelete", testCreateAndDelete},
+		{"EventsSpanningSeveralDays", testEventsSpanningSeveralDays},
+		{"DayBoundariesInTimeZone", testDayBoundariesInTimeZone},
+		{"RecurringSeries", testRecurringSeries},
+		{"GetByUID", testGetByUID},
+		{"GetAll", testGetAll},
+		{"GetEventsForRange", testGetEventsForRange},
+		{"GetEventsForRangePages", testGetEventsForRangePages},
+		{"UpdateFields", testUpdateFields},
+		{"Versions", testVersions},
+		{"WithinTx", testWithinTx},
+		{"WithinTxRollback", testWithinTxRollback},
+		{"Trash", testTrash},
+		{"Revisions", testRevisions},
+		{"Changes", testChanges},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test.run(t, newRepo)
+		})
+	}
+}
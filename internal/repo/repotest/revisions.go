@@ -0,0 +1,91 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+func testRevisions(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	repo := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	uid := uuid.New()
+
+	event := entity.Event{
+		Start:    start,
+		End:      start.Add(time.Hour),
+		TimeZone: "UTC",
+		Text:     "stand-up",
+		RRule:    "FREQ=DAILY;COUNT=3",
+		Version:  1,
+	}
+
+	if err := repo.Create(ctx, userID, uid, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updated := event
+	updated.Text, updated.Version = "retro", 2
+
+	revisions := []entity.Revision{
+		{Op: entity.RevisionCreate, Actor: "user:1", At: at, After: &event},
+		{Op: entity.RevisionUpdate, Actor: "alice", At: at.Add(time.Minute), Before: &event, After: &updated},
+		{Op: entity.RevisionDelete, Actor: "alice", At: at.Add(time.Hour), Before: &updated},
+	}
+
+	for _, revision := range revisions {
+		if err := repo.AddRevision(ctx, userID, uid, revision); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	got, err := repo.GetRevisions(ctx, userID, uid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != len(revisions) {
+		t.Fatalf("expected %d revisions, got %+v", len(revisions), got)
+	}
+
+	for i, revision := range got {
+		want := revisions[i]
+		if revision.Number != int64(i+1) || revision.Op != want.Op || revision.Actor != want.Actor ||
+			!revision.At.Equal(want.At) || (revision.Before == nil) != (want.Before == nil) ||
+			(revision.After == nil) != (want.After == nil) {
+			t.Fatalf("unexpected revision %d: %+v", i+1, revision)
+		}
+	}
+
+	if got[1].Before.Text != event.Text || got[1].After.Text != updated.Text || got[1].After.Version != 2 ||
+		got[1].After.RRule != event.RRule || !got[1].After.Start.Equal(start) {
+		t.Fatalf("unexpected events of revision 2: %+v, %+v", got[1].Before, got[1].After)
+	}
+
+	if changes := got[1].Changes(); len(changes) != 1 || changes[0].Field != "text" {
+		t.Fatalf("expected only text changed, got %+v", changes)
+	}
+
+	got, err = repo.GetRevisions(ctx, userID, uuid.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != 0 {
+		t.Fatalf("expected no revisions, got %+v", got)
+	}
+
+	if _, err = repo.GetRevisions(ctx, 2, uid); !errors.Is(err, errs.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+func testTrash(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	repo := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	deletedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	series, single := uuid.New(), uuid.New()
+
+	event := entity.Event{
+		Start:    start,
+		End:      start.Add(time.Hour),
+		TimeZone: "UTC",
+		Text:     "stand-up",
+		RRule:    "FREQ=DAILY;COUNT=3",
+	}
+
+	for _, uid := range []uuid.UUID{series, single} {
+		if err := repo.Create(ctx, userID, uid, event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := repo.Delete(ctx, userID, series, 2, deletedAt); !errors.Is(err, errs.ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+
+	if err := repo.Delete(ctx, userID, series, 1, deletedAt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events, err := repo.GetEventsForDay(ctx, userID, start)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := events[series]; ok || len(events) != 1 {
+		t.Fatalf("expected only the event not deleted, got %v", events)
+	}
+
+	if _, err = repo.GetByUID(ctx, userID, series); !errors.Is(err, errs.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+
+	trash, err := repo.GetTrash(ctx, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(trash) != 1 || trash[0].UID != series || !trash[0].DeletedAt.Equal(deletedAt) ||
+		trash[0].RRule != event.RRule || trash[0].Text != event.Text || trash[0].Version != 1 {
+		t.Fatalf("unexpected trash %+v", trash)
+	}
+
+	if _, err = repo.Restore(ctx, userID, single); !errors.Is(err, errs.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+
+	restored, err := repo.Restore(ctx, userID, series)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if restored.Version != 2 || restored.Text != event.Text || !restored.Start.Equal(start) {
+		t.Fatalf("unexpected restored event %+v", restored)
+	}
+
+	events, err = repo.GetEventsForDay(ctx, userID, start)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := events[series]; !ok {
+		t.Fatalf("expected the restored event, got %v", events)
+	}
+
+	// the uid of a trashed event is taken by a new one.
+	if err = repo.Delete(ctx, userID, series, 0, deletedAt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err = repo.Create(ctx, userID, series, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = repo.Restore(ctx, userID, series); !errors.Is(err, errs.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+
+	if err = repo.Delete(ctx, userID, single, 0, deletedAt.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	purged, err := repo.PurgeTrash(ctx, deletedAt.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if purged != 1 {
+		t.Fatalf("expected 1 purged event, got %d", purged)
+	}
+
+	trash, err = repo.GetTrash(ctx, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(trash) != 1 || trash[0].UID != single {
+		t.Fatalf("unexpected trash %+v", trash)
+	}
+
+	if _, err = repo.GetTrash(ctx, 2); !errors.Is(err, errs.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	if _, err = repo.Restore(ctx, 2, single); !errors.Is(err, errs.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
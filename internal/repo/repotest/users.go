@@ -0,0 +1,40 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+)
+
+// Users runs the tests every repo.UsersRepo passes, newRepo returns an empty one.
+func Users(t *testing.T, newRepo func(t *testing.T) repo.UsersRepo) {
+	users := newRepo(t)
+
+	ctx := context.Background()
+	userID := 1
+
+	_, err := users.Get(ctx, userID)
+	if !errors.Is(err, errs.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	for _, tz := range []string{"Asia/Vladivostok", "America/Los_Angeles"} {
+		err = users.Save(ctx, entity.User{ID: userID, TimeZone: tz})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		user, err := users.Get(ctx, userID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if user.TimeZone != tz {
+			t.Fatalf("expected %q, got %q", tz, user.TimeZone)
+		}
+	}
+}
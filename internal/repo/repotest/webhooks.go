@@ -0,0 +1,146 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+// Webhooks runs the tests every repo.WebhooksRepo passes, newRepo returns an empty one.
+func Webhooks(t *testing.T, newRepo func(t *testing.T) repo.WebhooksRepo) {
+	repo := newRepo(t)
+
+	ctx := context.Background()
+	now := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	all := entity.Webhook{ID: uuid.New(), UserID: 1, URL: "http://bot.local/hook", Secret: "s1", CreatedAt: now}
+	deleted := entity.Webhook{
+		ID:        uuid.New(),
+		UserID:    1,
+		URL:       "http://crm.local/hook",
+		Secret:    "s2",
+		Kinds:     []entity.NotificationKind{entity.NotificationDeleted},
+		CreatedAt: now.Add(time.Minute),
+	}
+	other := entity.Webhook{ID: uuid.New(), UserID: 2, URL: "http://other.local/hook", Secret: "s3", CreatedAt: now}
+
+	for _, w := range []entity.Webhook{all, deleted, other} {
+		if err := repo.CreateWebhook(ctx, w); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	webhooks, err := repo.GetWebhooks(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(webhooks) != 2 || webhooks[0].ID != all.ID || webhooks[0].Kinds != nil || webhooks[1].ID != deleted.ID ||
+		!slices.Equal(webhooks[1].Kinds, deleted.Kinds) || webhooks[1].Secret != deleted.Secret ||
+		!webhooks[1].CreatedAt.Equal(deleted.CreatedAt) {
+		t.Fatalf("expected webhooks of the user, got %+v", webhooks)
+	}
+
+	delivery := func(w entity.Webhook, nextAttemptAt time.Time) entity.Delivery {
+		return entity.Delivery{
+			ID:            uuid.New(),
+			WebhookID:     w.ID,
+			UserID:        w.UserID,
+			Kind:          entity.NotificationCreated,
+			Payload:       []byte(`{"type":"created"}`),
+			Status:        entity.DeliveryPending,
+			NextAttemptAt: nextAttemptAt,
+			CreatedAt:     now,
+		}
+	}
+
+	first := delivery(all, now)
+	later := delivery(all, now.Add(time.Hour))
+	earlier := delivery(other, now.Add(-time.Minute))
+
+	if err = repo.AddDeliveries(ctx, []entity.Delivery{first, later, earlier}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	due, err := repo.GetDueDeliveries(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(due) != 2 || due[0].ID != earlier.ID || due[1].ID != first.ID || due[1].URL != all.URL ||
+		due[1].Secret != all.Secret || string(due[1].Payload) != string(first.Payload) {
+		t.Fatalf("expected due deliveries, the earliest first, got %+v", due)
+	}
+
+	if due, err = repo.GetDueDeliveries(ctx, now, 1); err != nil || len(due) != 1 || due[0].ID != earlier.ID {
+		t.Fatalf("expected the earliest due delivery, got %+v, %v", due, err)
+	}
+
+	failed := entity.DeliveryAttempt{At: now, StatusCode: 500, Error: "status 500", Duration: 30 * time.Millisecond}
+	first.NextAttemptAt = now.Add(30 * time.Second)
+
+	if err = repo.AddAttempt(ctx, first, failed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if due, err = repo.GetDueDeliveries(ctx, now, 10); err != nil || len(due) != 1 || due[0].ID != earlier.ID {
+		t.Fatalf("expected the retry to wait, got %+v, %v", due, err)
+	}
+
+	succeeded := entity.DeliveryAttempt{At: first.NextAttemptAt, StatusCode: 204, Duration: 10 * time.Millisecond}
+	first.Status = entity.DeliveryDelivered
+
+	if err = repo.AddAttempt(ctx, first, succeeded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deliveries, err := repo.GetDeliveries(ctx, 1, all.ID, "", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(deliveries) != 2 || deliveries[0].ID != later.ID || deliveries[1].ID != first.ID ||
+		deliveries[1].Status != entity.DeliveryDelivered ||
+		!slices.Equal(deliveries[1].Attempts, []entity.DeliveryAttempt{failed, succeeded}) {
+		t.Fatalf("expected deliveries of the webhook, the newest first, got %+v", deliveries)
+	}
+
+	if deliveries, err = repo.GetDeliveries(ctx, 1, all.ID, entity.DeliveryDelivered, 10); err != nil ||
+		len(deliveries) != 1 || deliveries[0].ID != first.ID {
+		t.Fatalf("expected the delivered one, got %+v, %v", deliveries, err)
+	}
+
+	if deliveries, err = repo.GetDeliveries(ctx, 1, all.ID, "", 1); err != nil ||
+		len(deliveries) != 1 || deliveries[0].ID != later.ID {
+		t.Fatalf("expected the newest one, got %+v, %v", deliveries, err)
+	}
+
+	if _, err = repo.GetDeliveries(ctx, 2, all.ID, "", 10); !errors.Is(err, errs.ErrWebhookNotFound) {
+		t.Fatalf("expected ErrWebhookNotFound, got %v", err)
+	}
+
+	if err = repo.DeleteWebhook(ctx, 2, all.ID); !errors.Is(err, errs.ErrWebhookNotFound) {
+		t.Fatalf("expected ErrWebhookNotFound, got %v", err)
+	}
+
+	if err = repo.DeleteWebhook(ctx, 1, all.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// deliveries of the webhook are gone with it.
+	if due, err = repo.GetDueDeliveries(ctx, now.Add(2*time.Hour), 10); err != nil || len(due) != 1 ||
+		due[0].ID != earlier.ID {
+		t.Fatalf("expected deliveries of other webhooks, got %+v, %v", due, err)
+	}
+
+	if webhooks, err = repo.GetWebhooks(ctx, 1); err != nil || len(webhooks) != 1 || webhooks[0].ID != deleted.ID {
+		t.Fatalf("expected the other webhook, got %+v, %v", webhooks, err)
+	}
+}
@@ -0,0 +1,112 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/repo/history"
+	"github.com/google/uuid"
+)
+
+const (
+	// _fewEvents and _manyEvents - sizes of the users whose writes are compared.
+	_fewEvents  = 100
+	_manyEvents = 20_000
+	// _writeSlack - bytes a write to the larger user may allocate above one to the smaller.
+	_writeSlack = 16 << 10
+)
+
+// Writes checks that an update with its revision, a transaction of history.EventsRepo, allocates
+// about as much for a user with many events as for one with few: a write of an in-process backend
+// must not copy the user. newRepo returns an empty repo.EventsRepo.
+func Writes(t *testing.T, newRepo func(t *testing.T) repo.EventsRepo) {
+	few := writeBytes(t, newRepo(t), _fewEvents)
+	many := writeBytes(t, newRepo(t), _manyEvents)
+
+	if many > 2*few+_writeSlack {
+		t.Fatalf("expected a write to allocate about %d bytes with %d events, got %d with %d",
+			few, _fewEvents, many, _manyEvents)
+	}
+}
+
+// BenchmarkWrites measures an update with its revision for users of different sizes.
+func BenchmarkWrites(b *testing.B, newRepo func(b *testing.B) repo.EventsRepo) {
+	for _, n := range []int{_fewEvents, _manyEvents, 5 * _manyEvents} {
+		b.Run(fmt.Sprintf("update/%d", n), func(b *testing.B) {
+			events, uids := fill(b, newRepo(b), n)
+
+			b.ReportAllocs()
+
+			i := 0
+			for b.Loop() {
+				update(b, events, uids[i%len(uids)])
+				i++
+			}
+		})
+	}
+}
+
+// writeBytes returns bytes an update allocates on average for a user with n events.
+func writeBytes(t *testing.T, r repo.EventsRepo, n int) uint64 {
+	t.Helper()
+
+	events, uids := fill(t, r, n)
+	writes := min(n, 200)
+
+	var before, after runtime.MemStats
+
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	for _, uid := range uids[:writes] {
+		update(t, events, uid)
+	}
+
+	runtime.ReadMemStats(&after)
+
+	return (after.TotalAlloc - before.TotalAlloc) / uint64(writes)
+}
+
+// fill creates n events of a user, a day apart, and returns r recording revisions of its writes.
+func fill(tb testing.TB, r repo.EventsRepo, n int) (*history.EventsRepo, []uuid.UUID) {
+	tb.Helper()
+
+	ctx := context.Background()
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	uids := make([]uuid.UUID, n)
+
+	for i := range uids {
+		uids[i] = uuid.New()
+		day := start.AddDate(0, 0, i)
+
+		event := entity.Event{Text: "event", Start: day, End: day.Add(time.Hour), TimeZone: "UTC"}
+		if err := r.Create(ctx, 1, uids[i], event); err != nil {
+			tb.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	return history.New(r), uids
+}
+
+// update moves the event an hour later.
+func update(tb testing.TB, events *history.EventsRepo, uid uuid.UUID) {
+	tb.Helper()
+
+	ctx := context.Background()
+
+	event, err := events.GetByUID(ctx, 1, uid)
+	if err != nil {
+		tb.Fatalf("unexpected error: %v", err)
+	}
+
+	event.Start, event.End = event.Start.Add(time.Hour), event.End.Add(time.Hour)
+
+	if _, err = events.Update(ctx, 1, uid, event); err != nil {
+		tb.Fatalf("unexpected error: %v", err)
+	}
+}
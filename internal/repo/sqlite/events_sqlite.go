@@ -0,0 +1,647 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/pkg/sqlite"
+	"github.com/andreyxaxa/calendar/pkg/types/date"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+	driver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// _floatingSlack widens the series bounds check so all-day series, stored
+// at UTC midnight, are found for periods in any time zone.
+const (
+	_floatingSlack = 24 * time.Hour
+
+	_eventColumns = `uid, start_at, end_at, all_day, time_zone, text, rrule, exdates, overrides, version`
+	// _trashColumns are the columns events and trash share besides user_id, uid and version.
+	_trashColumns = `start_at, end_at, all_day, time_zone, text, rrule, exdates, overrides, series_start, series_end`
+)
+
+// querier - *sql.DB or *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// EventsRepo - queries go to db: the DB, or tx in WithinTx.
+type EventsRepo struct {
+	*sqlite.SQLite
+
+	db querier
+	tx *sql.Tx
+}
+
+// New returns new EventsRepo(struct)
+func New(s *sqlite.SQLite) *EventsRepo {
+	return &EventsRepo{SQLite: s, db: s.DB}
+}
+
+// WithinTx -. Nested calls run in savepoints.
+func (r *EventsRepo) WithinTx(ctx context.Context, fn func(tx repo.EventsRepo) error) error {
+	return r.atomic(ctx, "WithinTx", func(tx *sql.Tx) error {
+		return fn(&EventsRepo{SQLite: r.SQLite, db: tx, tx: tx})
+	})
+}
+
+// atomic runs fn in a transaction, in WithinTx - in a savepoint of its one.
+// Errors of fn are returned as they are.
+func (r *EventsRepo) atomic(ctx context.Context, op string, fn func(tx *sql.Tx) error) error {
+	if r.tx != nil {
+		return savepoint(ctx, r.tx, op, fn)
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("EventsRepo - %s - r.DB.BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("EventsRepo - %s - tx.Commit: %w", op, err)
+	}
+
+	return nil
+}
+
+func savepoint(ctx context.Context, tx *sql.Tx, op string, fn func(tx *sql.Tx) error) error {
+	if _, err := tx.ExecContext(ctx, `SAVEPOINT atomic`); err != nil {
+		return fmt.Errorf("EventsRepo - %s - tx.ExecContext savepoint: %w", op, err)
+	}
+
+	if err := fn(tx); err != nil {
+		// ROLLBACK TO keeps the savepoint, RELEASE drops it.
+		if _, rbErr := tx.ExecContext(ctx, `ROLLBACK TO atomic; RELEASE atomic`); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("EventsRepo - %s - tx.ExecContext rollback to: %w", op, rbErr))
+		}
+
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `RELEASE atomic`); err != nil {
+		return fmt.Errorf("EventsRepo - %s - tx.ExecContext release: %w", op, err)
+	}
+
+	return nil
+}
+
+// Create -.
+func (r *EventsRepo) Create(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) error {
+	recurrence, err := newRecurrence(event)
+	if err != nil {
+		return fmt.Errorf("EventsRepo - Create - newRecurrence: %w", err)
+	}
+
+	return r.atomic(ctx, "Create", func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO users (id) VALUES (?) ON CONFLICT DO NOTHING`, userID)
+		if err != nil {
+			return fmt.Errorf("EventsRepo - Create - tx.ExecContext users: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO events (user_id, uid, start_at, end_at, all_day, time_zone, text,
+				rrule, exdates, overrides, series_start, series_end, version)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1)`,
+			userID, eventUID, event.Start.UnixMicro(), event.End.UnixMicro(), event.AllDay, event.TimeZone, event.Text,
+			event.RRule, recurrence.exDates, recurrence.overrides, recurrence.seriesStart, recurrence.seriesEnd,
+		)
+		if err != nil {
+			var sqliteErr *driver.Error
+			if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY {
+				return errs.ErrAlreadyExists
+			}
+
+			return fmt.Errorf("EventsRepo - Create - tx.ExecContext events: %w", err)
+		}
+
+		if err = change(ctx, tx, userID, eventUID, false); err != nil {
+			return fmt.Errorf("EventsRepo - Create - change: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Update -.
+func (r *EventsRepo) Update(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) (int64, error) {
+	var version int64
+
+	err := r.atomic(ctx, "Update", func(tx *sql.Tx) error {
+		var err error
+
+		version, err = update(ctx, tx, userID, eventUID, event)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return missed(ctx, tx, userID, eventUID, "Update")
+			}
+
+			return fmt.Errorf("EventsRepo - Update - update: %w", err)
+		}
+
+		if err = change(ctx, tx, userID, eventUID, false); err != nil {
+			return fmt.Errorf("EventsRepo - Update - change: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// UpdateFields -.
+func (r *EventsRepo) UpdateFields(ctx context.Context, userID int, eventUID uuid.UUID, patch entity.Event,
+	mask entity.FieldMask,
+) (entity.Event, error) {
+	var event entity.Event
+
+	err := r.atomic(ctx, "UpdateFields", func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx,
+			`SELECT `+_eventColumns+` FROM events WHERE user_id = ? AND uid = ?`,
+			userID, eventUID,
+		)
+
+		_, current, err := scanEvent(row)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				// the only connection is taken by tx.
+				return notFound(ctx, tx, userID, "UpdateFields")
+			}
+
+			return fmt.Errorf("EventsRepo - UpdateFields - scanEvent: %w", err)
+		}
+
+		if patch.Version != 0 && patch.Version != current.Version {
+			return errs.ErrPreconditionFailed
+		}
+
+		event = current.Merge(patch, mask)
+
+		if event.Version, err = update(ctx, tx, userID, eventUID, event); err != nil {
+			return fmt.Errorf("EventsRepo - UpdateFields - update: %w", err)
+		}
+
+		if err = change(ctx, tx, userID, eventUID, false); err != nil {
+			return fmt.Errorf("EventsRepo - UpdateFields - change: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.Event{}, err
+	}
+
+	return event, nil
+}
+
+// update writes all columns of the event if it has the expected version and returns the new one.
+// sql.ErrNoRows means the event is missing or has another version.
+func update(ctx context.Context, db querier, userID int, eventUID uuid.UUID, event entity.Event) (int64, error) {
+	recurrence, err := newRecurrence(event)
+	if err != nil {
+		return 0, fmt.Errorf("newRecurrence: %w", err)
+	}
+
+	var version int64
+
+	err = db.QueryRowContext(ctx,
+		`UPDATE events SET start_at = ?, end_at = ?, all_day = ?, time_zone = ?, text = ?,
+			rrule = ?, exdates = ?, overrides = ?, series_start = ?, series_end = ?, version = version + 1
+		WHERE user_id = ? AND uid = ? AND (? = 0 OR version = ?)
+		RETURNING version`,
+		event.Start.UnixMicro(), event.End.UnixMicro(), event.AllDay, event.TimeZone, event.Text,
+		event.RRule, recurrence.exDates, recurrence.overrides, recurrence.seriesStart, recurrence.seriesEnd,
+		userID, eventUID, event.Version, event.Version,
+	).Scan(&version)
+
+	return version, err
+}
+
+// change numbers the write of the event as the next change of the user, see repo.EventsRepo.
+func change(ctx context.Context, db querier, userID int, eventUID uuid.UUID, deleted bool) error {
+	var seq int64
+
+	err := db.QueryRowContext(ctx,
+		`UPDATE users SET change_seq = change_seq + 1 WHERE id = ? RETURNING change_seq`, userID,
+	).Scan(&seq)
+	if err != nil {
+		return fmt.Errorf("db.QueryRowContext users: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx,
+		`INSERT INTO changes (user_id, uid, seq, deleted) VALUES (?, ?, ?, ?)
+		ON CONFLICT (user_id, uid) DO UPDATE SET seq = excluded.seq, deleted = excluded.deleted`,
+		userID, eventUID, seq, deleted,
+	)
+	if err != nil {
+		return fmt.Errorf("db.ExecContext changes: %w", err)
+	}
+
+	return nil
+}
+
+// Delete -.
+func (r *EventsRepo) Delete(ctx context.Context, userID int, eventUID uuid.UUID, version int64,
+	deletedAt time.Time,
+) error {
+	return r.atomic(ctx, "Delete", func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `DELETE FROM trash WHERE user_id = ? AND uid = ?`, userID, eventUID)
+		if err != nil {
+			return fmt.Errorf("EventsRepo - Delete - tx.ExecContext trash: %w", err)
+		}
+
+		res, err := tx.ExecContext(ctx,
+			`INSERT INTO trash (user_id, uid, `+_trashColumns+`, version, deleted_at)
+			SELECT user_id, uid, `+_trashColumns+`, version, ? FROM events
+			WHERE user_id = ? AND uid = ? AND (? = 0 OR version = ?)`,
+			deletedAt.UnixMicro(), userID, eventUID, version, version,
+		)
+		if err != nil {
+			return fmt.Errorf("EventsRepo - Delete - tx.ExecContext insert: %w", err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("EventsRepo - Delete - res.RowsAffected: %w", err)
+		}
+
+		if affected == 0 {
+			return missed(ctx, tx, userID, eventUID, "Delete")
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM events WHERE user_id = ? AND uid = ?`, userID, eventUID)
+		if err != nil {
+			return fmt.Errorf("EventsRepo - Delete - tx.ExecContext events: %w", err)
+		}
+
+		if err = change(ctx, tx, userID, eventUID, true); err != nil {
+			return fmt.Errorf("EventsRepo - Delete - change: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetChanges -.
+func (r *EventsRepo) GetChanges(ctx context.Context, userID int, after int64, limit int,
+) ([]entity.Change, int64, error) {
+	var (
+		changes []entity.Change
+		seq     int64
+	)
+
+	// read in a transaction, so the changes and the events they point to agree.
+	err := r.atomic(ctx, "GetChanges", func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `SELECT change_seq FROM users WHERE id = ?`, userID).Scan(&seq)
+		if err != nil {
+			// the user never wrote: there are no changes yet.
+			if errors.Is(err, sql.ErrNoRows) {
+				changes = []entity.Change{}
+
+				return nil
+			}
+
+			return fmt.Errorf("EventsRepo - GetChanges - tx.QueryRowContext: %w", err)
+		}
+
+		if changes, err = queryChanges(ctx, tx, userID, after, limit); err != nil {
+			return fmt.Errorf("EventsRepo - GetChanges - queryChanges: %w", err)
+		}
+
+		if len(changes) == 0 {
+			return nil
+		}
+
+		view := &EventsRepo{SQLite: r.SQLite, db: tx, tx: tx}
+
+		events, err := view.queryEvents(ctx, "GetChanges",
+			`SELECT `+_eventColumns+` FROM events WHERE user_id = ?1 AND uid IN (
+				SELECT uid FROM changes WHERE user_id = ?1 AND seq > ?2 AND seq <= ?3 AND NOT deleted
+			)`,
+			userID, after, changes[len(changes)-1].Seq,
+		)
+		if err != nil {
+			return err
+		}
+
+		for i, ch := range changes {
+			if !ch.Deleted {
+				changes[i].Event = events[ch.UID]
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return changes, seq, nil
+}
+
+// queryChanges returns up to limit changes of the user numbered after the position, without events.
+func queryChanges(ctx context.Context, db querier, userID int, after int64, limit int) ([]entity.Change, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT uid, seq, deleted FROM changes WHERE user_id = ? AND seq > ? ORDER BY seq LIMIT ?`,
+		userID, after, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("db.QueryContext: %w", err)
+	}
+	defer rows.Close()
+
+	changes := make([]entity.Change, 0)
+
+	for rows.Next() {
+		var ch entity.Change
+
+		if err = rows.Scan(&ch.UID, &ch.Seq, &ch.Deleted); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		changes = append(changes, ch)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return changes, nil
+}
+
+// GetByUID -.
+func (r *EventsRepo) GetByUID(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+_eventColumns+` FROM events WHERE user_id = ? AND uid = ?`,
+		userID, eventUID,
+	)
+
+	_, event, err := scanEvent(row)
+	if err == nil {
+		return event, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return entity.Event{}, fmt.Errorf("EventsRepo - GetByUID - scanEvent: %w", err)
+	}
+
+	exists, err := userExists(ctx, r.db, userID)
+	if err != nil {
+		return entity.Event{}, fmt.Errorf("EventsRepo - GetByUID - userExists: %w", err)
+	}
+
+	if !exists {
+		return entity.Event{}, errs.ErrUserNotFound
+	}
+
+	return entity.Event{}, errs.ErrEventNotFound
+}
+
+// GetAll -.
+func (r *EventsRepo) GetAll(ctx context.Context, userID int) (map[uuid.UUID]entity.Event, error) {
+	exists, err := userExists(ctx, r.db, userID)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - GetAll - userExists: %w", err)
+	}
+
+	if !exists {
+		return nil, errs.ErrUserNotFound
+	}
+
+	return r.queryEvents(ctx, "GetAll", `SELECT `+_eventColumns+` FROM events WHERE user_id = ?`, userID)
+}
+
+// GetEventsForDay -.
+func (r *EventsRepo) GetEventsForDay(ctx context.Context, userID int, d time.Time) (map[uuid.UUID]entity.Event, error) {
+	from, to := date.DayRange(d)
+
+	return r.getEvents(ctx, userID, from, to, "GetEventsForDay")
+}
+
+// GetEventsForWeek -.
+func (r *EventsRepo) GetEventsForWeek(ctx context.Context, userID int, d time.Time) (map[uuid.UUID]entity.Event, error) {
+	from, to := date.WeekRange(d)
+
+	return r.getEvents(ctx, userID, from, to, "GetEventsForWeek")
+}
+
+// GetEventsForMonth -.
+func (r *EventsRepo) GetEventsForMonth(ctx context.Context, userID int, d time.Time) (map[uuid.UUID]entity.Event, error) {
+	from, to := date.MonthRange(d)
+
+	return r.getEvents(ctx, userID, from, to, "GetEventsForMonth")
+}
+
+// GetEventsForRange reads the page from the index of events by start.
+func (r *EventsRepo) GetEventsForRange(ctx context.Context, userID int, from, to time.Time,
+	page entity.PageRequest,
+) (entity.EventsPage, error) {
+	exists, err := userExists(ctx, r.db, userID)
+	if err != nil {
+		return entity.EventsPage{}, fmt.Errorf("EventsRepo - GetEventsForRange - userExists: %w", err)
+	}
+
+	if !exists {
+		return entity.EventsPage{}, errs.ErrUserNotFound
+	}
+
+	series, err := r.queryEvents(ctx, "GetEventsForRange",
+		`SELECT `+_eventColumns+` FROM events
+		WHERE user_id = ?1 AND rrule <> '' AND series_start < ?3 AND (series_end IS NULL OR series_end > ?2)`,
+		userID, from.Add(-_floatingSlack).UnixMicro(), to.Add(_floatingSlack).UnixMicro(),
+	)
+	if err != nil {
+		return entity.EventsPage{}, err
+	}
+
+	order, after := "ASC", ">"
+	if page.Desc {
+		order, after = "DESC", "<"
+	}
+
+	query := `SELECT ` + _eventColumns + ` FROM events
+		WHERE user_id = ?1 AND rrule = '' AND (
+			(NOT all_day AND start_at < ?3 AND (end_at > ?2 OR start_at >= ?2))
+			OR (all_day AND start_at < ?5 AND end_at > ?4)
+		)`
+	args := []any{userID, from.UnixMicro(), to.UnixMicro(), date.Floating(from).UnixMicro(), date.FloatingCeil(to).UnixMicro(), page.Limit}
+
+	if page.After != nil {
+		query += ` AND (start_at ` + after + ` ?7 OR (start_at = ?7 AND uid ` + after + ` ?8))`
+		args = append(args, page.After.Start.UnixMicro(), page.After.UID)
+	}
+
+	query += ` ORDER BY start_at ` + order + `, uid ` + order + ` LIMIT ?6`
+
+	events, err := r.queryOccurrences(ctx, "GetEventsForRange", query, args...)
+	if err != nil {
+		return entity.EventsPage{}, err
+	}
+
+	return entity.EventsPage{Events: events, Series: series}, nil
+}
+
+// queryOccurrences runs a query selecting _eventColumns, keeping the order of its rows.
+func (r *EventsRepo) queryOccurrences(ctx context.Context, op, query string, args ...any) ([]entity.Occurrence, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - %s - r.db.QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	occurrences := make([]entity.Occurrence, 0)
+
+	for rows.Next() {
+		uid, event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("EventsRepo - %s - scanEvent: %w", op, err)
+		}
+
+		occurrences = append(occurrences, entity.Occurrence{UID: uid, Event: event})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("EventsRepo - %s - rows.Err: %w", op, err)
+	}
+
+	return occurrences, nil
+}
+
+// getEvents returns events of the user overlapping [from, to), see entity.Event.Overlaps,
+// and series that may have instances there.
+func (r *EventsRepo) getEvents(ctx context.Context, userID int, from, to time.Time, op string) (map[uuid.UUID]entity.Event, error) {
+	exists, err := userExists(ctx, r.db, userID)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - %s - userExists: %w", op, err)
+	}
+
+	if !exists {
+		return nil, errs.ErrUserNotFound
+	}
+
+	return r.queryEvents(ctx, op,
+		`SELECT `+_eventColumns+` FROM events
+		WHERE user_id = ?1 AND (
+			(rrule = '' AND NOT all_day AND start_at < ?3 AND (end_at > ?2 OR start_at >= ?2))
+			OR (rrule = '' AND all_day AND start_at < ?5 AND end_at > ?4)
+			OR (rrule <> '' AND series_start < ?7 AND (series_end IS NULL OR series_end > ?6))
+		)`,
+		userID, from.UnixMicro(), to.UnixMicro(), date.Floating(from).UnixMicro(), date.FloatingCeil(to).UnixMicro(),
+		from.Add(-_floatingSlack).UnixMicro(), to.Add(_floatingSlack).UnixMicro(),
+	)
+}
+
+// queryEvents runs a query selecting _eventColumns.
+func (r *EventsRepo) queryEvents(ctx context.Context, op, query string, args ...any) (map[uuid.UUID]entity.Event, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - %s - r.db.QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	events := make(map[uuid.UUID]entity.Event)
+
+	for rows.Next() {
+		uid, event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("EventsRepo - %s - scanEvent: %w", op, err)
+		}
+
+		events[uid] = event
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("EventsRepo - %s - rows.Err: %w", op, err)
+	}
+
+	return events, nil
+}
+
+// scanEvent scans a row of _eventColumns, followed by columns scanned into extra.
+func scanEvent(row interface{ Scan(dest ...any) error }, extra ...any) (uuid.UUID, entity.Event, error) {
+	var (
+		uid                uuid.UUID
+		start, end         int64
+		exDates, overrides string
+		event              entity.Event
+	)
+
+	dest := []any{&uid, &start, &end, &event.AllDay, &event.TimeZone, &event.Text, &event.RRule, &exDates, &overrides,
+		&event.Version}
+
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return uuid.UUID{}, entity.Event{}, err
+	}
+
+	event.Start, event.End = time.UnixMicro(start).UTC(), time.UnixMicro(end).UTC()
+
+	if event.ExDates, err = decodeExDates(exDates); err != nil {
+		return uuid.UUID{}, entity.Event{}, fmt.Errorf("decodeExDates: %w", err)
+	}
+
+	if event.Overrides, err = decodeOverrides(overrides); err != nil {
+		return uuid.UUID{}, entity.Event{}, fmt.Errorf("decodeOverrides: %w", err)
+	}
+
+	return uid, event, nil
+}
+
+// missed resolves which of ErrPreconditionFailed/ErrUserNotFound/ErrEventNotFound caused an empty write.
+func missed(ctx context.Context, db querier, userID int, eventUID uuid.UUID, op string) error {
+	var exists bool
+
+	err := db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM events WHERE user_id = ? AND uid = ?)`, userID, eventUID,
+	).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("EventsRepo - %s - db.QueryRowContext: %w", op, err)
+	}
+
+	if exists {
+		return errs.ErrPreconditionFailed
+	}
+
+	return notFound(ctx, db, userID, op)
+}
+
+// notFound resolves which of ErrUserNotFound/ErrEventNotFound an event lookup missed on.
+func notFound(ctx context.Context, db querier, userID int, op string) error {
+	exists, err := userExists(ctx, db, userID)
+	if err != nil {
+		return fmt.Errorf("EventsRepo - %s - userExists: %w", op, err)
+	}
+
+	if !exists {
+		return errs.ErrUserNotFound
+	}
+
+	return errs.ErrEventNotFound
+}
+
+func userExists(ctx context.Context, db querier, userID int) (bool, error) {
+	var exists bool
+
+	err := db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)`, userID).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
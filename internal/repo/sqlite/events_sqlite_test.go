@@ -0,0 +1,33 @@
+package sqlite_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/repo/repotest"
+	sqliterepo "github.com/andreyxaxa/calendar/internal/repo/sqlite"
+	"github.com/andreyxaxa/calendar/pkg/sqlite"
+)
+
+func eventsRepo(t *testing.T) *sqliterepo.EventsRepo {
+	t.Helper()
+
+	s, err := sqlite.New(filepath.Join(t.TempDir(), "calendar.db"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(s.Close)
+
+	if err = sqliterepo.Migrate(s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return sqliterepo.New(s)
+}
+
+func TestEventsRepo(t *testing.T) {
+	repotest.Events(t, func(t *testing.T) repo.EventsRepo {
+		return eventsRepo(t)
+	})
+}
@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/sqlite"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+)
+
+// IdempotencyRepo -.
+type IdempotencyRepo struct {
+	*sqlite.SQLite
+}
+
+// NewIdempotencyRepo returns new IdempotencyRepo(struct)
+func NewIdempotencyRepo(s *sqlite.SQLite) *IdempotencyRepo {
+	return &IdempotencyRepo{s}
+}
+
+// Reserve -.
+func (r *IdempotencyRepo) Reserve(ctx context.Context, key entity.IdempotencyKey, now time.Time) (entity.IdempotencyKey, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.IdempotencyKey{}, fmt.Errorf("IdempotencyRepo - Reserve - r.DB.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= ?`, now.UnixMicro())
+	if err != nil {
+		return entity.IdempotencyKey{}, fmt.Errorf("IdempotencyRepo - Reserve - tx.ExecContext delete: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO idempotency_keys (key, fingerprint, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (key) DO NOTHING`,
+		key.Key, key.Fingerprint, key.ExpiresAt.UnixMicro(),
+	)
+	if err != nil {
+		return entity.IdempotencyKey{}, fmt.Errorf("IdempotencyRepo - Reserve - tx.ExecContext insert: %w", err)
+	}
+
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return entity.IdempotencyKey{}, fmt.Errorf("IdempotencyRepo - Reserve - res.RowsAffected: %w", err)
+	}
+
+	if inserted == 0 {
+		stored, err := scanIdempotencyKey(tx.QueryRowContext(ctx,
+			`SELECT key, fingerprint, response, expires_at FROM idempotency_keys WHERE key = ?`, key.Key,
+		))
+		if err != nil {
+			return entity.IdempotencyKey{}, fmt.Errorf("IdempotencyRepo - Reserve - scanIdempotencyKey: %w", err)
+		}
+
+		return stored, errs.ErrAlreadyExists
+	}
+
+	if err = tx.Commit(); err != nil {
+		return entity.IdempotencyKey{}, fmt.Errorf("IdempotencyRepo - Reserve - tx.Commit: %w", err)
+	}
+
+	key.Response = nil
+
+	return key, nil
+}
+
+// Complete -.
+func (r *IdempotencyRepo) Complete(ctx context.Context, key string, response entity.StoredResponse) error {
+	b, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("IdempotencyRepo - Complete - json.Marshal: %w", err)
+	}
+
+	_, err = r.DB.ExecContext(ctx, `UPDATE idempotency_keys SET response = ? WHERE key = ?`, string(b), key)
+	if err != nil {
+		return fmt.Errorf("IdempotencyRepo - Complete - r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+// Release -.
+func (r *IdempotencyRepo) Release(ctx context.Context, key string) error {
+	_, err := r.DB.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE key = ?`, key)
+	if err != nil {
+		return fmt.Errorf("IdempotencyRepo - Release - r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+func scanIdempotencyKey(row *sql.Row) (entity.IdempotencyKey, error) {
+	var (
+		key       entity.IdempotencyKey
+		response  sql.NullString
+		expiresAt int64
+	)
+
+	if err := row.Scan(&key.Key, &key.Fingerprint, &response, &expiresAt); err != nil {
+		return entity.IdempotencyKey{}, err
+	}
+
+	key.ExpiresAt = time.UnixMicro(expiresAt).UTC()
+
+	if response.Valid {
+		key.Response = &entity.StoredResponse{}
+
+		if err := json.Unmarshal([]byte(response.String), key.Response); err != nil {
+			return entity.IdempotencyKey{}, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+	}
+
+	return key, nil
+}
@@ -0,0 +1,15 @@
+package sqlite_test
+
+import (
+	"testing"
+
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/repo/repotest"
+	sqliterepo "github.com/andreyxaxa/calendar/internal/repo/sqlite"
+)
+
+func TestIdempotencyRepo(t *testing.T) {
+	repotest.Idempotency(t, func(t *testing.T) repo.IdempotencyRepo {
+		return sqliterepo.NewIdempotencyRepo(eventsRepo(t).SQLite)
+	})
+}
@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+
+	"github.com/andreyxaxa/calendar/pkg/sqlite"
+	"github.com/golang-migrate/migrate/v4"
+	sqlitemigrate "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrate applies embedded migrations up to the latest version.
+func Migrate(s *sqlite.SQLite) error {
+	src, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return fmt.Errorf("sqlite - Migrate - iofs.New: %w", err)
+	}
+
+	// the driver is not closed: it would close s.DB as well.
+	driver, err := sqlitemigrate.WithInstance(s.DB, &sqlitemigrate.Config{})
+	if err != nil {
+		return fmt.Errorf("sqlite - Migrate - sqlitemigrate.WithInstance: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "sqlite", driver)
+	if err != nil {
+		return fmt.Errorf("sqlite - Migrate - migrate.NewWithInstance: %w", err)
+	}
+
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("sqlite - Migrate - m.Up: %w", err)
+	}
+
+	return nil
+}
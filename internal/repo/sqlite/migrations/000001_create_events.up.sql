@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS events (
+    user_id INTEGER NOT NULL REFERENCES users (id),
+    uid     TEXT    NOT NULL,
+    date    TEXT    NOT NULL,
+    text    TEXT    NOT NULL,
+    PRIMARY KEY (user_id, uid)
+);
+
+CREATE INDEX IF NOT EXISTS events_user_id_date_idx ON events (user_id, date);
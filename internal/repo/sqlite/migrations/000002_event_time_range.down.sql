@@ -0,0 +1,11 @@
+ALTER TABLE events ADD COLUMN date TEXT NOT NULL DEFAULT '';
+
+UPDATE events SET date = date(start_at / 1000000, 'unixepoch');
+
+DROP INDEX IF EXISTS events_user_id_start_at_idx;
+
+ALTER TABLE events DROP COLUMN start_at;
+ALTER TABLE events DROP COLUMN end_at;
+ALTER TABLE events DROP COLUMN all_day;
+
+CREATE INDEX IF NOT EXISTS events_user_id_date_idx ON events (user_id, date);
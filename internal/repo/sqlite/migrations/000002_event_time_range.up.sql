@@ -0,0 +1,16 @@
+-- start_at and end_at are unix microseconds.
+ALTER TABLE events ADD COLUMN start_at INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE events ADD COLUMN end_at INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE events ADD COLUMN all_day INTEGER NOT NULL DEFAULT 0;
+
+-- events created before were whole days.
+UPDATE events
+SET start_at = CAST(strftime('%s', date) AS INTEGER) * 1000000,
+    end_at   = CAST(strftime('%s', date, '+1 day') AS INTEGER) * 1000000,
+    all_day  = 1;
+
+DROP INDEX IF EXISTS events_user_id_date_idx;
+
+ALTER TABLE events DROP COLUMN date;
+
+CREATE INDEX IF NOT EXISTS events_user_id_start_at_idx ON events (user_id, start_at);
@@ -0,0 +1,3 @@
+ALTER TABLE events DROP COLUMN time_zone;
+
+ALTER TABLE users DROP COLUMN time_zone;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS events_user_id_recurring_idx;
+
+ALTER TABLE events DROP COLUMN series_end;
+ALTER TABLE events DROP COLUMN exdates;
+ALTER TABLE events DROP COLUMN rrule;
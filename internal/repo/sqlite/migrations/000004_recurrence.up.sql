@@ -0,0 +1,9 @@
+-- exdates is a JSON array of unix microseconds, series_end is the end of the
+-- last instance, NULL for endless series.
+ALTER TABLE events ADD COLUMN rrule TEXT NOT NULL DEFAULT '';
+ALTER TABLE events ADD COLUMN exdates TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE events ADD COLUMN series_end INTEGER;
+
+UPDATE events SET series_end = end_at;
+
+CREATE INDEX IF NOT EXISTS events_user_id_recurring_idx ON events (user_id) WHERE rrule <> '';
@@ -0,0 +1,2 @@
+ALTER TABLE events DROP COLUMN series_start;
+ALTER TABLE events DROP COLUMN overrides;
@@ -0,0 +1,6 @@
+-- overrides is a JSON array, series_start is the start of the earliest
+-- instance: overrides may move it before start_at.
+ALTER TABLE events ADD COLUMN overrides TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE events ADD COLUMN series_start INTEGER NOT NULL DEFAULT 0;
+
+UPDATE events SET series_start = start_at;
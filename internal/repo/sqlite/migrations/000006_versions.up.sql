@@ -0,0 +1,2 @@
+-- version grows with every write of the event, see repo.EventsRepo.
+ALTER TABLE events ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
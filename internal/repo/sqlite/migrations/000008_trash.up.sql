@@ -0,0 +1,20 @@
+-- trash keeps deleted events with the columns of events, deleted_at is unix microseconds.
+CREATE TABLE IF NOT EXISTS trash (
+    user_id      INTEGER NOT NULL REFERENCES users (id),
+    uid          TEXT    NOT NULL,
+    start_at     INTEGER NOT NULL,
+    end_at       INTEGER NOT NULL,
+    all_day      INTEGER NOT NULL,
+    time_zone    TEXT    NOT NULL,
+    text         TEXT    NOT NULL,
+    rrule        TEXT    NOT NULL,
+    exdates      TEXT    NOT NULL,
+    overrides    TEXT    NOT NULL,
+    series_start INTEGER NOT NULL,
+    series_end   INTEGER,
+    version      INTEGER NOT NULL,
+    deleted_at   INTEGER NOT NULL,
+    PRIMARY KEY (user_id, uid)
+);
+
+CREATE INDEX IF NOT EXISTS trash_deleted_at_idx ON trash (deleted_at);
@@ -0,0 +1,12 @@
+-- revisions are appended only, before and after are JSON of the event, NULL where it was missing.
+CREATE TABLE IF NOT EXISTS revisions (
+    user_id INTEGER NOT NULL REFERENCES users (id),
+    uid     TEXT    NOT NULL,
+    number  INTEGER NOT NULL,
+    op      TEXT    NOT NULL,
+    actor   TEXT    NOT NULL,
+    at      INTEGER NOT NULL,
+    before  TEXT,
+    after   TEXT,
+    PRIMARY KEY (user_id, uid, number)
+);
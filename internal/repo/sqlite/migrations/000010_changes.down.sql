@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS changes;
+
+ALTER TABLE users DROP COLUMN change_seq;
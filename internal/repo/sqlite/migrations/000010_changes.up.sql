@@ -0,0 +1,24 @@
+-- changes keep the latest change of each event ever written, numbered by change_seq of its user.
+ALTER TABLE users ADD COLUMN change_seq INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS changes (
+    user_id INTEGER NOT NULL REFERENCES users (id),
+    uid     TEXT    NOT NULL,
+    seq     INTEGER NOT NULL,
+    deleted INTEGER NOT NULL,
+    PRIMARY KEY (user_id, uid)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS changes_user_id_seq_idx ON changes (user_id, seq);
+
+-- events and tombstones of the trash written so far are numbered anew.
+INSERT INTO changes (user_id, uid, seq, deleted)
+SELECT user_id, uid, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY deleted, uid), deleted
+FROM (
+    SELECT user_id, uid, 0 AS deleted FROM events
+    UNION ALL
+    SELECT user_id, uid, 1 AS deleted FROM trash t
+    WHERE NOT EXISTS (SELECT 1 FROM events e WHERE e.user_id = t.user_id AND e.uid = t.uid)
+);
+
+UPDATE users SET change_seq = (SELECT COUNT(*) FROM changes WHERE changes.user_id = users.id);
@@ -0,0 +1,38 @@
+-- kinds is a JSON array of notification kinds, empty for all of them.
+CREATE TABLE IF NOT EXISTS webhooks (
+    id         TEXT    PRIMARY KEY,
+    user_id    INTEGER NOT NULL,
+    url        TEXT    NOT NULL,
+    secret     TEXT    NOT NULL,
+    kinds      TEXT    NOT NULL DEFAULT '[]',
+    created_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS webhooks_user_id_idx ON webhooks (user_id);
+
+-- seq orders deliveries by the time they were added, payload is the body as it is sent.
+CREATE TABLE IF NOT EXISTS deliveries (
+    seq             INTEGER PRIMARY KEY AUTOINCREMENT,
+    id              TEXT    NOT NULL UNIQUE,
+    webhook_id      TEXT    NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    user_id         INTEGER NOT NULL,
+    kind            TEXT    NOT NULL,
+    payload         TEXT    NOT NULL,
+    status          TEXT    NOT NULL,
+    next_attempt_at INTEGER NOT NULL,
+    created_at      INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS deliveries_webhook_id_idx ON deliveries (webhook_id, seq);
+CREATE INDEX IF NOT EXISTS deliveries_due_idx ON deliveries (next_attempt_at) WHERE status = 'pending';
+
+-- duration is in microseconds, status_code is 0 if no response came.
+CREATE TABLE IF NOT EXISTS delivery_attempts (
+    delivery_id TEXT    NOT NULL REFERENCES deliveries (id) ON DELETE CASCADE,
+    number      INTEGER NOT NULL,
+    at          INTEGER NOT NULL,
+    status_code INTEGER NOT NULL,
+    error       TEXT    NOT NULL,
+    duration    INTEGER NOT NULL,
+    PRIMARY KEY (delivery_id, number)
+);
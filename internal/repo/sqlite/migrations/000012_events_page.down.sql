@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS events_user_id_start_at_uid_idx;
+CREATE INDEX IF NOT EXISTS events_user_id_start_at_idx ON events (user_id, start_at);
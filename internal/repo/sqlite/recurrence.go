@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+)
+
+// recurrence holds the column values of an event's recurrence.
+type recurrence struct {
+	// exDates is a JSON array of unix microseconds, overrides of entity.Override.
+	exDates     string
+	overrides   string
+	seriesStart int64
+	// seriesEnd is NULL for endless series.
+	seriesEnd sql.NullInt64
+}
+
+func newRecurrence(event entity.Event) (recurrence, error) {
+	micros := make([]int64, len(event.ExDates))
+	for i, exDate := range event.ExDates {
+		micros[i] = exDate.UnixMicro()
+	}
+
+	exDates, err := json.Marshal(micros)
+	if err != nil {
+		return recurrence{}, err
+	}
+
+	overrides, err := json.Marshal(event.Overrides)
+	if err != nil {
+		return recurrence{}, err
+	}
+
+	if event.Overrides == nil {
+		overrides = []byte("[]")
+	}
+
+	end, err := event.SeriesEnd()
+	if err != nil {
+		return recurrence{}, err
+	}
+
+	return recurrence{
+		exDates:     string(exDates),
+		overrides:   string(overrides),
+		seriesStart: event.SeriesStart().UnixMicro(),
+		seriesEnd:   sql.NullInt64{Int64: end.UnixMicro(), Valid: !end.IsZero()},
+	}, nil
+}
+
+func decodeExDates(s string) ([]time.Time, error) {
+	var micros []int64
+	if err := json.Unmarshal([]byte(s), &micros); err != nil {
+		return nil, err
+	}
+
+	if len(micros) == 0 {
+		return nil, nil
+	}
+
+	exDates := make([]time.Time, len(micros))
+	for i, m := range micros {
+		exDates[i] = time.UnixMicro(m).UTC()
+	}
+
+	return exDates, nil
+}
+
+func decodeOverrides(s string) ([]entity.Override, error) {
+	var overrides []entity.Override
+	if err := json.Unmarshal([]byte(s), &overrides); err != nil {
+		return nil, err
+	}
+
+	if len(overrides) == 0 {
+		return nil, nil
+	}
+
+	for i := range overrides {
+		o := &overrides[i]
+		o.RecurrenceID, o.Start, o.End = o.RecurrenceID.UTC(), o.Start.UTC(), o.End.UTC()
+	}
+
+	return overrides, nil
+}
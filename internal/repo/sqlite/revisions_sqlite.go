@@ -0,0 +1,120 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+// AddRevision -.
+func (r *EventsRepo) AddRevision(ctx context.Context, userID int, eventUID uuid.UUID, revision entity.Revision) error {
+	before, err := encodeRevisionEvent(revision.Before)
+	if err != nil {
+		return fmt.Errorf("EventsRepo - AddRevision - encodeRevisionEvent before: %w", err)
+	}
+
+	after, err := encodeRevisionEvent(revision.After)
+	if err != nil {
+		return fmt.Errorf("EventsRepo - AddRevision - encodeRevisionEvent after: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx,
+		`INSERT INTO revisions (user_id, uid, number, op, actor, at, before, after)
+		SELECT ?1, ?2, COALESCE(MAX(number), 0) + 1, ?3, ?4, ?5, ?6, ?7 FROM revisions
+		WHERE user_id = ?1 AND uid = ?2`,
+		userID, eventUID, revision.Op, revision.Actor, revision.At.UnixMicro(), before, after,
+	)
+	if err != nil {
+		return fmt.Errorf("EventsRepo - AddRevision - r.db.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+// GetRevisions -.
+func (r *EventsRepo) GetRevisions(ctx context.Context, userID int, eventUID uuid.UUID) ([]entity.Revision, error) {
+	exists, err := userExists(ctx, r.db, userID)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - GetRevisions - userExists: %w", err)
+	}
+
+	if !exists {
+		return nil, errs.ErrUserNotFound
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT number, op, actor, at, before, after FROM revisions
+		WHERE user_id = ? AND uid = ? ORDER BY number`,
+		userID, eventUID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - GetRevisions - r.db.QueryContext: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []entity.Revision
+
+	for rows.Next() {
+		var (
+			revision      entity.Revision
+			at            int64
+			before, after sql.NullString
+		)
+
+		err = rows.Scan(&revision.Number, &revision.Op, &revision.Actor, &at, &before, &after)
+		if err != nil {
+			return nil, fmt.Errorf("EventsRepo - GetRevisions - rows.Scan: %w", err)
+		}
+
+		revision.At = time.UnixMicro(at).UTC()
+
+		if revision.Before, err = decodeRevisionEvent(before); err != nil {
+			return nil, fmt.Errorf("EventsRepo - GetRevisions - decodeRevisionEvent before: %w", err)
+		}
+
+		if revision.After, err = decodeRevisionEvent(after); err != nil {
+			return nil, fmt.Errorf("EventsRepo - GetRevisions - decodeRevisionEvent after: %w", err)
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("EventsRepo - GetRevisions - rows.Err: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// encodeRevisionEvent returns the event as JSON, NULL for nil.
+func encodeRevisionEvent(event *entity.Event) (sql.NullString, error) {
+	if event == nil {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func decodeRevisionEvent(s sql.NullString) (*entity.Event, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+
+	var event entity.Event
+	if err := json.Unmarshal([]byte(s.String), &event); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
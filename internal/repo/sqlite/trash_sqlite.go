@@ -0,0 +1,130 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+	driver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Restore -.
+func (r *EventsRepo) Restore(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error) {
+	var event entity.Event
+
+	err := r.atomic(ctx, "Restore", func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx,
+			`INSERT INTO events (user_id, uid, `+_trashColumns+`, version)
+			SELECT user_id, uid, `+_trashColumns+`, version + 1 FROM trash
+			WHERE user_id = ? AND uid = ?`,
+			userID, eventUID,
+		)
+		if err != nil {
+			var sqliteErr *driver.Error
+			if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY {
+				return errs.ErrAlreadyExists
+			}
+
+			return fmt.Errorf("EventsRepo - Restore - tx.ExecContext insert: %w", err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("EventsRepo - Restore - res.RowsAffected: %w", err)
+		}
+
+		if affected == 0 {
+			return notFound(ctx, tx, userID, "Restore")
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM trash WHERE user_id = ? AND uid = ?`, userID, eventUID)
+		if err != nil {
+			return fmt.Errorf("EventsRepo - Restore - tx.ExecContext trash: %w", err)
+		}
+
+		if err = change(ctx, tx, userID, eventUID, false); err != nil {
+			return fmt.Errorf("EventsRepo - Restore - change: %w", err)
+		}
+
+		row := tx.QueryRowContext(ctx,
+			`SELECT `+_eventColumns+` FROM events WHERE user_id = ? AND uid = ?`,
+			userID, eventUID,
+		)
+
+		if _, event, err = scanEvent(row); err != nil {
+			return fmt.Errorf("EventsRepo - Restore - scanEvent: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.Event{}, err
+	}
+
+	return event, nil
+}
+
+// GetTrash -.
+func (r *EventsRepo) GetTrash(ctx context.Context, userID int) ([]entity.TrashedEvent, error) {
+	exists, err := userExists(ctx, r.db, userID)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - GetTrash - userExists: %w", err)
+	}
+
+	if !exists {
+		return nil, errs.ErrUserNotFound
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+_eventColumns+`, deleted_at FROM trash WHERE user_id = ?`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("EventsRepo - GetTrash - r.db.QueryContext: %w", err)
+	}
+	defer rows.Close()
+
+	var events []entity.TrashedEvent
+
+	for rows.Next() {
+		var deletedAt int64
+
+		uid, event, err := scanEvent(rows, &deletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("EventsRepo - GetTrash - scanEvent: %w", err)
+		}
+
+		events = append(events, entity.TrashedEvent{
+			UID:       uid,
+			DeletedAt: time.UnixMicro(deletedAt).UTC(),
+			Event:     event,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("EventsRepo - GetTrash - rows.Err: %w", err)
+	}
+
+	return events, nil
+}
+
+// PurgeTrash -.
+func (r *EventsRepo) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM trash WHERE deleted_at < ?`, before.UnixMicro())
+	if err != nil {
+		return 0, fmt.Errorf("EventsRepo - PurgeTrash - r.db.ExecContext: %w", err)
+	}
+
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("EventsRepo - PurgeTrash - res.RowsAffected: %w", err)
+	}
+
+	return purged, nil
+}
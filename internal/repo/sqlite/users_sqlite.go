@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/sqlite"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+)
+
+// UsersRepo -.
+type UsersRepo struct {
+	*sqlite.SQLite
+}
+
+// NewUsersRepo returns new UsersRepo(struct)
+func NewUsersRepo(s *sqlite.SQLite) *UsersRepo {
+	return &UsersRepo{s}
+}
+
+// Get -.
+func (r *UsersRepo) Get(ctx context.Context, userID int) (entity.User, error) {
+	user := entity.User{ID: userID}
+
+	err := r.DB.QueryRowContext(ctx, `SELECT time_zone FROM users WHERE id = ?`, userID).Scan(&user.TimeZone)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.User{}, errs.ErrUserNotFound
+		}
+
+		return entity.User{}, fmt.Errorf("UsersRepo - Get - r.DB.QueryRowContext: %w", err)
+	}
+
+	return user, nil
+}
+
+// Save -.
+func (r *UsersRepo) Save(ctx context.Context, user entity.User) error {
+	_, err := r.DB.ExecContext(ctx,
+		`INSERT INTO users (id, time_zone) VALUES (?, ?)
+		ON CONFLICT (id) DO UPDATE SET time_zone = excluded.time_zone`,
+		user.ID, user.TimeZone,
+	)
+	if err != nil {
+		return fmt.Errorf("UsersRepo - Save - r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package sqlite_test
+
+import (
+	"testing"
+
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/repo/repotest"
+	sqliterepo "github.com/andreyxaxa/calendar/internal/repo/sqlite"
+)
+
+func TestUsersRepo(t *testing.T) {
+	repotest.Users(t, func(t *testing.T) repo.UsersRepo {
+		return sqliterepo.NewUsersRepo(eventsRepo(t).SQLite)
+	})
+}
@@ -0,0 +1,339 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/sqlite"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+const _deliveryColumns = `d.id, d.webhook_id, d.user_id, d.kind, d.payload, d.status, d.next_attempt_at, d.created_at`
+
+// WebhooksRepo -.
+type WebhooksRepo struct {
+	*sqlite.SQLite
+}
+
+// NewWebhooksRepo returns new WebhooksRepo(struct)
+func NewWebhooksRepo(s *sqlite.SQLite) *WebhooksRepo {
+	return &WebhooksRepo{s}
+}
+
+// CreateWebhook -.
+func (r *WebhooksRepo) CreateWebhook(ctx context.Context, webhook entity.Webhook) error {
+	kinds, err := json.Marshal(webhook.Kinds)
+	if err != nil {
+		return fmt.Errorf("WebhooksRepo - CreateWebhook - json.Marshal: %w", err)
+	}
+
+	_, err = r.DB.ExecContext(ctx,
+		`INSERT INTO webhooks (id, user_id, url, secret, kinds, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		webhook.ID.String(), webhook.UserID, webhook.URL, webhook.Secret, string(kinds), webhook.CreatedAt.UnixMicro(),
+	)
+	if err != nil {
+		return fmt.Errorf("WebhooksRepo - CreateWebhook - r.DB.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+// GetWebhooks -.
+func (r *WebhooksRepo) GetWebhooks(ctx context.Context, userID int) ([]entity.Webhook, error) {
+	rows, err := r.DB.QueryContext(ctx,
+		`SELECT id, user_id, url, secret, kinds, created_at FROM webhooks
+		WHERE user_id = ? ORDER BY created_at, id`, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - GetWebhooks - r.DB.QueryContext: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]entity.Webhook, 0)
+
+	for rows.Next() {
+		var (
+			w         entity.Webhook
+			id, kinds string
+			createdAt int64
+		)
+
+		if err = rows.Scan(&id, &w.UserID, &w.URL, &w.Secret, &kinds, &createdAt); err != nil {
+			return nil, fmt.Errorf("WebhooksRepo - GetWebhooks - rows.Scan: %w", err)
+		}
+
+		if w.ID, err = uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("WebhooksRepo - GetWebhooks - uuid.Parse: %w", err)
+		}
+
+		if err = json.Unmarshal([]byte(kinds), &w.Kinds); err != nil {
+			return nil, fmt.Errorf("WebhooksRepo - GetWebhooks - json.Unmarshal: %w", err)
+		}
+
+		w.CreatedAt = time.UnixMicro(createdAt).UTC()
+		webhooks = append(webhooks, w)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - GetWebhooks - rows.Err: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook -.
+func (r *WebhooksRepo) DeleteWebhook(ctx context.Context, userID int, id uuid.UUID) error {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM webhooks WHERE user_id = ? AND id = ?`, userID, id.String())
+	if err != nil {
+		return fmt.Errorf("WebhooksRepo - DeleteWebhook - r.DB.ExecContext: %w", err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("WebhooksRepo - DeleteWebhook - res.RowsAffected: %w", err)
+	}
+
+	if deleted == 0 {
+		return errs.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// AddDeliveries -.
+func (r *WebhooksRepo) AddDeliveries(ctx context.Context, deliveries []entity.Delivery) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("WebhooksRepo - AddDeliveries - r.DB.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, d := range deliveries {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO deliveries (id, webhook_id, user_id, kind, payload, status, next_attempt_at, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			d.ID.String(), d.WebhookID.String(), d.UserID, string(d.Kind), string(d.Payload), string(d.Status),
+			d.NextAttemptAt.UnixMicro(), d.CreatedAt.UnixMicro(),
+		)
+		if err != nil {
+			return fmt.Errorf("WebhooksRepo - AddDeliveries - tx.ExecContext: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("WebhooksRepo - AddDeliveries - tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+// GetDueDeliveries -.
+func (r *WebhooksRepo) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.DueDelivery, error) {
+	rows, err := r.DB.QueryContext(ctx,
+		`SELECT `+_deliveryColumns+`, w.url, w.secret FROM deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = ? AND d.next_attempt_at <= ?
+		ORDER BY d.next_attempt_at, d.seq LIMIT ?`,
+		string(entity.DeliveryPending), now.UnixMicro(), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - GetDueDeliveries - r.DB.QueryContext: %w", err)
+	}
+	defer rows.Close()
+
+	due := make([]entity.DueDelivery, 0)
+
+	for rows.Next() {
+		var d entity.DueDelivery
+
+		if d.Delivery, err = scanDelivery(rows, &d.URL, &d.Secret); err != nil {
+			return nil, fmt.Errorf("WebhooksRepo - GetDueDeliveries - scanDelivery: %w", err)
+		}
+
+		due = append(due, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - GetDueDeliveries - rows.Err: %w", err)
+	}
+
+	rows.Close()
+
+	for i := range due {
+		if due[i].Attempts, err = r.queryAttempts(ctx, due[i].ID); err != nil {
+			return nil, fmt.Errorf("WebhooksRepo - GetDueDeliveries - r.queryAttempts: %w", err)
+		}
+	}
+
+	return due, nil
+}
+
+// AddAttempt -.
+func (r *WebhooksRepo) AddAttempt(ctx context.Context, delivery entity.Delivery, attempt entity.DeliveryAttempt) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("WebhooksRepo - AddAttempt - r.DB.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE deliveries SET status = ?, next_attempt_at = ? WHERE id = ?`,
+		string(delivery.Status), delivery.NextAttemptAt.UnixMicro(), delivery.ID.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("WebhooksRepo - AddAttempt - tx.ExecContext update: %w", err)
+	}
+
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("WebhooksRepo - AddAttempt - res.RowsAffected: %w", err)
+	}
+
+	// the webhook was deleted meanwhile.
+	if updated == 0 {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO delivery_attempts (delivery_id, number, at, status_code, error, duration)
+		SELECT ?1, COALESCE(MAX(number), 0) + 1, ?2, ?3, ?4, ?5 FROM delivery_attempts WHERE delivery_id = ?1`,
+		delivery.ID.String(), attempt.At.UnixMicro(), attempt.StatusCode, attempt.Error, attempt.Duration.Microseconds(),
+	)
+	if err != nil {
+		return fmt.Errorf("WebhooksRepo - AddAttempt - tx.ExecContext insert: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("WebhooksRepo - AddAttempt - tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeliveries -.
+func (r *WebhooksRepo) GetDeliveries(ctx context.Context, userID int, webhookID uuid.UUID,
+	status entity.DeliveryStatus, limit int,
+) ([]entity.Delivery, error) {
+	var exists bool
+
+	err := r.DB.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM webhooks WHERE user_id = ? AND id = ?)`, userID, webhookID.String(),
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - GetDeliveries - r.DB.QueryRowContext: %w", err)
+	}
+
+	if !exists {
+		return nil, errs.ErrWebhookNotFound
+	}
+
+	rows, err := r.DB.QueryContext(ctx,
+		`SELECT `+_deliveryColumns+` FROM deliveries d
+		WHERE d.webhook_id = ?1 AND (?2 = '' OR d.status = ?2)
+		ORDER BY d.seq DESC LIMIT ?3`,
+		webhookID.String(), string(status), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - GetDeliveries - r.DB.QueryContext: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]entity.Delivery, 0)
+
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("WebhooksRepo - GetDeliveries - scanDelivery: %w", err)
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("WebhooksRepo - GetDeliveries - rows.Err: %w", err)
+	}
+
+	rows.Close()
+
+	for i := range deliveries {
+		if deliveries[i].Attempts, err = r.queryAttempts(ctx, deliveries[i].ID); err != nil {
+			return nil, fmt.Errorf("WebhooksRepo - GetDeliveries - r.queryAttempts: %w", err)
+		}
+	}
+
+	return deliveries, nil
+}
+
+// queryAttempts returns attempts of the delivery, the oldest first. Rows of another query must
+// be closed before: the database has a single connection.
+func (r *WebhooksRepo) queryAttempts(ctx context.Context, deliveryID uuid.UUID) ([]entity.DeliveryAttempt, error) {
+	rows, err := r.DB.QueryContext(ctx,
+		`SELECT at, status_code, error, duration FROM delivery_attempts
+		WHERE delivery_id = ? ORDER BY number`, deliveryID.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("r.DB.QueryContext: %w", err)
+	}
+	defer rows.Close()
+
+	var attempts []entity.DeliveryAttempt
+
+	for rows.Next() {
+		var (
+			a            entity.DeliveryAttempt
+			at, duration int64
+		)
+
+		if err = rows.Scan(&at, &a.StatusCode, &a.Error, &duration); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		a.At = time.UnixMicro(at).UTC()
+		a.Duration = time.Duration(duration) * time.Microsecond
+		attempts = append(attempts, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return attempts, nil
+}
+
+// scanDelivery scans a row of _deliveryColumns, followed by columns scanned into extra.
+func scanDelivery(row interface{ Scan(dest ...any) error }, extra ...any) (entity.Delivery, error) {
+	var (
+		d                        entity.Delivery
+		id, webhookID            string
+		kind, payload, status    string
+		nextAttemptAt, createdAt int64
+	)
+
+	dest := append([]any{&id, &webhookID, &d.UserID, &kind, &payload, &status, &nextAttemptAt, &createdAt}, extra...)
+
+	if err := row.Scan(dest...); err != nil {
+		return entity.Delivery{}, err
+	}
+
+	var err error
+
+	if d.ID, err = uuid.Parse(id); err != nil {
+		return entity.Delivery{}, fmt.Errorf("uuid.Parse: %w", err)
+	}
+
+	if d.WebhookID, err = uuid.Parse(webhookID); err != nil {
+		return entity.Delivery{}, fmt.Errorf("uuid.Parse: %w", err)
+	}
+
+	d.Kind = entity.NotificationKind(kind)
+	d.Payload = []byte(payload)
+	d.Status = entity.DeliveryStatus(status)
+	d.NextAttemptAt = time.UnixMicro(nextAttemptAt).UTC()
+	d.CreatedAt = time.UnixMicro(createdAt).UTC()
+
+	return d, nil
+}
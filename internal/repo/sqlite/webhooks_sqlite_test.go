@@ -0,0 +1,15 @@
+package sqlite_test
+
+import (
+	"testing"
+
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/repo/repotest"
+	sqliterepo "github.com/andreyxaxa/calendar/internal/repo/sqlite"
+)
+
+func TestWebhooksRepo(t *testing.T) {
+	repotest.Webhooks(t, func(t *testing.T) repo.WebhooksRepo {
+		return sqliterepo.NewWebhooksRepo(eventsRepo(t).SQLite)
+	})
+}
@@ -0,0 +1,139 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func batchOperations() []entity.BatchOperation {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	event := entity.Event{Text: "stand-up", Start: start, End: start.Add(15 * time.Minute), TimeZone: "UTC"}
+
+	moved := event
+	moved.Start, moved.End = start.Add(time.Hour), start.Add(2*time.Hour)
+
+	return []entity.BatchOperation{
+		{Op: entity.BatchCreate, UID: uuid.New(), Event: event},
+		{Op: entity.BatchUpdate, UID: uuid.New(), Event: moved},
+		{Op: entity.BatchDelete, UID: uuid.New(), Event: entity.Event{Version: 3}},
+	}
+}
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+	ops := batchOperations()
+
+	gomock.InOrder(
+		repo.EXPECT().Create(ctx, userID, ops[0].UID, ops[0].Event).Return(nil),
+		repo.EXPECT().Update(ctx, userID, ops[1].UID, ops[1].Event).Return(int64(0), errs.ErrEventNotFound),
+		repo.EXPECT().Delete(ctx, userID, ops[2].UID, int64(3), gomock.Any()).Return(nil),
+	)
+
+	results, err := useCase.Batch(ctx, userID, ops, false)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	if results[0].Err != nil || results[0].UID != ops[0].UID || results[0].Event.Version != 1 {
+		t.Fatalf("expected the created event, got %+v", results[0])
+	}
+
+	if !errors.Is(results[1].Err, errs.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", results[1].Err)
+	}
+
+	if results[2].Err != nil {
+		t.Fatalf("unexpected error: %v", results[2].Err)
+	}
+}
+
+func TestBatchAtomic(t *testing.T) {
+	t.Parallel()
+
+	useCase, mockRepo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+	ops := batchOperations()
+
+	mockRepo.
+		EXPECT().
+		WithinTx(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(tx repo.EventsRepo) error) error {
+			return fn(mockRepo)
+		}).
+		Times(2)
+
+	gomock.InOrder(
+		mockRepo.EXPECT().Create(ctx, userID, ops[0].UID, ops[0].Event).Return(nil),
+		mockRepo.EXPECT().Update(ctx, userID, ops[1].UID, ops[1].Event).Return(int64(2), nil),
+		mockRepo.EXPECT().Delete(ctx, userID, ops[2].UID, int64(3), gomock.Any()).Return(nil),
+	)
+
+	results, err := useCase.Batch(ctx, userID, ops, true)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 3 || results[1].Event.Version != 2 {
+		t.Fatalf("expected 3 results with the updated event, got %+v", results)
+	}
+
+	// the first failure stops the batch.
+	gomock.InOrder(
+		mockRepo.EXPECT().Create(ctx, userID, ops[0].UID, ops[0].Event).Return(nil),
+		mockRepo.EXPECT().Update(ctx, userID, ops[1].UID, ops[1].Event).Return(int64(0), errs.ErrPreconditionFailed),
+	)
+
+	_, err = useCase.Batch(ctx, userID, ops, true)
+
+	var batchErr *entity.BatchError
+	if !errors.As(err, &batchErr) || batchErr.Index != 1 {
+		t.Fatalf("expected BatchError of operation 1, got %v", err)
+	}
+
+	if !errors.Is(err, errs.ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+}
+
+func TestBatchAtomicErr(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	repo.
+		EXPECT().
+		WithinTx(ctx, gomock.Any()).
+		Return(errStorageProblem)
+
+	_, err := useCase.Batch(ctx, 1, batchOperations(), true)
+
+	if !errors.Is(err, errStorageProblem) {
+		t.Fatalf("expected wrapped error, got %v", err)
+	}
+}
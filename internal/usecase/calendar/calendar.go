@@ -0,0 +1,49 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/usecase"
+	"github.com/andreyxaxa/calendar/pkg/ical"
+)
+
+// UseCase - imported events are created through events, so they go the same way as created ones.
+type UseCase struct {
+	repo   repo.EventsRepo
+	events usecase.Events
+}
+
+// New returns new UseCase(struct)
+func New(r repo.EventsRepo, e usecase.Events) *UseCase {
+	return &UseCase{
+		repo:   r,
+		events: e,
+	}
+}
+
+// Export returns all events of the user as an iCalendar (RFC 5545) feed.
+func (uc *UseCase) Export(ctx context.Context, userID int) ([]byte, error) {
+	events, err := uc.repo.GetAll(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("CalendarUseCase - Export - uc.repo.GetAll: %w", err)
+	}
+
+	cal, err := vcalendar(events, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("CalendarUseCase - Export - vcalendar: %w", err)
+	}
+
+	cal.Add(ical.NewProp("METHOD", "PUBLISH"))
+
+	var buf bytes.Buffer
+
+	if err = ical.Encode(&buf, cal); err != nil {
+		return nil, fmt.Errorf("CalendarUseCase - Export - ical.Encode: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,162 @@
+package calendar
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/ical"
+	"github.com/google/uuid"
+)
+
+const _prodID = "-//andreyxaxa//calendar//EN"
+
+// zoneSpan - what a VTIMEZONE has to cover.
+type zoneSpan struct {
+	loc      *time.Location
+	from, to time.Time
+}
+
+// vcalendar returns a VCALENDAR with a VEVENT per event and per override of a series,
+// ordered by start, and a VTIMEZONE per time zone of timed events. It has no METHOD:
+// CalDAV objects cant have one.
+func vcalendar(events map[uuid.UUID]entity.Event, now time.Time) (ical.Component, error) {
+	cal := ical.Component{Name: "VCALENDAR"}
+	cal.Add(
+		ical.NewProp("VERSION", "2.0"),
+		ical.NewProp("PRODID", _prodID),
+		ical.NewProp("CALSCALE", "GREGORIAN"),
+	)
+
+	uids := make([]uuid.UUID, 0, len(events))
+	for uid := range events {
+		uids = append(uids, uid)
+	}
+
+	slices.SortFunc(uids, func(a, b uuid.UUID) int {
+		if c := events[a].Start.Compare(events[b].Start); c != 0 {
+			return c
+		}
+
+		return bytes.Compare(a[:], b[:])
+	})
+
+	stamp := ical.NewProp("DTSTAMP", now.UTC().Format(ical.UTCTimeFormat))
+	zones := make(map[string]*zoneSpan)
+
+	var vevents []ical.Component
+
+	for _, uid := range uids {
+		event := events[uid]
+
+		loc, err := time.LoadLocation(event.TimeZone)
+		if err != nil {
+			return ical.Component{}, fmt.Errorf("event %s: %w", uid, err)
+		}
+
+		vevents = append(vevents, vevent(uid, event, loc, stamp)...)
+
+		if !event.AllDay && loc != time.UTC {
+			cover(zones, loc, event)
+		}
+	}
+
+	names := make([]string, 0, len(zones))
+	for name := range zones {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		span := zones[name]
+		cal.AddComponent(ical.Timezone(span.loc, span.from, span.to))
+	}
+
+	cal.AddComponent(vevents...)
+
+	return cal, nil
+}
+
+// vevent returns the event and overrides of a series, they share the UID and
+// overrides are told apart by RECURRENCE-ID.
+func vevent(uid uuid.UUID, event entity.Event, loc *time.Location, stamp ical.Prop) []ical.Component {
+	master := ical.Component{Name: "VEVENT"}
+	master.Add(ical.NewProp("UID", uid.String()), stamp)
+	master.Add(timing(event.Start, event.End, event.AllDay, loc)...)
+	master.Add(ical.Text("SUMMARY", event.Text))
+
+	if !event.Recurring() {
+		return []ical.Component{master}
+	}
+
+	master.Add(ical.NewProp("RRULE", event.RRule))
+
+	if len(event.ExDates) > 0 {
+		master.Add(instant("EXDATE", event.AllDay, loc, event.ExDates...))
+	}
+
+	res := []ical.Component{master}
+
+	for _, o := range event.Overrides {
+		override := ical.Component{Name: "VEVENT"}
+		override.Add(ical.NewProp("UID", uid.String()), stamp)
+		override.Add(instant("RECURRENCE-ID", event.AllDay, loc, o.RecurrenceID))
+		override.Add(timing(o.Start, o.End, o.AllDay, loc)...)
+		override.Add(ical.Text("SUMMARY", o.Text))
+
+		res = append(res, override)
+	}
+
+	return res
+}
+
+// timing returns DTSTART and DTEND: dates for all-day events, wall clock of loc for timed ones.
+func timing(start, end time.Time, allDay bool, loc *time.Location) []ical.Prop {
+	return []ical.Prop{
+		instant("DTSTART", allDay, loc, start),
+		instant("DTEND", allDay, loc, end),
+	}
+}
+
+func instant(name string, allDay bool, loc *time.Location, times ...time.Time) ical.Prop {
+	if allDay {
+		return ical.Date(name, times...)
+	}
+
+	local := make([]time.Time, len(times))
+	for i, t := range times {
+		local[i] = t.In(loc)
+	}
+
+	return ical.DateTime(name, local...)
+}
+
+// cover widens the span of loc to the years the event takes.
+func cover(zones map[string]*zoneSpan, loc *time.Location, event entity.Event) {
+	start := event.SeriesStart().In(loc)
+	from := time.Date(start.Year(), time.January, 1, 0, 0, 0, 0, loc)
+
+	// an endless series relies on the rules still in force after its start.
+	to, err := event.SeriesEnd()
+	if err != nil || to.IsZero() {
+		to = event.Start
+	}
+
+	span, ok := zones[loc.String()]
+	if !ok {
+		zones[loc.String()] = &zoneSpan{loc: loc, from: from, to: to}
+
+		return
+	}
+
+	if from.Before(span.from) {
+		span.from = from
+	}
+
+	if to.After(span.to) {
+		span.to = to
+	}
+}
@@ -0,0 +1,344 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/usecase"
+	"github.com/andreyxaxa/calendar/pkg/ical"
+	"github.com/andreyxaxa/calendar/pkg/rrule"
+	"github.com/andreyxaxa/calendar/pkg/types/date"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+)
+
+// imported - a VEVENT mapped to an event, with the overrides of its series applied.
+type imported struct {
+	entry entity.ImportEntry
+	event entity.Event
+}
+
+// Import creates the VEVENTs of an iCalendar file as events of the user. Changed and cancelled
+// instances of a series become its overrides and exdates, floating times are taken in loc.
+// The events are created in one transaction: a storage error imports none of them.
+// Event UIDs are derived from iCalendar ones, so events imported before are skipped and
+// a file can be imported again.
+func (uc *UseCase) Import(ctx context.Context, userID int, r io.Reader, loc *time.Location) (entity.ImportReport, error) {
+	calendars, err := ical.Decode(r)
+	if err != nil {
+		return entity.ImportReport{}, fmt.Errorf("CalendarUseCase - Import - ical.Decode: %w", err)
+	}
+
+	var report entity.ImportReport
+
+	err = uc.events.WithinTx(ctx, func(events usecase.Events) error {
+		report = entity.ImportReport{}
+
+		for _, cal := range calendars {
+			if cal.Name != "VCALENDAR" {
+				continue
+			}
+
+			for _, e := range fromCalendar(cal, loc, &report) {
+				_, err := events.Create(ctx, userID, e.entry.EventUID, e.event)
+				if errors.Is(err, errs.ErrAlreadyExists) {
+					e.entry.Reason = "already imported"
+					report.Skipped = append(report.Skipped, e.entry)
+
+					continue
+				}
+
+				if err != nil {
+					return fmt.Errorf("events.Create: %w", err)
+				}
+
+				report.Imported = append(report.Imported, e.entry)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.ImportReport{}, fmt.Errorf("CalendarUseCase - Import - uc.events.WithinTx: %w", err)
+	}
+
+	for _, entries := range [][]entity.ImportEntry{report.Imported, report.Skipped, report.Failed} {
+		slices.SortStableFunc(entries, func(a, b entity.ImportEntry) int {
+			return a.Line - b.Line
+		})
+	}
+
+	return report, nil
+}
+
+// fromCalendar maps the VEVENTs of cal, those that cant be imported go to the report.
+func fromCalendar(cal ical.Component, loc *time.Location, report *entity.ImportReport) []imported {
+	locs := ical.NewLocations(cal)
+
+	var (
+		events    []imported
+		overrides []ical.Component
+	)
+
+	byUID := make(map[string]int)
+
+	for _, c := range cal.Components {
+		if c.Name != "VEVENT" {
+			continue
+		}
+
+		entry := entity.ImportEntry{Line: c.Line}
+
+		uid, ok := c.Prop("UID")
+		if !ok || uid.Value == "" {
+			entry.Reason = "UID required"
+			report.Failed = append(report.Failed, entry)
+
+			continue
+		}
+
+		entry.ICalUID = uid.Value
+
+		if _, ok = c.Prop("RECURRENCE-ID"); ok {
+			overrides = append(overrides, c)
+
+			continue
+		}
+
+		if cancelled(c) {
+			entry.Reason = "cancelled"
+			report.Skipped = append(report.Skipped, entry)
+
+			continue
+		}
+
+		if i, ok := byUID[entry.ICalUID]; ok {
+			entry.Reason = fmt.Sprintf("duplicate of the VEVENT at line %d", events[i].entry.Line)
+			report.Skipped = append(report.Skipped, entry)
+
+			continue
+		}
+
+		event, err := toEvent(c, locs, loc)
+		if err != nil {
+			entry.Reason = err.Error()
+			report.Failed = append(report.Failed, entry)
+
+			continue
+		}
+
+		entry.EventUID = entity.ImportUID(entry.ICalUID)
+		byUID[entry.ICalUID] = len(events)
+		events = append(events, imported{entry: entry, event: event})
+	}
+
+	for _, c := range overrides {
+		uid, _ := c.Prop("UID")
+		entry := entity.ImportEntry{Line: c.Line, ICalUID: uid.Value}
+
+		i, ok := byUID[entry.ICalUID]
+		if !ok {
+			entry.Reason = "changed instance of a series that is not imported"
+			report.Skipped = append(report.Skipped, entry)
+
+			continue
+		}
+
+		if err := applyOverride(&events[i].event, c, locs, loc); err != nil {
+			entry.Reason = err.Error()
+			report.Failed = append(report.Failed, entry)
+		}
+	}
+
+	return events
+}
+
+func cancelled(c ical.Component) bool {
+	status, _ := c.Prop("STATUS")
+
+	return strings.EqualFold(status.Value, "CANCELLED")
+}
+
+// toEvent maps a VEVENT the way create_event takes events: all-day ones become
+// floating days, timed ones keep the zone of DTSTART.
+func toEvent(c ical.Component, locs *ical.Locations, loc *time.Location) (entity.Event, error) {
+	start, end, allDay, err := parseTiming(c, locs, loc)
+	if err != nil {
+		return entity.Event{}, err
+	}
+
+	event := entity.Event{Start: start, End: end, AllDay: allDay, TimeZone: loc.String()}
+
+	if !allDay {
+		event.TimeZone = start.Location().String()
+		event.Start, event.End = start.UTC(), end.UTC()
+	}
+
+	summary, _ := c.Prop("SUMMARY")
+	if event.Text = ical.Unescape(summary.Value); event.Text == "" {
+		return entity.Event{}, errors.New("SUMMARY required")
+	}
+
+	if _, ok := c.Prop("RDATE"); ok {
+		return entity.Event{}, errors.New("RDATE is not supported")
+	}
+
+	rules := c.All("RRULE")
+	if len(rules) == 0 {
+		return event, nil
+	}
+
+	if len(rules) > 1 {
+		return entity.Event{}, errors.New("several RRULEs are not supported")
+	}
+
+	rule, err := rrule.Parse(rules[0].Value)
+	if err != nil {
+		return entity.Event{}, err
+	}
+
+	event.RRule = rule.String()
+
+	for _, p := range c.All("EXDATE") {
+		times, isDate, err := p.Times(locs, start.Location())
+		if err != nil {
+			return entity.Event{}, err
+		}
+
+		for _, t := range times {
+			event.ExDates = append(event.ExDates, instanceStart(t, isDate, start, allDay))
+		}
+	}
+
+	return event, nil
+}
+
+// applyOverride applies a VEVENT with RECURRENCE-ID to the series.
+func applyOverride(series *entity.Event, c ical.Component, locs *ical.Locations, loc *time.Location) error {
+	p, _ := c.Prop("RECURRENCE-ID")
+
+	if strings.EqualFold(p.Param("RANGE"), "THISANDFUTURE") {
+		return errors.New("RECURRENCE-ID with RANGE=THISANDFUTURE is not supported")
+	}
+
+	first := series.Start
+	if !series.AllDay {
+		// series.TimeZone comes from DTSTART, it loads.
+		zone, _ := time.LoadLocation(series.TimeZone)
+		first = first.In(zone)
+	}
+
+	times, isDate, err := p.Times(locs, loc)
+	if err != nil {
+		return err
+	}
+
+	recurrenceID := instanceStart(times[0], isDate, first, series.AllDay)
+
+	ok, err := series.HasInstance(recurrenceID)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("RECURRENCE-ID %s is not an instance of the series", p.Value)
+	}
+
+	if cancelled(c) {
+		series.Cancel(recurrenceID)
+
+		return nil
+	}
+
+	override := entity.Override{RecurrenceID: recurrenceID, Text: series.Text}
+
+	if summary, ok := c.Prop("SUMMARY"); ok && summary.Value != "" {
+		override.Text = ical.Unescape(summary.Value)
+	}
+
+	if _, ok := c.Prop("DTSTART"); !ok {
+		instance := series.Instance(recurrenceID)
+		override.Start, override.End, override.AllDay = instance.Start, instance.End, series.AllDay
+		series.Override(override)
+
+		return nil
+	}
+
+	start, end, allDay, err := parseTiming(c, locs, loc)
+	if err != nil {
+		return err
+	}
+
+	override.Start, override.End, override.AllDay = start.UTC(), end.UTC(), allDay
+	series.Override(override)
+
+	return nil
+}
+
+// parseTiming returns the start and end of a VEVENT: DTEND, DTSTART + DURATION or, without
+// both, the day of an all-day event or the start of a timed one. Dates come as UTC midnight.
+func parseTiming(c ical.Component, locs *ical.Locations, loc *time.Location) (start, end time.Time, allDay bool, err error) {
+	p, ok := c.Prop("DTSTART")
+	if !ok {
+		return time.Time{}, time.Time{}, false, errors.New("DTSTART required")
+	}
+
+	times, allDay, err := p.Times(locs, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+
+	start = times[0]
+
+	if p, ok = c.Prop("DTEND"); ok {
+		times, _, err = p.Times(locs, start.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, false, err
+		}
+
+		end = times[0]
+		if allDay {
+			end = date.Floating(end)
+		}
+	} else if p, ok = c.Prop("DURATION"); ok {
+		d, err := ical.ParseDuration(p.Value)
+		if err != nil {
+			return time.Time{}, time.Time{}, false, err
+		}
+
+		end = d.Add(start)
+	} else if allDay {
+		end = start.AddDate(0, 0, 1)
+	} else {
+		end = start
+	}
+
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, false, errors.New("DTEND cant be before DTSTART")
+	}
+
+	// an empty all-day event still takes its day.
+	if allDay && !end.After(start) {
+		end = start.AddDate(0, 0, 1)
+	}
+
+	return start, end, allDay, nil
+}
+
+// instanceStart maps an EXDATE or RECURRENCE-ID value to an instance start of the series starting
+// at first: floating days for all-day series, for timed ones a date means the instance that day.
+func instanceStart(t time.Time, isDate bool, first time.Time, allDay bool) time.Time {
+	switch {
+	case allDay:
+		return date.Floating(t)
+	case isDate:
+		return time.Date(t.Year(), t.Month(), t.Day(), first.Hour(), first.Minute(), first.Second(), 0, first.Location()).UTC()
+	default:
+		return t.UTC()
+	}
+}
@@ -0,0 +1,293 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/usecase"
+	"github.com/andreyxaxa/calendar/pkg/ical"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+// ObjectTags lists events of the user as calendar objects without data. With from or to set,
+// only events that may have instances overlapping [from, to) are listed, a zero bound is open.
+func (uc *UseCase) ObjectTags(ctx context.Context, userID int, from, to time.Time) ([]entity.CalendarObject, error) {
+	events, err := uc.repo.GetAll(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("CalendarUseCase - ObjectTags - uc.repo.GetAll: %w", err)
+	}
+
+	objects := make([]entity.CalendarObject, 0, len(events))
+
+	for uid, event := range events {
+		ok, err := inRange(uid, event, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("CalendarUseCase - ObjectTags - inRange: %w", err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		tag, err := etag(event)
+		if err != nil {
+			return nil, fmt.Errorf("CalendarUseCase - ObjectTags - etag: %w", err)
+		}
+
+		objects = append(objects, entity.CalendarObject{UID: uid, ETag: tag})
+	}
+
+	slices.SortFunc(objects, func(a, b entity.CalendarObject) int {
+		return bytes.Compare(a.UID[:], b.UID[:])
+	})
+
+	return objects, nil
+}
+
+// Objects returns the events with their iCalendar data, in order of eventUIDs.
+// Events that dont exist are left out.
+func (uc *UseCase) Objects(ctx context.Context, userID int, eventUIDs []uuid.UUID) ([]entity.CalendarObject, error) {
+	objects := make([]entity.CalendarObject, 0, len(eventUIDs))
+
+	for _, uid := range eventUIDs {
+		event, err := uc.repo.GetByUID(ctx, userID, uid)
+		if errors.Is(err, errs.ErrEventNotFound) || errors.Is(err, errs.ErrUserNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("CalendarUseCase - Objects - uc.repo.GetByUID: %w", err)
+		}
+
+		object, err := calendarObject(uid, event)
+		if err != nil {
+			return nil, fmt.Errorf("CalendarUseCase - Objects - calendarObject: %w", err)
+		}
+
+		objects = append(objects, object)
+	}
+
+	return objects, nil
+}
+
+// PutObject creates or replaces the event from a calendar object: a VCALENDAR with a VEVENT
+// and the changed instances of its series, as CalDAV clients store them. Floating times are
+// taken in loc. It reports whether the event was created.
+func (uc *UseCase) PutObject(ctx context.Context, userID int, eventUID uuid.UUID, r io.Reader, loc *time.Location,
+	cond entity.Preconditions,
+) (bool, error) {
+	calendars, err := ical.Decode(r)
+	if err != nil {
+		return false, fmt.Errorf("CalendarUseCase - PutObject - ical.Decode: %w", err)
+	}
+
+	event, err := objectEvent(calendars, loc)
+	if err != nil {
+		return false, fmt.Errorf("CalendarUseCase - PutObject - objectEvent: %w", err)
+	}
+
+	var created bool
+
+	// the preconditions are checked in the transaction of the write.
+	err = uc.events.WithinTx(ctx, func(events usecase.Events) error {
+		tag, version, err := currentTag(ctx, events, userID, eventUID, cond)
+		if err != nil {
+			return fmt.Errorf("currentTag: %w", err)
+		}
+
+		if !cond.Hold(tag) {
+			return errs.ErrPreconditionFailed
+		}
+
+		if tag == "" {
+			if _, err = events.Create(ctx, userID, eventUID, event); err != nil {
+				return fmt.Errorf("events.Create: %w", err)
+			}
+
+			created = true
+
+			return nil
+		}
+
+		event.Version = version
+
+		if _, err = events.Replace(ctx, userID, eventUID, event); err != nil {
+			return fmt.Errorf("events.Replace: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("CalendarUseCase - PutObject - uc.events.WithinTx: %w", err)
+	}
+
+	return created, nil
+}
+
+// DeleteObject deletes the event if the preconditions hold.
+func (uc *UseCase) DeleteObject(ctx context.Context, userID int, eventUID uuid.UUID, cond entity.Preconditions) error {
+	err := uc.events.WithinTx(ctx, func(events usecase.Events) error {
+		tag, version, err := currentTag(ctx, events, userID, eventUID, cond)
+		if err != nil {
+			return fmt.Errorf("currentTag: %w", err)
+		}
+
+		if tag == "" {
+			return errs.ErrEventNotFound
+		}
+
+		if !cond.Hold(tag) {
+			return errs.ErrPreconditionFailed
+		}
+
+		if err = events.Delete(ctx, userID, eventUID, version); err != nil {
+			return fmt.Errorf("events.Delete: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("CalendarUseCase - DeleteObject - uc.events.WithinTx: %w", err)
+	}
+
+	return nil
+}
+
+// currentTag returns the ETag of the event, "" if there is no such event. If there are
+// preconditions, it returns the version too: the write expecting it fails unless they still hold.
+func currentTag(ctx context.Context, events usecase.Events, userID int, eventUID uuid.UUID,
+	cond entity.Preconditions,
+) (string, int64, error) {
+	event, err := events.GetByUID(ctx, userID, eventUID)
+	if errors.Is(err, errs.ErrEventNotFound) || errors.Is(err, errs.ErrUserNotFound) {
+		return "", 0, nil
+	}
+
+	if err != nil {
+		return "", 0, fmt.Errorf("events.GetByUID: %w", err)
+	}
+
+	tag, err := etag(event)
+	if err != nil {
+		return "", 0, fmt.Errorf("etag: %w", err)
+	}
+
+	if len(cond.IfMatch) == 0 && len(cond.IfNoneMatch) == 0 {
+		return tag, 0, nil
+	}
+
+	return tag, event.Version, nil
+}
+
+// etag hashes what is stored of the event, so it changes with any field of it.
+func etag(event entity.Event) (string, error) {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+func calendarObject(uid uuid.UUID, event entity.Event) (entity.CalendarObject, error) {
+	tag, err := etag(event)
+	if err != nil {
+		return entity.CalendarObject{}, fmt.Errorf("etag: %w", err)
+	}
+
+	cal, err := vcalendar(map[uuid.UUID]entity.Event{uid: event}, time.Now())
+	if err != nil {
+		return entity.CalendarObject{}, fmt.Errorf("vcalendar: %w", err)
+	}
+
+	var buf bytes.Buffer
+
+	if err = ical.Encode(&buf, cal); err != nil {
+		return entity.CalendarObject{}, fmt.Errorf("ical.Encode: %w", err)
+	}
+
+	return entity.CalendarObject{UID: uid, ETag: tag, Data: buf.Bytes()}, nil
+}
+
+// objectEvent maps a calendar object the way Import maps files, but the object must hold
+// exactly one event: anything Import would skip or fail makes it invalid.
+func objectEvent(calendars []ical.Component, loc *time.Location) (entity.Event, error) {
+	if len(calendars) != 1 || calendars[0].Name != "VCALENDAR" {
+		return entity.Event{}, fmt.Errorf("%w: a single VCALENDAR expected", errs.ErrInvalidCalendarData)
+	}
+
+	var report entity.ImportReport
+
+	events := fromCalendar(calendars[0], loc, &report)
+
+	for _, entries := range [][]entity.ImportEntry{report.Failed, report.Skipped} {
+		if len(entries) > 0 {
+			return entity.Event{}, fmt.Errorf("%w: line %d: %s", errs.ErrInvalidCalendarData, entries[0].Line, entries[0].Reason)
+		}
+	}
+
+	switch len(events) {
+	case 0:
+		return entity.Event{}, fmt.Errorf("%w: no VEVENT", errs.ErrInvalidCalendarData)
+	case 1:
+		return events[0].event, nil
+	default:
+		return entity.Event{}, fmt.Errorf("%w: VEVENTs of several UIDs", errs.ErrInvalidCalendarData)
+	}
+}
+
+// _endOfTime stands for an open upper bound.
+var _endOfTime = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// inRange reports whether the event may have instances overlapping [from, to). With an open
+// bound a series is only checked against its start or end, exdates aside.
+func inRange(uid uuid.UUID, event entity.Event, from, to time.Time) (bool, error) {
+	if from.IsZero() && to.IsZero() {
+		return true, nil
+	}
+
+	if !event.Recurring() {
+		upper := to
+		if upper.IsZero() {
+			upper = _endOfTime
+		}
+
+		return event.Overlaps(from, upper), nil
+	}
+
+	if !to.IsZero() && !event.SeriesStart().Before(to) {
+		return false, nil
+	}
+
+	end, err := event.SeriesEnd()
+	if err != nil {
+		return false, err
+	}
+
+	if !from.IsZero() && !end.IsZero() && !end.After(from) {
+		return false, nil
+	}
+
+	if from.IsZero() || to.IsZero() {
+		return true, nil
+	}
+
+	occurrences, err := event.Occurrences(uid, from, to)
+	if err != nil {
+		return false, err
+	}
+
+	return len(occurrences) > 0, nil
+}
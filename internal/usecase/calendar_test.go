@@ -0,0 +1,684 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/usecase"
+	"github.com/andreyxaxa/calendar/internal/usecase/calendar"
+	"github.com/andreyxaxa/calendar/pkg/ical"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func calendarUseCase(t *testing.T) (*calendar.UseCase, *MockEventsRepo, *MockEvents, *gomock.Controller) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	repo := NewMockEventsRepo(mockCtl)
+	events := NewMockEvents(mockCtl)
+	events.EXPECT().WithinTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, fn func(usecase.Events) error) error {
+			return fn(events)
+		}).AnyTimes()
+
+	useCase := calendar.New(repo, events)
+
+	return useCase, repo, events, mockCtl
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _, ctrl := calendarUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+
+	seriesUID := uuid.MustParse("5f0f7d3c-3a52-4b43-9d0e-2f1c0cbe1a47")
+	meetingUID := uuid.MustParse("0c8a4b4e-7f1d-4b5e-9a43-6c1f3e2a9b10")
+	holidayUID := uuid.MustParse("bb52a762-f283-48ad-8cb5-cfe8e5bfa8eb")
+
+	// 10:00 in Moscow.
+	start := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+
+	events := map[uuid.UUID]entity.Event{
+		seriesUID: {
+			Start:    start,
+			End:      start.Add(time.Hour),
+			TimeZone: "Europe/Moscow",
+			Text:     "stand-up",
+			RRule:    "FREQ=WEEKLY;BYDAY=MO",
+			ExDates:  []time.Time{start.AddDate(0, 0, 14)},
+			Overrides: []entity.Override{{
+				RecurrenceID: start.AddDate(0, 0, 7),
+				Start:        start.AddDate(0, 0, 7).Add(5 * time.Hour),
+				End:          start.AddDate(0, 0, 7).Add(6 * time.Hour),
+				Text:         "moved",
+			}},
+		},
+		meetingUID: {
+			Start:    start.AddDate(0, 0, 1),
+			End:      start.AddDate(0, 0, 1).Add(30 * time.Minute),
+			TimeZone: "UTC",
+			Text:     "call Bob; agenda: budget, hiring\nbring notes",
+		},
+		holidayUID: {
+			Start:    time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC),
+			End:      time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC),
+			AllDay:   true,
+			TimeZone: "Europe/Moscow",
+			Text:     "holiday",
+		},
+	}
+
+	repo.
+		EXPECT().
+		GetAll(ctx, userID).
+		Return(events, nil)
+
+	data, err := useCase.Export(ctx, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	feed := string(data)
+
+	expected := []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"BEGIN:VTIMEZONE\r\nTZID:Europe/Moscow\r\n",
+		// series first, ordered by start.
+		"BEGIN:VEVENT\r\nUID:5f0f7d3c-3a52-4b43-9d0e-2f1c0cbe1a47\r\n",
+		"DTSTART;TZID=Europe/Moscow:20260302T100000\r\nDTEND;TZID=Europe/Moscow:20260302T110000\r\n",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO\r\nEXDATE;TZID=Europe/Moscow:20260316T100000\r\n",
+		"RECURRENCE-ID;TZID=Europe/Moscow:20260309T100000\r\nDTSTART;TZID=Europe/Moscow:20260309T150000\r\n",
+		"DTSTART:20260303T070000Z\r\nDTEND:20260303T073000Z\r\n",
+		`SUMMARY:call Bob\; agenda: budget\, hiring\nbring notes` + "\r\n",
+		"DTSTART;VALUE=DATE:20260308\r\nDTEND;VALUE=DATE:20260309\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	}
+
+	last := 0
+
+	for _, s := range expected {
+		i := strings.Index(feed[last:], s)
+		if i < 0 {
+			t.Fatalf("expected %q after offset %d in:\n%s", s, last, feed)
+		}
+
+		last += i
+	}
+
+	if n := strings.Count(feed, "BEGIN:VTIMEZONE"); n != 1 {
+		t.Fatalf("expected a single VTIMEZONE, got %d", n)
+	}
+
+	if n := strings.Count(feed, "BEGIN:VEVENT"); n != 4 {
+		t.Fatalf("expected 4 VEVENTs, got %d", n)
+	}
+}
+
+func TestExportErr(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _, ctrl := calendarUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	repo.
+		EXPECT().
+		GetAll(ctx, 1).
+		Return(nil, errs.ErrUserNotFound)
+
+	_, err := useCase.Export(ctx, 1)
+	if !errors.Is(err, errs.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+// _importFile - numbered by line: a series with an exdate, a moved and a cancelled
+// instance, an all-day event, a folded floating event, and entries that cant be imported.
+var _importFile = strings.Join([]string{
+	"BEGIN:VCALENDAR",        // 1
+	"VERSION:2.0",            // 2
+	"BEGIN:VEVENT",           // 3
+	"UID:series@example.com", // 4
+	"DTSTART;TZID=Russian Standard Time:20260302T100000", // 5
+	"DTEND;TZID=Russian Standard Time:20260302T110000",   // 6
+	"RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=5",                 // 7
+	"EXDATE;TZID=Russian Standard Time:20260316T100000",  // 8
+	"SUMMARY:stand-up",       // 9
+	"END:VEVENT",             // 10
+	"BEGIN:VEVENT",           // 11
+	"UID:series@example.com", // 12
+	"RECURRENCE-ID;TZID=Russian Standard Time:20260309T100000", // 13
+	"DTSTART;TZID=Russian Standard Time:20260309T150000",       // 14
+	"DURATION:PT30M",                 // 15
+	"END:VEVENT",                     // 16
+	"BEGIN:VEVENT",                   // 17
+	"UID:series@example.com",         // 18
+	"RECURRENCE-ID:20260323T070000Z", // 19
+	"STATUS:CANCELLED",               // 20
+	"END:VEVENT",                     // 21
+	"BEGIN:VEVENT",                   // 22
+	"UID:holiday@example.com",        // 23
+	"DTSTART;VALUE=DATE:20260308",    // 24
+	"SUMMARY:holiday",                // 25
+	"END:VEVENT",                     // 26
+	"BEGIN:VEVENT",                   // 27
+	"UID:call@example.com",           // 28
+	"DTSTART:20260303T120000",        // 29
+	"DTEND:20260303T123000",          // 30
+	"SUMMARY:call Bob\\, agenda: budget\\nand hir", // 31
+	" ing",                                     // 32
+	"END:VEVENT",                               // 33
+	"BEGIN:VEVENT",                             // 34
+	"UID:yearly@example.com",                   // 35
+	"DTSTART;VALUE=DATE:20260308",              // 36
+	"RRULE:FREQ=YEARLY;BYMONTH=3",              // 37
+	"SUMMARY:anniversary",                      // 38
+	"END:VEVENT",                               // 39
+	"BEGIN:VEVENT",                             // 40
+	"UID:untitled@example.com",                 // 41
+	"DTSTART:20260304T120000Z",                 // 42
+	"END:VEVENT",                               // 43
+	"BEGIN:VEVENT",                             // 44
+	"UID:holiday@example.com",                  // 45
+	"DTSTART;VALUE=DATE:20260309",              // 46
+	"SUMMARY:holiday again",                    // 47
+	"END:VEVENT",                               // 48
+	"BEGIN:VEVENT",                             // 49
+	"UID:orphan@example.com",                   // 50
+	"RECURRENCE-ID:20260309T070000Z",           // 51
+	"DTSTART:20260309T080000Z",                 // 52
+	"SUMMARY:orphan",                           // 53
+	"END:VEVENT",                               // 54
+	"BEGIN:VEVENT",                             // 55
+	"UID:5f0f7d3c-3a52-4b43-9d0e-2f1c0cbe1a47", // 56
+	"DTSTART:20260305T090000Z",                 // 57
+	"SUMMARY:exported before",                  // 58
+	"END:VEVENT",                               // 59
+	"END:VCALENDAR",                            // 60
+}, "\r\n") + "\r\n"
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	useCase, _, events, ctrl := calendarUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+
+	vvo, err := time.LoadLocation("Asia/Vladivostok")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	created := make(map[uuid.UUID]entity.Event)
+	exported := uuid.MustParse("5f0f7d3c-3a52-4b43-9d0e-2f1c0cbe1a47")
+
+	events.
+		EXPECT().
+		Create(ctx, userID, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int, uid uuid.UUID, event entity.Event) (entity.Event, error) {
+			if uid == exported {
+				return entity.Event{}, errs.ErrAlreadyExists
+			}
+
+			created[uid] = event
+
+			return event, nil
+		}).
+		Times(4)
+
+	report, err := useCase.Import(ctx, userID, strings.NewReader(_importFile), vvo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := func(entries []entity.ImportEntry) []int {
+		var res []int
+		for _, e := range entries {
+			res = append(res, e.Line)
+		}
+
+		return res
+	}
+
+	if got := lines(report.Imported); !slices.Equal(got, []int{3, 22, 27}) {
+		t.Fatalf("expected imported at lines [3 22 27], got %v", got)
+	}
+
+	if got := lines(report.Skipped); !slices.Equal(got, []int{44, 49, 55}) {
+		t.Fatalf("expected skipped at lines [44 49 55], got %v %+v", got, report.Skipped)
+	}
+
+	if got := lines(report.Failed); !slices.Equal(got, []int{34, 40}) {
+		t.Fatalf("expected failed at lines [34 40], got %v %+v", got, report.Failed)
+	}
+
+	moscow := time.FixedZone("MSK", 3*3600)
+
+	series := created[report.Imported[0].EventUID]
+	if series.TimeZone != "Europe/Moscow" || !series.Start.Equal(time.Date(2026, 3, 2, 10, 0, 0, 0, moscow)) ||
+		series.RRule != "FREQ=WEEKLY;COUNT=5;BYDAY=MO" {
+		t.Fatalf("unexpected series %+v", series)
+	}
+
+	// the exdate and the cancelled instance.
+	expectedExDates := []time.Time{time.Date(2026, 3, 16, 7, 0, 0, 0, time.UTC), time.Date(2026, 3, 23, 7, 0, 0, 0, time.UTC)}
+	if !slices.EqualFunc(series.ExDates, expectedExDates, time.Time.Equal) {
+		t.Fatalf("expected exdates %v, got %v", expectedExDates, series.ExDates)
+	}
+
+	if len(series.Overrides) != 1 || series.Overrides[0].Text != "stand-up" ||
+		!series.Overrides[0].Start.Equal(time.Date(2026, 3, 9, 15, 0, 0, 0, moscow)) ||
+		series.Overrides[0].End.Sub(series.Overrides[0].Start) != 30*time.Minute {
+		t.Fatalf("unexpected overrides %+v", series.Overrides)
+	}
+
+	holiday := created[report.Imported[1].EventUID]
+	if !holiday.AllDay || !holiday.Start.Equal(time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)) ||
+		!holiday.End.Equal(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected all-day event %+v", holiday)
+	}
+
+	call := created[report.Imported[2].EventUID]
+	if call.Text != "call Bob, agenda: budget\nand hiring" || call.TimeZone != "Asia/Vladivostok" ||
+		!call.Start.Equal(time.Date(2026, 3, 3, 12, 0, 0, 0, vvo)) {
+		t.Fatalf("unexpected floating event %+v", call)
+	}
+}
+
+func TestImportErr(t *testing.T) {
+	t.Parallel()
+
+	useCase, _, events, ctrl := calendarUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+
+	errStorage := errors.New("storage problems")
+
+	gomock.InOrder(
+		events.EXPECT().Create(ctx, userID, gomock.Any(), gomock.Any()).Return(entity.Event{}, nil),
+		events.EXPECT().Create(ctx, userID, gomock.Any(), gomock.Any()).Return(entity.Event{}, errStorage),
+	)
+
+	report, err := useCase.Import(ctx, userID, strings.NewReader(_importFile), time.UTC)
+	if !errors.Is(err, errStorage) {
+		t.Fatalf("expected storage error, got %v", err)
+	}
+
+	if len(report.Imported) != 0 {
+		t.Fatalf("expected nothing imported, got %+v", report.Imported)
+	}
+}
+
+func TestImportInvalidFile(t *testing.T) {
+	t.Parallel()
+
+	useCase, _, _, ctrl := calendarUseCase(t)
+	defer ctrl.Finish()
+
+	data := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:1\r\nEND:VCALENDAR\r\n"
+
+	_, err := useCase.Import(context.Background(), 1, strings.NewReader(data), time.UTC)
+	if !errors.Is(err, ical.ErrInvalidData) || !strings.Contains(err.Error(), "line 4") {
+		t.Fatalf("expected ErrInvalidData at line 4, got %v", err)
+	}
+}
+
+func TestObjectTags(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _, ctrl := calendarUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+
+	seriesUID := uuid.MustParse("5f0f7d3c-3a52-4b43-9d0e-2f1c0cbe1a47")
+	meetingUID := uuid.MustParse("0c8a4b4e-7f1d-4b5e-9a43-6c1f3e2a9b10")
+	holidayUID := uuid.MustParse("bb52a762-f283-48ad-8cb5-cfe8e5bfa8eb")
+
+	start := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+
+	series := entity.Event{
+		Start:    start,
+		End:      start.Add(time.Hour),
+		TimeZone: "Europe/Moscow",
+		Text:     "stand-up",
+		RRule:    "FREQ=WEEKLY;COUNT=3",
+		Overrides: []entity.Override{{
+			RecurrenceID: start.AddDate(0, 0, 7),
+			Start:        start.AddDate(0, 0, 10),
+			End:          start.AddDate(0, 0, 10).Add(time.Hour),
+			Text:         "moved to thursday",
+		}},
+	}
+
+	events := map[uuid.UUID]entity.Event{
+		seriesUID: series,
+		meetingUID: {
+			Start:    start.AddDate(0, 0, 1),
+			End:      start.AddDate(0, 0, 1).Add(30 * time.Minute),
+			TimeZone: "UTC",
+			Text:     "call",
+		},
+		holidayUID: {
+			Start:    time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC),
+			End:      time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC),
+			AllDay:   true,
+			TimeZone: "Europe/Moscow",
+			Text:     "holiday",
+		},
+	}
+
+	repo.
+		EXPECT().
+		GetAll(ctx, userID).
+		Return(events, nil).
+		Times(5)
+
+	day := func(d int) time.Time {
+		return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		expected []uuid.UUID
+	}{
+		{"all", time.Time{}, time.Time{}, []uuid.UUID{meetingUID, seriesUID, holidayUID}},
+		{"moved instance", day(12), day(13), []uuid.UUID{seriesUID}},
+		{"original start of the moved instance", day(9), day(10), nil},
+		{"open end", day(4), time.Time{}, []uuid.UUID{seriesUID, holidayUID}},
+		{"open start", time.Time{}, day(3), []uuid.UUID{seriesUID}},
+	}
+
+	for _, tc := range tests {
+		objects, err := useCase.ObjectTags(ctx, userID, tc.from, tc.to)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+
+		var uids []uuid.UUID
+
+		for _, o := range objects {
+			if o.ETag == "" || o.Data != nil {
+				t.Fatalf("%s: expected an ETag and no data, got %+v", tc.name, o)
+			}
+
+			uids = append(uids, o.UID)
+		}
+
+		if !slices.Equal(uids, tc.expected) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.expected, uids)
+		}
+	}
+}
+
+func TestObjects(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _, ctrl := calendarUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+
+	uid := uuid.MustParse("5f0f7d3c-3a52-4b43-9d0e-2f1c0cbe1a47")
+	missing := uuid.MustParse("0c8a4b4e-7f1d-4b5e-9a43-6c1f3e2a9b10")
+
+	event := entity.Event{
+		Start:    time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC),
+		End:      time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC),
+		TimeZone: "Europe/Moscow",
+		Text:     "stand-up",
+	}
+
+	repo.EXPECT().GetByUID(ctx, userID, missing).Return(entity.Event{}, errs.ErrEventNotFound)
+	repo.EXPECT().GetByUID(ctx, userID, uid).Return(event, nil).Times(2)
+
+	objects, err := useCase.Objects(ctx, userID, []uuid.UUID{missing, uid})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(objects) != 1 || objects[0].UID != uid {
+		t.Fatalf("expected only %s, got %+v", uid, objects)
+	}
+
+	data := string(objects[0].Data)
+
+	for _, s := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"BEGIN:VTIMEZONE\r\nTZID:Europe/Moscow\r\n",
+		"UID:5f0f7d3c-3a52-4b43-9d0e-2f1c0cbe1a47\r\n",
+		"DTSTART;TZID=Europe/Moscow:20260302T100000\r\n",
+	} {
+		if !strings.Contains(data, s) {
+			t.Fatalf("expected %q in:\n%s", s, data)
+		}
+	}
+
+	// calendar objects cant have METHOD.
+	if strings.Contains(data, "METHOD:") {
+		t.Fatalf("unexpected METHOD in:\n%s", data)
+	}
+
+	again, err := useCase.Objects(ctx, userID, []uuid.UUID{uid})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if again[0].ETag != objects[0].ETag {
+		t.Fatalf("expected the same ETag, got %s and %s", objects[0].ETag, again[0].ETag)
+	}
+}
+
+// _object - a series with a moved instance, as a CalDAV client PUTs it.
+var _object = strings.Join([]string{
+	"BEGIN:VCALENDAR",
+	"VERSION:2.0",
+	"BEGIN:VEVENT",
+	"UID:ABC-123",
+	"DTSTART;TZID=Europe/Berlin:20260302T100000",
+	"DTEND;TZID=Europe/Berlin:20260302T110000",
+	"RRULE:FREQ=WEEKLY;COUNT=5",
+	"SUMMARY:sync",
+	"END:VEVENT",
+	"BEGIN:VEVENT",
+	"UID:ABC-123",
+	"RECURRENCE-ID;TZID=Europe/Berlin:20260309T100000",
+	"DTSTART;TZID=Europe/Berlin:20260309T150000",
+	"DTEND;TZID=Europe/Berlin:20260309T160000",
+	"SUMMARY:sync (moved)",
+	"END:VEVENT",
+	"END:VCALENDAR",
+}, "\r\n") + "\r\n"
+
+func TestPutObject(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userID := 1
+	uid := uuid.MustParse("5f0f7d3c-3a52-4b43-9d0e-2f1c0cbe1a47")
+
+	current := entity.Event{
+		Start:    time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+		End:      time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+		TimeZone: "Europe/Berlin",
+		Text:     "sync",
+		Version:  3,
+	}
+
+	// the ETag of current, as clients got it.
+	tagUseCase, tagRepo, _, tagCtrl := calendarUseCase(t)
+	defer tagCtrl.Finish()
+
+	tagRepo.EXPECT().GetAll(ctx, userID).Return(map[uuid.UUID]entity.Event{uid: current}, nil)
+
+	tags, err := tagUseCase.ObjectTags(ctx, userID, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	currentTag := tags[0].ETag
+
+	tests := []struct {
+		name    string
+		exists  bool
+		cond    entity.Preconditions
+		data    string
+		write   string
+		created bool
+		err     error
+	}{
+		{
+			name:    "create",
+			cond:    entity.Preconditions{IfNoneMatch: []string{"*"}},
+			data:    _object,
+			write:   "Create",
+			created: true,
+		},
+		{
+			name:   "replace",
+			exists: true,
+			cond:   entity.Preconditions{IfMatch: []string{currentTag}},
+			data:   _object,
+			write:  "Replace",
+		},
+		{
+			name:   "stale ETag",
+			exists: true,
+			cond:   entity.Preconditions{IfMatch: []string{"stale"}},
+			data:   _object,
+			err:    errs.ErrPreconditionFailed,
+		},
+		{
+			name:   "create over an existing one",
+			exists: true,
+			cond:   entity.Preconditions{IfNoneMatch: []string{"*"}},
+			data:   _object,
+			err:    errs.ErrPreconditionFailed,
+		},
+		{
+			name: "replace of a missing one",
+			cond: entity.Preconditions{IfMatch: []string{"*"}},
+			data: _object,
+			err:  errs.ErrPreconditionFailed,
+		},
+		{
+			name: "several events",
+			data: strings.Replace(_object, "RECURRENCE-ID;TZID=Europe/Berlin:20260309T100000\r\n", "", 1),
+			err:  errs.ErrInvalidCalendarData,
+		},
+		{
+			name: "no event",
+			data: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:1\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			err:  errs.ErrInvalidCalendarData,
+		},
+		{
+			name: "malformed",
+			data: "BEGIN:VCALENDAR\r\n",
+			err:  ical.ErrInvalidData,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			useCase, _, events, ctrl := calendarUseCase(t)
+			defer ctrl.Finish()
+
+			if tc.exists {
+				events.EXPECT().GetByUID(ctx, userID, uid).Return(current, nil).AnyTimes()
+			} else {
+				events.EXPECT().GetByUID(ctx, userID, uid).Return(entity.Event{}, errs.ErrEventNotFound).AnyTimes()
+			}
+
+			var stored entity.Event
+
+			store := func(_ context.Context, _ int, _ uuid.UUID, event entity.Event) (entity.Event, error) {
+				stored = event
+
+				return event, nil
+			}
+
+			switch tc.write {
+			case "Create":
+				events.EXPECT().Create(ctx, userID, uid, gomock.Any()).DoAndReturn(store)
+			case "Replace":
+				events.EXPECT().Replace(ctx, userID, uid, gomock.Any()).DoAndReturn(store)
+			}
+
+			created, err := useCase.PutObject(ctx, userID, uid, strings.NewReader(tc.data), time.UTC, tc.cond)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected %v, got %v", tc.err, err)
+			}
+
+			if created != tc.created {
+				t.Fatalf("expected created %v, got %v", tc.created, created)
+			}
+
+			if tc.write == "" {
+				return
+			}
+
+			if stored.Text != "sync" || stored.RRule != "FREQ=WEEKLY;COUNT=5" || len(stored.Overrides) != 1 ||
+				stored.Overrides[0].Text != "sync (moved)" ||
+				!stored.Overrides[0].RecurrenceID.Equal(time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)) {
+				t.Fatalf("unexpected event %+v", stored)
+			}
+
+			// the write fails if current changed after the ETag was checked.
+			if tc.write == "Replace" && stored.Version != current.Version {
+				t.Fatalf("expected the write to expect version %d, got %d", current.Version, stored.Version)
+			}
+		})
+	}
+}
+
+func TestDeleteObject(t *testing.T) {
+	t.Parallel()
+
+	useCase, _, events, ctrl := calendarUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+	uid := uuid.MustParse("5f0f7d3c-3a52-4b43-9d0e-2f1c0cbe1a47")
+	missing := uuid.MustParse("0c8a4b4e-7f1d-4b5e-9a43-6c1f3e2a9b10")
+
+	events.EXPECT().GetByUID(ctx, userID, missing).Return(entity.Event{}, errs.ErrEventNotFound)
+	events.EXPECT().GetByUID(ctx, userID, uid).Return(entity.Event{Text: "stand-up"}, nil).Times(2)
+	events.EXPECT().Delete(ctx, userID, uid, int64(0)).Return(nil)
+
+	err := useCase.DeleteObject(ctx, userID, missing, entity.Preconditions{})
+	if !errors.Is(err, errs.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+
+	err = useCase.DeleteObject(ctx, userID, uid, entity.Preconditions{IfMatch: []string{"stale"}})
+	if !errors.Is(err, errs.ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+
+	if err = useCase.DeleteObject(ctx, userID, uid, entity.Preconditions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/andreyxaxa/calendar/internal/entity"
@@ -9,13 +10,72 @@ import (
 )
 
 type (
-	// Events - interface of usecase
+	// Events - interface of usecase.
+	// WithinTx runs fn against Events on a transaction of the repo, see repo.UnitOfWork.
 	Events interface {
-		Create(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) error
-		Update(ctx context.Context, userID int, eventUID uuid.UUID, text string, date time.Time) error
-		Delete(ctx context.Context, userID int, eventUID uuid.UUID) error
-		GetEventsForDay(ctx context.Context, userID int, date time.Time) (map[uuid.UUID]entity.Event, error)
-		GetEventsForWeek(ctx context.Context, userID int, date time.Time) (map[uuid.UUID]entity.Event, error)
-		GetEventsForMonth(ctx context.Context, userID int, date time.Time) (map[uuid.UUID]entity.Event, error)
+		WithinTx(ctx context.Context, fn func(tx Events) error) error
+		Create(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) (entity.Event, error)
+		Update(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) (entity.Event, error)
+		Replace(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) (entity.Event, error)
+		UpdateFields(ctx context.Context, userID int, eventUID uuid.UUID, patch entity.Event,
+			mask entity.FieldMask) (entity.Event, error)
+		UpdateOccurrence(ctx context.Context, userID int, eventUID uuid.UUID, recurrenceID time.Time,
+			scope entity.Scope, event entity.Event) (entity.Occurrence, error)
+		Delete(ctx context.Context, userID int, eventUID uuid.UUID, version int64) error
+		DeleteOccurrence(ctx context.Context, userID int, eventUID uuid.UUID, recurrenceID time.Time,
+			scope entity.Scope, version int64) error
+		Restore(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error)
+		GetTrash(ctx context.Context, userID int) ([]entity.TrashedEvent, error)
+		PurgeTrash(ctx context.Context, before time.Time) (int64, error)
+		GetHistory(ctx context.Context, userID int, eventUID uuid.UUID) ([]entity.Revision, error)
+		Revert(ctx context.Context, userID int, eventUID uuid.UUID, revision int64, version int64) (entity.Event, error)
+		Sync(ctx context.Context, userID int, after int64, limit int) (entity.ChangesPage, error)
+		Subscribe(ctx context.Context, userID int, after uint64) <-chan entity.Notification
+		Batch(ctx context.Context, userID int, ops []entity.BatchOperation, atomic bool) ([]entity.BatchResult, error)
+		GetByUID(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error)
+		GetEventsForDay(ctx context.Context, userID int, date time.Time) ([]entity.Occurrence, error)
+		GetEventsForWeek(ctx context.Context, userID int, date time.Time) ([]entity.Occurrence, error)
+		GetEventsForMonth(ctx context.Context, userID int, date time.Time) ([]entity.Occurrence, error)
+		GetEventsForRange(ctx context.Context, userID int, from, to time.Time,
+			page entity.PageRequest) (entity.OccurrencesPage, error)
+	}
+
+	// Calendar - interface of usecase
+	Calendar interface {
+		Export(ctx context.Context, userID int) ([]byte, error)
+		Import(ctx context.Context, userID int, r io.Reader, loc *time.Location) (entity.ImportReport, error)
+		ObjectTags(ctx context.Context, userID int, from, to time.Time) ([]entity.CalendarObject, error)
+		Objects(ctx context.Context, userID int, eventUIDs []uuid.UUID) ([]entity.CalendarObject, error)
+		PutObject(ctx context.Context, userID int, eventUID uuid.UUID, r io.Reader, loc *time.Location,
+			cond entity.Preconditions) (created bool, err error)
+		DeleteObject(ctx context.Context, userID int, eventUID uuid.UUID, cond entity.Preconditions) error
+	}
+
+	// Users - interface of usecase
+	Users interface {
+		Get(ctx context.Context, userID int) (entity.User, error)
+		Update(ctx context.Context, user entity.User) error
+	}
+
+	// Webhooks - interface of usecase.
+	// Notify queues deliveries of a notification to webhooks of its user, Dispatch stores them and
+	// attempts the due ones, Queued signals that Notify was called since the last Dispatch.
+	Webhooks interface {
+		Create(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error)
+		GetWebhooks(ctx context.Context, userID int) ([]entity.Webhook, error)
+		Delete(ctx context.Context, userID int, id uuid.UUID) error
+		GetDeliveries(ctx context.Context, userID int, webhookID uuid.UUID, status entity.DeliveryStatus,
+			limit int) ([]entity.Delivery, error)
+		Notify(n entity.Notification)
+		Queued() <-chan struct{}
+		Dropped() int
+		Dispatch(ctx context.Context) (int, error)
+	}
+
+	// Idempotency - interface of usecase
+	Idempotency interface {
+		Begin(ctx context.Context, key, fingerprint string) (*entity.StoredResponse, error)
+		Complete(ctx context.Context, key string, response entity.StoredResponse) error
+		Release(ctx context.Context, key string) error
 	}
 )
@@ -3,78 +3,607 @@ package events
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/andreyxaxa/calendar/internal/entity"
 	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/usecase"
+	"github.com/andreyxaxa/calendar/pkg/pubsub"
+	"github.com/andreyxaxa/calendar/pkg/types/date"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
 	"github.com/google/uuid"
 )
 
-// UseCase -.
+const (
+	// _expandWindow - series are expanded for a page of a range in a window of at least a week.
+	_expandWindow = 7 * 24 * time.Hour
+	// _expandSlack - instances of all-day series start up to a day off their time in a zone.
+	_expandSlack = 24 * time.Hour
+)
+
+// UseCase - writes of events are published to notifications, by user, once they are committed,
+// and passed to listeners with ID of the published notification. Listeners must not block.
 type UseCase struct {
-	repo repo.EventsRepo
+	repo          repo.EventsRepo
+	notifications *pubsub.Broker[int, entity.Notification]
+	listeners     []func(entity.Notification)
+
+	// pending is set in a transaction: notifications of its writes wait for the commit.
+	pending *[]entity.Notification
 }
 
 // New returns new UseCase(struct)
-func New(r repo.EventsRepo) *UseCase {
+func New(r repo.EventsRepo, notifications *pubsub.Broker[int, entity.Notification],
+	listeners ...func(entity.Notification),
+) *UseCase {
 	return &UseCase{
-		repo: r,
+		repo:          r,
+		notifications: notifications,
+		listeners:     listeners,
+	}
+}
+
+// WithinTx -.
+func (uc *UseCase) WithinTx(ctx context.Context, fn func(tx usecase.Events) error) error {
+	return uc.withinTx(ctx, func(tx *UseCase) error {
+		return fn(tx)
+	})
+}
+
+// withinTx runs fn against UseCase on a transaction of the repo. Notifications of its writes
+// are published if it is committed, in a nested one - with those of the outer one.
+func (uc *UseCase) withinTx(ctx context.Context, fn func(tx *UseCase) error) error {
+	var pending []entity.Notification
+
+	err := uc.repo.WithinTx(ctx, func(tx repo.EventsRepo) error {
+		return fn(&UseCase{repo: tx, notifications: uc.notifications, listeners: uc.listeners, pending: &pending})
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, n := range pending {
+		uc.publish(n)
+	}
+
+	return nil
+}
+
+// notify publishes the write of the event.
+func (uc *UseCase) notify(kind entity.NotificationKind, userID int, eventUID uuid.UUID, event entity.Event) {
+	uc.publish(entity.Notification{Kind: kind, UserID: userID, UID: eventUID, Event: event.Clone()})
+}
+
+func (uc *UseCase) publish(n entity.Notification) {
+	if uc.pending != nil {
+		*uc.pending = append(*uc.pending, n)
+
+		return
+	}
+
+	n.ID = uc.notifications.Publish(n.UserID, n)
+
+	for _, listener := range uc.listeners {
+		listener(n)
 	}
 }
 
-// Create -.
-func (uc *UseCase) Create(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) error {
+// Create returns the stored event, of version 1.
+func (uc *UseCase) Create(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) (entity.Event, error) {
 	if err := uc.repo.Create(ctx, userID, eventUID, event); err != nil {
-		return fmt.Errorf("EventsUseCase - Create - uc.repo.Create: %w", err)
+		return entity.Event{}, fmt.Errorf("EventsUseCase - Create - uc.repo.Create: %w", err)
+	}
+
+	event.Version = 1
+	uc.notify(entity.NotificationCreated, userID, eventUID, event)
+
+	return event, nil
+}
+
+// Update replaces the event. Overrides of a series are kept as long as its instances stay where they were.
+// Version of event is the expected one, see repo.EventsRepo. It returns the stored event.
+func (uc *UseCase) Update(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) (entity.Event, error) {
+	if event.Recurring() {
+		current, err := uc.repo.GetByUID(ctx, userID, eventUID)
+		if err != nil {
+			return entity.Event{}, fmt.Errorf("EventsUseCase - Update - uc.repo.GetByUID: %w", err)
+		}
+
+		if err = checkVersion(current, event.Version); err != nil {
+			return entity.Event{}, fmt.Errorf("EventsUseCase - Update - checkVersion: %w", err)
+		}
+
+		// written over what was read: an override added meanwhile fails the write instead of being lost.
+		event.Version = current.Version
+
+		if current.SameSeries(event) {
+			event.Overrides = current.Overrides
+		}
+	}
+
+	version, err := uc.repo.Update(ctx, userID, eventUID, event)
+	if err != nil {
+		return entity.Event{}, fmt.Errorf("EventsUseCase - Update - uc.repo.Update: %w", err)
+	}
+
+	event.Version = version
+	uc.notify(entity.NotificationUpdated, userID, eventUID, event)
+
+	return event, nil
+}
+
+// Replace replaces the event as a whole: unlike Update, overrides are those of event.
+func (uc *UseCase) Replace(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) (entity.Event, error) {
+	version, err := uc.repo.Update(ctx, userID, eventUID, event)
+	if err != nil {
+		return entity.Event{}, fmt.Errorf("EventsUseCase - Replace - uc.repo.Update: %w", err)
+	}
+
+	event.Version = version
+	uc.notify(entity.NotificationUpdated, userID, eventUID, event)
+
+	return event, nil
+}
+
+// UpdateFields changes only the fields of mask, see entity.Event.Merge, and returns the stored event.
+func (uc *UseCase) UpdateFields(ctx context.Context, userID int, eventUID uuid.UUID, patch entity.Event,
+	mask entity.FieldMask,
+) (entity.Event, error) {
+	event, err := uc.repo.UpdateFields(ctx, userID, eventUID, patch, mask)
+	if err != nil {
+		return entity.Event{}, fmt.Errorf("EventsUseCase - UpdateFields - uc.repo.UpdateFields: %w", err)
+	}
+
+	uc.notify(entity.NotificationUpdated, userID, eventUID, event)
+
+	return event, nil
+}
+
+// UpdateOccurrence applies event to the instance of series eventUID starting at recurrenceID:
+// ScopeThis stores it as an override, ScopeFollowing ends the series before the instance and
+// starts a new one (with the rest of the rule unless event has its own), ScopeAll is Update.
+// It returns what was stored: the instance, the new series or the updated event.
+// Version of event is the expected one of series eventUID. The series is written at the version
+// it was read, so a concurrent write fails it with errs.ErrPreconditionFailed rather than being lost.
+func (uc *UseCase) UpdateOccurrence(ctx context.Context, userID int, eventUID uuid.UUID, recurrenceID time.Time,
+	scope entity.Scope, event entity.Event,
+) (entity.Occurrence, error) {
+	if scope == entity.ScopeAll {
+		updated, err := uc.Update(ctx, userID, eventUID, event)
+		if err != nil {
+			return entity.Occurrence{}, err
+		}
+
+		return entity.Occurrence{UID: eventUID, Event: updated}, nil
+	}
+
+	series, err := uc.series(ctx, userID, eventUID, recurrenceID)
+	if err != nil {
+		return entity.Occurrence{}, fmt.Errorf("EventsUseCase - UpdateOccurrence - uc.series: %w", err)
+	}
+
+	if err = checkVersion(series, event.Version); err != nil {
+		return entity.Occurrence{}, fmt.Errorf("EventsUseCase - UpdateOccurrence - checkVersion: %w", err)
+	}
+
+	if scope == entity.ScopeThis {
+		series.Override(entity.Override{
+			RecurrenceID: recurrenceID,
+			Start:        event.Start,
+			End:          event.End,
+			AllDay:       event.AllDay,
+			Text:         event.Text,
+		})
+
+		if series.Version, err = uc.repo.Update(ctx, userID, eventUID, series); err != nil {
+			return entity.Occurrence{}, fmt.Errorf("EventsUseCase - UpdateOccurrence - uc.repo.Update: %w", err)
+		}
+
+		uc.notify(entity.NotificationUpdated, userID, eventUID, series)
+
+		instance := series
+		instance.Start, instance.End, instance.AllDay, instance.Text = event.Start, event.End, event.AllDay, event.Text
+
+		return entity.Occurrence{UID: eventUID, RecurrenceID: recurrenceID, Event: instance}, nil
+	}
+
+	head, tail, err := series.Split(recurrenceID)
+	if err != nil {
+		return entity.Occurrence{}, fmt.Errorf("EventsUseCase - UpdateOccurrence - series.Split: %w", err)
+	}
+
+	if !event.Recurring() {
+		event.RRule = tail.String()
+	}
+
+	// the first instance starts the whole series.
+	if recurrenceID.Equal(series.Start) {
+		updated, err := uc.Update(ctx, userID, eventUID, event)
+		if err != nil {
+			return entity.Occurrence{}, err
+		}
+
+		return entity.Occurrence{UID: eventUID, Event: updated}, nil
+	}
+
+	// the new series starts and the old one ends before it together, no instance is lost or doubled.
+	newUID := uuid.New()
+	event.Version = 0
+
+	err = uc.withinTx(ctx, func(tx *UseCase) error {
+		if err := tx.repo.Create(ctx, userID, newUID, event); err != nil {
+			return fmt.Errorf("tx.repo.Create: %w", err)
+		}
+
+		version, err := tx.repo.Update(ctx, userID, eventUID, head)
+		if err != nil {
+			return fmt.Errorf("tx.repo.Update: %w", err)
+		}
+
+		head.Version = version
+		tx.notify(entity.NotificationUpdated, userID, eventUID, head)
+
+		return nil
+	})
+	if err != nil {
+		return entity.Occurrence{}, fmt.Errorf("EventsUseCase - UpdateOccurrence - uc.withinTx: %w", err)
+	}
+
+	event.Version = 1
+	uc.notify(entity.NotificationCreated, userID, newUID, event)
+
+	return entity.Occurrence{UID: newUID, Event: event}, nil
+}
+
+// Delete moves the event to the trash, see Restore. It takes the expected version, see repo.EventsRepo.
+func (uc *UseCase) Delete(ctx context.Context, userID int, eventUID uuid.UUID, version int64) error {
+	if err := uc.repo.Delete(ctx, userID, eventUID, version, time.Now()); err != nil {
+		return fmt.Errorf("EventsUseCase - Delete - uc.repo.Delete: %w", err)
 	}
 
+	uc.notify(entity.NotificationDeleted, userID, eventUID, entity.Event{})
+
 	return nil
 }
 
-// Update -.
-func (uc *UseCase) Update(ctx context.Context, userID int, eventUID uuid.UUID, text string, date time.Time) error {
-	if err := uc.repo.Update(ctx, userID, eventUID, text, date); err != nil {
-		return fmt.Errorf("EventsUseCase - Update - uc.repo.Update: %w", err)
+// DeleteOccurrence cancels the instance of series eventUID starting at recurrenceID (ScopeThis),
+// ends the series before it (ScopeFollowing) or deletes the whole series (ScopeAll).
+// version is the expected one of the series, it is written at the version it was read as UpdateOccurrence does.
+func (uc *UseCase) DeleteOccurrence(ctx context.Context, userID int, eventUID uuid.UUID, recurrenceID time.Time,
+	scope entity.Scope, version int64,
+) error {
+	if scope == entity.ScopeAll {
+		return uc.Delete(ctx, userID, eventUID, version)
+	}
+
+	series, err := uc.series(ctx, userID, eventUID, recurrenceID)
+	if err != nil {
+		return fmt.Errorf("EventsUseCase - DeleteOccurrence - uc.series: %w", err)
+	}
+
+	if err = checkVersion(series, version); err != nil {
+		return fmt.Errorf("EventsUseCase - DeleteOccurrence - checkVersion: %w", err)
+	}
+
+	if scope == entity.ScopeFollowing && recurrenceID.Equal(series.Start) {
+		return uc.Delete(ctx, userID, eventUID, series.Version)
+	}
+
+	if scope == entity.ScopeThis {
+		series.Cancel(recurrenceID)
+	} else {
+		if series, _, err = series.Split(recurrenceID); err != nil {
+			return fmt.Errorf("EventsUseCase - DeleteOccurrence - series.Split: %w", err)
+		}
+	}
+
+	if series.Version, err = uc.repo.Update(ctx, userID, eventUID, series); err != nil {
+		return fmt.Errorf("EventsUseCase - DeleteOccurrence - uc.repo.Update: %w", err)
 	}
 
+	uc.notify(entity.NotificationUpdated, userID, eventUID, series)
+
 	return nil
 }
 
-// Delete -.
-func (uc *UseCase) Delete(ctx context.Context, userID int, eventUID uuid.UUID) error {
-	if err := uc.repo.Delete(ctx, userID, eventUID); err != nil {
-		return fmt.Errorf("EventsUseCase - Delete - uc.repo.Delete: %w", err)
+// Batch runs ops in order. Unless atomic, each operation is tried and its result tells if it failed.
+// An atomic batch stops at the first failure, returned as *entity.BatchError, and applies none.
+func (uc *UseCase) Batch(ctx context.Context, userID int, ops []entity.BatchOperation,
+	atomic bool,
+) ([]entity.BatchResult, error) {
+	results := make([]entity.BatchResult, len(ops))
+
+	if !atomic {
+		for i, op := range ops {
+			results[i] = uc.apply(ctx, userID, op)
+		}
+
+		return results, nil
+	}
+
+	err := uc.withinTx(ctx, func(tx *UseCase) error {
+		for i, op := range ops {
+			if results[i] = tx.apply(ctx, userID, op); results[i].Err != nil {
+				return &entity.BatchError{Index: i, Err: results[i].Err}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("EventsUseCase - Batch - uc.withinTx: %w", err)
+	}
+
+	return results, nil
+}
+
+// apply runs an operation of a batch.
+func (uc *UseCase) apply(ctx context.Context, userID int, op entity.BatchOperation) entity.BatchResult {
+	result := entity.BatchResult{UID: op.UID}
+
+	switch op.Op {
+	case entity.BatchCreate:
+		result.Event, result.Err = uc.Create(ctx, userID, op.UID, op.Event)
+	case entity.BatchUpdate:
+		result.Event, result.Err = uc.Update(ctx, userID, op.UID, op.Event)
+	case entity.BatchDelete:
+		result.Err = uc.Delete(ctx, userID, op.UID, op.Event.Version)
+	default:
+		result.Err = fmt.Errorf("unknown batch operation %d", op.Op)
+	}
+
+	return result
+}
+
+// series returns the recurring event eventUID, checking it has an instance starting at recurrenceID.
+func (uc *UseCase) series(ctx context.Context, userID int, eventUID uuid.UUID, recurrenceID time.Time) (entity.Event, error) {
+	series, err := uc.repo.GetByUID(ctx, userID, eventUID)
+	if err != nil {
+		return entity.Event{}, fmt.Errorf("uc.repo.GetByUID: %w", err)
+	}
+
+	if !series.Recurring() {
+		return entity.Event{}, errs.ErrNotRecurring
+	}
+
+	ok, err := series.HasInstance(recurrenceID)
+	if err != nil {
+		return entity.Event{}, fmt.Errorf("series.HasInstance: %w", err)
+	}
+
+	if !ok {
+		return entity.Event{}, errs.ErrOccurrenceNotFound
+	}
+
+	return series, nil
+}
+
+// checkVersion fails with ErrPreconditionFailed unless event has the expected version or it is 0.
+// The repo checks it too, it is for writes depending on what was read.
+func checkVersion(event entity.Event, version int64) error {
+	if version != 0 && version != event.Version {
+		return errs.ErrPreconditionFailed
 	}
 
 	return nil
 }
 
+// GetByUID -.
+func (uc *UseCase) GetByUID(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error) {
+	event, err := uc.repo.GetByUID(ctx, userID, eventUID)
+	if err != nil {
+		return entity.Event{}, fmt.Errorf("EventsUseCase - GetByUID - uc.repo.GetByUID: %w", err)
+	}
+
+	return event, nil
+}
+
 // GetEventsForDay -.
-func (uc *UseCase) GetEventsForDay(ctx context.Context, userID int, date time.Time) (map[uuid.UUID]entity.Event, error) {
-	events, err := uc.repo.GetEventsForDay(ctx, userID, date)
+func (uc *UseCase) GetEventsForDay(ctx context.Context, userID int, d time.Time) ([]entity.Occurrence, error) {
+	events, err := uc.repo.GetEventsForDay(ctx, userID, d)
 	if err != nil {
 		return nil, fmt.Errorf("EventsUseCase - GetEventsForDay - uc.repo.GetEventsForDay: %w", err)
 	}
 
-	return events, nil
+	from, to := date.DayRange(d)
+
+	occurrences, err := expand(events, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("EventsUseCase - GetEventsForDay - expand: %w", err)
+	}
+
+	return occurrences, nil
 }
 
 // GetEventsForWeek -.
-func (uc *UseCase) GetEventsForWeek(ctx context.Context, userID int, date time.Time) (map[uuid.UUID]entity.Event, error) {
-	events, err := uc.repo.GetEventsForWeek(ctx, userID, date)
+func (uc *UseCase) GetEventsForWeek(ctx context.Context, userID int, d time.Time) ([]entity.Occurrence, error) {
+	events, err := uc.repo.GetEventsForWeek(ctx, userID, d)
 	if err != nil {
 		return nil, fmt.Errorf("EventsUseCase - GetEventsForWeek - uc.repo.GetEventsForWeek: %w", err)
 	}
 
-	return events, nil
+	from, to := date.WeekRange(d)
+
+	occurrences, err := expand(events, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("EventsUseCase - GetEventsForWeek - expand: %w", err)
+	}
+
+	return occurrences, nil
 }
 
 // GetEventsForMonth -.
-func (uc *UseCase) GetEventsForMonth(ctx context.Context, userID int, date time.Time) (map[uuid.UUID]entity.Event, error) {
-	events, err := uc.repo.GetEventsForMonth(ctx, userID, date)
+func (uc *UseCase) GetEventsForMonth(ctx context.Context, userID int, d time.Time) ([]entity.Occurrence, error) {
+	events, err := uc.repo.GetEventsForMonth(ctx, userID, d)
 	if err != nil {
 		return nil, fmt.Errorf("EventsUseCase - GetEventsForMonth - uc.repo.GetEventsForMonth: %w", err)
 	}
 
-	return events, nil
+	from, to := date.MonthRange(d)
+
+	occurrences, err := expand(events, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("EventsUseCase - GetEventsForMonth - expand: %w", err)
+	}
+
+	return occurrences, nil
+}
+
+// GetEventsForRange returns a page of the occurrences within [from, to), ordered by entity.Cursor.
+// The repo reads the page of events, series are expanded only around it, see pageOf.
+func (uc *UseCase) GetEventsForRange(ctx context.Context, userID int, from, to time.Time,
+	page entity.PageRequest,
+) (entity.OccurrencesPage, error) {
+	// one more event than fits tells where the page surely ends.
+	events, err := uc.repo.GetEventsForRange(ctx, userID, from, to,
+		entity.PageRequest{Limit: page.Limit + 1, After: page.After, Desc: page.Desc})
+	if err != nil {
+		return entity.OccurrencesPage{}, fmt.Errorf("EventsUseCase - GetEventsForRange - uc.repo.GetEventsForRange: %w", err)
+	}
+
+	res, err := pageOf(events, from, to, page)
+	if err != nil {
+		return entity.OccurrencesPage{}, fmt.Errorf("EventsUseCase - GetEventsForRange - pageOf: %w", err)
+	}
+
+	return res, nil
+}
+
+// pageOf merges events, read for page with one more event than fits, with instances of their series.
+// Series are expanded in a window from the cursor: up to the event that does not fit, if there is one,
+// otherwise it doubles until the page is full or the window covers the rest of [from, to).
+func pageOf(events entity.EventsPage, from, to time.Time, page entity.PageRequest) (entity.OccurrencesPage, error) {
+	if page.Limit <= 0 {
+		return entity.OccurrencesPage{Occurrences: []entity.Occurrence{}}, nil
+	}
+
+	// follows reports whether a comes after b in the order of the page.
+	follows := func(a, b entity.Cursor) bool {
+		if page.Desc {
+			return a.Compare(b) < 0
+		}
+
+		return a.Compare(b) > 0
+	}
+
+	// the window grows from the cursor: forward from lower or, for Desc, back from upper.
+	lower, upper := from, to
+
+	if page.After != nil {
+		if page.Desc {
+			upper = earlier(to, page.After.Start.Add(_expandSlack+time.Nanosecond))
+		} else {
+			lower = later(from, page.After.Start.Add(-_expandSlack))
+		}
+	}
+
+	// last is the event that does not fit: nothing after it is in the page, the window ends past it.
+	var last *entity.Cursor
+
+	window := _expandWindow
+
+	if len(events.Events) > page.Limit {
+		c := events.Events[page.Limit].Cursor()
+		last = &c
+
+		if page.Desc {
+			window = upper.Sub(c.Start) + _expandSlack + time.Nanosecond
+		} else {
+			window = c.Start.Sub(lower) + _expandSlack + time.Nanosecond
+		}
+	}
+
+	for {
+		lo, hi := lower, earlier(to, lower.Add(window))
+		if page.Desc {
+			lo, hi = later(from, upper.Add(-window)), upper
+		}
+
+		instances, err := expand(events.Series, lo, hi)
+		if err != nil {
+			return entity.OccurrencesPage{}, err
+		}
+
+		occurrences := slices.Concat(events.Events, instances)
+		slices.SortFunc(occurrences, func(a, b entity.Occurrence) int {
+			if page.Desc {
+				return b.Cursor().Compare(a.Cursor())
+			}
+
+			return a.Cursor().Compare(b.Cursor())
+		})
+
+		// complete tells whether the window reached the end of the range. Otherwise instances starting
+		// near its far end may be missing, occurrences from there on wait for a wider one.
+		complete := !page.Desc && hi.Equal(to) || page.Desc && lo.Equal(from)
+
+		occurrences = slices.DeleteFunc(occurrences, func(o entity.Occurrence) bool {
+			c := o.Cursor()
+
+			switch {
+			case page.After != nil && !follows(c, *page.After):
+				return true
+			case last != nil && follows(c, *last):
+				return true
+			case complete:
+				return false
+			case page.Desc:
+				return !o.Start.After(lo.Add(_expandSlack))
+			default:
+				return !o.Start.Before(hi.Add(-_expandSlack))
+			}
+		})
+
+		if len(occurrences) > page.Limit || complete {
+			res := entity.OccurrencesPage{Occurrences: occurrences[:min(page.Limit, len(occurrences))]}
+
+			if len(occurrences) > page.Limit {
+				next := occurrences[page.Limit-1].Cursor()
+				res.Next = &next
+			}
+
+			return res, nil
+		}
+
+		window *= 2
+	}
+}
+
+// earlier returns the earlier of the times.
+func earlier(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+
+	return a
+}
+
+// later returns the later of the times.
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+
+	return a
+}
+
+// expand turns events into their occurrences within [from, to), ordered by start.
+func expand(events map[uuid.UUID]entity.Event, from, to time.Time) ([]entity.Occurrence, error) {
+	occurrences := make([]entity.Occurrence, 0, len(events))
+
+	for uid, event := range events {
+		instances, err := event.Occurrences(uid, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", uid, err)
+		}
+
+		occurrences = append(occurrences, instances...)
+	}
+
+	slices.SortFunc(occurrences, func(a, b entity.Occurrence) int {
+		return a.Cursor().Compare(b.Cursor())
+	})
+
+	return occurrences, nil
 }
@@ -0,0 +1,69 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+// GetHistory returns revisions of the event, the first one first.
+// It fails with errs.ErrEventNotFound if the event has none.
+func (uc *UseCase) GetHistory(ctx context.Context, userID int, eventUID uuid.UUID) ([]entity.Revision, error) {
+	revisions, err := uc.repo.GetRevisions(ctx, userID, eventUID)
+	if err != nil {
+		return nil, fmt.Errorf("EventsUseCase - GetHistory - uc.repo.GetRevisions: %w", err)
+	}
+
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("EventsUseCase - GetHistory: %w", errs.ErrEventNotFound)
+	}
+
+	return revisions, nil
+}
+
+// Revert brings the event back to what it was after the revision: it replaces the event as a whole
+// or deletes it if the revision did. The revert is a write of its own and takes the expected version,
+// see repo.EventsRepo. An event in the trash is to be restored first. It returns the stored event,
+// the zero one if it was deleted.
+func (uc *UseCase) Revert(ctx context.Context, userID int, eventUID uuid.UUID, revision int64,
+	version int64,
+) (entity.Event, error) {
+	revisions, err := uc.repo.GetRevisions(ctx, userID, eventUID)
+	if err != nil {
+		return entity.Event{}, fmt.Errorf("EventsUseCase - Revert - uc.repo.GetRevisions: %w", err)
+	}
+
+	i := slices.IndexFunc(revisions, func(r entity.Revision) bool {
+		return r.Number == revision
+	})
+	if i < 0 {
+		return entity.Event{}, fmt.Errorf("EventsUseCase - Revert: %w", errs.ErrRevisionNotFound)
+	}
+
+	target := revisions[i].After
+	if target == nil {
+		if err = uc.repo.Delete(ctx, userID, eventUID, version, time.Now()); err != nil {
+			return entity.Event{}, fmt.Errorf("EventsUseCase - Revert - uc.repo.Delete: %w", err)
+		}
+
+		uc.notify(entity.NotificationDeleted, userID, eventUID, entity.Event{})
+
+		return entity.Event{}, nil
+	}
+
+	event := *target
+	event.Version = version
+
+	if event.Version, err = uc.repo.Update(ctx, userID, eventUID, event); err != nil {
+		return entity.Event{}, fmt.Errorf("EventsUseCase - Revert - uc.repo.Update: %w", err)
+	}
+
+	uc.notify(entity.NotificationUpdated, userID, eventUID, event)
+
+	return event, nil
+}
@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+)
+
+// Subscribe returns notifications of writes of events of the user, following the one with ID after
+// unless it is 0, then those published from now on. If some of the following ones are lost,
+// a NotificationReset comes first instead. The channel is closed once ctx is done or the subscriber
+// falls too far behind, it is to resume after the last notification it got.
+func (uc *UseCase) Subscribe(ctx context.Context, userID int, after uint64) <-chan entity.Notification {
+	sub, last, missed := uc.notifications.Subscribe(userID, after)
+	c := make(chan entity.Notification)
+
+	go func() {
+		defer close(c)
+		defer sub.Close()
+
+		if missed {
+			select {
+			case c <- entity.Notification{ID: last, Kind: entity.NotificationReset, UserID: userID}:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		for {
+			select {
+			case msg, ok := <-sub.C:
+				if !ok {
+					return
+				}
+
+				n := msg.Data
+				n.ID = msg.ID
+
+				select {
+				case c <- n:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return c
+}
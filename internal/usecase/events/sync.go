@@ -0,0 +1,33 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+)
+
+// Sync returns up to limit changes of events of the user following the position of the change
+// sequence, 0 for all of them. A position the sequence never reached, as after the storage was
+// replaced, fails with errs.ErrSyncTokenExpired: the client has to sync from 0.
+func (uc *UseCase) Sync(ctx context.Context, userID int, after int64, limit int) (entity.ChangesPage, error) {
+	// one more change tells if the page is the last one.
+	changes, seq, err := uc.repo.GetChanges(ctx, userID, after, limit+1)
+	if err != nil {
+		return entity.ChangesPage{}, fmt.Errorf("EventsUseCase - Sync - uc.repo.GetChanges: %w", err)
+	}
+
+	if after > seq {
+		return entity.ChangesPage{}, errs.ErrSyncTokenExpired
+	}
+
+	page := entity.ChangesPage{Changes: changes, Seq: seq}
+
+	if len(changes) > limit {
+		page.Changes, page.More = changes[:limit], true
+		page.Seq = page.Changes[limit-1].Seq
+	}
+
+	return page, nil
+}
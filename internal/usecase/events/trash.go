@@ -0,0 +1,54 @@
+package events
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/google/uuid"
+)
+
+// Restore moves the event back from the trash and returns it. It fails with errs.ErrAlreadyExists
+// if an event was created under its uid after the deletion.
+func (uc *UseCase) Restore(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error) {
+	event, err := uc.repo.Restore(ctx, userID, eventUID)
+	if err != nil {
+		return entity.Event{}, fmt.Errorf("EventsUseCase - Restore - uc.repo.Restore: %w", err)
+	}
+
+	uc.notify(entity.NotificationCreated, userID, eventUID, event)
+
+	return event, nil
+}
+
+// GetTrash returns trashed events of the user, the latest deleted first.
+func (uc *UseCase) GetTrash(ctx context.Context, userID int) ([]entity.TrashedEvent, error) {
+	events, err := uc.repo.GetTrash(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("EventsUseCase - GetTrash - uc.repo.GetTrash: %w", err)
+	}
+
+	slices.SortFunc(events, func(a, b entity.TrashedEvent) int {
+		if c := b.DeletedAt.Compare(a.DeletedAt); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.UID.String(), b.UID.String())
+	})
+
+	return events, nil
+}
+
+// PurgeTrash deletes events of all users trashed before the time for good
+// and returns their number.
+func (uc *UseCase) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	purged, err := uc.repo.PurgeTrash(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("EventsUseCase - PurgeTrash - uc.repo.PurgeTrash: %w", err)
+	}
+
+	return purged, nil
+}
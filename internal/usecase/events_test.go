@@ -3,11 +3,16 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"runtime"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/andreyxaxa/calendar/internal/entity"
 	"github.com/andreyxaxa/calendar/internal/usecase/events"
+	"github.com/andreyxaxa/calendar/pkg/pubsub"
+	"github.com/andreyxaxa/calendar/pkg/rrule"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
 	"github.com/google/uuid"
 	"go.uber.org/mock/gomock"
 )
@@ -21,7 +26,7 @@ func eventsUseCase(t *testing.T) (*events.UseCase, *MockEventsRepo, *gomock.Cont
 
 	repo := NewMockEventsRepo(mockCtl)
 
-	useCase := events.New(repo)
+	useCase := events.New(repo, pubsub.New[int, entity.Notification]())
 
 	return useCase, repo, mockCtl
 }
@@ -36,8 +41,9 @@ func TestCreateOK(t *testing.T) {
 	userID := 1
 	eventUID := uuid.New()
 	event := entity.Event{
-		Text: "meeting with friend",
-		Date: time.Now(),
+		Text:  "meeting with friend",
+		Start: time.Now(),
+		End:   time.Now().Add(time.Hour),
 	}
 
 	repo.
@@ -45,11 +51,15 @@ func TestCreateOK(t *testing.T) {
 		Create(ctx, userID, eventUID, event).
 		Return(nil)
 
-	err := useCase.Create(ctx, userID, eventUID, event)
+	result, err := useCase.Create(ctx, userID, eventUID, event)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+
+	if result.Version != 1 {
+		t.Fatalf("expected version 1, got %d", result.Version)
+	}
 }
 
 func TestCreateErr(t *testing.T) {
@@ -68,7 +78,7 @@ func TestCreateErr(t *testing.T) {
 		Create(ctx, userID, eventUID, event).
 		Return(errStorageProblem)
 
-	err := useCase.Create(ctx, userID, eventUID, event)
+	_, err := useCase.Create(ctx, userID, eventUID, event)
 
 	if err == nil {
 		t.Fatal("expected error")
@@ -88,19 +98,26 @@ func TestUpdateOK(t *testing.T) {
 	ctx := context.Background()
 	userID := 1
 	eventUID := uuid.New()
-	text := "updated text"
-	date := time.Now()
+	event := entity.Event{
+		Text:  "updated text",
+		Start: time.Now(),
+		End:   time.Now(),
+	}
 
 	repo.
 		EXPECT().
-		Update(ctx, userID, eventUID, text, date).
-		Return(nil)
+		Update(ctx, userID, eventUID, event).
+		Return(int64(2), nil)
 
-	err := useCase.Update(ctx, userID, eventUID, text, date)
+	result, err := useCase.Update(ctx, userID, eventUID, event)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+
+	if result.Version != 2 {
+		t.Fatalf("expected version 2, got %d", result.Version)
+	}
 }
 
 func TestUpdateErr(t *testing.T) {
@@ -111,10 +128,10 @@ func TestUpdateErr(t *testing.T) {
 
 	repo.
 		EXPECT().
-		Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-		Return(errStorageProblem)
+		Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(int64(0), errStorageProblem)
 
-	err := useCase.Update(context.Background(), 1, uuid.New(), "text", time.Now())
+	_, err := useCase.Update(context.Background(), 1, uuid.New(), entity.Event{Text: "text"})
 
 	if err == nil {
 		t.Fatal("expected error")
@@ -125,6 +142,52 @@ func TestUpdateErr(t *testing.T) {
 	}
 }
 
+func TestUpdateFields(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+	eventUID := uuid.New()
+	patch := entity.Event{Text: "renamed"}
+	stored := entity.Event{Text: "renamed", Start: time.Now(), End: time.Now().Add(time.Hour)}
+
+	repo.
+		EXPECT().
+		UpdateFields(ctx, userID, eventUID, patch, entity.FieldText).
+		Return(stored, nil)
+
+	result, err := useCase.UpdateFields(ctx, userID, eventUID, patch, entity.FieldText)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Start.Equal(stored.Start) {
+		t.Fatalf("expected the stored event, got %+v", result)
+	}
+}
+
+func TestUpdateFieldsErr(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	repo.
+		EXPECT().
+		UpdateFields(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(entity.Event{}, errs.ErrEventNotFound)
+
+	_, err := useCase.UpdateFields(context.Background(), 1, uuid.New(), entity.Event{}, entity.FieldText)
+
+	if !errors.Is(err, errs.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+}
+
 func TestDeleteOK(t *testing.T) {
 	t.Parallel()
 
@@ -137,10 +200,10 @@ func TestDeleteOK(t *testing.T) {
 
 	repo.
 		EXPECT().
-		Delete(ctx, userID, eventUID).
+		Delete(ctx, userID, eventUID, int64(3), gomock.Any()).
 		Return(nil)
 
-	err := useCase.Delete(ctx, userID, eventUID)
+	err := useCase.Delete(ctx, userID, eventUID, 3)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -155,10 +218,10 @@ func TestDeleteErr(t *testing.T) {
 
 	repo.
 		EXPECT().
-		Delete(gomock.Any(), gomock.Any(), gomock.Any()).
+		Delete(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(errStorageProblem)
 
-	err := useCase.Delete(context.Background(), 1, uuid.New())
+	err := useCase.Delete(context.Background(), 1, uuid.New(), 0)
 
 	if err == nil {
 		t.Fatal("expected error")
@@ -169,6 +232,51 @@ func TestDeleteErr(t *testing.T) {
 	}
 }
 
+func TestGetByUID(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+	eventUID := uuid.New()
+	expected := entity.Event{Text: "text", Start: time.Now(), End: time.Now().Add(time.Hour)}
+
+	repo.
+		EXPECT().
+		GetByUID(ctx, userID, eventUID).
+		Return(expected, nil)
+
+	result, err := useCase.GetByUID(ctx, userID, eventUID)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Text != expected.Text {
+		t.Fatalf("expected %q, got %q", expected.Text, result.Text)
+	}
+}
+
+func TestGetByUIDErr(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	repo.
+		EXPECT().
+		GetByUID(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(entity.Event{}, errs.ErrEventNotFound)
+
+	_, err := useCase.GetByUID(context.Background(), 1, uuid.New())
+
+	if !errors.Is(err, errs.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+}
+
 func TestGetEventsForDayOK(t *testing.T) {
 	t.Parallel()
 
@@ -179,7 +287,7 @@ func TestGetEventsForDayOK(t *testing.T) {
 	userID := 1
 	date := time.Now()
 
-	expected := map[uuid.UUID]entity.Event{uuid.New(): {Text: "text"}}
+	expected := map[uuid.UUID]entity.Event{uuid.New(): {Text: "text", Start: date, End: date.Add(time.Hour)}}
 
 	repo.
 		EXPECT().
@@ -233,7 +341,7 @@ func TestGetEventsForWeekOK(t *testing.T) {
 	userID := 1
 	date := time.Now()
 
-	expected := map[uuid.UUID]entity.Event{uuid.New(): {Text: "text"}}
+	expected := map[uuid.UUID]entity.Event{uuid.New(): {Text: "text", Start: date, End: date.Add(time.Hour)}}
 
 	repo.
 		EXPECT().
@@ -287,7 +395,7 @@ func TestGetEventsForMonthOK(t *testing.T) {
 	userID := 1
 	date := time.Now()
 
-	expected := map[uuid.UUID]entity.Event{uuid.New(): {Text: "text"}}
+	expected := map[uuid.UUID]entity.Event{uuid.New(): {Text: "text", Start: date, End: date.Add(time.Hour)}}
 
 	repo.
 		EXPECT().
@@ -330,3 +438,274 @@ func TestGetEventsForMonthErr(t *testing.T) {
 		t.Fatalf("expected wrapped error, got %v", err)
 	}
 }
+
+func TestGetEventsForWeekExpandsSeries(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+	// monday
+	date := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	seriesUID, singleUID := uuid.New(), uuid.New()
+	start := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	events := map[uuid.UUID]entity.Event{
+		seriesUID: {
+			Text:     "stand-up",
+			Start:    start,
+			End:      start.Add(15 * time.Minute),
+			TimeZone: "UTC",
+			RRule:    "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+			ExDates:  []time.Time{time.Date(2026, 1, 7, 9, 0, 0, 0, time.UTC)},
+		},
+		singleUID: {
+			Text:  "lunch",
+			Start: time.Date(2026, 1, 6, 12, 0, 0, 0, time.UTC),
+			End:   time.Date(2026, 1, 6, 13, 0, 0, 0, time.UTC),
+		},
+	}
+
+	repo.
+		EXPECT().
+		GetEventsForWeek(ctx, userID, date).
+		Return(events, nil)
+
+	result, err := useCase.GetEventsForWeek(ctx, userID, date)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		uid   uuid.UUID
+		start time.Time
+	}{
+		{seriesUID, time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)},
+		{singleUID, time.Date(2026, 1, 6, 12, 0, 0, 0, time.UTC)},
+		{seriesUID, time.Date(2026, 1, 9, 9, 0, 0, 0, time.UTC)},
+	}
+
+	if len(result) != len(expected) {
+		t.Fatalf("expected %d occurrences, got %d", len(expected), len(result))
+	}
+
+	for i, occurrence := range result {
+		if occurrence.UID != expected[i].uid || !occurrence.Start.Equal(expected[i].start) {
+			t.Fatalf("occurrence %d: expected %s at %s, got %s at %s",
+				i, expected[i].uid, expected[i].start, occurrence.UID, occurrence.Start)
+		}
+
+		recurring := occurrence.UID == seriesUID
+		if recurring != !occurrence.RecurrenceID.IsZero() {
+			t.Fatalf("occurrence %d: unexpected recurrence id %s", i, occurrence.RecurrenceID)
+		}
+
+		if occurrence.End.Sub(occurrence.Start) != events[occurrence.UID].End.Sub(events[occurrence.UID].Start) {
+			t.Fatalf("occurrence %d: duration changed", i)
+		}
+	}
+}
+
+func TestGetEventsForDayInvalidRule(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	date := time.Now()
+
+	repo.
+		EXPECT().
+		GetEventsForDay(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(map[uuid.UUID]entity.Event{uuid.New(): {Start: date, End: date, RRule: "FREQ=SOMETIMES"}}, nil)
+
+	_, err := useCase.GetEventsForDay(context.Background(), 1, date)
+
+	if !errors.Is(err, rrule.ErrInvalidRule) {
+		t.Fatalf("expected ErrInvalidRule, got %v", err)
+	}
+}
+
+func TestGetEventsForRangePages(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+	from := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+
+	seriesUID, singleUID := uuid.New(), uuid.New()
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	series := map[uuid.UUID]entity.Event{
+		seriesUID: {
+			Text:     "stand-up",
+			Start:    start,
+			End:      start.Add(15 * time.Minute),
+			TimeZone: "UTC",
+			RRule:    "FREQ=DAILY;COUNT=8",
+		},
+	}
+	singles := []entity.Occurrence{{
+		UID: singleUID,
+		Event: entity.Event{
+			Text:  "lunch",
+			Start: time.Date(2026, 1, 6, 12, 0, 0, 0, time.UTC),
+			End:   time.Date(2026, 1, 6, 13, 0, 0, 0, time.UTC),
+		},
+	}}
+
+	repo.
+		EXPECT().
+		GetEventsForRange(ctx, userID, from, to, gomock.Any()).
+		DoAndReturn(eventsPage(singles, series)).
+		AnyTimes()
+
+	for _, desc := range []bool{false, true} {
+		var (
+			starts []time.Time
+			after  *entity.Cursor
+		)
+
+		for pages := 0; ; pages++ {
+			if pages > 3 {
+				t.Fatalf("desc=%t: too many pages", desc)
+			}
+
+			page, err := useCase.GetEventsForRange(ctx, userID, from, to, entity.PageRequest{Limit: 2, After: after, Desc: desc})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, occurrence := range page.Occurrences {
+				starts = append(starts, occurrence.Start)
+			}
+
+			if page.Next == nil {
+				break
+			}
+
+			after = page.Next
+		}
+
+		// 9:00 of Jan 5-8 and the lunch.
+		if len(starts) != 5 {
+			t.Fatalf("desc=%t: expected 5 occurrences, got %d", desc, len(starts))
+		}
+
+		for i := 1; i < len(starts); i++ {
+			if starts[i].Before(starts[i-1]) != desc || starts[i].Equal(starts[i-1]) {
+				t.Fatalf("desc=%t: occurrences out of order: %v", desc, starts)
+			}
+		}
+	}
+}
+
+func TestGetEventsForRangeLongSeries(t *testing.T) {
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(9000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	uid := uuid.New()
+	series := map[uuid.UUID]entity.Event{
+		uid: {Text: "stand-up", Start: start, End: start.Add(15 * time.Minute), TimeZone: "UTC", RRule: "FREQ=DAILY"},
+	}
+
+	repo.
+		EXPECT().
+		GetEventsForRange(ctx, userID, from, to, gomock.Any()).
+		DoAndReturn(eventsPage(nil, series)).
+		AnyTimes()
+
+	instance := time.Date(2030, 6, 1, 9, 0, 0, 0, time.UTC)
+	after := &entity.Cursor{Start: instance, UID: uid, RecurrenceID: instance}
+
+	for _, desc := range []bool{false, true} {
+		var before, stats runtime.MemStats
+
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+
+		page, err := useCase.GetEventsForRange(ctx, userID, from, to, entity.PageRequest{Limit: 3, After: after, Desc: desc})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		runtime.ReadMemStats(&stats)
+
+		// the range holds millions of instances, a page must not expand them all.
+		if allocated := stats.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+			t.Fatalf("desc=%t: expected the series expanded around the page, allocated %d bytes", desc, allocated)
+		}
+
+		if len(page.Occurrences) != 3 || page.Next == nil {
+			t.Fatalf("desc=%t: expected a full page with next, got %d occurrences", desc, len(page.Occurrences))
+		}
+
+		expected := after.Start.AddDate(0, 0, 1)
+		if desc {
+			expected = after.Start.AddDate(0, 0, -1)
+		}
+
+		if got := page.Occurrences[0].Start; !got.Equal(expected) {
+			t.Fatalf("desc=%t: expected the page to start at %v, got %v", desc, expected, got)
+		}
+	}
+}
+
+// eventsPage returns a fake of repo.EventsRepo.GetEventsForRange: singles, sorted by their cursors,
+// are paged, series are returned whole.
+func eventsPage(singles []entity.Occurrence, series map[uuid.UUID]entity.Event,
+) func(context.Context, int, time.Time, time.Time, entity.PageRequest) (entity.EventsPage, error) {
+	return func(_ context.Context, _ int, _, _ time.Time, page entity.PageRequest) (entity.EventsPage, error) {
+		events := slices.Clone(singles)
+		if page.Desc {
+			slices.Reverse(events)
+		}
+
+		events = slices.DeleteFunc(events, func(o entity.Occurrence) bool {
+			if page.After == nil {
+				return false
+			}
+
+			if page.Desc {
+				return o.Cursor().Compare(*page.After) >= 0
+			}
+
+			return o.Cursor().Compare(*page.After) <= 0
+		})
+
+		return entity.EventsPage{Events: events[:min(page.Limit, len(events))], Series: series}, nil
+	}
+}
+
+func TestGetEventsForRangeErr(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	repo.
+		EXPECT().
+		GetEventsForRange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(entity.EventsPage{}, errStorageProblem)
+
+	_, err := useCase.GetEventsForRange(context.Background(), 1, time.Now(), time.Now().Add(time.Hour),
+		entity.PageRequest{Limit: 10})
+
+	if !errors.Is(err, errStorageProblem) {
+		t.Fatalf("expected wrapped error, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetHistory(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+	written, unknown := uuid.New(), uuid.New()
+
+	repo.EXPECT().GetRevisions(ctx, userID, written).Return([]entity.Revision{
+		{Number: 1, Op: entity.RevisionCreate, After: &entity.Event{Text: "stand-up", Version: 1}},
+	}, nil)
+	repo.EXPECT().GetRevisions(ctx, userID, unknown).Return(nil, nil)
+
+	revisions, err := useCase.GetHistory(ctx, userID, written)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(revisions) != 1 || revisions[0].Number != 1 {
+		t.Fatalf("unexpected revisions %+v", revisions)
+	}
+
+	if _, err = useCase.GetHistory(ctx, userID, unknown); !errors.Is(err, errs.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+}
+
+func TestRevert(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userID := 1
+	uid := uuid.New()
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	created := entity.Event{Start: start, End: start.Add(time.Hour), Text: "stand-up", Version: 1}
+	updated := entity.Event{Start: start, End: start.Add(time.Hour), Text: "retro", Version: 2}
+	revisions := []entity.Revision{
+		{Number: 1, Op: entity.RevisionCreate, After: &created},
+		{Number: 2, Op: entity.RevisionUpdate, Before: &created, After: &updated},
+		{Number: 3, Op: entity.RevisionDelete, Before: &updated},
+	}
+
+	tests := []struct {
+		name     string
+		revision int64
+		version  int64
+		mock     func(repo *MockEventsRepo)
+		want     entity.Event
+		err      error
+	}{
+		{
+			name:     "replaces the event",
+			revision: 1,
+			version:  4,
+			mock: func(repo *MockEventsRepo) {
+				event := created
+				event.Version = 4
+
+				repo.EXPECT().Update(ctx, userID, uid, event).Return(int64(5), nil)
+			},
+			want: entity.Event{Start: start, End: start.Add(time.Hour), Text: "stand-up", Version: 5},
+		},
+		{
+			name:     "deletes the event",
+			revision: 3,
+			mock: func(repo *MockEventsRepo) {
+				repo.EXPECT().Delete(ctx, userID, uid, int64(0), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:     "version mismatch",
+			revision: 2,
+			version:  1,
+			mock: func(repo *MockEventsRepo) {
+				repo.EXPECT().Update(ctx, userID, uid, gomock.Any()).Return(int64(0), errs.ErrPreconditionFailed)
+			},
+			err: errs.ErrPreconditionFailed,
+		},
+		{
+			name:     "unknown revision",
+			revision: 4,
+			mock:     func(*MockEventsRepo) {},
+			err:      errs.ErrRevisionNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			useCase, repo, ctrl := eventsUseCase(t)
+			defer ctrl.Finish()
+
+			repo.EXPECT().GetRevisions(ctx, userID, uid).Return(revisions, nil)
+			tc.mock(repo)
+
+			event, err := useCase.Revert(ctx, userID, uid, tc.revision, tc.version)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected %v, got %v", tc.err, err)
+			}
+
+			if event.Text != tc.want.Text || event.Version != tc.want.Version {
+				t.Fatalf("expected %+v, got %+v", tc.want, event)
+			}
+		})
+	}
+}
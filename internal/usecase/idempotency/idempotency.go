@@ -0,0 +1,74 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+)
+
+// UseCase - responses are kept for ttl after the request came.
+type UseCase struct {
+	repo repo.IdempotencyRepo
+	ttl  time.Duration
+}
+
+// New returns new UseCase(struct)
+func New(r repo.IdempotencyRepo, ttl time.Duration) *UseCase {
+	return &UseCase{
+		repo: r,
+		ttl:  ttl,
+	}
+}
+
+// Begin reserves key for the request with fingerprint, the caller then completes or releases it.
+// If the request was already answered, it returns the response to replay. A key of another
+// request fails with errs.ErrIdempotencyKeyReused, one being handled with errs.ErrRequestInProgress.
+func (uc *UseCase) Begin(ctx context.Context, key, fingerprint string) (*entity.StoredResponse, error) {
+	now := time.Now()
+
+	stored, err := uc.repo.Reserve(ctx, entity.IdempotencyKey{
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(uc.ttl),
+	}, now)
+	if err == nil {
+		return nil, nil
+	}
+
+	if !errors.Is(err, errs.ErrAlreadyExists) {
+		return nil, fmt.Errorf("IdempotencyUseCase - Begin - uc.repo.Reserve: %w", err)
+	}
+
+	if stored.Fingerprint != fingerprint {
+		return nil, errs.ErrIdempotencyKeyReused
+	}
+
+	if stored.Response == nil {
+		return nil, errs.ErrRequestInProgress
+	}
+
+	return stored.Response, nil
+}
+
+// Complete stores the response to replay on retries.
+func (uc *UseCase) Complete(ctx context.Context, key string, response entity.StoredResponse) error {
+	if err := uc.repo.Complete(ctx, key, response); err != nil {
+		return fmt.Errorf("IdempotencyUseCase - Complete - uc.repo.Complete: %w", err)
+	}
+
+	return nil
+}
+
+// Release forgets the key of a failed request, so it can be retried.
+func (uc *UseCase) Release(ctx context.Context, key string) error {
+	if err := uc.repo.Release(ctx, key); err != nil {
+		return fmt.Errorf("IdempotencyUseCase - Release - uc.repo.Release: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/usecase/idempotency"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"go.uber.org/mock/gomock"
+)
+
+func idempotencyUseCase(t *testing.T) (*idempotency.UseCase, *MockIdempotencyRepo, *gomock.Controller) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	repo := NewMockIdempotencyRepo(mockCtl)
+
+	useCase := idempotency.New(repo, time.Hour)
+
+	return useCase, repo, mockCtl
+}
+
+func TestBeginNewKey(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := idempotencyUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	repo.
+		EXPECT().
+		Reserve(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key entity.IdempotencyKey, now time.Time) (entity.IdempotencyKey, error) {
+			if key.Key != "retry-1" || key.Fingerprint != "create" || !key.ExpiresAt.Equal(now.Add(time.Hour)) {
+				t.Fatalf("unexpected key %+v at %v", key, now)
+			}
+
+			return entity.IdempotencyKey{}, nil
+		})
+
+	response, err := useCase.Begin(ctx, "retry-1", "create")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if response != nil {
+		t.Fatalf("expected no response, got %+v", response)
+	}
+}
+
+func TestBeginReplay(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := idempotencyUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	stored := entity.StoredResponse{Status: 201, Body: []byte(`{"uid":"x"}`)}
+
+	repo.
+		EXPECT().
+		Reserve(ctx, gomock.Any(), gomock.Any()).
+		Return(entity.IdempotencyKey{Key: "retry-1", Fingerprint: "create", Response: &stored}, errs.ErrAlreadyExists)
+
+	response, err := useCase.Begin(ctx, "retry-1", "create")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if response == nil || response.Status != stored.Status || string(response.Body) != string(stored.Body) {
+		t.Fatalf("expected %+v, got %+v", stored, response)
+	}
+}
+
+func TestBeginConflicts(t *testing.T) {
+	t.Parallel()
+
+	stored := entity.StoredResponse{Status: 201}
+
+	tests := []struct {
+		name     string
+		existing entity.IdempotencyKey
+		expected error
+	}{
+		{
+			name:     "another request",
+			existing: entity.IdempotencyKey{Key: "retry-1", Fingerprint: "other", Response: &stored},
+			expected: errs.ErrIdempotencyKeyReused,
+		},
+		{
+			name:     "in progress",
+			existing: entity.IdempotencyKey{Key: "retry-1", Fingerprint: "create"},
+			expected: errs.ErrRequestInProgress,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			useCase, repo, ctrl := idempotencyUseCase(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+
+			repo.
+				EXPECT().
+				Reserve(ctx, gomock.Any(), gomock.Any()).
+				Return(tc.existing, errs.ErrAlreadyExists)
+
+			_, err := useCase.Begin(ctx, "retry-1", "create")
+
+			if !errors.Is(err, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestBeginErr(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := idempotencyUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	repo.
+		EXPECT().
+		Reserve(ctx, gomock.Any(), gomock.Any()).
+		Return(entity.IdempotencyKey{}, errStorageProblem)
+
+	_, err := useCase.Begin(ctx, "retry-1", "create")
+
+	if !errors.Is(err, errStorageProblem) {
+		t.Fatalf("expected wrapped error, got %v", err)
+	}
+}
+
+func TestCompleteIdempotencyKeyErr(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := idempotencyUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	response := entity.StoredResponse{Status: 201}
+
+	repo.
+		EXPECT().
+		Complete(ctx, "retry-1", response).
+		Return(errStorageProblem)
+
+	err := useCase.Complete(ctx, "retry-1", response)
+
+	if !errors.Is(err, errStorageProblem) {
+		t.Fatalf("expected wrapped error, got %v", err)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	time "time"
 
 	entity "github.com/andreyxaxa/calendar/internal/entity"
+	repo "github.com/andreyxaxa/calendar/internal/repo"
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -43,89 +44,537 @@ func (m *MockEventsRepo) EXPECT() *MockEventsRepoMockRecorder {
 	return m.recorder
 }
 
+// AddRevision mocks base method.
+func (m *MockEventsRepo) AddRevision(ctx context.Context, userID int, eventUID uuid.UUID, revision entity.Revision) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRevision", ctx, userID, eventUID, revision)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRevision indicates an expected call of AddRevision.
+func (mr *MockEventsRepoMockRecorder) AddRevision(ctx, userID, eventUID, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRevision", reflect.TypeOf((*MockEventsRepo)(nil).AddRevision), ctx, userID, eventUID, revision)
+}
+
 // Create mocks base method.
-func (m *MockEventsRepo) Create(arg0 context.Context, arg1 int, arg2 uuid.UUID, arg3 entity.Event) error {
+func (m *MockEventsRepo) Create(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "Create", ctx, userID, eventUID, event)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockEventsRepoMockRecorder) Create(arg0, arg1, arg2, arg3 any) *gomock.Call {
+func (mr *MockEventsRepoMockRecorder) Create(ctx, userID, eventUID, event any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEventsRepo)(nil).Create), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEventsRepo)(nil).Create), ctx, userID, eventUID, event)
 }
 
 // Delete mocks base method.
-func (m *MockEventsRepo) Delete(arg0 context.Context, arg1 int, arg2 uuid.UUID) error {
+func (m *MockEventsRepo) Delete(ctx context.Context, userID int, eventUID uuid.UUID, version int64, deletedAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, eventUID, version, deletedAt)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockEventsRepoMockRecorder) Delete(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockEventsRepoMockRecorder) Delete(ctx, userID, eventUID, version, deletedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEventsRepo)(nil).Delete), ctx, userID, eventUID, version, deletedAt)
+}
+
+// GetAll mocks base method.
+func (m *MockEventsRepo) GetAll(ctx context.Context, userID int) (map[uuid.UUID]entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userID)
+	ret0, _ := ret[0].(map[uuid.UUID]entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockEventsRepoMockRecorder) GetAll(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockEventsRepo)(nil).GetAll), ctx, userID)
+}
+
+// GetByUID mocks base method.
+func (m *MockEventsRepo) GetByUID(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUID", ctx, userID, eventUID)
+	ret0, _ := ret[0].(entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUID indicates an expected call of GetByUID.
+func (mr *MockEventsRepoMockRecorder) GetByUID(ctx, userID, eventUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUID", reflect.TypeOf((*MockEventsRepo)(nil).GetByUID), ctx, userID, eventUID)
+}
+
+// GetChanges mocks base method.
+func (m *MockEventsRepo) GetChanges(ctx context.Context, userID int, after int64, limit int) ([]entity.Change, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChanges", ctx, userID, after, limit)
+	ret0, _ := ret[0].([]entity.Change)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetChanges indicates an expected call of GetChanges.
+func (mr *MockEventsRepoMockRecorder) GetChanges(ctx, userID, after, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEventsRepo)(nil).Delete), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChanges", reflect.TypeOf((*MockEventsRepo)(nil).GetChanges), ctx, userID, after, limit)
 }
 
 // GetEventsForDay mocks base method.
-func (m *MockEventsRepo) GetEventsForDay(arg0 context.Context, arg1 int, arg2 time.Time) (map[uuid.UUID]entity.Event, error) {
+func (m *MockEventsRepo) GetEventsForDay(ctx context.Context, userID int, date time.Time) (map[uuid.UUID]entity.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventsForDay", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "GetEventsForDay", ctx, userID, date)
 	ret0, _ := ret[0].(map[uuid.UUID]entity.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEventsForDay indicates an expected call of GetEventsForDay.
-func (mr *MockEventsRepoMockRecorder) GetEventsForDay(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockEventsRepoMockRecorder) GetEventsForDay(ctx, userID, date any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForDay", reflect.TypeOf((*MockEventsRepo)(nil).GetEventsForDay), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForDay", reflect.TypeOf((*MockEventsRepo)(nil).GetEventsForDay), ctx, userID, date)
 }
 
 // GetEventsForMonth mocks base method.
-func (m *MockEventsRepo) GetEventsForMonth(arg0 context.Context, arg1 int, arg2 time.Time) (map[uuid.UUID]entity.Event, error) {
+func (m *MockEventsRepo) GetEventsForMonth(ctx context.Context, userID int, date time.Time) (map[uuid.UUID]entity.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventsForMonth", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "GetEventsForMonth", ctx, userID, date)
 	ret0, _ := ret[0].(map[uuid.UUID]entity.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEventsForMonth indicates an expected call of GetEventsForMonth.
-func (mr *MockEventsRepoMockRecorder) GetEventsForMonth(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockEventsRepoMockRecorder) GetEventsForMonth(ctx, userID, date any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForMonth", reflect.TypeOf((*MockEventsRepo)(nil).GetEventsForMonth), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForMonth", reflect.TypeOf((*MockEventsRepo)(nil).GetEventsForMonth), ctx, userID, date)
+}
+
+// GetEventsForRange mocks base method.
+func (m *MockEventsRepo) GetEventsForRange(ctx context.Context, userID int, from, to time.Time, page entity.PageRequest) (entity.EventsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventsForRange", ctx, userID, from, to, page)
+	ret0, _ := ret[0].(entity.EventsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventsForRange indicates an expected call of GetEventsForRange.
+func (mr *MockEventsRepoMockRecorder) GetEventsForRange(ctx, userID, from, to, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForRange", reflect.TypeOf((*MockEventsRepo)(nil).GetEventsForRange), ctx, userID, from, to, page)
 }
 
 // GetEventsForWeek mocks base method.
-func (m *MockEventsRepo) GetEventsForWeek(arg0 context.Context, arg1 int, arg2 time.Time) (map[uuid.UUID]entity.Event, error) {
+func (m *MockEventsRepo) GetEventsForWeek(ctx context.Context, userID int, date time.Time) (map[uuid.UUID]entity.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventsForWeek", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "GetEventsForWeek", ctx, userID, date)
 	ret0, _ := ret[0].(map[uuid.UUID]entity.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEventsForWeek indicates an expected call of GetEventsForWeek.
-func (mr *MockEventsRepoMockRecorder) GetEventsForWeek(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockEventsRepoMockRecorder) GetEventsForWeek(ctx, userID, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForWeek", reflect.TypeOf((*MockEventsRepo)(nil).GetEventsForWeek), ctx, userID, date)
+}
+
+// GetRevisions mocks base method.
+func (m *MockEventsRepo) GetRevisions(ctx context.Context, userID int, eventUID uuid.UUID) ([]entity.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", ctx, userID, eventUID)
+	ret0, _ := ret[0].([]entity.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockEventsRepoMockRecorder) GetRevisions(ctx, userID, eventUID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForWeek", reflect.TypeOf((*MockEventsRepo)(nil).GetEventsForWeek), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockEventsRepo)(nil).GetRevisions), ctx, userID, eventUID)
+}
+
+// GetTrash mocks base method.
+func (m *MockEventsRepo) GetTrash(ctx context.Context, userID int) ([]entity.TrashedEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx, userID)
+	ret0, _ := ret[0].([]entity.TrashedEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockEventsRepoMockRecorder) GetTrash(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockEventsRepo)(nil).GetTrash), ctx, userID)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockEventsRepo) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockEventsRepoMockRecorder) PurgeTrash(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockEventsRepo)(nil).PurgeTrash), ctx, before)
+}
+
+// Restore mocks base method.
+func (m *MockEventsRepo) Restore(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, userID, eventUID)
+	ret0, _ := ret[0].(entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockEventsRepoMockRecorder) Restore(ctx, userID, eventUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockEventsRepo)(nil).Restore), ctx, userID, eventUID)
 }
 
 // Update mocks base method.
-func (m *MockEventsRepo) Update(arg0 context.Context, arg1 int, arg2 uuid.UUID, arg3 string, arg4 time.Time) error {
+func (m *MockEventsRepo) Update(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "Update", ctx, userID, eventUID, event)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockEventsRepoMockRecorder) Update(ctx, userID, eventUID, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockEventsRepo)(nil).Update), ctx, userID, eventUID, event)
+}
+
+// UpdateFields mocks base method.
+func (m *MockEventsRepo) UpdateFields(ctx context.Context, userID int, eventUID uuid.UUID, patch entity.Event, mask entity.FieldMask) (entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFields", ctx, userID, eventUID, patch, mask)
+	ret0, _ := ret[0].(entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFields indicates an expected call of UpdateFields.
+func (mr *MockEventsRepoMockRecorder) UpdateFields(ctx, userID, eventUID, patch, mask any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFields", reflect.TypeOf((*MockEventsRepo)(nil).UpdateFields), ctx, userID, eventUID, patch, mask)
+}
+
+// WithinTx mocks base method.
+func (m *MockEventsRepo) WithinTx(ctx context.Context, fn func(repo.EventsRepo) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", ctx, fn)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Update indicates an expected call of Update.
-func (mr *MockEventsRepoMockRecorder) Update(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MockEventsRepoMockRecorder) WithinTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*MockEventsRepo)(nil).WithinTx), ctx, fn)
+}
+
+// MockUnitOfWork is a mock of UnitOfWork interface.
+type MockUnitOfWork struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkMockRecorder
+	isgomock struct{}
+}
+
+// MockUnitOfWorkMockRecorder is the mock recorder for MockUnitOfWork.
+type MockUnitOfWorkMockRecorder struct {
+	mock *MockUnitOfWork
+}
+
+// NewMockUnitOfWork creates a new mock instance.
+func NewMockUnitOfWork(ctrl *gomock.Controller) *MockUnitOfWork {
+	mock := &MockUnitOfWork{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWork) EXPECT() *MockUnitOfWorkMockRecorder {
+	return m.recorder
+}
+
+// WithinTx mocks base method.
+func (m *MockUnitOfWork) WithinTx(ctx context.Context, fn func(repo.EventsRepo) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MockUnitOfWorkMockRecorder) WithinTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*MockUnitOfWork)(nil).WithinTx), ctx, fn)
+}
+
+// MockUsersRepo is a mock of UsersRepo interface.
+type MockUsersRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsersRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockUsersRepoMockRecorder is the mock recorder for MockUsersRepo.
+type MockUsersRepoMockRecorder struct {
+	mock *MockUsersRepo
+}
+
+// NewMockUsersRepo creates a new mock instance.
+func NewMockUsersRepo(ctrl *gomock.Controller) *MockUsersRepo {
+	mock := &MockUsersRepo{ctrl: ctrl}
+	mock.recorder = &MockUsersRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsersRepo) EXPECT() *MockUsersRepoMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockUsersRepo) Get(ctx context.Context, userID int) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockUsersRepoMockRecorder) Get(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUsersRepo)(nil).Get), ctx, userID)
+}
+
+// Save mocks base method.
+func (m *MockUsersRepo) Save(ctx context.Context, user entity.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockUsersRepoMockRecorder) Save(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockUsersRepo)(nil).Save), ctx, user)
+}
+
+// MockIdempotencyRepo is a mock of IdempotencyRepo interface.
+type MockIdempotencyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyRepoMockRecorder is the mock recorder for MockIdempotencyRepo.
+type MockIdempotencyRepoMockRecorder struct {
+	mock *MockIdempotencyRepo
+}
+
+// NewMockIdempotencyRepo creates a new mock instance.
+func NewMockIdempotencyRepo(ctrl *gomock.Controller) *MockIdempotencyRepo {
+	mock := &MockIdempotencyRepo{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyRepo) EXPECT() *MockIdempotencyRepoMockRecorder {
+	return m.recorder
+}
+
+// Complete mocks base method.
+func (m *MockIdempotencyRepo) Complete(ctx context.Context, key string, response entity.StoredResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, key, response)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyRepoMockRecorder) Complete(ctx, key, response any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotencyRepo)(nil).Complete), ctx, key, response)
+}
+
+// Release mocks base method.
+func (m *MockIdempotencyRepo) Release(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIdempotencyRepoMockRecorder) Release(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIdempotencyRepo)(nil).Release), ctx, key)
+}
+
+// Reserve mocks base method.
+func (m *MockIdempotencyRepo) Reserve(ctx context.Context, key entity.IdempotencyKey, now time.Time) (entity.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, key, now)
+	ret0, _ := ret[0].(entity.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockIdempotencyRepoMockRecorder) Reserve(ctx, key, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockIdempotencyRepo)(nil).Reserve), ctx, key, now)
+}
+
+// MockWebhooksRepo is a mock of WebhooksRepo interface.
+type MockWebhooksRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhooksRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhooksRepoMockRecorder is the mock recorder for MockWebhooksRepo.
+type MockWebhooksRepoMockRecorder struct {
+	mock *MockWebhooksRepo
+}
+
+// NewMockWebhooksRepo creates a new mock instance.
+func NewMockWebhooksRepo(ctrl *gomock.Controller) *MockWebhooksRepo {
+	mock := &MockWebhooksRepo{ctrl: ctrl}
+	mock.recorder = &MockWebhooksRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhooksRepo) EXPECT() *MockWebhooksRepoMockRecorder {
+	return m.recorder
+}
+
+// AddAttempt mocks base method.
+func (m *MockWebhooksRepo) AddAttempt(ctx context.Context, delivery entity.Delivery, attempt entity.DeliveryAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAttempt", ctx, delivery, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAttempt indicates an expected call of AddAttempt.
+func (mr *MockWebhooksRepoMockRecorder) AddAttempt(ctx, delivery, attempt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAttempt", reflect.TypeOf((*MockWebhooksRepo)(nil).AddAttempt), ctx, delivery, attempt)
+}
+
+// AddDeliveries mocks base method.
+func (m *MockWebhooksRepo) AddDeliveries(ctx context.Context, deliveries []entity.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDeliveries", ctx, deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDeliveries indicates an expected call of AddDeliveries.
+func (mr *MockWebhooksRepoMockRecorder) AddDeliveries(ctx, deliveries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDeliveries", reflect.TypeOf((*MockWebhooksRepo)(nil).AddDeliveries), ctx, deliveries)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhooksRepo) CreateWebhook(ctx context.Context, webhook entity.Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhooksRepoMockRecorder) CreateWebhook(ctx, webhook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhooksRepo)(nil).CreateWebhook), ctx, webhook)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhooksRepo) DeleteWebhook(ctx context.Context, userID int, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhooksRepoMockRecorder) DeleteWebhook(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhooksRepo)(nil).DeleteWebhook), ctx, userID, id)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockWebhooksRepo) GetDeliveries(ctx context.Context, userID int, webhookID uuid.UUID, status entity.DeliveryStatus, limit int) ([]entity.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, userID, webhookID, status, limit)
+	ret0, _ := ret[0].([]entity.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockWebhooksRepoMockRecorder) GetDeliveries(ctx, userID, webhookID, status, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockWebhooksRepo)(nil).GetDeliveries), ctx, userID, webhookID, status, limit)
+}
+
+// GetDueDeliveries mocks base method.
+func (m *MockWebhooksRepo) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.DueDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueDeliveries", ctx, now, limit)
+	ret0, _ := ret[0].([]entity.DueDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueDeliveries indicates an expected call of GetDueDeliveries.
+func (mr *MockWebhooksRepoMockRecorder) GetDueDeliveries(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueDeliveries", reflect.TypeOf((*MockWebhooksRepo)(nil).GetDueDeliveries), ctx, now, limit)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockWebhooksRepo) GetWebhooks(ctx context.Context, userID int) ([]entity.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx, userID)
+	ret0, _ := ret[0].([]entity.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockWebhooksRepoMockRecorder) GetWebhooks(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockEventsRepo)(nil).Update), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockWebhooksRepo)(nil).GetWebhooks), ctx, userID)
 }
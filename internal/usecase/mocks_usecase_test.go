@@ -11,10 +11,12 @@ package usecase_test
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 	time "time"
 
 	entity "github.com/andreyxaxa/calendar/internal/entity"
+	usecase "github.com/andreyxaxa/calendar/internal/usecase"
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -43,89 +45,684 @@ func (m *MockEvents) EXPECT() *MockEventsMockRecorder {
 	return m.recorder
 }
 
+// Batch mocks base method.
+func (m *MockEvents) Batch(ctx context.Context, userID int, ops []entity.BatchOperation, atomic bool) ([]entity.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Batch", ctx, userID, ops, atomic)
+	ret0, _ := ret[0].([]entity.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Batch indicates an expected call of Batch.
+func (mr *MockEventsMockRecorder) Batch(ctx, userID, ops, atomic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Batch", reflect.TypeOf((*MockEvents)(nil).Batch), ctx, userID, ops, atomic)
+}
+
 // Create mocks base method.
-func (m *MockEvents) Create(arg0 context.Context, arg1 int, arg2 uuid.UUID, arg3 entity.Event) error {
+func (m *MockEvents) Create(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) (entity.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Create", ctx, userID, eventUID, event)
+	ret0, _ := ret[0].(entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockEventsMockRecorder) Create(arg0, arg1, arg2, arg3 any) *gomock.Call {
+func (mr *MockEventsMockRecorder) Create(ctx, userID, eventUID, event any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEvents)(nil).Create), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEvents)(nil).Create), ctx, userID, eventUID, event)
 }
 
 // Delete mocks base method.
-func (m *MockEvents) Delete(arg0 context.Context, arg1 int, arg2 uuid.UUID) error {
+func (m *MockEvents) Delete(ctx context.Context, userID int, eventUID uuid.UUID, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, eventUID, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockEventsMockRecorder) Delete(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockEventsMockRecorder) Delete(ctx, userID, eventUID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEvents)(nil).Delete), ctx, userID, eventUID, version)
+}
+
+// DeleteOccurrence mocks base method.
+func (m *MockEvents) DeleteOccurrence(ctx context.Context, userID int, eventUID uuid.UUID, recurrenceID time.Time, scope entity.Scope, version int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOccurrence", ctx, userID, eventUID, recurrenceID, scope, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOccurrence indicates an expected call of DeleteOccurrence.
+func (mr *MockEventsMockRecorder) DeleteOccurrence(ctx, userID, eventUID, recurrenceID, scope, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOccurrence", reflect.TypeOf((*MockEvents)(nil).DeleteOccurrence), ctx, userID, eventUID, recurrenceID, scope, version)
+}
+
+// GetByUID mocks base method.
+func (m *MockEvents) GetByUID(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUID", ctx, userID, eventUID)
+	ret0, _ := ret[0].(entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUID indicates an expected call of GetByUID.
+func (mr *MockEventsMockRecorder) GetByUID(ctx, userID, eventUID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEvents)(nil).Delete), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUID", reflect.TypeOf((*MockEvents)(nil).GetByUID), ctx, userID, eventUID)
 }
 
 // GetEventsForDay mocks base method.
-func (m *MockEvents) GetEventsForDay(arg0 context.Context, arg1 int, arg2 time.Time) (map[uuid.UUID]entity.Event, error) {
+func (m *MockEvents) GetEventsForDay(ctx context.Context, userID int, date time.Time) ([]entity.Occurrence, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventsForDay", arg0, arg1, arg2)
-	ret0, _ := ret[0].(map[uuid.UUID]entity.Event)
+	ret := m.ctrl.Call(m, "GetEventsForDay", ctx, userID, date)
+	ret0, _ := ret[0].([]entity.Occurrence)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEventsForDay indicates an expected call of GetEventsForDay.
-func (mr *MockEventsMockRecorder) GetEventsForDay(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockEventsMockRecorder) GetEventsForDay(ctx, userID, date any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForDay", reflect.TypeOf((*MockEvents)(nil).GetEventsForDay), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForDay", reflect.TypeOf((*MockEvents)(nil).GetEventsForDay), ctx, userID, date)
 }
 
 // GetEventsForMonth mocks base method.
-func (m *MockEvents) GetEventsForMonth(arg0 context.Context, arg1 int, arg2 time.Time) (map[uuid.UUID]entity.Event, error) {
+func (m *MockEvents) GetEventsForMonth(ctx context.Context, userID int, date time.Time) ([]entity.Occurrence, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventsForMonth", arg0, arg1, arg2)
-	ret0, _ := ret[0].(map[uuid.UUID]entity.Event)
+	ret := m.ctrl.Call(m, "GetEventsForMonth", ctx, userID, date)
+	ret0, _ := ret[0].([]entity.Occurrence)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEventsForMonth indicates an expected call of GetEventsForMonth.
-func (mr *MockEventsMockRecorder) GetEventsForMonth(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockEventsMockRecorder) GetEventsForMonth(ctx, userID, date any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForMonth", reflect.TypeOf((*MockEvents)(nil).GetEventsForMonth), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForMonth", reflect.TypeOf((*MockEvents)(nil).GetEventsForMonth), ctx, userID, date)
+}
+
+// GetEventsForRange mocks base method.
+func (m *MockEvents) GetEventsForRange(ctx context.Context, userID int, from, to time.Time, page entity.PageRequest) (entity.OccurrencesPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventsForRange", ctx, userID, from, to, page)
+	ret0, _ := ret[0].(entity.OccurrencesPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventsForRange indicates an expected call of GetEventsForRange.
+func (mr *MockEventsMockRecorder) GetEventsForRange(ctx, userID, from, to, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForRange", reflect.TypeOf((*MockEvents)(nil).GetEventsForRange), ctx, userID, from, to, page)
 }
 
 // GetEventsForWeek mocks base method.
-func (m *MockEvents) GetEventsForWeek(arg0 context.Context, arg1 int, arg2 time.Time) (map[uuid.UUID]entity.Event, error) {
+func (m *MockEvents) GetEventsForWeek(ctx context.Context, userID int, date time.Time) ([]entity.Occurrence, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEventsForWeek", arg0, arg1, arg2)
-	ret0, _ := ret[0].(map[uuid.UUID]entity.Event)
+	ret := m.ctrl.Call(m, "GetEventsForWeek", ctx, userID, date)
+	ret0, _ := ret[0].([]entity.Occurrence)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEventsForWeek indicates an expected call of GetEventsForWeek.
-func (mr *MockEventsMockRecorder) GetEventsForWeek(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockEventsMockRecorder) GetEventsForWeek(ctx, userID, date any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForWeek", reflect.TypeOf((*MockEvents)(nil).GetEventsForWeek), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForWeek", reflect.TypeOf((*MockEvents)(nil).GetEventsForWeek), ctx, userID, date)
+}
+
+// GetHistory mocks base method.
+func (m *MockEvents) GetHistory(ctx context.Context, userID int, eventUID uuid.UUID) ([]entity.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, userID, eventUID)
+	ret0, _ := ret[0].([]entity.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockEventsMockRecorder) GetHistory(ctx, userID, eventUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockEvents)(nil).GetHistory), ctx, userID, eventUID)
+}
+
+// GetTrash mocks base method.
+func (m *MockEvents) GetTrash(ctx context.Context, userID int) ([]entity.TrashedEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx, userID)
+	ret0, _ := ret[0].([]entity.TrashedEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockEventsMockRecorder) GetTrash(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockEvents)(nil).GetTrash), ctx, userID)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockEvents) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockEventsMockRecorder) PurgeTrash(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockEvents)(nil).PurgeTrash), ctx, before)
+}
+
+// Replace mocks base method.
+func (m *MockEvents) Replace(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) (entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, userID, eventUID, event)
+	ret0, _ := ret[0].(entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockEventsMockRecorder) Replace(ctx, userID, eventUID, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockEvents)(nil).Replace), ctx, userID, eventUID, event)
+}
+
+// Restore mocks base method.
+func (m *MockEvents) Restore(ctx context.Context, userID int, eventUID uuid.UUID) (entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, userID, eventUID)
+	ret0, _ := ret[0].(entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockEventsMockRecorder) Restore(ctx, userID, eventUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockEvents)(nil).Restore), ctx, userID, eventUID)
+}
+
+// Revert mocks base method.
+func (m *MockEvents) Revert(ctx context.Context, userID int, eventUID uuid.UUID, revision, version int64) (entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revert", ctx, userID, eventUID, revision, version)
+	ret0, _ := ret[0].(entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revert indicates an expected call of Revert.
+func (mr *MockEventsMockRecorder) Revert(ctx, userID, eventUID, revision, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockEvents)(nil).Revert), ctx, userID, eventUID, revision, version)
+}
+
+// Subscribe mocks base method.
+func (m *MockEvents) Subscribe(ctx context.Context, userID int, after uint64) <-chan entity.Notification {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, userID, after)
+	ret0, _ := ret[0].(<-chan entity.Notification)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockEventsMockRecorder) Subscribe(ctx, userID, after any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockEvents)(nil).Subscribe), ctx, userID, after)
+}
+
+// Sync mocks base method.
+func (m *MockEvents) Sync(ctx context.Context, userID int, after int64, limit int) (entity.ChangesPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", ctx, userID, after, limit)
+	ret0, _ := ret[0].(entity.ChangesPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockEventsMockRecorder) Sync(ctx, userID, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockEvents)(nil).Sync), ctx, userID, after, limit)
 }
 
 // Update mocks base method.
-func (m *MockEvents) Update(arg0 context.Context, arg1 int, arg2 uuid.UUID, arg3 string, arg4 time.Time) error {
+func (m *MockEvents) Update(ctx context.Context, userID int, eventUID uuid.UUID, event entity.Event) (entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, userID, eventUID, event)
+	ret0, _ := ret[0].(entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockEventsMockRecorder) Update(ctx, userID, eventUID, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockEvents)(nil).Update), ctx, userID, eventUID, event)
+}
+
+// UpdateFields mocks base method.
+func (m *MockEvents) UpdateFields(ctx context.Context, userID int, eventUID uuid.UUID, patch entity.Event, mask entity.FieldMask) (entity.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFields", ctx, userID, eventUID, patch, mask)
+	ret0, _ := ret[0].(entity.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFields indicates an expected call of UpdateFields.
+func (mr *MockEventsMockRecorder) UpdateFields(ctx, userID, eventUID, patch, mask any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFields", reflect.TypeOf((*MockEvents)(nil).UpdateFields), ctx, userID, eventUID, patch, mask)
+}
+
+// UpdateOccurrence mocks base method.
+func (m *MockEvents) UpdateOccurrence(ctx context.Context, userID int, eventUID uuid.UUID, recurrenceID time.Time, scope entity.Scope, event entity.Event) (entity.Occurrence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOccurrence", ctx, userID, eventUID, recurrenceID, scope, event)
+	ret0, _ := ret[0].(entity.Occurrence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOccurrence indicates an expected call of UpdateOccurrence.
+func (mr *MockEventsMockRecorder) UpdateOccurrence(ctx, userID, eventUID, recurrenceID, scope, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOccurrence", reflect.TypeOf((*MockEvents)(nil).UpdateOccurrence), ctx, userID, eventUID, recurrenceID, scope, event)
+}
+
+// WithinTx mocks base method.
+func (m *MockEvents) WithinTx(ctx context.Context, fn func(usecase.Events) error) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "WithinTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MockEventsMockRecorder) WithinTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*MockEvents)(nil).WithinTx), ctx, fn)
+}
+
+// MockCalendar is a mock of Calendar interface.
+type MockCalendar struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalendarMockRecorder
+	isgomock struct{}
+}
+
+// MockCalendarMockRecorder is the mock recorder for MockCalendar.
+type MockCalendarMockRecorder struct {
+	mock *MockCalendar
+}
+
+// NewMockCalendar creates a new mock instance.
+func NewMockCalendar(ctrl *gomock.Controller) *MockCalendar {
+	mock := &MockCalendar{ctrl: ctrl}
+	mock.recorder = &MockCalendarMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalendar) EXPECT() *MockCalendarMockRecorder {
+	return m.recorder
+}
+
+// DeleteObject mocks base method.
+func (m *MockCalendar) DeleteObject(ctx context.Context, userID int, eventUID uuid.UUID, cond entity.Preconditions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteObject", ctx, userID, eventUID, cond)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteObject indicates an expected call of DeleteObject.
+func (mr *MockCalendarMockRecorder) DeleteObject(ctx, userID, eventUID, cond any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteObject", reflect.TypeOf((*MockCalendar)(nil).DeleteObject), ctx, userID, eventUID, cond)
+}
+
+// Export mocks base method.
+func (m *MockCalendar) Export(ctx context.Context, userID int) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, userID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockCalendarMockRecorder) Export(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockCalendar)(nil).Export), ctx, userID)
+}
+
+// Import mocks base method.
+func (m *MockCalendar) Import(ctx context.Context, userID int, r io.Reader, loc *time.Location) (entity.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, userID, r, loc)
+	ret0, _ := ret[0].(entity.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockCalendarMockRecorder) Import(ctx, userID, r, loc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockCalendar)(nil).Import), ctx, userID, r, loc)
+}
+
+// ObjectTags mocks base method.
+func (m *MockCalendar) ObjectTags(ctx context.Context, userID int, from, to time.Time) ([]entity.CalendarObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ObjectTags", ctx, userID, from, to)
+	ret0, _ := ret[0].([]entity.CalendarObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ObjectTags indicates an expected call of ObjectTags.
+func (mr *MockCalendarMockRecorder) ObjectTags(ctx, userID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObjectTags", reflect.TypeOf((*MockCalendar)(nil).ObjectTags), ctx, userID, from, to)
+}
+
+// Objects mocks base method.
+func (m *MockCalendar) Objects(ctx context.Context, userID int, eventUIDs []uuid.UUID) ([]entity.CalendarObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Objects", ctx, userID, eventUIDs)
+	ret0, _ := ret[0].([]entity.CalendarObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Objects indicates an expected call of Objects.
+func (mr *MockCalendarMockRecorder) Objects(ctx, userID, eventUIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Objects", reflect.TypeOf((*MockCalendar)(nil).Objects), ctx, userID, eventUIDs)
+}
+
+// PutObject mocks base method.
+func (m *MockCalendar) PutObject(ctx context.Context, userID int, eventUID uuid.UUID, r io.Reader, loc *time.Location, cond entity.Preconditions) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutObject", ctx, userID, eventUID, r, loc, cond)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutObject indicates an expected call of PutObject.
+func (mr *MockCalendarMockRecorder) PutObject(ctx, userID, eventUID, r, loc, cond any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutObject", reflect.TypeOf((*MockCalendar)(nil).PutObject), ctx, userID, eventUID, r, loc, cond)
+}
+
+// MockUsers is a mock of Users interface.
+type MockUsers struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsersMockRecorder
+	isgomock struct{}
+}
+
+// MockUsersMockRecorder is the mock recorder for MockUsers.
+type MockUsersMockRecorder struct {
+	mock *MockUsers
+}
+
+// NewMockUsers creates a new mock instance.
+func NewMockUsers(ctrl *gomock.Controller) *MockUsers {
+	mock := &MockUsers{ctrl: ctrl}
+	mock.recorder = &MockUsersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsers) EXPECT() *MockUsersMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockUsers) Get(ctx context.Context, userID int) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockUsersMockRecorder) Get(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUsers)(nil).Get), ctx, userID)
+}
+
+// Update mocks base method.
+func (m *MockUsers) Update(ctx context.Context, user entity.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockEventsMockRecorder) Update(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+func (mr *MockUsersMockRecorder) Update(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUsers)(nil).Update), ctx, user)
+}
+
+// MockWebhooks is a mock of Webhooks interface.
+type MockWebhooks struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhooksMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhooksMockRecorder is the mock recorder for MockWebhooks.
+type MockWebhooksMockRecorder struct {
+	mock *MockWebhooks
+}
+
+// NewMockWebhooks creates a new mock instance.
+func NewMockWebhooks(ctrl *gomock.Controller) *MockWebhooks {
+	mock := &MockWebhooks{ctrl: ctrl}
+	mock.recorder = &MockWebhooksMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhooks) EXPECT() *MockWebhooksMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhooks) Create(ctx context.Context, webhook entity.Webhook) (entity.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, webhook)
+	ret0, _ := ret[0].(entity.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhooksMockRecorder) Create(ctx, webhook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhooks)(nil).Create), ctx, webhook)
+}
+
+// Delete mocks base method.
+func (m *MockWebhooks) Delete(ctx context.Context, userID int, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhooksMockRecorder) Delete(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhooks)(nil).Delete), ctx, userID, id)
+}
+
+// Dispatch mocks base method.
+func (m *MockWebhooks) Dispatch(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dispatch", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Dispatch indicates an expected call of Dispatch.
+func (mr *MockWebhooksMockRecorder) Dispatch(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dispatch", reflect.TypeOf((*MockWebhooks)(nil).Dispatch), ctx)
+}
+
+// Dropped mocks base method.
+func (m *MockWebhooks) Dropped() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dropped")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// Dropped indicates an expected call of Dropped.
+func (mr *MockWebhooksMockRecorder) Dropped() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dropped", reflect.TypeOf((*MockWebhooks)(nil).Dropped))
+}
+
+// GetDeliveries mocks base method.
+func (m *MockWebhooks) GetDeliveries(ctx context.Context, userID int, webhookID uuid.UUID, status entity.DeliveryStatus, limit int) ([]entity.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, userID, webhookID, status, limit)
+	ret0, _ := ret[0].([]entity.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockWebhooksMockRecorder) GetDeliveries(ctx, userID, webhookID, status, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockWebhooks)(nil).GetDeliveries), ctx, userID, webhookID, status, limit)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockWebhooks) GetWebhooks(ctx context.Context, userID int) ([]entity.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx, userID)
+	ret0, _ := ret[0].([]entity.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockWebhooksMockRecorder) GetWebhooks(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockWebhooks)(nil).GetWebhooks), ctx, userID)
+}
+
+// Notify mocks base method.
+func (m *MockWebhooks) Notify(n entity.Notification) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Notify", n)
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockWebhooksMockRecorder) Notify(n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockWebhooks)(nil).Notify), n)
+}
+
+// Queued mocks base method.
+func (m *MockWebhooks) Queued() <-chan struct{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Queued")
+	ret0, _ := ret[0].(<-chan struct{})
+	return ret0
+}
+
+// Queued indicates an expected call of Queued.
+func (mr *MockWebhooksMockRecorder) Queued() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Queued", reflect.TypeOf((*MockWebhooks)(nil).Queued))
+}
+
+// MockIdempotency is a mock of Idempotency interface.
+type MockIdempotency struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyMockRecorder is the mock recorder for MockIdempotency.
+type MockIdempotencyMockRecorder struct {
+	mock *MockIdempotency
+}
+
+// NewMockIdempotency creates a new mock instance.
+func NewMockIdempotency(ctrl *gomock.Controller) *MockIdempotency {
+	mock := &MockIdempotency{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotency) EXPECT() *MockIdempotencyMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockIdempotency) Begin(ctx context.Context, key, fingerprint string) (*entity.StoredResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx, key, fingerprint)
+	ret0, _ := ret[0].(*entity.StoredResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockIdempotencyMockRecorder) Begin(ctx, key, fingerprint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockIdempotency)(nil).Begin), ctx, key, fingerprint)
+}
+
+// Complete mocks base method.
+func (m *MockIdempotency) Complete(ctx context.Context, key string, response entity.StoredResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, key, response)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyMockRecorder) Complete(ctx, key, response any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotency)(nil).Complete), ctx, key, response)
+}
+
+// Release mocks base method.
+func (m *MockIdempotency) Release(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIdempotencyMockRecorder) Release(ctx, key any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockEvents)(nil).Update), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIdempotency)(nil).Release), ctx, key)
 }
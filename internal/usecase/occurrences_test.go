@@ -0,0 +1,569 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/usecase"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+// weeklySeries starts on monday 2026-01-05 09:00 UTC.
+func weeklySeries(rule string) entity.Event {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	return entity.Event{
+		Text:     "stand-up",
+		Start:    start,
+		End:      start.Add(15 * time.Minute),
+		TimeZone: "UTC",
+		RRule:    rule,
+	}
+}
+
+func week(n int) time.Time {
+	return time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC).AddDate(0, 0, 7*n)
+}
+
+// withinTx lets transactions of repo run their function against repo itself.
+func withinTx(mockRepo *MockEventsRepo) {
+	mockRepo.
+		EXPECT().
+		WithinTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(tx repo.EventsRepo) error) error {
+			return fn(mockRepo)
+		}).
+		AnyTimes()
+}
+
+func TestUpdateKeepsOverridesOfSameSeries(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	uid := uuid.New()
+
+	current := weeklySeries("FREQ=WEEKLY")
+	current.Overrides = []entity.Override{{RecurrenceID: week(1), Start: week(1).Add(time.Hour), End: week(1).Add(2 * time.Hour)}}
+
+	renamed := weeklySeries("FREQ=WEEKLY")
+	renamed.Text = "daily scrum"
+
+	moved := weeklySeries("FREQ=WEEKLY")
+	moved.Start, moved.End = moved.Start.Add(time.Hour), moved.End.Add(time.Hour)
+
+	tests := []struct {
+		event     entity.Event
+		overrides int
+	}{
+		{renamed, 1},
+		{moved, 0},
+	}
+
+	for _, tt := range tests {
+		repo.EXPECT().GetByUID(ctx, 1, uid).Return(current, nil)
+		repo.EXPECT().
+			Update(ctx, 1, uid, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ int, _ uuid.UUID, event entity.Event) (int64, error) {
+				if len(event.Overrides) != tt.overrides {
+					t.Fatalf("expected %d overrides, got %d", tt.overrides, len(event.Overrides))
+				}
+
+				return 2, nil
+			})
+
+		if _, err := useCase.Update(ctx, 1, uid, tt.event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestUpdateSeriesWrittenOverWhatWasRead(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	uid := uuid.New()
+
+	current := weeklySeries("FREQ=WEEKLY")
+	current.Version = 3
+
+	renamed := weeklySeries("FREQ=WEEKLY")
+	renamed.Text = "daily scrum"
+
+	// an override added after the read bumped the version: the write must not drop it.
+	repo.EXPECT().GetByUID(ctx, 1, uid).Return(current, nil)
+	repo.EXPECT().
+		Update(ctx, 1, uid, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int, _ uuid.UUID, event entity.Event) (int64, error) {
+			if event.Version != current.Version {
+				t.Fatalf("expected the write to expect version %d, got %d", current.Version, event.Version)
+			}
+
+			return 0, errs.ErrPreconditionFailed
+		})
+
+	if _, err := useCase.Update(ctx, 1, uid, renamed); !errors.Is(err, errs.ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+}
+
+func TestUpdateThisOccurrence(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	uid := uuid.New()
+	series := weeklySeries("FREQ=WEEKLY")
+
+	moved := entity.Event{Text: "stand-up, later", Start: week(2).Add(3 * time.Hour), End: week(2).Add(4 * time.Hour)}
+
+	repo.EXPECT().GetByUID(ctx, 1, uid).Return(series, nil)
+	repo.EXPECT().
+		Update(ctx, 1, uid, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int, _ uuid.UUID, event entity.Event) (int64, error) {
+			if event.RRule != series.RRule || len(event.Overrides) != 1 {
+				t.Fatalf("expected the series with one override, got %+v", event)
+			}
+
+			o := event.Overrides[0]
+			if !o.RecurrenceID.Equal(week(2)) || !o.Start.Equal(moved.Start) || o.Text != moved.Text {
+				t.Fatalf("unexpected override %+v", o)
+			}
+
+			return 2, nil
+		})
+
+	occurrence, err := useCase.UpdateOccurrence(ctx, 1, uid, week(2), entity.ScopeThis, moved)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if occurrence.UID != uid || !occurrence.RecurrenceID.Equal(week(2)) || !occurrence.Start.Equal(moved.Start) {
+		t.Fatalf("unexpected occurrence %+v", occurrence)
+	}
+}
+
+func TestUpdateFollowingOccurrences(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	uid := uuid.New()
+
+	series := weeklySeries("FREQ=WEEKLY;COUNT=10")
+	series.ExDates = []time.Time{week(1), week(5)}
+
+	later := entity.Event{Text: "stand-up", Start: week(3).Add(time.Hour), End: week(3).Add(time.Hour + 15*time.Minute), TimeZone: "UTC"}
+
+	var newUID uuid.UUID
+
+	withinTx(repo)
+	repo.EXPECT().GetByUID(ctx, 1, uid).Return(series, nil)
+	repo.EXPECT().
+		Create(ctx, 1, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int, eventUID uuid.UUID, event entity.Event) error {
+			newUID = eventUID
+
+			// 3 of 10 instances stay with the old series, cancelled ones count too.
+			if event.RRule != "FREQ=WEEKLY;COUNT=7" {
+				t.Fatalf("expected the rest of the rule, got %q", event.RRule)
+			}
+
+			return nil
+		})
+	repo.EXPECT().
+		Update(ctx, 1, uid, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int, _ uuid.UUID, event entity.Event) (int64, error) {
+			if event.RRule != "FREQ=WEEKLY;UNTIL=20260126T085959Z" {
+				t.Fatalf("expected the series to end before the split, got %q", event.RRule)
+			}
+
+			if len(event.ExDates) != 1 || !event.ExDates[0].Equal(week(1)) {
+				t.Fatalf("expected only earlier exdates, got %v", event.ExDates)
+			}
+
+			return 2, nil
+		})
+
+	occurrence, err := useCase.UpdateOccurrence(ctx, 1, uid, week(3), entity.ScopeFollowing, later)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if occurrence.UID != newUID || occurrence.UID == uid {
+		t.Fatalf("expected the new series uid, got %s", occurrence.UID)
+	}
+}
+
+func TestUpdateFollowingFromFirstOccurrence(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	uid := uuid.New()
+	series := weeklySeries("FREQ=WEEKLY")
+
+	renamed := weeklySeries("")
+	renamed.Text = "daily scrum"
+
+	repo.EXPECT().GetByUID(ctx, 1, uid).Return(series, nil).Times(2)
+	repo.EXPECT().
+		Update(ctx, 1, uid, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int, _ uuid.UUID, event entity.Event) (int64, error) {
+			if event.RRule != series.RRule || event.Text != renamed.Text {
+				t.Fatalf("expected the whole series renamed, got %+v", event)
+			}
+
+			return 2, nil
+		})
+
+	occurrence, err := useCase.UpdateOccurrence(ctx, 1, uid, week(0), entity.ScopeFollowing, renamed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if occurrence.UID != uid {
+		t.Fatalf("expected the series uid, got %s", occurrence.UID)
+	}
+}
+
+func TestUpdateOccurrenceErr(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	uid := uuid.New()
+
+	cancelled := weeklySeries("FREQ=WEEKLY")
+	cancelled.ExDates = []time.Time{week(1)}
+
+	tests := []struct {
+		name     string
+		event    entity.Event
+		at       time.Time
+		expected error
+	}{
+		{"not recurring", weeklySeries(""), week(0), errs.ErrNotRecurring},
+		{"not an instance", weeklySeries("FREQ=WEEKLY"), week(1).Add(time.Hour), errs.ErrOccurrenceNotFound},
+		{"cancelled instance", cancelled, week(1), errs.ErrOccurrenceNotFound},
+		{"after the series", weeklySeries("FREQ=WEEKLY;COUNT=2"), week(2), errs.ErrOccurrenceNotFound},
+	}
+
+	for _, tt := range tests {
+		useCase, repo, ctrl := eventsUseCase(t)
+
+		repo.EXPECT().GetByUID(ctx, 1, uid).Return(tt.event, nil)
+
+		_, err := useCase.UpdateOccurrence(ctx, 1, uid, tt.at, entity.ScopeThis, entity.Event{})
+		if !errors.Is(err, tt.expected) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.expected, err)
+		}
+
+		ctrl.Finish()
+	}
+}
+
+func TestOccurrenceVersions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	uid := uuid.New()
+
+	series := weeklySeries("FREQ=WEEKLY")
+	series.Version = 3
+
+	moved := entity.Event{Text: "stand-up, later", Start: week(2).Add(time.Hour), End: week(2).Add(2 * time.Hour)}
+
+	// a stale version fails before anything is written.
+	for _, scope := range []entity.Scope{entity.ScopeThis, entity.ScopeFollowing} {
+		useCase, repo, ctrl := eventsUseCase(t)
+
+		repo.EXPECT().GetByUID(ctx, 1, uid).Return(series, nil)
+
+		stale := moved
+		stale.Version = 2
+
+		_, err := useCase.UpdateOccurrence(ctx, 1, uid, week(2), scope, stale)
+		if !errors.Is(err, errs.ErrPreconditionFailed) {
+			t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+		}
+
+		ctrl.Finish()
+	}
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	repo.EXPECT().GetByUID(ctx, 1, uid).Return(series, nil).Times(2)
+	repo.EXPECT().Update(ctx, 1, uid, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int, _ uuid.UUID, event entity.Event) (int64, error) {
+			if event.Version != series.Version {
+				t.Fatalf("expected the write to expect version %d, got %d", series.Version, event.Version)
+			}
+
+			return event.Version + 1, nil
+		}).
+		Times(2)
+
+	moved.Version = series.Version
+
+	occurrence, err := useCase.UpdateOccurrence(ctx, 1, uid, week(2), entity.ScopeThis, moved)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if occurrence.Version != 4 {
+		t.Fatalf("expected version 4, got %d", occurrence.Version)
+	}
+
+	if err = useCase.DeleteOccurrence(ctx, 1, uid, week(1), entity.ScopeThis, series.Version); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestOccurrenceConcurrentWrites(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	uid := uuid.New()
+
+	series := weeklySeries("FREQ=WEEKLY")
+	series.Version = 3
+
+	moved := entity.Event{Text: "stand-up, later", Start: week(2).Add(time.Hour), End: week(2).Add(2 * time.Hour)}
+
+	// the series changed after it was read: without If-Match the write still expects the read version.
+	expectRead := func(_ context.Context, _ int, _ uuid.UUID, event entity.Event) (int64, error) {
+		if event.Version != series.Version {
+			t.Fatalf("expected the write to expect version %d, got %d", series.Version, event.Version)
+		}
+
+		return 0, errs.ErrPreconditionFailed
+	}
+
+	tests := []struct {
+		name   string
+		expect func(repo *MockEventsRepo)
+		write  func(uc usecase.Events) error
+	}{
+		{
+			name: "update this",
+			expect: func(repo *MockEventsRepo) {
+				repo.EXPECT().Update(ctx, 1, uid, gomock.Any()).DoAndReturn(expectRead)
+			},
+			write: func(uc usecase.Events) error {
+				_, err := uc.UpdateOccurrence(ctx, 1, uid, week(2), entity.ScopeThis, moved)
+
+				return err
+			},
+		},
+		{
+			name: "update following",
+			expect: func(repo *MockEventsRepo) {
+				withinTx(repo)
+				repo.EXPECT().Create(ctx, 1, gomock.Any(), gomock.Any()).Return(nil)
+				repo.EXPECT().Update(ctx, 1, uid, gomock.Any()).DoAndReturn(expectRead)
+			},
+			write: func(uc usecase.Events) error {
+				_, err := uc.UpdateOccurrence(ctx, 1, uid, week(2), entity.ScopeFollowing, moved)
+
+				return err
+			},
+		},
+		{
+			name: "delete this",
+			expect: func(repo *MockEventsRepo) {
+				repo.EXPECT().Update(ctx, 1, uid, gomock.Any()).DoAndReturn(expectRead)
+			},
+			write: func(uc usecase.Events) error {
+				return uc.DeleteOccurrence(ctx, 1, uid, week(2), entity.ScopeThis, 0)
+			},
+		},
+		{
+			name: "delete following",
+			expect: func(repo *MockEventsRepo) {
+				repo.EXPECT().Update(ctx, 1, uid, gomock.Any()).DoAndReturn(expectRead)
+			},
+			write: func(uc usecase.Events) error {
+				return uc.DeleteOccurrence(ctx, 1, uid, week(2), entity.ScopeFollowing, 0)
+			},
+		},
+		{
+			name: "delete following from the first",
+			expect: func(repo *MockEventsRepo) {
+				repo.EXPECT().Delete(ctx, 1, uid, series.Version, gomock.Any()).Return(errs.ErrPreconditionFailed)
+			},
+			write: func(uc usecase.Events) error {
+				return uc.DeleteOccurrence(ctx, 1, uid, week(0), entity.ScopeFollowing, 0)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		useCase, repo, ctrl := eventsUseCase(t)
+
+		repo.EXPECT().GetByUID(ctx, 1, uid).Return(series, nil)
+		tt.expect(repo)
+
+		if err := tt.write(useCase); !errors.Is(err, errs.ErrPreconditionFailed) {
+			t.Fatalf("%s: expected ErrPreconditionFailed, got %v", tt.name, err)
+		}
+
+		ctrl.Finish()
+	}
+}
+
+func TestDeleteOccurrenceStaleVersion(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	uid := uuid.New()
+
+	series := weeklySeries("FREQ=WEEKLY")
+	series.Version = 3
+
+	// nothing is written.
+	repo.EXPECT().GetByUID(ctx, 1, uid).Return(series, nil)
+
+	err := useCase.DeleteOccurrence(ctx, 1, uid, week(2), entity.ScopeThis, 2)
+	if !errors.Is(err, errs.ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+}
+
+func TestDeleteOccurrence(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	uid := uuid.New()
+
+	series := weeklySeries("FREQ=WEEKLY")
+	series.Overrides = []entity.Override{
+		{RecurrenceID: week(2), Start: week(2).Add(time.Hour), End: week(2).Add(2 * time.Hour)},
+		{RecurrenceID: week(4), Start: week(4).Add(time.Hour), End: week(4).Add(2 * time.Hour)},
+	}
+
+	tests := []struct {
+		name      string
+		scope     entity.Scope
+		at        time.Time
+		rrule     string
+		exDates   int
+		overrides int
+	}{
+		{"this", entity.ScopeThis, week(2), "FREQ=WEEKLY", 1, 1},
+		{"following", entity.ScopeFollowing, week(3), "FREQ=WEEKLY;UNTIL=20260126T085959Z", 0, 1},
+	}
+
+	for _, tt := range tests {
+		useCase, repo, ctrl := eventsUseCase(t)
+
+		repo.EXPECT().GetByUID(ctx, 1, uid).Return(series, nil)
+		repo.EXPECT().
+			Update(ctx, 1, uid, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ int, _ uuid.UUID, event entity.Event) (int64, error) {
+				if event.RRule != tt.rrule || len(event.ExDates) != tt.exDates || len(event.Overrides) != tt.overrides {
+					t.Fatalf("%s: unexpected series %+v", tt.name, event)
+				}
+
+				return 2, nil
+			})
+
+		if err := useCase.DeleteOccurrence(ctx, 1, uid, tt.at, tt.scope, 0); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+
+		ctrl.Finish()
+	}
+
+	if len(series.Overrides) != 2 {
+		t.Fatal("the series read from the repo was modified")
+	}
+}
+
+func TestDeleteFollowingFromFirstOccurrence(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	uid := uuid.New()
+
+	repo.EXPECT().GetByUID(ctx, 1, uid).Return(weeklySeries("FREQ=WEEKLY"), nil)
+	repo.EXPECT().Delete(ctx, 1, uid, int64(0), gomock.Any()).Return(nil)
+
+	if err := useCase.DeleteOccurrence(ctx, 1, uid, week(0), entity.ScopeFollowing, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetEventsForWeekAppliesOverrides(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	uid := uuid.New()
+
+	// the instance of week 2 moves to tuesday of week 3, the one of week 4 is cancelled.
+	series := weeklySeries("FREQ=WEEKLY")
+	series.ExDates = []time.Time{week(4)}
+	series.Overrides = []entity.Override{{
+		RecurrenceID: week(2),
+		Start:        week(3).AddDate(0, 0, 1),
+		End:          week(3).AddDate(0, 0, 1).Add(time.Hour),
+		Text:         "moved",
+	}}
+
+	tests := []struct {
+		monday   time.Time
+		expected []string
+	}{
+		{week(2), nil},
+		{week(3), []string{"stand-up", "moved"}},
+		{week(4), nil},
+		{week(5), []string{"stand-up"}},
+	}
+
+	for _, tt := range tests {
+		repo.EXPECT().
+			GetEventsForWeek(ctx, 1, tt.monday).
+			Return(map[uuid.UUID]entity.Event{uid: series}, nil)
+
+		result, err := useCase.GetEventsForWeek(ctx, 1, tt.monday)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(result) != len(tt.expected) {
+			t.Fatalf("%s: expected %d occurrences, got %d", tt.monday, len(tt.expected), len(result))
+		}
+
+		for i, occurrence := range result {
+			if occurrence.Text != tt.expected[i] {
+				t.Fatalf("%s: occurrence %d: expected %q, got %q", tt.monday, i, tt.expected[i], occurrence.Text)
+			}
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/internal/usecase/events"
+	"github.com/andreyxaxa/calendar/pkg/pubsub"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func streamUseCase(t *testing.T) (*events.UseCase, *MockEventsRepo, *pubsub.Broker[int, entity.Notification]) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	repo := NewMockEventsRepo(mockCtl)
+	notifications := pubsub.New[int, entity.Notification]()
+
+	return events.New(repo, notifications), repo, notifications
+}
+
+func next(t *testing.T, c <-chan entity.Notification) entity.Notification {
+	t.Helper()
+
+	select {
+	case n, ok := <-c:
+		if !ok {
+			t.Fatal("stream closed")
+		}
+
+		return n
+	case <-time.After(time.Second):
+		t.Fatal("no notification")
+	}
+
+	return entity.Notification{}
+}
+
+func TestStreamNotifies(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _ := streamUseCase(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	userID := 1
+	eventUID := uuid.New()
+	event := entity.Event{Text: "stand-up", Start: time.Now(), End: time.Now().Add(time.Hour)}
+
+	stream := useCase.Subscribe(ctx, userID, 0)
+	other := useCase.Subscribe(ctx, userID+1, 0)
+
+	repo.EXPECT().Create(ctx, userID, eventUID, event).Return(nil)
+	repo.EXPECT().Delete(ctx, userID, eventUID, int64(1), gomock.Any()).Return(nil)
+
+	if _, err := useCase.Create(ctx, userID, eventUID, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := useCase.Delete(ctx, userID, eventUID, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	created := next(t, stream)
+	if created.Kind != entity.NotificationCreated || created.UID != eventUID || created.Event.Version != 1 {
+		t.Fatalf("unexpected notification %+v", created)
+	}
+
+	deleted := next(t, stream)
+	if deleted.Kind != entity.NotificationDeleted || deleted.UID != eventUID || deleted.ID <= created.ID {
+		t.Fatalf("unexpected notification %+v after %+v", deleted, created)
+	}
+
+	select {
+	case n := <-other:
+		t.Fatalf("unexpected notification of another user %+v", n)
+	default:
+	}
+
+	cancel()
+
+	for range stream {
+	}
+}
+
+func TestStreamAtomicBatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userID := 1
+	ops := batchOperations()
+
+	tests := []struct {
+		name   string
+		commit error
+		want   int
+	}{
+		{name: "committed", want: len(ops)},
+		{name: "rolled back", commit: errStorageProblem},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			useCase, mockRepo, notifications := streamUseCase(t)
+
+			sub, _, _ := notifications.Subscribe(userID, 0)
+			defer sub.Close()
+
+			mockRepo.
+				EXPECT().
+				WithinTx(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, fn func(tx repo.EventsRepo) error) error {
+					if err := fn(mockRepo); err != nil {
+						return err
+					}
+
+					// nothing is published before the commit.
+					if len(sub.C) != 0 {
+						t.Fatalf("expected no notifications inside the transaction, got %d", len(sub.C))
+					}
+
+					return tc.commit
+				})
+
+			mockRepo.EXPECT().Create(ctx, userID, ops[0].UID, ops[0].Event).Return(nil)
+			mockRepo.EXPECT().Update(ctx, userID, ops[1].UID, ops[1].Event).Return(int64(2), nil)
+			mockRepo.EXPECT().Delete(ctx, userID, ops[2].UID, ops[2].Event.Version, gomock.Any()).Return(nil)
+
+			_, _ = useCase.Batch(ctx, userID, ops, true)
+
+			if len(sub.C) != tc.want {
+				t.Fatalf("expected %d notifications, got %d", tc.want, len(sub.C))
+			}
+		})
+	}
+}
+
+func TestStreamResume(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, _ := streamUseCase(t)
+
+	ctx := context.Background()
+	userID := 1
+	first, second := uuid.New(), uuid.New()
+
+	repo.EXPECT().Create(ctx, userID, gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	subCtx, cancel := context.WithCancel(ctx)
+	stream := useCase.Subscribe(subCtx, userID, 0)
+
+	if _, err := useCase.Create(ctx, userID, first, entity.Event{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	last := next(t, stream)
+	cancel()
+
+	// written while the subscriber was away.
+	if _, err := useCase.Create(ctx, userID, second, entity.Event{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resumed, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if n := next(t, useCase.Subscribe(resumed, userID, last.ID)); n.UID != second || n.ID <= last.ID {
+		t.Fatalf("expected notification of %s after %d, got %+v", second, last.ID, n)
+	}
+
+	// IDs of a previous process are not kept.
+	if n := next(t, useCase.Subscribe(resumed, userID, 1)); n.Kind != entity.NotificationReset || n.ID <= last.ID {
+		t.Fatalf("expected reset, got %+v", n)
+	}
+}
+
+func TestStreamListeners(t *testing.T) {
+	t.Parallel()
+
+	mockCtl := gomock.NewController(t)
+	mockRepo := NewMockEventsRepo(mockCtl)
+
+	var got []entity.Notification
+
+	useCase := events.New(mockRepo, pubsub.New[int, entity.Notification](), func(n entity.Notification) {
+		got = append(got, n)
+	})
+
+	ctx := context.Background()
+	userID := 1
+	ops := batchOperations()
+
+	mockRepo.
+		EXPECT().
+		WithinTx(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(tx repo.EventsRepo) error) error {
+			if err := fn(mockRepo); err != nil {
+				return err
+			}
+
+			if len(got) != 0 {
+				t.Fatalf("expected listeners to wait for the commit, got %+v", got)
+			}
+
+			return errStorageProblem
+		})
+	mockRepo.EXPECT().Create(ctx, userID, ops[0].UID, ops[0].Event).Return(nil).Times(2)
+	mockRepo.EXPECT().Update(ctx, userID, ops[1].UID, ops[1].Event).Return(int64(2), nil)
+	mockRepo.EXPECT().Delete(ctx, userID, ops[2].UID, ops[2].Event.Version, gomock.Any()).Return(nil)
+
+	// rolled back.
+	_, _ = useCase.Batch(ctx, userID, ops, true)
+
+	if _, err := useCase.Create(ctx, userID, ops[0].UID, ops[0].Event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != 1 || got[0].Kind != entity.NotificationCreated || got[0].UID != ops[0].UID || got[0].ID == 0 {
+		t.Fatalf("expected the created notification with its ID, got %+v", got)
+	}
+}
@@ -0,0 +1,105 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userID := 1
+	changes := []entity.Change{
+		{UID: uuid.New(), Seq: 4, Event: entity.Event{Text: "stand-up", Version: 2}},
+		{UID: uuid.New(), Seq: 5, Deleted: true},
+		{UID: uuid.New(), Seq: 7, Event: entity.Event{Text: "retro", Version: 1}},
+	}
+
+	tests := []struct {
+		name    string
+		after   int64
+		limit   int
+		changes []entity.Change
+		seq     int64
+		repoErr error
+		want    entity.ChangesPage
+		err     error
+	}{
+		{
+			name:    "last page",
+			after:   3,
+			limit:   3,
+			changes: changes,
+			seq:     7,
+			want:    entity.ChangesPage{Changes: changes, Seq: 7},
+		},
+		{
+			name:    "more follow",
+			after:   3,
+			limit:   2,
+			changes: changes,
+			seq:     7,
+			want:    entity.ChangesPage{Changes: changes[:2], Seq: 5, More: true},
+		},
+		{
+			name:    "up to date",
+			after:   7,
+			limit:   2,
+			changes: []entity.Change{},
+			seq:     7,
+			want:    entity.ChangesPage{Changes: []entity.Change{}, Seq: 7},
+		},
+		{
+			name:    "token ahead of the sequence",
+			after:   8,
+			limit:   2,
+			changes: []entity.Change{},
+			seq:     7,
+			err:     errs.ErrSyncTokenExpired,
+		},
+		{
+			name:    "user who never wrote",
+			limit:   2,
+			changes: []entity.Change{},
+			want:    entity.ChangesPage{Changes: []entity.Change{}},
+		},
+		{
+			name:    "storage problems",
+			limit:   2,
+			repoErr: errStorageProblem,
+			err:     errStorageProblem,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			useCase, repo, ctrl := eventsUseCase(t)
+			defer ctrl.Finish()
+
+			repo.EXPECT().GetChanges(ctx, userID, tc.after, tc.limit+1).Return(tc.changes, tc.seq, tc.repoErr)
+
+			page, err := useCase.Sync(ctx, userID, tc.after, tc.limit)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected %v, got %v", tc.err, err)
+			}
+
+			if page.Seq != tc.want.Seq || page.More != tc.want.More || len(page.Changes) != len(tc.want.Changes) {
+				t.Fatalf("expected %+v, got %+v", tc.want, page)
+			}
+
+			for i, change := range page.Changes {
+				if change.UID != tc.want.Changes[i].UID {
+					t.Fatalf("expected %+v, got %+v", tc.want.Changes, page.Changes)
+				}
+			}
+		})
+	}
+}
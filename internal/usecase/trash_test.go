@@ -0,0 +1,91 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"github.com/google/uuid"
+)
+
+func TestGetTrash(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+	deletedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	first, second := uuid.New(), uuid.New()
+
+	repo.EXPECT().GetTrash(ctx, userID).Return([]entity.TrashedEvent{
+		{UID: first, DeletedAt: deletedAt},
+		{UID: second, DeletedAt: deletedAt.Add(time.Hour)},
+	}, nil)
+
+	trash, err := useCase.GetTrash(ctx, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(trash) != 2 || trash[0].UID != second || trash[1].UID != first {
+		t.Fatalf("expected the latest deleted first, got %+v", trash)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+	restored, taken := uuid.New(), uuid.New()
+
+	repo.EXPECT().Restore(ctx, userID, restored).Return(entity.Event{Text: "stand-up", Version: 2}, nil)
+	repo.EXPECT().Restore(ctx, userID, taken).Return(entity.Event{}, errs.ErrAlreadyExists)
+
+	event, err := useCase.Restore(ctx, userID, restored)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if event.Text != "stand-up" || event.Version != 2 {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	if _, err = useCase.Restore(ctx, userID, taken); !errors.Is(err, errs.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := eventsUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	before := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	repo.EXPECT().PurgeTrash(ctx, before).Return(int64(3), nil)
+	repo.EXPECT().PurgeTrash(ctx, before).Return(int64(0), errStorageProblem)
+
+	purged, err := useCase.PurgeTrash(ctx, before)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if purged != 3 {
+		t.Fatalf("expected 3 purged events, got %d", purged)
+	}
+
+	if _, err = useCase.PurgeTrash(ctx, before); !errors.Is(err, errStorageProblem) {
+		t.Fatalf("expected wrapped error, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+)
+
+// UseCase -.
+type UseCase struct {
+	repo repo.UsersRepo
+}
+
+// New returns new UseCase(struct)
+func New(r repo.UsersRepo) *UseCase {
+	return &UseCase{
+		repo: r,
+	}
+}
+
+// Get returns the user, unknown users get default settings.
+func (uc *UseCase) Get(ctx context.Context, userID int) (entity.User, error) {
+	user, err := uc.repo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) {
+			return entity.User{ID: userID, TimeZone: entity.DefaultTimeZone}, nil
+		}
+
+		return entity.User{}, fmt.Errorf("UsersUseCase - Get - uc.repo.Get: %w", err)
+	}
+
+	return user, nil
+}
+
+// Update -.
+func (uc *UseCase) Update(ctx context.Context, user entity.User) error {
+	if err := uc.repo.Save(ctx, user); err != nil {
+		return fmt.Errorf("UsersUseCase - Update - uc.repo.Save: %w", err)
+	}
+
+	return nil
+}
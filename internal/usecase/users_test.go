@@ -0,0 +1,138 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/usecase/users"
+	"github.com/andreyxaxa/calendar/pkg/types/errs"
+	"go.uber.org/mock/gomock"
+)
+
+func usersUseCase(t *testing.T) (*users.UseCase, *MockUsersRepo, *gomock.Controller) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	repo := NewMockUsersRepo(mockCtl)
+
+	useCase := users.New(repo)
+
+	return useCase, repo, mockCtl
+}
+
+func TestGetUserOK(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := usersUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	user := entity.User{ID: 1, TimeZone: "Europe/Moscow"}
+
+	repo.
+		EXPECT().
+		Get(ctx, user.ID).
+		Return(user, nil)
+
+	result, err := useCase.Get(ctx, user.ID)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result != user {
+		t.Fatalf("expected %+v, got %+v", user, result)
+	}
+}
+
+func TestGetUserDefault(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := usersUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+
+	repo.
+		EXPECT().
+		Get(ctx, userID).
+		Return(entity.User{}, errs.ErrUserNotFound)
+
+	result, err := useCase.Get(ctx, userID)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := entity.User{ID: userID, TimeZone: entity.DefaultTimeZone}
+	if result != expected {
+		t.Fatalf("expected %+v, got %+v", expected, result)
+	}
+}
+
+func TestGetUserErr(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := usersUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	userID := 1
+
+	repo.
+		EXPECT().
+		Get(ctx, userID).
+		Return(entity.User{}, errStorageProblem)
+
+	_, err := useCase.Get(ctx, userID)
+
+	if !errors.Is(err, errStorageProblem) {
+		t.Fatalf("expected wrapped error, got %v", err)
+	}
+}
+
+func TestUpdateUserOK(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := usersUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	user := entity.User{ID: 1, TimeZone: "Asia/Tokyo"}
+
+	repo.
+		EXPECT().
+		Save(ctx, user).
+		Return(nil)
+
+	err := useCase.Update(ctx, user)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUpdateUserErr(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo, ctrl := usersUseCase(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	user := entity.User{ID: 1, TimeZone: "Asia/Tokyo"}
+
+	repo.
+		EXPECT().
+		Save(ctx, user).
+		Return(errStorageProblem)
+
+	err := useCase.Update(ctx, user)
+
+	if !errors.Is(err, errStorageProblem) {
+		t.Fatalf("expected wrapped error, got %v", err)
+	}
+}
@@ -0,0 +1,376 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/repo"
+	"github.com/andreyxaxa/calendar/pkg/webhook"
+	"github.com/google/uuid"
+)
+
+const (
+	// _dispatchBatch - how many due deliveries are fetched at a time.
+	_dispatchBatch = 100
+	// _dispatchWorkers - how many webhooks are sent to at a time.
+	_dispatchWorkers = 8
+	// _maxBackoff bounds the wait before a retry.
+	_maxBackoff = 24 * time.Hour
+)
+
+// UseCase - a delivery is attempted up to maxAttempts times, the n-th retry backoff*2^(n-1)
+// after the failed attempt, at most _maxBackoff, then it is dead. At most queueSize notifications
+// wait for their deliveries to be stored, the ones beyond are dropped.
+type UseCase struct {
+	repo        repo.WebhooksRepo
+	client      *webhook.Client
+	maxAttempts int
+	backoff     time.Duration
+	queueSize   int
+
+	mu sync.Mutex
+	// queue holds notifications whose deliveries are not stored yet.
+	queue []entity.Notification
+	// dropped counts notifications dropped since the last Dropped.
+	dropped int
+	queued  chan struct{}
+}
+
+// New returns new UseCase(struct)
+func New(r repo.WebhooksRepo, client *webhook.Client, maxAttempts int, backoff time.Duration,
+	queueSize int,
+) *UseCase {
+	return &UseCase{
+		repo:        r,
+		client:      client,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		queueSize:   queueSize,
+		queued:      make(chan struct{}, _dispatchWorkers),
+	}
+}
+
+// payload - body of a delivery. Event is the stored event, missing for deleted ones.
+type payload struct {
+	ID        uuid.UUID               `json:"id"`
+	Type      entity.NotificationKind `json:"type"`
+	CreatedAt time.Time               `json:"created_at"`
+	UserID    int                     `json:"user_id"`
+	UID       uuid.UUID               `json:"uid"`
+	Event     *entity.Event           `json:"event,omitempty"`
+}
+
+// Create stores a new webhook, generating its secret unless it is set.
+func (uc *UseCase) Create(ctx context.Context, w entity.Webhook) (entity.Webhook, error) {
+	w.ID = uuid.New()
+	w.CreatedAt = time.Now().UTC()
+
+	if w.Secret == "" {
+		b := make([]byte, 32)
+		// never fails, see rand.Read.
+		_, _ = rand.Read(b)
+		w.Secret = hex.EncodeToString(b)
+	}
+
+	if err := uc.repo.CreateWebhook(ctx, w); err != nil {
+		return entity.Webhook{}, fmt.Errorf("WebhooksUseCase - Create - uc.repo.CreateWebhook: %w", err)
+	}
+
+	return w, nil
+}
+
+// GetWebhooks -.
+func (uc *UseCase) GetWebhooks(ctx context.Context, userID int) ([]entity.Webhook, error) {
+	webhooks, err := uc.repo.GetWebhooks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("WebhooksUseCase - GetWebhooks - uc.repo.GetWebhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// Delete deletes the webhook with its deliveries.
+func (uc *UseCase) Delete(ctx context.Context, userID int, id uuid.UUID) error {
+	if err := uc.repo.DeleteWebhook(ctx, userID, id); err != nil {
+		return fmt.Errorf("WebhooksUseCase - Delete - uc.repo.DeleteWebhook: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeliveries returns up to limit deliveries of the webhook in status, of any if it is empty,
+// the newest first. entity.DeliveryDead ones are the dead-letter list.
+func (uc *UseCase) GetDeliveries(ctx context.Context, userID int, webhookID uuid.UUID, status entity.DeliveryStatus,
+	limit int,
+) ([]entity.Delivery, error) {
+	deliveries, err := uc.repo.GetDeliveries(ctx, userID, webhookID, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("WebhooksUseCase - GetDeliveries - uc.repo.GetDeliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// Notify queues deliveries of n to webhooks of its user, Dispatch stores them. It doesnt block:
+// n is dropped if the queue is full, e.g. while the repository fails.
+func (uc *UseCase) Notify(n entity.Notification) {
+	uc.mu.Lock()
+	if len(uc.queue) < uc.queueSize {
+		uc.queue = append(uc.queue, n)
+	} else {
+		uc.dropped++
+	}
+	uc.mu.Unlock()
+
+	select {
+	case uc.queued <- struct{}{}:
+	default:
+	}
+}
+
+// Queued signals that Notify was called since the last Dispatch.
+func (uc *UseCase) Queued() <-chan struct{} {
+	return uc.queued
+}
+
+// Dropped returns how many notifications were dropped since its last call.
+func (uc *UseCase) Dropped() int {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	dropped := uc.dropped
+	uc.dropped = 0
+
+	return dropped
+}
+
+// Dispatch stores deliveries of the queued notifications and attempts the due ones, it returns
+// how many of them are dead after all attempts.
+func (uc *UseCase) Dispatch(ctx context.Context) (int, error) {
+	if err := uc.store(ctx); err != nil {
+		return 0, fmt.Errorf("WebhooksUseCase - Dispatch - uc.store: %w", err)
+	}
+
+	dead := 0
+
+	for {
+		due, err := uc.repo.GetDueDeliveries(ctx, time.Now(), _dispatchBatch)
+		if err != nil {
+			return dead, fmt.Errorf("WebhooksUseCase - Dispatch - uc.repo.GetDueDeliveries: %w", err)
+		}
+
+		n, err := uc.attemptAll(ctx, due)
+		dead += n
+
+		if err != nil {
+			return dead, fmt.Errorf("WebhooksUseCase - Dispatch - uc.attemptAll: %w", err)
+		}
+
+		if len(due) < _dispatchBatch {
+			return dead, nil
+		}
+	}
+}
+
+// attemptAll attempts deliveries to up to _dispatchWorkers webhooks at a time, those to a webhook
+// one by one in order: a slow receiver holds up only its own deliveries, each for the timeout
+// of the client at most. It returns how many deliveries are dead after the attempts.
+func (uc *UseCase) attemptAll(ctx context.Context, due []entity.DueDelivery) (int, error) {
+	var (
+		webhooks []uuid.UUID
+		byID     = make(map[uuid.UUID][]entity.DueDelivery)
+	)
+
+	for _, d := range due {
+		if _, ok := byID[d.WebhookID]; !ok {
+			webhooks = append(webhooks, d.WebhookID)
+		}
+
+		byID[d.WebhookID] = append(byID[d.WebhookID], d)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		dead    int
+		errs    []error
+		workers = make(chan struct{}, _dispatchWorkers)
+	)
+
+	for _, id := range webhooks {
+		workers <- struct{}{}
+
+		wg.Add(1)
+
+		go func(deliveries []entity.DueDelivery) {
+			defer wg.Done()
+			defer func() { <-workers }()
+
+			for _, d := range deliveries {
+				status, err := uc.attempt(ctx, d)
+
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, err)
+				} else if status == entity.DeliveryDead {
+					dead++
+				}
+				mu.Unlock()
+
+				if err != nil {
+					return
+				}
+			}
+		}(byID[id])
+	}
+
+	wg.Wait()
+
+	return dead, errors.Join(errs...)
+}
+
+// store stores deliveries of the queued notifications, they stay queued if it fails.
+func (uc *UseCase) store(ctx context.Context) error {
+	uc.mu.Lock()
+	queue := uc.queue
+	uc.queue = nil
+	uc.mu.Unlock()
+
+	if len(queue) == 0 {
+		return nil
+	}
+
+	deliveries, err := uc.deliveries(ctx, queue)
+	if err == nil {
+		err = uc.repo.AddDeliveries(ctx, deliveries)
+	}
+
+	if err != nil {
+		uc.mu.Lock()
+		uc.queue = append(queue, uc.queue...)
+		if len(uc.queue) > uc.queueSize {
+			uc.dropped += len(uc.queue) - uc.queueSize
+			uc.queue = uc.queue[:uc.queueSize]
+		}
+		uc.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+// deliveries returns deliveries of the notifications to the webhooks that want them.
+func (uc *UseCase) deliveries(ctx context.Context, queue []entity.Notification) ([]entity.Delivery, error) {
+	webhooks := make(map[int][]entity.Webhook)
+	now := time.Now().UTC()
+
+	var deliveries []entity.Delivery
+
+	for _, n := range queue {
+		if _, ok := webhooks[n.UserID]; !ok {
+			w, err := uc.repo.GetWebhooks(ctx, n.UserID)
+			if err != nil {
+				return nil, fmt.Errorf("uc.repo.GetWebhooks: %w", err)
+			}
+
+			webhooks[n.UserID] = w
+		}
+
+		for _, w := range webhooks[n.UserID] {
+			if !w.Wants(n.Kind) {
+				continue
+			}
+
+			d := entity.Delivery{
+				ID:            uuid.New(),
+				WebhookID:     w.ID,
+				UserID:        n.UserID,
+				Kind:          n.Kind,
+				Status:        entity.DeliveryPending,
+				NextAttemptAt: now,
+				CreatedAt:     now,
+			}
+
+			p := payload{ID: d.ID, Type: n.Kind, CreatedAt: now, UserID: n.UserID, UID: n.UID}
+			if n.Kind != entity.NotificationDeleted {
+				p.Event = &n.Event
+			}
+
+			// payload holds nothing json cant encode.
+			d.Payload, _ = json.Marshal(p)
+			deliveries = append(deliveries, d)
+		}
+	}
+
+	return deliveries, nil
+}
+
+// attempt sends the delivery and stores the attempt, it returns the status of the delivery after it.
+// Nothing is stored if ctx is done: the delivery is attempted again by the next Dispatch.
+func (uc *UseCase) attempt(ctx context.Context, d entity.DueDelivery) (entity.DeliveryStatus, error) {
+	at := time.Now()
+
+	code, err := uc.client.Send(ctx, webhook.Request{
+		URL:    d.URL,
+		Secret: d.Secret,
+		ID:     d.ID.String(),
+		Event:  string(d.Kind),
+		Body:   d.Payload,
+	})
+	if ctx.Err() != nil {
+		return d.Status, ctx.Err()
+	}
+
+	attempt := entity.DeliveryAttempt{At: at.UTC(), StatusCode: code, Duration: time.Since(at)}
+
+	switch attempts := len(d.Attempts) + 1; {
+	case err == nil:
+		d.Status = entity.DeliveryDelivered
+	case attempts >= uc.maxAttempts:
+		attempt.Error = attemptError(code, err)
+		d.Status = entity.DeliveryDead
+	default:
+		attempt.Error = attemptError(code, err)
+		d.NextAttemptAt = at.Add(uc.retryAfter(attempts)).UTC()
+	}
+
+	if err = uc.repo.AddAttempt(ctx, d.Delivery, attempt); err != nil {
+		return d.Status, fmt.Errorf("uc.repo.AddAttempt: %w", err)
+	}
+
+	return d.Status, nil
+}
+
+// retryAfter returns the wait before the retry after failures failed attempts: backoff doubled
+// after each but the first, at most _maxBackoff.
+func (uc *UseCase) retryAfter(failures int) time.Duration {
+	wait := uc.backoff
+
+	for i := 1; i < failures && wait < _maxBackoff; i++ {
+		wait *= 2
+	}
+
+	return min(wait, _maxBackoff)
+}
+
+// attemptError describes why an attempt failed for the user: the status or the cause of err
+// rather than where it was found.
+func attemptError(code int, err error) string {
+	if code != 0 {
+		return fmt.Sprintf("unexpected status %d", code)
+	}
+
+	if cause := errors.Unwrap(err); cause != nil {
+		return cause.Error()
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,413 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/internal/entity"
+	"github.com/andreyxaxa/calendar/internal/usecase/webhooks"
+	"github.com/andreyxaxa/calendar/pkg/webhook"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	_maxAttempts = 3
+	_queueSize   = 3
+)
+
+func webhooksUseCase(t *testing.T) (*webhooks.UseCase, *MockWebhooksRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	repo := NewMockWebhooksRepo(mockCtl)
+
+	return webhooks.New(repo, webhook.New(webhook.Timeout(time.Second)), _maxAttempts, time.Minute,
+		_queueSize), repo
+}
+
+// receiver - a local webhook receiver answering with status, it keeps bodies of requests
+// with a valid signature.
+type receiver struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	bodies [][]byte
+}
+
+func newReceiver(t *testing.T, secret string, status int) *receiver {
+	t.Helper()
+
+	r := &receiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		b, _ := io.ReadAll(req.Body)
+
+		if !webhook.Verify(secret, b, req.Header.Get(webhook.HeaderSignature)) {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		r.mu.Lock()
+		r.bodies = append(r.bodies, b)
+		r.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.Close)
+
+	return r
+}
+
+func TestCreateWebhook(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := webhooksUseCase(t)
+
+	ctx := context.Background()
+
+	repo.EXPECT().CreateWebhook(ctx, gomock.Any()).Return(nil).Times(2)
+
+	generated, err := useCase.Create(ctx, entity.Webhook{UserID: 1, URL: "http://bot.local/hook"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if generated.ID == uuid.Nil || generated.CreatedAt.IsZero() || len(generated.Secret) != 64 {
+		t.Fatalf("expected ID, time and a generated secret, got %+v", generated)
+	}
+
+	set, err := useCase.Create(ctx, entity.Webhook{UserID: 1, URL: "http://bot.local/hook", Secret: "s1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if set.Secret != "s1" || set.ID == generated.ID {
+		t.Fatalf("expected the secret kept, got %+v", set)
+	}
+}
+
+func TestDispatchDelivers(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := webhooksUseCase(t)
+	bot := newReceiver(t, "s1", http.StatusNoContent)
+
+	ctx := context.Background()
+	userID := 1
+	all := entity.Webhook{ID: uuid.New(), UserID: userID, URL: bot.URL, Secret: "s1"}
+	deletedOnly := entity.Webhook{
+		ID:     uuid.New(),
+		UserID: userID,
+		URL:    bot.URL,
+		Secret: "s1",
+		Kinds:  []entity.NotificationKind{entity.NotificationDeleted},
+	}
+
+	created := entity.Notification{
+		ID:     1,
+		Kind:   entity.NotificationCreated,
+		UserID: userID,
+		UID:    uuid.New(),
+		Event:  entity.Event{Text: "stand-up", Version: 1},
+	}
+	deleted := entity.Notification{ID: 2, Kind: entity.NotificationDeleted, UserID: userID, UID: created.UID}
+
+	useCase.Notify(created)
+	useCase.Notify(deleted)
+
+	select {
+	case <-useCase.Queued():
+	default:
+		t.Fatal("expected queued notifications to be signalled")
+	}
+
+	var stored []entity.Delivery
+
+	repo.EXPECT().GetWebhooks(ctx, userID).Return([]entity.Webhook{all, deletedOnly}, nil)
+	repo.EXPECT().AddDeliveries(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, d []entity.Delivery) error {
+		stored = d
+
+		return nil
+	})
+	repo.EXPECT().GetDueDeliveries(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(context.Context, time.Time, int) ([]entity.DueDelivery, error) {
+			due := make([]entity.DueDelivery, 0, len(stored))
+			for _, d := range stored {
+				due = append(due, entity.DueDelivery{Delivery: d, URL: bot.URL, Secret: "s1"})
+			}
+
+			return due, nil
+		})
+	repo.EXPECT().AddAttempt(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, d entity.Delivery, a entity.DeliveryAttempt) error {
+			if d.Status != entity.DeliveryDelivered || a.StatusCode != http.StatusNoContent || a.Error != "" {
+				t.Errorf("expected a delivered attempt, got %+v, %+v", d, a)
+			}
+
+			return nil
+		}).Times(3)
+
+	dead, err := useCase.Dispatch(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dead != 0 {
+		t.Fatalf("expected no dead deliveries, got %d", dead)
+	}
+
+	// created goes to the webhook of all kinds, deleted to both.
+	if len(stored) != 3 || stored[0].WebhookID != all.ID || stored[0].Kind != entity.NotificationCreated ||
+		stored[1].WebhookID != all.ID || stored[2].WebhookID != deletedOnly.ID ||
+		stored[2].Kind != entity.NotificationDeleted || stored[0].Status != entity.DeliveryPending {
+		t.Fatalf("unexpected deliveries %+v", stored)
+	}
+
+	if len(bot.bodies) != 3 {
+		t.Fatalf("expected 3 signed requests, got %d", len(bot.bodies))
+	}
+
+	type payload struct {
+		ID    uuid.UUID               `json:"id"`
+		Type  entity.NotificationKind `json:"type"`
+		UID   uuid.UUID               `json:"uid"`
+		Event *entity.Event           `json:"event"`
+	}
+
+	// webhooks are sent to concurrently, payloads are found by their delivery.
+	payloads := make(map[uuid.UUID]payload)
+
+	for _, b := range bot.bodies {
+		var p payload
+
+		if err = json.Unmarshal(b, &p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		payloads[p.ID] = p
+	}
+
+	p := payloads[stored[0].ID]
+	if p.Type != entity.NotificationCreated || p.UID != created.UID || p.Event == nil || p.Event.Text != "stand-up" {
+		t.Fatalf("unexpected payload %+v", p)
+	}
+
+	if p, ok := payloads[stored[2].ID]; !ok || p.Event != nil {
+		t.Fatalf("expected no event for deleted, got %+v", p)
+	}
+}
+
+func TestDispatchSlowReceiver(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := webhooksUseCase(t)
+	fast := newReceiver(t, "s1", http.StatusNoContent)
+
+	var once sync.Once
+
+	received := make(chan struct{})
+	// slow answers once fast got its delivery, it fails if that waits for slow.
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-received:
+			w.WriteHeader(http.StatusNoContent)
+		case <-time.After(500 * time.Millisecond):
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(slow.Close)
+
+	ctx := context.Background()
+	delivery := func(url string) entity.DueDelivery {
+		return entity.DueDelivery{
+			Delivery: entity.Delivery{
+				ID:        uuid.New(),
+				WebhookID: uuid.New(),
+				Kind:      entity.NotificationCreated,
+				Payload:   []byte(`{}`),
+				Status:    entity.DeliveryPending,
+			},
+			URL:    url,
+			Secret: "s1",
+		}
+	}
+
+	repo.EXPECT().GetDueDeliveries(ctx, gomock.Any(), gomock.Any()).Return(
+		[]entity.DueDelivery{delivery(slow.URL), delivery(fast.URL)}, nil)
+	repo.EXPECT().AddAttempt(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, d entity.Delivery, a entity.DeliveryAttempt) error {
+			if d.Status != entity.DeliveryDelivered {
+				t.Errorf("expected a delivered attempt, got %+v, %+v", d, a)
+			}
+
+			once.Do(func() { close(received) })
+
+			return nil
+		}).Times(2)
+
+	if _, err := useCase.Dispatch(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDispatchRetries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		attempts int
+		status   entity.DeliveryStatus
+		backoff  time.Duration
+		dead     int
+		// maxAttempts is _maxAttempts unless set.
+		maxAttempts int
+	}{
+		{name: "first failure", attempts: 0, status: entity.DeliveryPending, backoff: time.Minute},
+		{name: "backoff doubles", attempts: 1, status: entity.DeliveryPending, backoff: 2 * time.Minute},
+		{name: "last attempt", attempts: _maxAttempts - 1, status: entity.DeliveryDead, dead: 1},
+		{
+			name:        "backoff is bounded",
+			attempts:    98,
+			status:      entity.DeliveryPending,
+			backoff:     24 * time.Hour,
+			maxAttempts: 100,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			useCase, repo := webhooksUseCase(t)
+			if tc.maxAttempts != 0 {
+				useCase = webhooks.New(repo, webhook.New(webhook.Timeout(time.Second)), tc.maxAttempts, time.Minute,
+					_queueSize)
+			}
+			crm := newReceiver(t, "s1", http.StatusServiceUnavailable)
+
+			ctx := context.Background()
+			due := entity.DueDelivery{
+				Delivery: entity.Delivery{
+					ID:       uuid.New(),
+					Kind:     entity.NotificationUpdated,
+					Payload:  []byte(`{}`),
+					Status:   entity.DeliveryPending,
+					Attempts: make([]entity.DeliveryAttempt, tc.attempts),
+				},
+				URL:    crm.URL,
+				Secret: "s1",
+			}
+
+			repo.EXPECT().GetDueDeliveries(ctx, gomock.Any(), gomock.Any()).Return([]entity.DueDelivery{due}, nil)
+			repo.EXPECT().AddAttempt(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, d entity.Delivery, a entity.DeliveryAttempt) error {
+					if d.Status != tc.status || a.StatusCode != http.StatusServiceUnavailable || a.Error == "" {
+						t.Errorf("expected a failed attempt, got %+v, %+v", d, a)
+					}
+
+					if tc.status == entity.DeliveryPending && !d.NextAttemptAt.Equal(a.At.Add(tc.backoff)) {
+						t.Errorf("expected the retry %v after %v, got %v", tc.backoff, a.At, d.NextAttemptAt)
+					}
+
+					return nil
+				})
+
+			dead, err := useCase.Dispatch(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if dead != tc.dead {
+				t.Fatalf("expected %d dead deliveries, got %d", tc.dead, dead)
+			}
+		})
+	}
+}
+
+func TestDispatchKeepsQueued(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := webhooksUseCase(t)
+
+	ctx := context.Background()
+	w := entity.Webhook{ID: uuid.New(), UserID: 1, URL: "http://bot.local/hook", Secret: "s1"}
+
+	useCase.Notify(entity.Notification{ID: 1, Kind: entity.NotificationCreated, UserID: 1, UID: uuid.New()})
+
+	gomock.InOrder(
+		repo.EXPECT().GetWebhooks(ctx, 1).Return([]entity.Webhook{w}, nil),
+		repo.EXPECT().AddDeliveries(ctx, gomock.Any()).Return(errStorageProblem),
+		// the notification is stored by the next Dispatch.
+		repo.EXPECT().GetWebhooks(ctx, 1).Return([]entity.Webhook{w}, nil),
+		repo.EXPECT().AddDeliveries(ctx, gomock.Len(1)).Return(nil),
+		repo.EXPECT().GetDueDeliveries(ctx, gomock.Any(), gomock.Any()).Return([]entity.DueDelivery{}, nil),
+	)
+
+	if _, err := useCase.Dispatch(ctx); !errors.Is(err, errStorageProblem) {
+		t.Fatalf("expected wrapped error, got %v", err)
+	}
+
+	if _, err := useCase.Dispatch(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNotifyQueueBounded(t *testing.T) {
+	t.Parallel()
+
+	useCase, repo := webhooksUseCase(t)
+
+	ctx := context.Background()
+	w := entity.Webhook{ID: uuid.New(), UserID: 1, URL: "http://bot.local/hook", Secret: "s1"}
+	notify := func(n int) {
+		for range n {
+			useCase.Notify(entity.Notification{Kind: entity.NotificationCreated, UserID: 1, UID: uuid.New()})
+		}
+	}
+
+	notify(_queueSize + 1)
+
+	if dropped := useCase.Dropped(); dropped != 1 {
+		t.Fatalf("expected 1 dropped notification, got %d", dropped)
+	}
+
+	if dropped := useCase.Dropped(); dropped != 0 {
+		t.Fatalf("expected the count reset, got %d", dropped)
+	}
+
+	gomock.InOrder(
+		repo.EXPECT().GetWebhooks(ctx, 1).DoAndReturn(func(context.Context, int) ([]entity.Webhook, error) {
+			// notified while the queue is being stored.
+			notify(2)
+
+			return []entity.Webhook{w}, nil
+		}),
+		repo.EXPECT().AddDeliveries(ctx, gomock.Any()).Return(errStorageProblem),
+		// the queue is kept up to its size.
+		repo.EXPECT().GetWebhooks(ctx, 1).Return([]entity.Webhook{w}, nil),
+		repo.EXPECT().AddDeliveries(ctx, gomock.Len(_queueSize)).Return(nil),
+		repo.EXPECT().GetDueDeliveries(ctx, gomock.Any(), gomock.Any()).Return([]entity.DueDelivery{}, nil),
+	)
+
+	if _, err := useCase.Dispatch(ctx); !errors.Is(err, errStorageProblem) {
+		t.Fatalf("expected wrapped error, got %v", err)
+	}
+
+	notify(1)
+
+	if dropped := useCase.Dropped(); dropped != 3 {
+		t.Fatalf("expected 3 dropped notifications, got %d", dropped)
+	}
+
+	if _, err := useCase.Dispatch(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
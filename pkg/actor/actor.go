@@ -0,0 +1,18 @@
+// Package actor carries who makes a request in its context, e.g. to audit writes.
+package actor
+
+import "context"
+
+type ctxKey struct{}
+
+// With returns a copy of ctx carrying the actor.
+func With(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, name)
+}
+
+// From returns the actor of ctx, "" if there is none.
+func From(ctx context.Context) string {
+	name, _ := ctx.Value(ctxKey{}).(string)
+
+	return name
+}
@@ -29,6 +29,20 @@ func WriteTimeout(timeout time.Duration) Option {
 	}
 }
 
+// BodyLimit -.
+func BodyLimit(bytes int) Option {
+	return func(s *Server) {
+		s.bodyLimit = bytes
+	}
+}
+
+// Methods adds request methods to the standard ones, e.g. PROPFIND of WebDAV.
+func Methods(methods ...string) Option {
+	return func(s *Server) {
+		s.methods = append(s.methods, methods...)
+	}
+}
+
 // ShutdownTimeout -.
 func ShutdownTimeout(timeout time.Duration) Option {
 	return func(s *Server) {
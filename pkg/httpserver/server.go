@@ -2,6 +2,7 @@ package httpserver
 
 import (
 	"encoding/json"
+	"slices"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -12,6 +13,7 @@ const (
 	_defaultReadTimeout     = 5 * time.Second
 	_defaultWriteTimeout    = 5 * time.Second
 	_defaultShutdownTimeout = 5 * time.Second
+	_defaultBodyLimit       = 4 * 1024 * 1024
 )
 
 // Server -.
@@ -23,6 +25,8 @@ type Server struct {
 	readTimeout     time.Duration
 	writeTimeout    time.Duration
 	shutdownTimeout time.Duration
+	bodyLimit       int
+	methods         []string
 }
 
 // New returns new Server
@@ -33,6 +37,7 @@ func New(opts ...Option) *Server {
 		readTimeout:     _defaultReadTimeout,
 		writeTimeout:    _defaultWriteTimeout,
 		shutdownTimeout: _defaultShutdownTimeout,
+		bodyLimit:       _defaultBodyLimit,
 	}
 
 	for _, opt := range opts {
@@ -42,8 +47,11 @@ func New(opts ...Option) *Server {
 	app := fiber.New(fiber.Config{
 		ReadTimeout:  s.readTimeout,
 		WriteTimeout: s.writeTimeout,
+		BodyLimit:    s.bodyLimit,
 		JSONDecoder:  json.Unmarshal,
 		JSONEncoder:  json.Marshal,
+		// the slice is copied: fiber keeps DefaultMethods shared.
+		RequestMethods: append(slices.Clone(fiber.DefaultMethods), s.methods...),
 	})
 
 	s.App = app
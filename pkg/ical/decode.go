@@ -0,0 +1,269 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidData is wrapped by every Decode error.
+var ErrInvalidData = errors.New("invalid icalendar data")
+
+// _maxLineBytes bounds an unfolded content line, a malformed file must not eat the memory.
+const _maxLineBytes = 1 << 20
+
+// ParseError - where Decode failed.
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+// Error -.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// Unwrap -.
+func (e *ParseError) Unwrap() error {
+	return ErrInvalidData
+}
+
+// Decode reads the top level components of r, usually a single VCALENDAR.
+// Lines may end with CRLF or LF, folded lines are joined, names are upper-cased
+// and param values unquoted. Values are kept as is, see Unescape.
+func Decode(r io.Reader) ([]Component, error) {
+	lines := newUnfolder(r)
+
+	var (
+		top   []Component
+		stack []*Component
+	)
+
+	for {
+		line, n, err := lines.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		p, err := parseLine(line)
+		if err != nil {
+			return nil, &ParseError{Line: n, Msg: err.Error()}
+		}
+
+		p.Line = n
+
+		switch p.Name {
+		case "BEGIN":
+			stack = append(stack, &Component{Name: strings.ToUpper(p.Value), Line: n})
+		case "END":
+			if len(stack) == 0 {
+				return nil, &ParseError{Line: n, Msg: "END:" + p.Value + " without BEGIN"}
+			}
+
+			c := stack[len(stack)-1]
+			if c.Name != strings.ToUpper(p.Value) {
+				return nil, &ParseError{Line: n, Msg: fmt.Sprintf("END:%s closes BEGIN:%s of line %d", p.Value, c.Name, c.Line)}
+			}
+
+			stack = stack[:len(stack)-1]
+
+			if len(stack) == 0 {
+				top = append(top, *c)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, *c)
+			}
+		default:
+			if len(stack) == 0 {
+				return nil, &ParseError{Line: n, Msg: "property " + p.Name + " outside of a component"}
+			}
+
+			c := stack[len(stack)-1]
+			c.Props = append(c.Props, p)
+		}
+	}
+
+	if len(stack) > 0 {
+		c := stack[len(stack)-1]
+
+		return nil, &ParseError{Line: c.Line, Msg: "BEGIN:" + c.Name + " without END"}
+	}
+
+	if len(top) == 0 {
+		return nil, &ParseError{Line: lines.n, Msg: "no components"}
+	}
+
+	return top, nil
+}
+
+// parseLine splits a content line into name, params and value.
+func parseLine(line string) (Prop, error) {
+	var p Prop
+
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return Prop{}, fmt.Errorf("malformed content line %q", truncate(line))
+	}
+
+	p.Name = strings.ToUpper(line[:i])
+	rest := line[i:]
+
+	for rest[0] == ';' {
+		rest = rest[1:]
+
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return Prop{}, fmt.Errorf("malformed param of %s", p.Name)
+		}
+
+		param := Param{Name: strings.ToUpper(rest[:eq])}
+		rest = rest[eq+1:]
+
+		// a value list, each value may be quoted.
+		var values []string
+
+		for {
+			var value string
+
+			if strings.HasPrefix(rest, `"`) {
+				end := strings.IndexByte(rest[1:], '"')
+				if end < 0 {
+					return Prop{}, fmt.Errorf("unterminated quote in param %s of %s", param.Name, p.Name)
+				}
+
+				value, rest = rest[1:end+1], rest[end+2:]
+			} else {
+				end := strings.IndexAny(rest, ",;:")
+				if end < 0 {
+					return Prop{}, fmt.Errorf("%s has no value", p.Name)
+				}
+
+				value, rest = rest[:end], rest[end:]
+			}
+
+			values = append(values, value)
+
+			if !strings.HasPrefix(rest, ",") {
+				break
+			}
+
+			rest = rest[1:]
+		}
+
+		if rest == "" {
+			return Prop{}, fmt.Errorf("%s has no value", p.Name)
+		}
+
+		param.Value = strings.Join(values, ",")
+		p.Params = append(p.Params, param)
+	}
+
+	if rest[0] != ':' {
+		return Prop{}, fmt.Errorf("malformed params of %s", p.Name)
+	}
+
+	p.Value = rest[1:]
+
+	return p, nil
+}
+
+// unfolder yields logical lines: physical ones joined where continuation lines
+// start with a space or a tab.
+type unfolder struct {
+	s *bufio.Scanner
+	// n is the number of the last physical line read.
+	n int
+
+	pending    string
+	pendingN   int
+	hasPending bool
+}
+
+func newUnfolder(r io.Reader) *unfolder {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), _maxLineBytes)
+
+	return &unfolder{s: s}
+}
+
+// next returns a logical line and the number of its first physical line.
+func (u *unfolder) next() (string, int, error) {
+	var (
+		sb    strings.Builder
+		start int
+	)
+
+	if u.hasPending {
+		sb.WriteString(u.pending)
+		start = u.pendingN
+		u.hasPending = false
+	}
+
+	for u.s.Scan() {
+		u.n++
+
+		line := strings.TrimSuffix(u.s.Text(), "\r")
+		if u.n == 1 {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+
+		if start > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			if sb.Len()+len(line) > _maxLineBytes {
+				return "", 0, &ParseError{Line: start, Msg: "content line too long"}
+			}
+
+			sb.WriteString(line[1:])
+
+			continue
+		}
+
+		if start > 0 {
+			u.pending, u.pendingN, u.hasPending = line, u.n, true
+
+			return sb.String(), start, nil
+		}
+
+		sb.WriteString(line)
+		start = u.n
+	}
+
+	if err := u.s.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return "", 0, &ParseError{Line: u.n + 1, Msg: "content line too long"}
+		}
+
+		return "", 0, err
+	}
+
+	if start > 0 {
+		return sb.String(), start, nil
+	}
+
+	return "", 0, io.EOF
+}
+
+func truncate(s string) string {
+	const limit = 40
+
+	if len(s) <= limit {
+		return s
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+
+	return s[:cut] + "..."
+}
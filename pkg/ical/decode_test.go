@@ -0,0 +1,185 @@
+package ical_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/pkg/ical"
+)
+
+func TestDecode(t *testing.T) {
+	// a BOM, LF line endings, a folded line and a quoted param.
+	data := "\uFEFFBEGIN:VCALENDAR\n" +
+		"begin:vevent\n" +
+		"UID:1\n" +
+		"SUMMARY;LANGUAGE=en:a long\n" +
+		"  summary\n" +
+		"\tcontinued\n" +
+		"ATTENDEE;CN=\"Doe, Bob\";ROLE=REQ-PARTICIPANT:mailto:bob@example.com\n" +
+		"END:VEVENT\n" +
+		"END:VCALENDAR\n"
+
+	calendars, err := ical.Decode(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(calendars) != 1 || len(calendars[0].Components) != 1 {
+		t.Fatalf("unexpected components %+v", calendars)
+	}
+
+	event := calendars[0].Components[0]
+	if event.Name != "VEVENT" || event.Line != 2 {
+		t.Fatalf("expected VEVENT at line 2, got %s at %d", event.Name, event.Line)
+	}
+
+	summary, ok := event.Prop("SUMMARY")
+	if !ok || summary.Value != "a long summarycontinued" || summary.Line != 4 || summary.Param("LANGUAGE") != "en" {
+		t.Fatalf("unexpected SUMMARY %+v", summary)
+	}
+
+	attendee, _ := event.Prop("ATTENDEE")
+	if attendee.Param("CN") != "Doe, Bob" || attendee.Param("ROLE") != "REQ-PARTICIPANT" || attendee.Value != "mailto:bob@example.com" {
+		t.Fatalf("unexpected ATTENDEE %+v", attendee)
+	}
+
+	if attendee.Line != 7 {
+		t.Fatalf("expected ATTENDEE at line 7, got %d", attendee.Line)
+	}
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	c := ical.Component{Name: "VCALENDAR"}
+	event := ical.Component{Name: "VEVENT"}
+	event.Add(ical.Text("SUMMARY", strings.Repeat("встреча; обед, ужин\n", 10)))
+	c.AddComponent(event)
+
+	calendars, err := ical.Decode(strings.NewReader(encode(t, c)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	summary, _ := calendars[0].Components[0].Prop("SUMMARY")
+	if got := ical.Unescape(summary.Value); got != strings.Repeat("встреча; обед, ужин\n", 10) {
+		t.Fatalf("unexpected SUMMARY %q", got)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		line int
+	}{
+		{"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VCALENDAR\r\n", 3},
+		{"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:1\r\n", 2},
+		{"UID:1\r\n", 1},
+		{"BEGIN:VCALENDAR\r\n\r\nno colon here\r\nEND:VCALENDAR\r\n", 3},
+		{"BEGIN:VCALENDAR\r\nATTENDEE;CN=\"Bob:mailto:bob@example.com\r\nEND:VCALENDAR\r\n", 2},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		_, err := ical.Decode(strings.NewReader(tt.data))
+
+		var parseErr *ical.ParseError
+		if !errors.As(err, &parseErr) || !errors.Is(err, ical.ErrInvalidData) {
+			t.Fatalf("%q: expected ParseError, got %v", tt.data, err)
+		}
+
+		if parseErr.Line != tt.line {
+			t.Fatalf("%q: expected line %d, got %v", tt.data, tt.line, err)
+		}
+	}
+}
+
+func TestUnescape(t *testing.T) {
+	if got := ical.Unescape(`a\\b\;c\,d\ne\Nf\`); got != "a\\b;c,d\ne\nf\\" {
+		t.Fatalf("unexpected %q", got)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected ical.Duration
+	}{
+		{"P1D", ical.Duration{Days: 1}},
+		{"P2W", ical.Duration{Days: 14}},
+		{"PT1H30M", ical.Duration{Clock: 90 * time.Minute}},
+		{"P1DT12H", ical.Duration{Days: 1, Clock: 12 * time.Hour}},
+		{"-PT15M", ical.Duration{Clock: -15 * time.Minute}},
+	}
+
+	for _, tt := range tests {
+		got, err := ical.ParseDuration(tt.s)
+		if err != nil || got != tt.expected {
+			t.Fatalf("%s: expected %+v, got %+v %v", tt.s, tt.expected, got, err)
+		}
+	}
+
+	for _, s := range []string{"", "P", "1D", "PT", "P1H", "PT1D", "P1", "P99999999999999999999D"} {
+		if _, err := ical.ParseDuration(s); err == nil {
+			t.Fatalf("%q: expected error", s)
+		}
+	}
+}
+
+func TestTimes(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VTIMEZONE\r\nTZID:Custom Zone\r\nX-LIC-LOCATION:America/New_York\r\nEND:VTIMEZONE\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;TZID=/mozilla.org/20050126_1/Europe/Berlin:20260302T090000\r\n" +
+		"DTEND;TZID=Custom Zone:20260302T090000\r\n" +
+		"EXDATE;TZID=W. Europe Standard Time:20260309T090000,20260316T090000\r\n" +
+		"RDATE;VALUE=DATE:20260401\r\n" +
+		"DUE:20260302T090000\r\n" +
+		"DTSTAMP:20260302T090000Z\r\n" +
+		"RECURRENCE-ID;TZID=Mars/Olympus:20260302T090000\r\n" +
+		"END:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	calendars, err := ical.Decode(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	locs := ical.NewLocations(calendars[0])
+	event := calendars[0].Components[1]
+
+	tests := []struct {
+		prop     string
+		expected string
+		date     bool
+	}{
+		{"DTSTART", "2026-03-02T09:00:00+01:00", false},
+		{"DTEND", "2026-03-02T09:00:00-05:00", false},
+		{"EXDATE", "2026-03-09T09:00:00+01:00", false},
+		{"RDATE", "2026-04-01T00:00:00Z", true},
+		{"DUE", "2026-03-02T09:00:00+10:00", false},
+		{"DTSTAMP", "2026-03-02T09:00:00Z", false},
+	}
+
+	vvo, err := time.LoadLocation("Asia/Vladivostok")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tt := range tests {
+		p, _ := event.Prop(tt.prop)
+
+		times, date, err := p.Times(locs, vvo)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.prop, err)
+		}
+
+		if got := times[0].Format(time.RFC3339); got != tt.expected || date != tt.date {
+			t.Fatalf("%s: expected %s %v, got %s %v", tt.prop, tt.expected, tt.date, got, date)
+		}
+	}
+
+	p, _ := event.Prop("RECURRENCE-ID")
+	if _, _, err = p.Times(locs, vvo); err == nil {
+		t.Fatalf("expected unknown TZID error")
+	}
+}
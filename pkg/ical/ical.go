@@ -0,0 +1,181 @@
+// Package ical reads and writes iCalendar (RFC 5545) data: components, content lines
+// with folding and escaping, value formats and VTIMEZONE definitions from Go's zone database.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// _maxLine - content lines longer than 75 octets are folded (RFC 5545, 3.1).
+const _maxLine = 75
+
+// Component - a BEGIN/END block such as VCALENDAR or VEVENT.
+// Line is where it begins in decoded data.
+type Component struct {
+	Name       string
+	Props      []Prop
+	Components []Component
+	Line       int
+}
+
+// Prop - a content line. Value is written as is, TEXT values need Escape, see Text.
+// Line is where it begins in decoded data.
+type Prop struct {
+	Name   string
+	Params []Param
+	Value  string
+	Line   int
+}
+
+// Param -.
+type Param struct {
+	Name  string
+	Value string
+}
+
+// NewProp -.
+func NewProp(name, value string, params ...Param) Prop {
+	return Prop{Name: name, Params: params, Value: value}
+}
+
+// Text returns a property of TEXT value s.
+func Text(name, s string) Prop {
+	return Prop{Name: name, Value: Escape(s)}
+}
+
+// Add appends properties.
+func (c *Component) Add(props ...Prop) {
+	c.Props = append(c.Props, props...)
+}
+
+// AddComponent appends nested components.
+func (c *Component) AddComponent(components ...Component) {
+	c.Components = append(c.Components, components...)
+}
+
+// Prop returns the first property named name.
+func (c Component) Prop(name string) (Prop, bool) {
+	for _, p := range c.Props {
+		if p.Name == name {
+			return p, true
+		}
+	}
+
+	return Prop{}, false
+}
+
+// All returns properties named name.
+func (c Component) All(name string) []Prop {
+	var props []Prop
+
+	for _, p := range c.Props {
+		if p.Name == name {
+			props = append(props, p)
+		}
+	}
+
+	return props
+}
+
+// Param returns the value of the param named name, empty if there is none.
+func (p Prop) Param(name string) string {
+	for _, param := range p.Params {
+		if param.Name == name {
+			return param.Value
+		}
+	}
+
+	return ""
+}
+
+// Escape escapes a TEXT value: backslashes, semicolons, commas and newlines.
+func Escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		`;`, `\;`,
+		`,`, `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// Encode writes c to w, lines end with CRLF and are folded at 75 octets.
+func Encode(w io.Writer, c Component) error {
+	bw := bufio.NewWriter(w)
+
+	encode(bw, c)
+
+	return bw.Flush()
+}
+
+func encode(w *bufio.Writer, c Component) {
+	writeLine(w, "BEGIN:"+c.Name)
+
+	for _, p := range c.Props {
+		writeLine(w, p.String())
+	}
+
+	for _, nested := range c.Components {
+		encode(w, nested)
+	}
+
+	writeLine(w, "END:"+c.Name)
+}
+
+// String returns the unfolded content line.
+func (p Prop) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(p.Name)
+
+	for _, param := range p.Params {
+		sb.WriteByte(';')
+		sb.WriteString(param.Name)
+		sb.WriteByte('=')
+		sb.WriteString(quoteParam(param.Value))
+	}
+
+	sb.WriteByte(':')
+	sb.WriteString(p.Value)
+
+	return sb.String()
+}
+
+// quoteParam quotes values with characters that separate params,
+// DQUOTE itself cant appear in a param value and is dropped.
+func quoteParam(v string) string {
+	v = strings.ReplaceAll(v, `"`, "")
+
+	if strings.ContainsAny(v, ":;,") {
+		return `"` + v + `"`
+	}
+
+	return v
+}
+
+// writeLine folds line into chunks of at most 75 octets, continuation lines
+// start with a space. Multi-octet characters are never split.
+func writeLine(w *bufio.Writer, line string) {
+	limit := _maxLine
+
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+
+		line = line[cut:]
+		// the leading space counts towards the limit.
+		limit = _maxLine - 1
+	}
+
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
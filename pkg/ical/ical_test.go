@@ -0,0 +1,129 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/andreyxaxa/calendar/pkg/ical"
+)
+
+func encode(t *testing.T, c ical.Component) string {
+	t.Helper()
+
+	var sb strings.Builder
+
+	if err := ical.Encode(&sb, c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return sb.String()
+}
+
+func TestEscape(t *testing.T) {
+	got := ical.Escape("a\\b;c,d\r\ne\nf")
+	expected := `a\\b\;c\,d\ne\nf`
+
+	if got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestFolding(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"ascii", strings.Repeat("abcdefghij", 20)},
+		{"cyrillic", strings.Repeat("встреча ", 30)},
+		{"emoji", strings.Repeat("🎉", 50)},
+	}
+
+	for _, tt := range tests {
+		c := ical.Component{Name: "VEVENT"}
+		c.Add(ical.Text("SUMMARY", tt.text))
+
+		// BEGIN, folded SUMMARY, END.
+		lines := strings.Split(strings.TrimSuffix(encode(t, c), "\r\n"), "\r\n")
+		folded := lines[1 : len(lines)-1]
+
+		var unfolded strings.Builder
+
+		for i, line := range folded {
+			if len(line) > 75 {
+				t.Fatalf("%s: line %d is %d octets long", tt.name, i, len(line))
+			}
+
+			if i > 0 {
+				if line[0] != ' ' {
+					t.Fatalf("%s: continuation line %d doesnt start with a space", tt.name, i)
+				}
+
+				line = line[1:]
+			}
+
+			if !utf8.ValidString(line) {
+				t.Fatalf("%s: line %d splits a character", tt.name, i)
+			}
+
+			unfolded.WriteString(line)
+		}
+
+		if len(folded) < 2 {
+			t.Fatalf("%s: expected the line to be folded", tt.name)
+		}
+
+		if expected := "SUMMARY:" + tt.text; unfolded.String() != expected {
+			t.Fatalf("%s: expected %q, got %q", tt.name, expected, unfolded.String())
+		}
+	}
+}
+
+func TestParamQuoting(t *testing.T) {
+	p := ical.NewProp("ATTENDEE", "mailto:bob@example.com", ical.Param{Name: "CN", Value: `Doe, "Bob"`})
+
+	if expected := `ATTENDEE;CN="Doe, Bob":mailto:bob@example.com`; p.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, p.String())
+	}
+}
+
+func TestDateTime(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		prop     ical.Prop
+		expected string
+	}{
+		{ical.DateTime("DTSTART", time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)), "DTSTART:20260302T090000Z"},
+		{
+			ical.DateTime("EXDATE", time.Date(2026, 3, 2, 9, 0, 0, 0, ny), time.Date(2026, 3, 9, 9, 0, 0, 0, ny)),
+			"EXDATE;TZID=America/New_York:20260302T090000,20260309T090000",
+		},
+		{ical.Date("DTSTART", time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)), "DTSTART;VALUE=DATE:20260302"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.prop.String(); got != tt.expected {
+			t.Fatalf("expected %q, got %q", tt.expected, got)
+		}
+	}
+}
+
+func TestUTCOffset(t *testing.T) {
+	tests := map[int]string{
+		0:                  "+0000",
+		3 * 3600:           "+0300",
+		-(4*3600 + 30*60):  "-0430",
+		5*3600 + 30*60 + 7: "+053007",
+	}
+
+	for offset, expected := range tests {
+		if got := ical.UTCOffset(offset); got != expected {
+			t.Fatalf("%d: expected %q, got %q", offset, expected, got)
+		}
+	}
+}
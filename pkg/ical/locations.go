@@ -0,0 +1,136 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// _windowsZones maps Windows zone names, used by Outlook and Exchange as TZID,
+// to IANA ones. Only the common zones are listed.
+var _windowsZones = map[string]string{
+	"Dateline Standard Time":          "Etc/GMT+12",
+	"Hawaiian Standard Time":          "Pacific/Honolulu",
+	"Alaskan Standard Time":           "America/Anchorage",
+	"Pacific Standard Time":           "America/Los_Angeles",
+	"Mountain Standard Time":          "America/Denver",
+	"US Mountain Standard Time":       "America/Phoenix",
+	"Central Standard Time":           "America/Chicago",
+	"Eastern Standard Time":           "America/New_York",
+	"Atlantic Standard Time":          "America/Halifax",
+	"SA Pacific Standard Time":        "America/Bogota",
+	"E. South America Standard Time":  "America/Sao_Paulo",
+	"Argentina Standard Time":         "America/Argentina/Buenos_Aires",
+	"UTC":                             "UTC",
+	"GMT Standard Time":               "Europe/London",
+	"Greenwich Standard Time":         "Atlantic/Reykjavik",
+	"W. Europe Standard Time":         "Europe/Berlin",
+	"Central Europe Standard Time":    "Europe/Budapest",
+	"Central European Standard Time":  "Europe/Warsaw",
+	"Romance Standard Time":           "Europe/Paris",
+	"E. Europe Standard Time":         "Europe/Chisinau",
+	"FLE Standard Time":               "Europe/Kiev",
+	"GTB Standard Time":               "Europe/Bucharest",
+	"Israel Standard Time":            "Asia/Jerusalem",
+	"Turkey Standard Time":            "Europe/Istanbul",
+	"Kaliningrad Standard Time":       "Europe/Kaliningrad",
+	"Russian Standard Time":           "Europe/Moscow",
+	"Belarus Standard Time":           "Europe/Minsk",
+	"Arabian Standard Time":           "Asia/Dubai",
+	"Samara Standard Time":            "Europe/Samara",
+	"Ekaterinburg Standard Time":      "Asia/Yekaterinburg",
+	"West Asia Standard Time":         "Asia/Tashkent",
+	"India Standard Time":             "Asia/Kolkata",
+	"Central Asia Standard Time":      "Asia/Almaty",
+	"N. Central Asia Standard Time":   "Asia/Novosibirsk",
+	"North Asia Standard Time":        "Asia/Krasnoyarsk",
+	"SE Asia Standard Time":           "Asia/Bangkok",
+	"China Standard Time":             "Asia/Shanghai",
+	"North Asia East Standard Time":   "Asia/Irkutsk",
+	"Singapore Standard Time":         "Asia/Singapore",
+	"Tokyo Standard Time":             "Asia/Tokyo",
+	"Korea Standard Time":             "Asia/Seoul",
+	"Yakutsk Standard Time":           "Asia/Yakutsk",
+	"Vladivostok Standard Time":       "Asia/Vladivostok",
+	"Magadan Standard Time":           "Asia/Magadan",
+	"Russia Time Zone 11":             "Asia/Kamchatka",
+	"AUS Eastern Standard Time":       "Australia/Sydney",
+	"E. Australia Standard Time":      "Australia/Brisbane",
+	"Cen. Australia Standard Time":    "Australia/Adelaide",
+	"W. Australia Standard Time":      "Australia/Perth",
+	"New Zealand Standard Time":       "Pacific/Auckland",
+	"South Africa Standard Time":      "Africa/Johannesburg",
+	"Egypt Standard Time":             "Africa/Cairo",
+	"Pacific SA Standard Time":        "America/Santiago",
+	"Canada Central Standard Time":    "America/Regina",
+	"Central America Standard Time":   "America/Guatemala",
+	"Central Standard Time (Mexico)":  "America/Mexico_City",
+	"Newfoundland Standard Time":      "America/St_Johns",
+	"Azores Standard Time":            "Atlantic/Azores",
+	"Morocco Standard Time":           "Africa/Casablanca",
+	"W. Central Africa Standard Time": "Africa/Lagos",
+	"E. Africa Standard Time":         "Africa/Nairobi",
+}
+
+// Locations resolves TZIDs of a calendar. A TZID is tried as an IANA name, with
+// a prefix such as /mozilla.org/20050126_1/Europe/Berlin, as the X-LIC-LOCATION
+// of its VTIMEZONE and as a Windows zone name.
+type Locations struct {
+	aliases map[string]string
+	loaded  map[string]*time.Location
+}
+
+// NewLocations collects the VTIMEZONEs of cal.
+func NewLocations(cal Component) *Locations {
+	l := &Locations{
+		aliases: make(map[string]string),
+		loaded:  make(map[string]*time.Location),
+	}
+
+	for _, c := range cal.Components {
+		if c.Name != "VTIMEZONE" {
+			continue
+		}
+
+		tzid, ok := c.Prop("TZID")
+		if !ok {
+			continue
+		}
+
+		if location, ok := c.Prop("X-LIC-LOCATION"); ok {
+			l.aliases[tzid.Value] = location.Value
+		}
+	}
+
+	return l
+}
+
+// Load -.
+func (l *Locations) Load(tzid string) (*time.Location, error) {
+	if loc, ok := l.loaded[tzid]; ok {
+		return loc, nil
+	}
+
+	candidates := []string{l.aliases[tzid], tzid, _windowsZones[tzid]}
+
+	// a prefixed name ends with the IANA one, which has up to three parts.
+	parts := strings.Split(strings.Trim(tzid, "/"), "/")
+	for n := min(3, len(parts)); n > 0; n-- {
+		candidates = append(candidates, strings.Join(parts[len(parts)-n:], "/"))
+	}
+
+	for _, name := range candidates {
+		// "" and "Local" would silently mean UTC or the server zone.
+		if name == "" || name == "Local" {
+			continue
+		}
+
+		if loc, err := time.LoadLocation(name); err == nil {
+			l.loaded[tzid] = loc
+
+			return loc, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown TZID %q", tzid)
+}
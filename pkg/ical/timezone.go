@@ -0,0 +1,167 @@
+package ical
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+var _weekdays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// transition - an offset change of a zone at onset.
+type transition struct {
+	onset    time.Time
+	from, to int
+	name     string
+	dst      bool
+}
+
+// local returns the onset on the wall clock before the change, as VTIMEZONE wants it.
+func (t transition) local() time.Time {
+	return t.onset.In(time.FixedZone("", t.from))
+}
+
+// yearly describes a transition the way tzdata rules do, e.g. "second sunday
+// of march at 02:00": transitions with equal yearly happen by the same rule.
+type yearly struct {
+	from, to int
+	name     string
+	dst      bool
+	month    time.Month
+	weekday  time.Weekday
+	n        int
+	clock    time.Duration
+}
+
+func (t transition) yearly() yearly {
+	local := t.local()
+	day := local.Day()
+
+	n := (day-1)/7 + 1
+	if daysIn(local) < day+7 {
+		n = -1
+	}
+
+	midnight := time.Date(local.Year(), local.Month(), day, 0, 0, 0, 0, local.Location())
+
+	return yearly{
+		from:    t.from,
+		to:      t.to,
+		name:    t.name,
+		dst:     t.dst,
+		month:   local.Month(),
+		weekday: local.Weekday(),
+		n:       n,
+		clock:   local.Sub(midnight),
+	}
+}
+
+// run - transitions by the same rule in consecutive years.
+type run struct {
+	first, last transition
+	count       int
+}
+
+// Timezone returns a VTIMEZONE describing loc from from to to. Go does not expose
+// zone rules, so offset changes are looked up one by one with time.Time.ZoneBounds,
+// and changes repeating every year become a single observance with a yearly RRULE.
+// The year after to is checked too, so rules still in force at to are left open.
+func Timezone(loc *time.Location, from, to time.Time) Component {
+	start := from.In(loc)
+	name, offset := start.Zone()
+
+	tz := Component{Name: "VTIMEZONE"}
+	tz.Add(NewProp("TZID", loc.String()))
+	initial := transition{onset: from, from: offset, to: offset, name: name, dst: start.IsDST()}
+	tz.AddComponent(observance(run{first: initial, last: initial, count: 1}, false))
+
+	changes := transitions(loc, from, to.AddDate(1, 0, 0))
+	if len(changes) == 0 {
+		return tz
+	}
+
+	lastYear := changes[len(changes)-1].local().Year()
+
+	var runs []*run
+
+	open := make(map[yearly]*run)
+
+	for _, t := range changes {
+		rule := t.yearly()
+
+		if r, ok := open[rule]; ok && r.last.local().Year() == t.local().Year()-1 {
+			r.last = t
+			r.count++
+
+			continue
+		}
+
+		r := &run{first: t, last: t, count: 1}
+		open[rule] = r
+		runs = append(runs, r)
+	}
+
+	for _, r := range runs {
+		tz.AddComponent(observance(*r, r.last.local().Year() == lastYear))
+	}
+
+	return tz
+}
+
+// observance returns a STANDARD or DAYLIGHT component for the run. A run of
+// several years gets a yearly RRULE, ending with its last change unless endless.
+func observance(r run, endless bool) Component {
+	t := r.first
+
+	c := Component{Name: "STANDARD"}
+	if t.dst {
+		c.Name = "DAYLIGHT"
+	}
+
+	c.Add(
+		NewProp("DTSTART", t.local().Format(LocalTimeFormat)),
+		NewProp("TZOFFSETFROM", UTCOffset(t.from)),
+		NewProp("TZOFFSETTO", UTCOffset(t.to)),
+	)
+
+	if r.count > 1 {
+		rule := t.yearly()
+		rrule := fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", rule.month, rule.n, _weekdays[rule.weekday])
+
+		if !endless {
+			rrule += ";UNTIL=" + r.last.onset.UTC().Format(UTCTimeFormat)
+		}
+
+		c.Add(NewProp("RRULE", rrule))
+	}
+
+	if t.name != "" {
+		c.Add(Text("TZNAME", t.name))
+	}
+
+	return c
+}
+
+// transitions returns offset changes of loc in (from, to), in order.
+func transitions(loc *time.Location, from, to time.Time) []transition {
+	var res []transition
+
+	t := from.In(loc)
+
+	for {
+		_, end := t.ZoneBounds()
+		if end.IsZero() || !end.Before(to) {
+			return slices.Clip(res)
+		}
+
+		_, prev := t.Zone()
+		t = end.In(loc)
+		name, offset := t.Zone()
+
+		res = append(res, transition{onset: end.UTC(), from: prev, to: offset, name: name, dst: t.IsDST()})
+	}
+}
+
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,66 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/pkg/ical"
+)
+
+func TestTimezone(t *testing.T) {
+	tests := []struct {
+		zone     string
+		expected []string
+		absent   []string
+	}{
+		{
+			// the 2007 rule change ends the old rules and starts new ones.
+			zone: "America/New_York",
+			expected: []string{
+				"BEGIN:DAYLIGHT\r\nDTSTART:20050403T020000\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\n" +
+					"RRULE:FREQ=YEARLY;BYMONTH=4;BYDAY=1SU;UNTIL=20060402T070000Z\r\nTZNAME:EDT\r\n",
+				"BEGIN:DAYLIGHT\r\nDTSTART:20070311T020000\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\n" +
+					"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU\r\nTZNAME:EDT\r\n",
+				"BEGIN:STANDARD\r\nDTSTART:20071104T020000\r\nTZOFFSETFROM:-0400\r\nTZOFFSETTO:-0500\r\n" +
+					"RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU\r\nTZNAME:EST\r\n",
+			},
+		},
+		{
+			// no changes since 2014.
+			zone:     "Europe/Moscow",
+			expected: []string{"BEGIN:STANDARD\r\nDTSTART:20150101T030000\r\nTZOFFSETFROM:+0300\r\nTZOFFSETTO:+0300\r\nTZNAME:MSK\r\n"},
+			absent:   []string{"RRULE", "DAYLIGHT"},
+		},
+	}
+
+	for _, tt := range tests {
+		loc, err := time.LoadLocation(tt.zone)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		from := time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC)
+		if len(tt.absent) > 0 {
+			from = time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+		}
+
+		got := encode(t, ical.Timezone(loc, from, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+		if !strings.HasPrefix(got, "BEGIN:VTIMEZONE\r\nTZID:"+tt.zone+"\r\n") {
+			t.Fatalf("%s: unexpected start of %q", tt.zone, got)
+		}
+
+		for _, s := range tt.expected {
+			if !strings.Contains(got, s) {
+				t.Fatalf("%s: expected %q in:\n%s", tt.zone, s, got)
+			}
+		}
+
+		for _, s := range tt.absent {
+			if strings.Contains(got, s) {
+				t.Fatalf("%s: unexpected %q in:\n%s", tt.zone, s, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Value formats.
+const (
+	DateFormat      = "20060102"
+	LocalTimeFormat = "20060102T150405"
+	UTCTimeFormat   = "20060102T150405Z"
+)
+
+// Date returns a DATE property, e.g. DTSTART;VALUE=DATE:20260102.
+func Date(name string, days ...time.Time) Prop {
+	return NewProp(name, join(days, DateFormat), Param{Name: "VALUE", Value: "DATE"})
+}
+
+// DateTime returns a DATE-TIME property: in UTC form for times in UTC,
+// otherwise as the wall clock with a TZID of the location.
+func DateTime(name string, times ...time.Time) Prop {
+	if len(times) == 0 || times[0].Location() == time.UTC {
+		utc := make([]time.Time, len(times))
+		for i, t := range times {
+			utc[i] = t.UTC()
+		}
+
+		return NewProp(name, join(utc, UTCTimeFormat))
+	}
+
+	loc := times[0].Location()
+
+	local := make([]time.Time, len(times))
+	for i, t := range times {
+		local[i] = t.In(loc)
+	}
+
+	return NewProp(name, join(local, LocalTimeFormat), Param{Name: "TZID", Value: loc.String()})
+}
+
+// UTCOffset formats an offset in seconds east of UTC as a UTC-OFFSET value, e.g. -0500.
+func UTCOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+
+	h, m, s := offset/3600, offset/60%60, offset%60
+	if s != 0 {
+		return fmt.Sprintf("%c%02d%02d%02d", sign, h, m, s)
+	}
+
+	return fmt.Sprintf("%c%02d%02d", sign, h, m)
+}
+
+func join(times []time.Time, layout string) string {
+	values := make([]string, len(times))
+	for i, t := range times {
+		values[i] = t.Format(layout)
+	}
+
+	return strings.Join(values, ",")
+}
+
+// Unescape reverses Escape.
+func Unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var sb strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			sb.WriteByte(s[i])
+
+			continue
+		}
+
+		i++
+
+		switch s[i] {
+		case 'n', 'N':
+			sb.WriteByte('\n')
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+
+	return sb.String()
+}
+
+// Times parses a DATE or DATE-TIME property, possibly a list as in EXDATE.
+// Dates come as UTC midnight with date set. Times with a TZID are resolved by
+// locs, floating ones (neither TZID nor Z) are taken in floating.
+func (p Prop) Times(locs *Locations, floating *time.Location) (times []time.Time, date bool, err error) {
+	date = p.Param("VALUE") == "DATE"
+
+	loc := floating
+
+	if tzid := p.Param("TZID"); tzid != "" && !date {
+		if loc, err = locs.Load(tzid); err != nil {
+			return nil, false, err
+		}
+	}
+
+	for _, value := range strings.Split(p.Value, ",") {
+		value = strings.TrimSpace(value)
+
+		var t time.Time
+
+		switch {
+		case date || len(value) == len(DateFormat):
+			date = true
+			t, err = time.Parse(DateFormat, value)
+		case strings.HasSuffix(value, "Z"):
+			t, err = time.Parse(UTCTimeFormat, value)
+		default:
+			t, err = time.ParseInLocation(LocalTimeFormat, value, loc)
+		}
+
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: invalid date or time %q", p.Name, value)
+		}
+
+		times = append(times, t)
+	}
+
+	return times, date, nil
+}
+
+// _maxDurationNum keeps duration parts far from overflowing time.Duration.
+const _maxDurationNum = 1_000_000
+
+// Duration - a DURATION value. Days are nominal: a day in a zone with DST may
+// last 23 or 25 hours, so they are applied with AddDate.
+type Duration struct {
+	Days  int
+	Clock time.Duration
+}
+
+// Add returns t moved by d.
+func (d Duration) Add(t time.Time) time.Time {
+	return t.AddDate(0, 0, d.Days).Add(d.Clock)
+}
+
+// ParseDuration parses a DURATION value such as P1D, PT1H30M or -P2W.
+func ParseDuration(s string) (Duration, error) {
+	invalid := fmt.Errorf("invalid duration %q", s)
+
+	sign := 1
+
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	s, ok := strings.CutPrefix(s, "P")
+	if !ok || s == "" {
+		return Duration{}, invalid
+	}
+
+	var (
+		d      Duration
+		inTime bool
+		num    int
+		digits bool
+		parts  int
+	)
+
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			if num > _maxDurationNum {
+				return Duration{}, invalid
+			}
+
+			num, digits = num*10+int(c-'0'), true
+
+			continue
+		case c == 'T' && !inTime && !digits:
+			inTime = true
+
+			continue
+		case !digits:
+			return Duration{}, invalid
+		}
+
+		switch {
+		case c == 'W' && !inTime:
+			d.Days += 7 * num
+		case c == 'D' && !inTime:
+			d.Days += num
+		case c == 'H' && inTime:
+			d.Clock += time.Duration(num) * time.Hour
+		case c == 'M' && inTime:
+			d.Clock += time.Duration(num) * time.Minute
+		case c == 'S' && inTime:
+			d.Clock += time.Duration(num) * time.Second
+		default:
+			return Duration{}, invalid
+		}
+
+		num, digits = 0, false
+		parts++
+	}
+
+	if digits || parts == 0 {
+		return Duration{}, invalid
+	}
+
+	d.Days *= sign
+	d.Clock *= time.Duration(sign)
+
+	return d, nil
+}
@@ -0,0 +1,84 @@
+// Package mergepatch reads JSON Merge Patch (RFC 7396) documents of flat resources:
+// a member of the patch replaces the field of the target, null removes it,
+// absent members leave fields as they are.
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ContentType - media type of merge patch documents.
+const ContentType = "application/merge-patch+json"
+
+var (
+	// ErrNotObject - the patch is not a JSON object. By RFC 7396 it would replace
+	// the whole target, a flat resource cant take that.
+	ErrNotObject = errors.New("merge patch must be a JSON object")
+	// ErrUnknownField -.
+	ErrUnknownField = errors.New("unknown field")
+)
+
+var _null = []byte("null")
+
+// Patch - members of a merge patch by name.
+type Patch map[string]json.RawMessage
+
+// Parse reads a merge patch, fields are the members the target has.
+func Parse(data []byte, fields ...string) (Patch, error) {
+	var p Patch
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return nil, ErrNotObject
+	}
+
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("mergepatch - Parse - json.Unmarshal: %w", err)
+	}
+
+	for name := range p {
+		if !slices.Contains(fields, name) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownField, name)
+		}
+	}
+
+	return p, nil
+}
+
+// Has reports whether the patch changes the field.
+func (p Patch) Has(name string) bool {
+	_, ok := p[name]
+
+	return ok
+}
+
+// HasAny reports whether the patch changes any of the fields.
+func (p Patch) HasAny(names ...string) bool {
+	return slices.ContainsFunc(names, p.Has)
+}
+
+// Removes reports whether the patch sets the field to null.
+func (p Patch) Removes(name string) bool {
+	value, ok := p[name]
+
+	return ok && bytes.Equal(value, _null)
+}
+
+// Decode decodes the new value of the field into v. v is left as is when
+// the field is absent or removed, so it should hold the default value.
+func (p Patch) Decode(name string, v any) error {
+	value, ok := p[name]
+	if !ok || bytes.Equal(value, _null) {
+		return nil
+	}
+
+	if err := json.Unmarshal(value, v); err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	return nil
+}
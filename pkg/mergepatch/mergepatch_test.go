@@ -0,0 +1,69 @@
+package mergepatch_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/andreyxaxa/calendar/pkg/mergepatch"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	p, err := mergepatch.Parse([]byte(` {"text": "new", "end": null} `), "text", "start", "end")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !p.Has("text") || !p.Has("end") || p.Has("start") {
+		t.Fatalf("unexpected members: %v", p)
+	}
+
+	if !p.HasAny("start", "end") || p.HasAny("start") {
+		t.Fatal("HasAny mismatch")
+	}
+
+	if p.Removes("text") || !p.Removes("end") || p.Removes("start") {
+		t.Fatal("Removes mismatch")
+	}
+
+	text, end := "old", "kept"
+
+	if err = p.Decode("text", &text); err != nil || text != "new" {
+		t.Fatalf("expected new, got %q (%v)", text, err)
+	}
+
+	if err = p.Decode("end", &end); err != nil || end != "kept" {
+		t.Fatalf("null must leave the value, got %q (%v)", end, err)
+	}
+
+	var n int
+	if err = p.Decode("text", &n); err == nil {
+		t.Fatal("expected type error")
+	}
+}
+
+func TestParseErr(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		data string
+		err  error
+	}{
+		{``, mergepatch.ErrNotObject},
+		{`null`, mergepatch.ErrNotObject},
+		{`["text"]`, mergepatch.ErrNotObject},
+		{`"text"`, mergepatch.ErrNotObject},
+		{`{"title": "x"}`, mergepatch.ErrUnknownField},
+		{`{"text": }`, nil},
+	} {
+		_, err := mergepatch.Parse([]byte(tc.data), "text")
+		if err == nil {
+			t.Fatalf("%s: expected error", tc.data)
+		}
+
+		if tc.err != nil && !errors.Is(err, tc.err) {
+			t.Fatalf("%s: expected %v, got %v", tc.data, tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package postgres
+
+import "time"
+
+// Option -.
+type Option func(*Postgres)
+
+// MaxPoolSize -.
+func MaxPoolSize(size int) Option {
+	return func(p *Postgres) {
+		p.maxPoolSize = size
+	}
+}
+
+// ConnAttempts -.
+func ConnAttempts(attempts int) Option {
+	return func(p *Postgres) {
+		p.connAttempts = attempts
+	}
+}
+
+// ConnTimeout -.
+func ConnTimeout(timeout time.Duration) Option {
+	return func(p *Postgres) {
+		p.connTimeout = timeout
+	}
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	_defaultMaxPoolSize  = 2
+	_defaultConnAttempts = 10
+	_defaultConnTimeout  = time.Second
+)
+
+// Postgres -.
+type Postgres struct {
+	Pool *pgxpool.Pool
+
+	maxPoolSize  int
+	connAttempts int
+	connTimeout  time.Duration
+}
+
+// New returns new Postgres with an established connection pool
+func New(url string, opts ...Option) (*Postgres, error) {
+	pg := &Postgres{
+		maxPoolSize:  _defaultMaxPoolSize,
+		connAttempts: _defaultConnAttempts,
+		connTimeout:  _defaultConnTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(pg)
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		return nil, fmt.Errorf("postgres - New - pgxpool.ParseConfig: %w", err)
+	}
+
+	poolConfig.MaxConns = int32(pg.maxPoolSize)
+
+	for pg.connAttempts > 0 {
+		err = pg.connect(poolConfig)
+		if err == nil {
+			break
+		}
+
+		log.Printf("postgres is trying to connect, attempts left: %d", pg.connAttempts)
+
+		time.Sleep(pg.connTimeout)
+
+		pg.connAttempts--
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("postgres - New - connAttempts == 0: %w", err)
+	}
+
+	return pg, nil
+}
+
+func (p *Postgres) connect(poolConfig *pgxpool.Config) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.connTimeout)
+	defer cancel()
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return err
+	}
+
+	if err = pool.Ping(ctx); err != nil {
+		pool.Close()
+
+		return err
+	}
+
+	p.Pool = pool
+
+	return nil
+}
+
+// Close -.
+func (p *Postgres) Close() {
+	if p.Pool != nil {
+		p.Pool.Close()
+	}
+}
@@ -0,0 +1,242 @@
+// Package pubsub is an in-process publish/subscribe of messages by topic. Messages are numbered
+// in the order they are published and the last ones of each topic are kept, so a subscriber that
+// lost its subscription resumes after the last message it got. A topic nobody subscribes to is
+// forgotten once its messages are older than the broker retains them.
+package pubsub
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	_defaultKeep   = 100
+	_defaultRetain = 10 * time.Minute
+)
+
+// Message - ID numbers messages of the broker in the order they were published.
+type Message[T any] struct {
+	ID   uint64
+	Data T
+}
+
+// Broker - topics of K with messages of T.
+type Broker[K comparable, T any] struct {
+	mu     sync.Mutex
+	topics map[K]*topic[T]
+	// id is ID of the last message, start the one the broker started after.
+	id, start uint64
+	// forgotten is ID of the last message of the deleted topics.
+	forgotten uint64
+	keep      int
+	retain    time.Duration
+	// sweepAt - when idle topics are deleted next.
+	sweepAt time.Time
+	closed  bool
+}
+
+type topic[T any] struct {
+	// recent are the last messages, the oldest first.
+	recent []Message[T]
+	// dropped is ID of the last message no longer kept.
+	dropped uint64
+	subs    map[*Subscription[T]]struct{}
+	// idleSince - when the last message was published or the last subscriber left, whichever is later.
+	idleSince time.Time
+}
+
+// Subscription - C gets messages of the topic. It is closed when the subscription ends: by Close,
+// by Close of the broker or when the subscriber falls behind more than the broker keeps.
+type Subscription[T any] struct {
+	C <-chan Message[T]
+
+	c     chan Message[T]
+	close func()
+}
+
+// Option -.
+type Option func(*options)
+
+type options struct {
+	keep   int
+	retain time.Duration
+}
+
+// Keep sets how many last messages of a topic are kept, 100 by default.
+func Keep(n int) Option {
+	return func(o *options) {
+		o.keep = n
+	}
+}
+
+// Retain sets how long messages of a topic nobody subscribes to are kept for resuming,
+// 10 minutes by default. The topic is deleted then.
+func Retain(d time.Duration) Option {
+	return func(o *options) {
+		o.retain = d
+	}
+}
+
+// New returns new Broker. IDs start from the current time in microseconds, so those of a restarted
+// process follow the ones before and resuming from them is reported as missed.
+func New[K comparable, T any](opts ...Option) *Broker[K, T] {
+	o := options{keep: _defaultKeep, retain: _defaultRetain}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	start := uint64(time.Now().UnixMicro())
+
+	return &Broker[K, T]{
+		topics: make(map[K]*topic[T]),
+		id:     start,
+		start:  start,
+		keep:   o.keep,
+		retain: o.retain,
+	}
+}
+
+// Publish sends data to subscribers of the topic and returns ID of the message.
+// Subscribers too slow to take it are unsubscribed.
+func (b *Broker[K, T]) Publish(key K, data T) uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.sweep(now)
+
+	b.id++
+	msg := Message[T]{ID: b.id, Data: data}
+
+	t := b.topic(key, now)
+	t.idleSince = now
+
+	t.recent = append(t.recent, msg)
+	if len(t.recent) > b.keep {
+		t.dropped = t.recent[0].ID
+		t.recent = append(t.recent[:0:0], t.recent[1:]...)
+	}
+
+	for sub := range t.subs {
+		select {
+		case sub.c <- msg:
+		default:
+			delete(t.subs, sub)
+			close(sub.c)
+		}
+	}
+
+	return msg.ID
+}
+
+// Subscribe subscribes to the topic. Unless after is 0, messages following the one with that ID are
+// sent first. missed reports that some of them are no longer kept, none are sent then.
+// last is ID of the last message of the broker.
+func (b *Broker[K, T]) Subscribe(key K, after uint64) (sub *Subscription[T], last uint64, missed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// the replayed messages and as many new ones fit.
+	c := make(chan Message[T], 2*b.keep)
+	sub = &Subscription[T]{C: c, c: c}
+
+	if b.closed {
+		close(c)
+
+		return sub, b.id, false
+	}
+
+	now := time.Now()
+	b.sweep(now)
+
+	t := b.topic(key, now)
+	missed = after != 0 && (after < t.dropped || after > b.id)
+
+	if after != 0 && !missed {
+		for _, msg := range t.recent {
+			if msg.ID > after {
+				c <- msg
+			}
+		}
+	}
+
+	t.subs[sub] = struct{}{}
+
+	sub.close = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, ok := t.subs[sub]; ok {
+			delete(t.subs, sub)
+			close(c)
+		}
+
+		if len(t.subs) == 0 {
+			t.idleSince = time.Now()
+		}
+	}
+
+	return sub, b.id, missed
+}
+
+// Close ends the subscription.
+func (s *Subscription[T]) Close() {
+	if s.close != nil {
+		s.close()
+	}
+}
+
+// Close ends all subscriptions, later ones end at once.
+func (b *Broker[K, T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+
+	for _, t := range b.topics {
+		for sub := range t.subs {
+			close(sub.c)
+		}
+
+		clear(t.subs)
+	}
+}
+
+// topic returns the topic of key, creating it. Must be called with b.mu held.
+func (b *Broker[K, T]) topic(key K, now time.Time) *topic[T] {
+	t, ok := b.topics[key]
+	if !ok {
+		// messages published before, those of a deleted topic of key too, are not kept.
+		t = &topic[T]{
+			dropped:   max(b.start, b.forgotten),
+			subs:      make(map[*Subscription[T]]struct{}),
+			idleSince: now,
+		}
+		b.topics[key] = t
+	}
+
+	return t
+}
+
+// sweep deletes topics nobody subscribes to, idle for retain. It runs once per retain rather than
+// per call, so topics are kept up to twice as long. Must be called with b.mu held.
+func (b *Broker[K, T]) sweep(now time.Time) {
+	if now.Before(b.sweepAt) {
+		return
+	}
+
+	for key, t := range b.topics {
+		if len(t.subs) > 0 || now.Sub(t.idleSince) < b.retain {
+			continue
+		}
+
+		if n := len(t.recent); n > 0 {
+			b.forgotten = max(b.forgotten, t.recent[n-1].ID)
+		}
+
+		delete(b.topics, key)
+	}
+
+	b.sweepAt = now.Add(b.retain)
+}
@@ -0,0 +1,171 @@
+package pubsub_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/pkg/pubsub"
+)
+
+func receive(t *testing.T, sub *pubsub.Subscription[string]) []string {
+	t.Helper()
+
+	var got []string
+
+	for {
+		select {
+		case msg, ok := <-sub.C:
+			if !ok {
+				return append(got, "closed")
+			}
+
+			got = append(got, msg.Data)
+		default:
+			return got
+		}
+	}
+}
+
+func TestPublish(t *testing.T) {
+	b := pubsub.New[int, string]()
+
+	sub, _, missed := b.Subscribe(1, 0)
+	defer sub.Close()
+
+	if missed {
+		t.Fatal("expected nothing missed")
+	}
+
+	first := b.Publish(1, "first")
+	b.Publish(2, "other topic")
+	second := b.Publish(1, "second")
+
+	if second <= first {
+		t.Fatalf("expected ids to grow, got %d after %d", second, first)
+	}
+
+	if got := receive(t, sub); !slices.Equal(got, []string{"first", "second"}) {
+		t.Fatalf("unexpected messages %v", got)
+	}
+
+	sub.Close()
+
+	if got := receive(t, sub); !slices.Equal(got, []string{"closed"}) {
+		t.Fatalf("expected closed subscription, got %v", got)
+	}
+}
+
+func TestResume(t *testing.T) {
+	b := pubsub.New[int, string](pubsub.Keep(2))
+
+	first := b.Publish(1, "first")
+	second := b.Publish(1, "second")
+	b.Publish(1, "third")
+	fourth := b.Publish(1, "fourth")
+
+	tests := []struct {
+		name   string
+		after  uint64
+		want   []string
+		missed bool
+	}{
+		{name: "kept", after: second, want: []string{"third", "fourth"}},
+		{name: "up to date", after: fourth},
+		{name: "no longer kept", after: first, missed: true},
+		{name: "of a previous process", after: first - 1, missed: true},
+		{name: "ahead of the broker", after: fourth + 1, missed: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sub, last, missed := b.Subscribe(1, tc.after)
+			defer sub.Close()
+
+			if last != fourth {
+				t.Fatalf("expected last %d, got %d", fourth, last)
+			}
+
+			if missed != tc.missed {
+				t.Fatalf("expected missed %v, got %v", tc.missed, missed)
+			}
+
+			if got := receive(t, sub); !slices.Equal(got, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestIdleTopicsForgotten(t *testing.T) {
+	retain := 20 * time.Millisecond
+	b := pubsub.New[int, string](pubsub.Retain(retain))
+
+	first := b.Publish(1, "idle")
+	b.Publish(1, "idle, again")
+
+	watched, _, _ := b.Subscribe(2, 0)
+	defer watched.Close()
+
+	kept := b.Publish(2, "watched")
+
+	time.Sleep(retain)
+	// the next call sweeps.
+	b.Publish(3, "other")
+
+	// topic 1 is deleted: resuming it cant be served.
+	sub, _, missed := b.Subscribe(1, first)
+	sub.Close()
+
+	if !missed {
+		t.Fatal("expected messages of a forgotten topic to be missed")
+	}
+
+	// topic 2 has a subscriber, so it stays with its messages.
+	resumed, _, missed := b.Subscribe(2, kept-1)
+	defer resumed.Close()
+
+	if missed {
+		t.Fatal("expected messages of a watched topic to be kept")
+	}
+
+	if got := receive(t, resumed); !slices.Equal(got, []string{"watched"}) {
+		t.Fatalf("unexpected messages %v", got)
+	}
+}
+
+func TestSlowSubscriber(t *testing.T) {
+	b := pubsub.New[int, string](pubsub.Keep(1))
+
+	sub, _, _ := b.Subscribe(1, 0)
+	defer sub.Close()
+
+	for range 3 {
+		b.Publish(1, "message")
+	}
+
+	// the buffer holds two messages, the subscriber is dropped on the third.
+	if got := receive(t, sub); !slices.Equal(got, []string{"message", "message", "closed"}) {
+		t.Fatalf("unexpected messages %v", got)
+	}
+}
+
+func TestClose(t *testing.T) {
+	b := pubsub.New[int, string]()
+
+	sub, _, _ := b.Subscribe(1, 0)
+	defer sub.Close()
+
+	b.Close()
+
+	if got := receive(t, sub); !slices.Equal(got, []string{"closed"}) {
+		t.Fatalf("expected closed subscription, got %v", got)
+	}
+
+	later, _, _ := b.Subscribe(1, 0)
+	defer later.Close()
+
+	if got := receive(t, later); !slices.Equal(got, []string{"closed"}) {
+		t.Fatalf("expected closed subscription, got %v", got)
+	}
+}
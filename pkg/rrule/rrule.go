@@ -0,0 +1,332 @@
+// Package rrule implements the part of RFC 5545 recurrence rules calendars actually use:
+// FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, BYDAY, BYMONTHDAY, COUNT, UNTIL and WKST.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRule is wrapped by every Parse error.
+var ErrInvalidRule = errors.New("invalid rrule")
+
+// Frequency -.
+type Frequency int
+
+// Frequencies -.
+const (
+	Daily Frequency = iota + 1
+	Weekly
+	Monthly
+	Yearly
+)
+
+var _frequencies = map[string]Frequency{
+	"DAILY":   Daily,
+	"WEEKLY":  Weekly,
+	"MONTHLY": Monthly,
+	"YEARLY":  Yearly,
+}
+
+// String -.
+func (f Frequency) String() string {
+	for name, freq := range _frequencies {
+		if freq == f {
+			return name
+		}
+	}
+
+	return "Frequency(" + strconv.Itoa(int(f)) + ")"
+}
+
+var _weekdays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// WeekdayNum is a BYDAY entry: a weekday with an optional ordinal within the
+// month or year, "2MO" is the second monday and "-1FR" the last friday.
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// String -.
+func (w WeekdayNum) String() string {
+	if w.N == 0 {
+		return _weekdays[w.Weekday]
+	}
+
+	return strconv.Itoa(w.N) + _weekdays[w.Weekday]
+}
+
+const (
+	_untilDateTime = "20060102T150405Z"
+	_untilDate     = "20060102"
+)
+
+// Rule - a parsed RRULE. Until is inclusive.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	Count      int
+	Until      time.Time
+	// WeekStart only matters for WEEKLY rules with INTERVAL > 1, Parse defaults it to monday.
+	WeekStart time.Weekday
+
+	// untilDate is set for a date-only UNTIL: it is compared with the wall date of occurrences.
+	untilDate bool
+}
+
+// Parse parses an RRULE value, with or without the "RRULE:" prefix.
+func Parse(s string) (Rule, error) {
+	s = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "RRULE:")
+
+	r := Rule{Interval: 1, WeekStart: time.Monday}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return Rule{}, invalid("malformed part %q", part)
+		}
+
+		if seen[name] {
+			return Rule{}, invalid("%s given twice", name)
+		}
+		seen[name] = true
+
+		var err error
+
+		switch name {
+		case "FREQ":
+			if r.Freq, ok = _frequencies[value]; !ok {
+				return Rule{}, invalid("unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = positive(value)
+		case "COUNT":
+			r.Count, err = positive(value)
+		case "UNTIL":
+			err = r.parseUntil(value)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseByMonthDay(value)
+		case "WKST":
+			var wkst WeekdayNum
+
+			wkst, err = parseWeekdayNum(value)
+			if err == nil && wkst.N != 0 {
+				err = fmt.Errorf("WKST %q cant have an ordinal", value)
+			}
+
+			r.WeekStart = wkst.Weekday
+		default:
+			return Rule{}, invalid("unsupported part %s", name)
+		}
+
+		if err != nil {
+			return Rule{}, invalid("%s: %v", name, err)
+		}
+	}
+
+	if err := r.validate(); err != nil {
+		return Rule{}, err
+	}
+
+	return r, nil
+}
+
+// String returns the rule in RFC 5545 form, parts in a fixed order.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq.String()}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	if !r.Until.IsZero() {
+		if r.untilDate {
+			parts = append(parts, "UNTIL="+r.Until.Format(_untilDate))
+		} else {
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format(_untilDateTime))
+		}
+	}
+
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = day.String()
+		}
+
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+_weekdays[r.WeekStart])
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func (r Rule) validate() error {
+	if r.Freq == 0 {
+		return invalid("FREQ required")
+	}
+
+	if r.Count > 0 && !r.Until.IsZero() {
+		return invalid("COUNT and UNTIL cant be used together")
+	}
+
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return invalid("BYMONTHDAY cant be used with FREQ=WEEKLY")
+	}
+
+	maxN := map[Frequency]int{Monthly: 5, Yearly: 53}[r.Freq]
+
+	for _, day := range r.ByDay {
+		if day.N == 0 {
+			continue
+		}
+
+		if maxN == 0 {
+			return invalid("BYDAY ordinals need FREQ=MONTHLY or FREQ=YEARLY")
+		}
+
+		if day.N > maxN || day.N < -maxN {
+			return invalid("BYDAY ordinal %d out of range", day.N)
+		}
+	}
+
+	return nil
+}
+
+// untilPassed reports whether t is after Until.
+func (r Rule) untilPassed(t time.Time) bool {
+	if r.Until.IsZero() {
+		return false
+	}
+
+	if r.untilDate {
+		return civil(t).After(r.Until)
+	}
+
+	return t.After(r.Until)
+}
+
+// parseUntil accepts a UTC date-time or a date, RFC 5545 requires the former
+// for events with a time zone and the latter for all-day ones.
+func (r *Rule) parseUntil(value string) error {
+	if t, err := time.Parse(_untilDateTime, value); err == nil {
+		r.Until = t
+
+		return nil
+	}
+
+	t, err := time.Parse(_untilDate, value)
+	if err != nil {
+		return fmt.Errorf("expected YYYYMMDD or YYYYMMDDTHHMMSSZ, got %q", value)
+	}
+
+	r.Until, r.untilDate = t, true
+
+	return nil
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+
+	for _, s := range strings.Split(value, ",") {
+		day, err := parseWeekdayNum(s)
+		if err != nil {
+			return nil, err
+		}
+
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+func parseWeekdayNum(s string) (WeekdayNum, error) {
+	if len(s) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid weekday %q", s)
+	}
+
+	code, ordinal := s[len(s)-2:], s[:len(s)-2]
+
+	for wd, name := range _weekdays {
+		if name != code {
+			continue
+		}
+
+		day := WeekdayNum{Weekday: time.Weekday(wd)}
+
+		if ordinal != "" {
+			n, err := strconv.Atoi(ordinal)
+			if err != nil || n == 0 {
+				return WeekdayNum{}, fmt.Errorf("invalid weekday %q", s)
+			}
+
+			day.N = n
+		}
+
+		return day, nil
+	}
+
+	return WeekdayNum{}, fmt.Errorf("invalid weekday %q", s)
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	var days []int
+
+	for _, s := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(s)
+		if err != nil || day == 0 || day > 31 || day < -31 {
+			return nil, fmt.Errorf("invalid month day %q", s)
+		}
+
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+func positive(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("expected a positive number, got %q", value)
+	}
+
+	return n, nil
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidRule, fmt.Sprintf(format, args...))
+}
+
+// EndBefore returns the rule without occurrences at or after t: COUNT is
+// dropped and UNTIL set to the second before t.
+func (r Rule) EndBefore(t time.Time) Rule {
+	r.Count = 0
+	r.Until = t.Add(-time.Second).UTC().Truncate(time.Second)
+	r.untilDate = false
+
+	return r
+}
@@ -0,0 +1,63 @@
+package rrule_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/andreyxaxa/calendar/pkg/rrule"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"RRULE:freq=weekly;byday=mo,we,fr", "FREQ=WEEKLY;BYDAY=MO,WE,FR"},
+		{"FREQ=WEEKLY;INTERVAL=1;BYDAY=TU", "FREQ=WEEKLY;BYDAY=TU"},
+		{"BYDAY=-1FR;FREQ=MONTHLY;COUNT=6", "FREQ=MONTHLY;COUNT=6;BYDAY=-1FR"},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,-1;UNTIL=20261231T235959Z", "FREQ=MONTHLY;UNTIL=20261231T235959Z;BYMONTHDAY=1,-1"},
+		{"FREQ=YEARLY;UNTIL=20300101", "FREQ=YEARLY;UNTIL=20300101"},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,SU;WKST=SU", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,SU;WKST=SU"},
+	}
+
+	for _, tt := range tests {
+		rule, err := rrule.Parse(tt.in)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.in, err)
+		}
+
+		if rule.String() != tt.expected {
+			t.Fatalf("%s: expected %q, got %q", tt.in, tt.expected, rule.String())
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;COUNT=3;UNTIL=20260101",
+		"FREQ=DAILY;UNTIL=2026-01-01",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=WEEKLY;WKST=1MO",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=DAILY;COUNT",
+	}
+
+	for _, in := range tests {
+		_, err := rrule.Parse(in)
+		if !errors.Is(err, rrule.ErrInvalidRule) {
+			t.Fatalf("%q: expected ErrInvalidRule, got %v", in, err)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package rrule
+
+import (
+	"slices"
+	"time"
+)
+
+// _maxYear bounds expansion of rules that never produce another occurrence,
+// like the 30th of every 12th month starting in february.
+const _maxYear = 9999
+
+// Set - a rule anchored at DTStart, minus ExDates.
+//
+// Occurrences keep the wall clock of DTStart in its location, so a 09:00
+// meeting stays at 09:00 across DST changes. As in most implementations
+// DTStart itself is an occurrence only if it matches the rule. ExDates still
+// count towards COUNT.
+type Set struct {
+	Rule    Rule
+	DTStart time.Time
+	ExDates []time.Time
+}
+
+// Between returns occurrences t with from <= t < to, in order.
+func (s Set) Between(from, to time.Time) []time.Time {
+	var occurrences []time.Time
+
+	s.iterate(from, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+
+		if !t.Before(from) {
+			occurrences = append(occurrences, t)
+		}
+
+		return true
+	})
+
+	return occurrences
+}
+
+// Last returns the last occurrence, zero time if there are none.
+// ok is false if the rule has neither COUNT nor UNTIL and never ends.
+func (s Set) Last() (last time.Time, ok bool) {
+	if s.Rule.Count == 0 && s.Rule.Until.IsZero() {
+		return time.Time{}, false
+	}
+
+	s.iterate(time.Time{}, func(t time.Time) bool {
+		last = t
+
+		return true
+	})
+
+	return last, true
+}
+
+// iterate yields occurrences in order until yield returns false or the rule ends.
+// Periods entirely before hint are skipped when the rule has no COUNT.
+func (s Set) iterate(hint time.Time, yield func(time.Time) bool) {
+	r := s.Rule
+	interval := max(r.Interval, 1)
+	start := s.DTStart
+	loc := start.Location()
+
+	base := r.periodStart(civil(start))
+	n := 0
+
+	if r.Count == 0 && hint.After(start) {
+		n = max(periodsBetween(r.Freq, base, r.periodStart(civil(hint.In(loc))))/interval-1, 0)
+	}
+
+	count := 0
+
+	for ; ; n++ {
+		period := advance(r.Freq, base, n*interval)
+		if period.Year() > _maxYear {
+			return
+		}
+
+		for _, day := range r.days(period, start) {
+			t := time.Date(day.Year(), day.Month(), day.Day(),
+				start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), loc)
+
+			if t.Before(start) {
+				continue
+			}
+
+			if r.untilPassed(t) {
+				return
+			}
+
+			count++
+
+			if !s.excluded(t) && !yield(t) {
+				return
+			}
+
+			if r.Count > 0 && count >= r.Count {
+				return
+			}
+		}
+	}
+}
+
+func (s Set) excluded(t time.Time) bool {
+	return slices.ContainsFunc(s.ExDates, t.Equal)
+}
+
+// days returns the days (as UTC midnights) of the period starting at period that
+// match the rule, in order. start supplies the defaults for missing BY* parts.
+func (r Rule) days(period, start time.Time) []time.Time {
+	var first, end time.Time
+
+	switch r.Freq {
+	case Daily:
+		first, end = period, period.AddDate(0, 0, 1)
+	case Weekly:
+		first, end = period, period.AddDate(0, 0, 7)
+	case Monthly:
+		first, end = period, period.AddDate(0, 1, 0)
+	case Yearly:
+		first, end = period, period.AddDate(1, 0, 0)
+	default:
+		return nil
+	}
+
+	scopeDays := int(end.Sub(first).Hours() / 24)
+	days := make([]time.Time, 0, 1)
+
+	for i := range scopeDays {
+		day := first.AddDate(0, 0, i)
+
+		if r.matches(day, i, scopeDays, civil(start)) {
+			days = append(days, day)
+		}
+	}
+
+	return days
+}
+
+// matches reports whether day, the i-th of scopeDays in its period, belongs to the rule.
+func (r Rule) matches(day time.Time, i, scopeDays int, start time.Time) bool {
+	if len(r.ByMonthDay) > 0 && !slices.ContainsFunc(r.ByMonthDay, func(md int) bool {
+		return monthDay(day, md)
+	}) {
+		return false
+	}
+
+	if len(r.ByDay) > 0 {
+		return slices.ContainsFunc(r.ByDay, func(wd WeekdayNum) bool {
+			return weekdayNum(day, i, scopeDays, wd)
+		})
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		return true
+	}
+
+	switch r.Freq {
+	case Weekly:
+		return day.Weekday() == start.Weekday()
+	case Monthly:
+		return day.Day() == start.Day()
+	case Yearly:
+		return day.Month() == start.Month() && day.Day() == start.Day()
+	default:
+		return true
+	}
+}
+
+// monthDay reports whether day is the md-th day of its month, negative md counts from the end.
+func monthDay(day time.Time, md int) bool {
+	if md > 0 {
+		return day.Day() == md
+	}
+
+	return lastDays(day) == -md
+}
+
+// lastDays returns 1 for the last day of the month, 2 for the one before and so on.
+func lastDays(day time.Time) int {
+	next := time.Date(day.Year(), day.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+
+	return int(next.Sub(day).Hours() / 24)
+}
+
+// weekdayNum reports whether day, the i-th of scopeDays in its period, matches wd.
+func weekdayNum(day time.Time, i, scopeDays int, wd WeekdayNum) bool {
+	if day.Weekday() != wd.Weekday {
+		return false
+	}
+
+	switch {
+	case wd.N > 0:
+		return i/7+1 == wd.N
+	case wd.N < 0:
+		return (scopeDays-1-i)/7+1 == -wd.N
+	default:
+		return true
+	}
+}
+
+// periodStart returns the first day of the period containing day.
+func (r Rule) periodStart(day time.Time) time.Time {
+	switch r.Freq {
+	case Weekly:
+		offset := (int(day.Weekday()) - int(r.WeekStart) + 7) % 7
+
+		return day.AddDate(0, 0, -offset)
+	case Monthly:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case Yearly:
+		return time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func advance(freq Frequency, period time.Time, n int) time.Time {
+	switch freq {
+	case Weekly:
+		return period.AddDate(0, 0, 7*n)
+	case Monthly:
+		return period.AddDate(0, n, 0)
+	case Yearly:
+		return period.AddDate(n, 0, 0)
+	default:
+		return period.AddDate(0, 0, n)
+	}
+}
+
+// periodsBetween returns how many whole periods of freq lie between period starts a and b.
+func periodsBetween(freq Frequency, a, b time.Time) int {
+	switch freq {
+	case Weekly:
+		return int(b.Sub(a).Hours()/24) / 7
+	case Monthly:
+		return (b.Year()-a.Year())*12 + int(b.Month()-a.Month())
+	case Yearly:
+		return b.Year() - a.Year()
+	default:
+		return int(b.Sub(a).Hours() / 24)
+	}
+}
+
+// civil returns the wall date of t as UTC midnight.
+func civil(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,294 @@
+package rrule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/pkg/rrule"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return loc
+}
+
+func mustParse(t *testing.T, s string) rrule.Rule {
+	t.Helper()
+
+	rule, err := rrule.Parse(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return rule
+}
+
+// dates parses "2006-01-02" days at 09:00 in loc.
+func dates(t *testing.T, loc *time.Location, days ...string) []time.Time {
+	t.Helper()
+
+	res := make([]time.Time, 0, len(days))
+
+	for _, day := range days {
+		d, err := time.ParseInLocation("2006-01-02 15:04", day+" 09:00", loc)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		res = append(res, d)
+	}
+
+	return res
+}
+
+func assertTimes(t *testing.T, name string, expected, got []time.Time) {
+	t.Helper()
+
+	if len(expected) != len(got) {
+		t.Fatalf("%s: expected %d occurrences %v, got %d %v", name, len(expected), expected, len(got), got)
+	}
+
+	for i := range expected {
+		if !expected[i].Equal(got[i]) {
+			t.Fatalf("%s: occurrence %d: expected %s, got %s", name, i, expected[i], got[i])
+		}
+	}
+}
+
+// TestRFC5545Examples checks examples from RFC 5545, section 3.8.5.3.
+func TestRFC5545Examples(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+	end := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		rule     string
+		start    string
+		exdates  []string
+		expected []string
+	}{
+		{
+			name:     "daily for 10 occurrences",
+			rule:     "FREQ=DAILY;COUNT=10",
+			start:    "1997-09-02",
+			expected: []string{"1997-09-02", "1997-09-03", "1997-09-04", "1997-09-05", "1997-09-06", "1997-09-07", "1997-09-08", "1997-09-09", "1997-09-10", "1997-09-11"},
+		},
+		{
+			name:     "every 10 days, 5 occurrences",
+			rule:     "FREQ=DAILY;INTERVAL=10;COUNT=5",
+			start:    "1997-09-02",
+			expected: []string{"1997-09-02", "1997-09-12", "1997-09-22", "1997-10-02", "1997-10-12"},
+		},
+		{
+			name:     "weekly on tuesday and thursday for five weeks",
+			rule:     "FREQ=WEEKLY;UNTIL=19971007T000000Z;WKST=SU;BYDAY=TU,TH",
+			start:    "1997-09-02",
+			expected: []string{"1997-09-02", "1997-09-04", "1997-09-09", "1997-09-11", "1997-09-16", "1997-09-18", "1997-09-23", "1997-09-25", "1997-09-30", "1997-10-02"},
+		},
+		{
+			name:     "every other week on tuesday and thursday, 8 occurrences",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;COUNT=8;WKST=SU;BYDAY=TU,TH",
+			start:    "1997-09-02",
+			expected: []string{"1997-09-02", "1997-09-04", "1997-09-16", "1997-09-18", "1997-09-30", "1997-10-02", "1997-10-14", "1997-10-16"},
+		},
+		{
+			name:     "week start monday",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO",
+			start:    "1997-08-05",
+			expected: []string{"1997-08-05", "1997-08-10", "1997-08-19", "1997-08-24"},
+		},
+		{
+			name:     "week start sunday",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU",
+			start:    "1997-08-05",
+			expected: []string{"1997-08-05", "1997-08-17", "1997-08-19", "1997-08-31"},
+		},
+		{
+			name:     "monthly on the first friday for 10 occurrences",
+			rule:     "FREQ=MONTHLY;COUNT=10;BYDAY=1FR",
+			start:    "1997-09-05",
+			expected: []string{"1997-09-05", "1997-10-03", "1997-11-07", "1997-12-05", "1998-01-02", "1998-02-06", "1998-03-06", "1998-04-03", "1998-05-01", "1998-06-05"},
+		},
+		{
+			name:     "monthly on the second-to-last monday for 6 months",
+			rule:     "FREQ=MONTHLY;COUNT=6;BYDAY=-2MO",
+			start:    "1997-09-22",
+			expected: []string{"1997-09-22", "1997-10-20", "1997-11-17", "1997-12-22", "1998-01-19", "1998-02-16"},
+		},
+		{
+			name:     "monthly on the third-to-the-last day of the month",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=-3;COUNT=6",
+			start:    "1997-09-28",
+			expected: []string{"1997-09-28", "1997-10-29", "1997-11-28", "1997-12-29", "1998-01-29", "1998-02-26"},
+		},
+		{
+			name:     "monthly on the 2nd and 15th for 10 occurrences",
+			rule:     "FREQ=MONTHLY;COUNT=10;BYMONTHDAY=2,15",
+			start:    "1997-09-02",
+			expected: []string{"1997-09-02", "1997-09-15", "1997-10-02", "1997-10-15", "1997-11-02", "1997-11-15", "1997-12-02", "1997-12-15", "1998-01-02", "1998-01-15"},
+		},
+		{
+			name:     "every friday the 13th",
+			rule:     "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13;COUNT=5",
+			start:    "1997-09-02",
+			exdates:  []string{"1997-09-02"},
+			expected: []string{"1998-02-13", "1998-03-13", "1998-11-13", "1999-08-13", "2000-10-13"},
+		},
+		{
+			name:     "every 20th monday of the year",
+			rule:     "FREQ=YEARLY;BYDAY=20MO;COUNT=3",
+			start:    "1997-05-19",
+			expected: []string{"1997-05-19", "1998-05-18", "1999-05-17"},
+		},
+	}
+
+	for _, tt := range tests {
+		set := rrule.Set{
+			Rule:    mustParse(t, tt.rule),
+			DTStart: dates(t, ny, tt.start)[0],
+			ExDates: dates(t, ny, tt.exdates...),
+		}
+
+		assertTimes(t, tt.name, dates(t, ny, tt.expected...), set.Between(set.DTStart, end))
+	}
+}
+
+func TestSkipsMissingDays(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     string
+		start    string
+		expected []string
+	}{
+		{
+			name:     "31st of every month",
+			rule:     "FREQ=MONTHLY;COUNT=4",
+			start:    "2026-01-31",
+			expected: []string{"2026-01-31", "2026-03-31", "2026-05-31", "2026-07-31"},
+		},
+		{
+			name:     "29th of february",
+			rule:     "FREQ=YEARLY;COUNT=3",
+			start:    "2024-02-29",
+			expected: []string{"2024-02-29", "2028-02-29", "2032-02-29"},
+		},
+		{
+			name:     "last day of every month",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3",
+			start:    "2028-01-15",
+			expected: []string{"2028-01-31", "2028-02-29", "2028-03-31"},
+		},
+	}
+
+	for _, tt := range tests {
+		set := rrule.Set{Rule: mustParse(t, tt.rule), DTStart: dates(t, time.UTC, tt.start)[0]}
+
+		assertTimes(t, tt.name, dates(t, time.UTC, tt.expected...), set.Between(set.DTStart, time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)))
+	}
+}
+
+func TestKeepsWallClockAcrossDST(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+
+	set := rrule.Set{
+		Rule:    mustParse(t, "FREQ=WEEKLY;COUNT=2"),
+		DTStart: time.Date(2026, 3, 2, 9, 0, 0, 0, ny),
+	}
+
+	got := set.Between(set.DTStart, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	expected := []time.Time{
+		time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 9, 13, 0, 0, 0, time.UTC),
+	}
+
+	assertTimes(t, "weekly across DST", expected, got)
+}
+
+func TestExDatesCountTowardsCount(t *testing.T) {
+	set := rrule.Set{
+		Rule:    mustParse(t, "FREQ=DAILY;COUNT=5"),
+		DTStart: dates(t, time.UTC, "2026-01-01")[0],
+		ExDates: dates(t, time.UTC, "2026-01-03"),
+	}
+
+	expected := dates(t, time.UTC, "2026-01-01", "2026-01-02", "2026-01-04", "2026-01-05")
+
+	assertTimes(t, "exdates", expected, set.Between(set.DTStart, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestUntilDateIsInclusive(t *testing.T) {
+	vvo := mustLoad(t, "Asia/Vladivostok")
+
+	set := rrule.Set{
+		Rule:    mustParse(t, "FREQ=DAILY;UNTIL=20260103"),
+		DTStart: dates(t, vvo, "2026-01-01")[0],
+	}
+
+	expected := dates(t, vvo, "2026-01-01", "2026-01-02", "2026-01-03")
+
+	assertTimes(t, "until date", expected, set.Between(set.DTStart, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+// TestBetweenSkipsAhead compares a window far from DTStart with a full expansion.
+func TestBetweenSkipsAhead(t *testing.T) {
+	rules := []string{
+		"FREQ=DAILY;INTERVAL=3",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+		"FREQ=MONTHLY;INTERVAL=5;BYDAY=-1SU",
+		"FREQ=YEARLY;BYMONTHDAY=13;BYDAY=FR",
+	}
+
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	from := time.Date(2035, 6, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2036, 6, 10, 0, 0, 0, 0, time.UTC)
+
+	for _, s := range rules {
+		set := rrule.Set{Rule: mustParse(t, s), DTStart: start}
+
+		var expected []time.Time
+
+		for _, occ := range set.Between(start, to) {
+			if !occ.Before(from) {
+				expected = append(expected, occ)
+			}
+		}
+
+		if len(expected) == 0 {
+			t.Fatalf("%s: expected occurrences in the window", s)
+		}
+
+		assertTimes(t, s, expected, set.Between(from, to))
+	}
+}
+
+func TestLast(t *testing.T) {
+	start := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		rule     string
+		expected time.Time
+		ok       bool
+	}{
+		{"FREQ=WEEKLY;COUNT=3", time.Date(2026, 2, 15, 9, 0, 0, 0, time.UTC), true},
+		{"FREQ=DAILY;UNTIL=20260205T000000Z", time.Date(2026, 2, 4, 9, 0, 0, 0, time.UTC), true},
+		{"FREQ=DAILY", time.Time{}, false},
+		// never produces an occurrence, but still terminates.
+		{"FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30;COUNT=1", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		set := rrule.Set{Rule: mustParse(t, tt.rule), DTStart: start}
+
+		last, ok := set.Last()
+		if ok != tt.ok || !last.Equal(tt.expected) {
+			t.Fatalf("%s: expected %s %v, got %s %v", tt.rule, tt.expected, tt.ok, last, ok)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	_ "modernc.org/sqlite" // pure-Go driver, registers "sqlite".
+)
+
+// SQLite -.
+type SQLite struct {
+	DB *sql.DB
+}
+
+// New opens (creating if needed) the database file at path.
+func New(path string) (*SQLite, error) {
+	dsn := "file:" + path + "?" + url.Values{
+		"_pragma": {
+			"foreign_keys(1)",
+			"journal_mode(WAL)",
+			"busy_timeout(5000)",
+		},
+	}.Encode()
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite - New - sql.Open: %w", err)
+	}
+
+	// sqlite allows a single writer, serialize access instead of failing with SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+
+	if err = db.Ping(); err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("sqlite - New - db.Ping: %w", err)
+	}
+
+	return &SQLite{DB: db}, nil
+}
+
+// Close -.
+func (s *SQLite) Close() {
+	s.DB.Close()
+}
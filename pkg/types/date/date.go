@@ -29,3 +29,42 @@ func (d *Date) UnmarshalJSON(b []byte) error {
 func (d Date) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + d.Time.Format("2006-01-02") + `"`), nil
 }
+
+// DayRange returns [from, to) bounds of the day containing t.
+func DayRange(t time.Time) (time.Time, time.Time) {
+	from := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	return from, from.AddDate(0, 0, 1)
+}
+
+// WeekRange returns [from, to) bounds of the ISO week (monday to monday) containing t.
+func WeekRange(t time.Time) (time.Time, time.Time) {
+	offset := (int(t.Weekday()) + 6) % 7
+	from := time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+
+	return from, from.AddDate(0, 0, 7)
+}
+
+// MonthRange returns [from, to) bounds of the month containing t.
+func MonthRange(t time.Time) (time.Time, time.Time) {
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+
+	return from, from.AddDate(0, 1, 0)
+}
+
+// Floating returns the calendar day of t (in t's location) as UTC midnight.
+// All-day events are stored this way, so they stay on the same day in every time zone.
+func Floating(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// FloatingCeil is Floating rounded up: a t past midnight yields the next day.
+func FloatingCeil(t time.Time) time.Time {
+	day := Floating(t)
+
+	if t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0 || t.Nanosecond() != 0 {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return day
+}
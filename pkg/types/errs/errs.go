@@ -11,4 +11,22 @@ var (
 	ErrEmptyResult = errors.New("empty result")
 	// ErrAlreadyExists -.
 	ErrAlreadyExists = errors.New("already exists")
+	// ErrOccurrenceNotFound -.
+	ErrOccurrenceNotFound = errors.New("occurrence not found")
+	// ErrNotRecurring -.
+	ErrNotRecurring = errors.New("event is not recurring")
+	// ErrPreconditionFailed -.
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrInvalidCalendarData -.
+	ErrInvalidCalendarData = errors.New("invalid calendar data")
+	// ErrIdempotencyKeyReused -.
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used with another request")
+	// ErrRequestInProgress -.
+	ErrRequestInProgress = errors.New("request with this idempotency key is in progress")
+	// ErrRevisionNotFound -.
+	ErrRevisionNotFound = errors.New("revision not found")
+	// ErrSyncTokenExpired -.
+	ErrSyncTokenExpired = errors.New("sync token expired")
+	// ErrWebhookNotFound -.
+	ErrWebhookNotFound = errors.New("webhook not found")
 )
@@ -0,0 +1,279 @@
+// Package webdav reads and writes the XML bodies of WebDAV (RFC 4918): PROPFIND requests,
+// property lists and multistatus responses.
+package webdav
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// NS - the DAV: namespace.
+const NS = "DAV:"
+
+// _prefixes - well-known namespaces get their usual prefixes, others ns0, ns1...
+var _prefixes = map[string]string{
+	NS:                               "d",
+	"urn:ietf:params:xml:ns:caldav":  "c",
+	"http://calendarserver.org/ns/":  "cs",
+	"http://apple.com/ns/ical/":      "ical",
+	"urn:ietf:params:xml:ns:carddav": "card",
+}
+
+// xml.EscapeText escapes quotes and newlines as well, calendar-data would be unreadable.
+// CR is kept as a reference: parsers turn a literal one into LF.
+var (
+	_textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	_attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "\r", "&#xD;", "\n", "&#xA;", "\t", "&#x9;")
+)
+
+// Element - a property or a part of its value.
+type Element struct {
+	Name     xml.Name
+	Attrs    []xml.Attr
+	Text     string
+	Children []Element
+}
+
+// NewElement -.
+func NewElement(name xml.Name, children ...Element) Element {
+	return Element{Name: name, Children: children}
+}
+
+// TextElement -.
+func TextElement(name xml.Name, text string) Element {
+	return Element{Name: name, Text: text}
+}
+
+// Href returns a DAV:href element.
+func Href(href string) Element {
+	return TextElement(xml.Name{Space: NS, Local: "href"}, href)
+}
+
+// Response - a resource in a multistatus: its found properties and the names of missing ones.
+// A non-zero Status replaces the properties, e.g. 404 for an unknown href.
+type Response struct {
+	Href     string
+	Found    []Element
+	NotFound []xml.Name
+	Status   int
+}
+
+// EncodeMultistatus writes a DAV:multistatus of responses.
+func EncodeMultistatus(w io.Writer, responses []Response) error {
+	multistatus := NewElement(xml.Name{Space: NS, Local: "multistatus"})
+
+	for _, r := range responses {
+		multistatus.Children = append(multistatus.Children, r.element())
+	}
+
+	return Encode(w, multistatus)
+}
+
+// EncodeError writes a DAV:error with the failed preconditions, e.g. CALDAV:valid-calendar-data.
+func EncodeError(w io.Writer, conditions ...xml.Name) error {
+	e := NewElement(xml.Name{Space: NS, Local: "error"})
+
+	for _, c := range conditions {
+		e.Children = append(e.Children, NewElement(c))
+	}
+
+	return Encode(w, e)
+}
+
+func (r Response) element() Element {
+	res := NewElement(xml.Name{Space: NS, Local: "response"}, Href(r.Href))
+
+	if r.Status != 0 {
+		res.Children = append(res.Children, status(r.Status))
+
+		return res
+	}
+
+	if len(r.Found) > 0 || len(r.NotFound) == 0 {
+		res.Children = append(res.Children, propstat(r.Found, http.StatusOK))
+	}
+
+	if len(r.NotFound) > 0 {
+		missing := make([]Element, len(r.NotFound))
+		for i, name := range r.NotFound {
+			missing[i] = NewElement(name)
+		}
+
+		res.Children = append(res.Children, propstat(missing, http.StatusNotFound))
+	}
+
+	return res
+}
+
+func propstat(props []Element, code int) Element {
+	return NewElement(xml.Name{Space: NS, Local: "propstat"},
+		NewElement(xml.Name{Space: NS, Local: "prop"}, props...),
+		status(code),
+	)
+}
+
+func status(code int) Element {
+	return TextElement(xml.Name{Space: NS, Local: "status"}, "HTTP/1.1 "+strconv.Itoa(code)+" "+http.StatusText(code))
+}
+
+// Encode writes e as an XML document, namespaces are declared on the root element.
+func Encode(w io.Writer, e Element) error {
+	spaces := namespaces(e, nil)
+
+	prefixes := make(map[string]string, len(spaces))
+	for i, space := range spaces {
+		if prefix, ok := _prefixes[space]; ok {
+			prefixes[space] = prefix
+		} else {
+			prefixes[space] = "ns" + strconv.Itoa(i)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xml.Header)
+
+	encode(bw, e, prefixes, spaces)
+
+	return bw.Flush()
+}
+
+// namespaces collects the namespaces of e and its children in order of appearance.
+// Names without a namespace are written unprefixed.
+func namespaces(e Element, spaces []string) []string {
+	if e.Name.Space != "" && !slices.Contains(spaces, e.Name.Space) {
+		spaces = append(spaces, e.Name.Space)
+	}
+
+	for _, c := range e.Children {
+		spaces = namespaces(c, spaces)
+	}
+
+	return spaces
+}
+
+func encode(w *bufio.Writer, e Element, prefixes map[string]string, declare []string) {
+	name := e.Name.Local
+	if e.Name.Space != "" {
+		name = prefixes[e.Name.Space] + ":" + name
+	}
+
+	w.WriteString("<" + name)
+
+	for _, space := range declare {
+		w.WriteString(" xmlns:" + prefixes[space] + `="` + _attrEscaper.Replace(space) + `"`)
+	}
+
+	for _, a := range e.Attrs {
+		w.WriteString(" " + a.Name.Local + `="` + _attrEscaper.Replace(a.Value) + `"`)
+	}
+
+	if e.Text == "" && len(e.Children) == 0 {
+		w.WriteString("/>")
+
+		return
+	}
+
+	w.WriteString(">" + _textEscaper.Replace(e.Text))
+
+	for _, c := range e.Children {
+		encode(w, c, prefixes, nil)
+	}
+
+	w.WriteString("</" + name + ">")
+}
+
+// PropNames - the children of a DAV:prop in a request, their names only.
+type PropNames []xml.Name
+
+// UnmarshalXML -.
+func (p *PropNames) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			*p = append(*p, t.Name)
+
+			if err = d.Skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// Propfind - a PROPFIND request: all properties, their names only or those listed in Prop.
+type Propfind struct {
+	XMLName  xml.Name  `xml:"DAV: propfind"`
+	AllProp  *struct{} `xml:"DAV: allprop"`
+	PropName *struct{} `xml:"DAV: propname"`
+	Prop     PropNames `xml:"DAV: prop"`
+}
+
+// DecodePropfind reads a PROPFIND body, an empty one asks for all properties.
+func DecodePropfind(r io.Reader) (Propfind, error) {
+	var p Propfind
+
+	err := xml.NewDecoder(r).Decode(&p)
+	if errors.Is(err, io.EOF) {
+		return Propfind{AllProp: &struct{}{}}, nil
+	}
+
+	if err != nil {
+		return Propfind{}, fmt.Errorf("invalid propfind: %w", err)
+	}
+
+	if p.AllProp == nil && p.PropName == nil && len(p.Prop) == 0 {
+		return Propfind{}, errors.New("invalid propfind: no allprop, propname or prop")
+	}
+
+	return p, nil
+}
+
+// Select picks the requested properties out of props, those of a resource.
+// Missing are the names asked for that props lack.
+func (p Propfind) Select(props []Element) (found []Element, missing []xml.Name) {
+	switch {
+	case p.AllProp != nil:
+		return props, nil
+	case p.PropName != nil:
+		found = make([]Element, len(props))
+		for i, prop := range props {
+			found[i] = NewElement(prop.Name)
+		}
+
+		return found, nil
+	}
+
+	return SelectProps(p.Prop, props)
+}
+
+// SelectProps picks props named in names, missing are the names props lack.
+func SelectProps(names []xml.Name, props []Element) (found []Element, missing []xml.Name) {
+	for _, name := range names {
+		i := slices.IndexFunc(props, func(e Element) bool {
+			return e.Name == name
+		})
+
+		if i < 0 {
+			missing = append(missing, name)
+
+			continue
+		}
+
+		found = append(found, props[i])
+	}
+
+	return found, missing
+}
@@ -0,0 +1,186 @@
+package webdav_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/andreyxaxa/calendar/pkg/webdav"
+)
+
+const _caldav = "urn:ietf:params:xml:ns:caldav"
+
+func TestEncodeMultistatus(t *testing.T) {
+	var sb strings.Builder
+
+	err := webdav.EncodeMultistatus(&sb, []webdav.Response{
+		{
+			Href: "/dav/users/1/calendar/",
+			Found: []webdav.Element{
+				webdav.NewElement(xml.Name{Space: webdav.NS, Local: "resourcetype"},
+					webdav.NewElement(xml.Name{Space: webdav.NS, Local: "collection"}),
+					webdav.NewElement(xml.Name{Space: _caldav, Local: "calendar"}),
+				),
+				webdav.TextElement(xml.Name{Space: webdav.NS, Local: "displayname"}, "Tom & Jerry <3"),
+			},
+			NotFound: []xml.Name{{Space: "urn:example", Local: "color"}},
+		},
+		{Href: "/dav/users/1/calendar/missing.ics", Status: http.StatusNotFound},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := xml.Header +
+		`<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:ns2="urn:example">` +
+		`<d:response><d:href>/dav/users/1/calendar/</d:href>` +
+		`<d:propstat><d:prop><d:resourcetype><d:collection/><c:calendar/></d:resourcetype>` +
+		`<d:displayname>Tom &amp; Jerry &lt;3</d:displayname></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>` +
+		`<d:propstat><d:prop><ns2:color/></d:prop><d:status>HTTP/1.1 404 Not Found</d:status></d:propstat>` +
+		`</d:response>` +
+		`<d:response><d:href>/dav/users/1/calendar/missing.ics</d:href><d:status>HTTP/1.1 404 Not Found</d:status></d:response>` +
+		`</d:multistatus>`
+
+	if sb.String() != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, sb.String())
+	}
+
+	// the output is well-formed and keeps the namespaces.
+	var parsed struct {
+		Responses []struct {
+			Href string `xml:"DAV: href"`
+		} `xml:"DAV: response"`
+	}
+
+	if err = xml.Unmarshal([]byte(sb.String()), &parsed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(parsed.Responses) != 2 || parsed.Responses[1].Href != "/dav/users/1/calendar/missing.ics" {
+		t.Fatalf("unexpected responses: %+v", parsed.Responses)
+	}
+}
+
+func TestEncodeText(t *testing.T) {
+	// calendar-data keeps its CRLF line ends through the XML.
+	data := "BEGIN:VCALENDAR\r\nSUMMARY:a & b <c>\r\nEND:VCALENDAR\r\n"
+
+	var sb strings.Builder
+
+	err := webdav.Encode(&sb, webdav.TextElement(xml.Name{Space: _caldav, Local: "calendar-data"}, data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var parsed struct {
+		Data string `xml:",chardata"`
+	}
+
+	if err = xml.Unmarshal([]byte(sb.String()), &parsed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if parsed.Data != data {
+		t.Fatalf("expected %q, got %q from %s", data, parsed.Data, sb.String())
+	}
+}
+
+func TestEncodeError(t *testing.T) {
+	var sb strings.Builder
+
+	if err := webdav.EncodeError(&sb, xml.Name{Space: _caldav, Local: "valid-calendar-data"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := xml.Header + `<d:error xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav"><c:valid-calendar-data/></d:error>`
+	if sb.String() != expected {
+		t.Fatalf("expected %s, got %s", expected, sb.String())
+	}
+}
+
+func TestDecodePropfind(t *testing.T) {
+	getetag := xml.Name{Space: webdav.NS, Local: "getetag"}
+	ctag := xml.Name{Space: "http://calendarserver.org/ns/", Local: "getctag"}
+	color := xml.Name{Space: "http://apple.com/ns/ical/", Local: "calendar-color"}
+
+	props := []webdav.Element{
+		webdav.TextElement(getetag, `"1"`),
+		webdav.TextElement(ctag, "2"),
+	}
+
+	tests := []struct {
+		name      string
+		body      string
+		found     []xml.Name
+		missing   []xml.Name
+		namesOnly bool
+	}{
+		{
+			name:  "empty body",
+			body:  "",
+			found: []xml.Name{getetag, ctag},
+		},
+		{
+			name:  "allprop",
+			body:  `<propfind xmlns="DAV:"><allprop/></propfind>`,
+			found: []xml.Name{getetag, ctag},
+		},
+		{
+			name:      "propname",
+			body:      `<D:propfind xmlns:D="DAV:"><D:propname/></D:propfind>`,
+			found:     []xml.Name{getetag, ctag},
+			namesOnly: true,
+		},
+		{
+			name: "prop",
+			body: `<?xml version="1.0"?>
+				<d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/" xmlns:a="http://apple.com/ns/ical/">
+					<d:prop><cs:getctag/><a:calendar-color/><d:getetag><ignored/></d:getetag></d:prop>
+				</d:propfind>`,
+			found:   []xml.Name{ctag, getetag},
+			missing: []xml.Name{color},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := webdav.DecodePropfind(strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			found, missing := p.Select(props)
+
+			names := make([]xml.Name, len(found))
+			for i, e := range found {
+				names[i] = e.Name
+
+				if tc.namesOnly && e.Text != "" {
+					t.Fatalf("expected no value of %s, got %q", e.Name.Local, e.Text)
+				}
+			}
+
+			if !slices.Equal(names, tc.found) {
+				t.Fatalf("expected found %v, got %v", tc.found, names)
+			}
+
+			if !slices.Equal(missing, tc.missing) {
+				t.Fatalf("expected missing %v, got %v", tc.missing, missing)
+			}
+		})
+	}
+}
+
+func TestDecodePropfindErrors(t *testing.T) {
+	for _, body := range []string{
+		`<propfind xmlns="DAV:"></propfind>`,
+		`<propfind xmlns="DAV:"><prop>`,
+		`<propfind><prop><getetag/></prop></propfind>`,
+	} {
+		if _, err := webdav.DecodePropfind(strings.NewReader(body)); err == nil {
+			t.Fatalf("expected an error for %s", body)
+		}
+	}
+}
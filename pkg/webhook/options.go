@@ -0,0 +1,13 @@
+package webhook
+
+import "time"
+
+// Option -.
+type Option func(*Client)
+
+// Timeout - how long a request may take, 10 seconds by default.
+func Timeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.http.Timeout = timeout
+	}
+}
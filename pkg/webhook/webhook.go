@@ -0,0 +1,105 @@
+// Package webhook sends webhook requests: JSON POSTed to a URL of the receiver, signed with
+// a secret shared with it.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	_defaultTimeout = 10 * time.Second
+	// _maxResponse - how much of a response is read, receivers are expected to answer with no body.
+	_maxResponse = 64 * 1024
+)
+
+// Headers of a request.
+const (
+	// HeaderSignature - signature of the body, see Sign.
+	HeaderSignature = "X-Webhook-Signature"
+	// HeaderID - ID of the request, the same for its retries.
+	HeaderID = "X-Webhook-ID"
+	// HeaderEvent - what the request is about.
+	HeaderEvent = "X-Webhook-Event"
+)
+
+// Request - Body is sent as JSON.
+type Request struct {
+	URL    string
+	Secret string
+	ID     string
+	Event  string
+	Body   []byte
+}
+
+// Client -.
+type Client struct {
+	http *http.Client
+}
+
+// New returns new Client. Redirects are not followed: a receiver answers where it was registered.
+func New(opts ...Option) *Client {
+	c := &Client{
+		http: &http.Client{
+			Timeout: _defaultTimeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Send POSTs the request signed with its secret. It fails unless the receiver answers with 2xx,
+// status is 0 if no response came.
+func (c *Client) Send(ctx context.Context, req Request) (status int, err error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return 0, fmt.Errorf("webhook - Send - http.NewRequestWithContext: %w", err)
+	}
+
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set(HeaderSignature, Sign(req.Secret, req.Body))
+	r.Header.Set(HeaderID, req.ID)
+	r.Header.Set(HeaderEvent, req.Event)
+
+	resp, err := c.http.Do(r)
+	if err != nil {
+		return 0, fmt.Errorf("webhook - Send - c.http.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// read to reuse the connection.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, _maxResponse))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook - Send - unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature of body: "sha256=" and hex of its HMAC-SHA256 with secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the one of body, receivers check it to trust a request.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
@@ -0,0 +1,126 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andreyxaxa/calendar/pkg/webhook"
+)
+
+func TestSend(t *testing.T) {
+	body := []byte(`{"type":"created"}`)
+
+	var got *http.Request
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+
+		if !webhook.Verify("secret", b, r.Header.Get(webhook.HeaderSignature)) {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		got = r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	status, err := webhook.New().Send(context.Background(), webhook.Request{
+		URL:    receiver.URL,
+		Secret: "secret",
+		ID:     "1",
+		Event:  "created",
+		Body:   body,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if status != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", status)
+	}
+
+	if got.Method != http.MethodPost || got.Header.Get("Content-Type") != "application/json" ||
+		got.Header.Get(webhook.HeaderID) != "1" || got.Header.Get(webhook.HeaderEvent) != "created" {
+		t.Fatalf("unexpected request %s %v", got.Method, got.Header)
+	}
+}
+
+func TestSendFails(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		timeout time.Duration
+		status  int
+	}{
+		{
+			name: "error status",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			status: http.StatusInternalServerError,
+		},
+		{
+			name: "redirect",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "/elsewhere", http.StatusFound)
+			},
+			status: http.StatusFound,
+		},
+		{
+			name: "timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			},
+			timeout: 50 * time.Millisecond,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			receiver := httptest.NewServer(tc.handler)
+			defer receiver.Close()
+
+			var opts []webhook.Option
+			if tc.timeout != 0 {
+				opts = append(opts, webhook.Timeout(tc.timeout))
+			}
+
+			status, err := webhook.New(opts...).Send(context.Background(), webhook.Request{URL: receiver.URL})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			if status != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, status)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"type":"created"}`)
+	signature := webhook.Sign("secret", body)
+
+	// HMAC-SHA256 of the body with "secret".
+	if signature != "sha256=ac2c666daae8150ddf14e5f6304ad2ba8154892fd69d54ea612ac8574ec9243d" {
+		t.Fatalf("unexpected signature %s", signature)
+	}
+
+	if !webhook.Verify("secret", body, signature) {
+		t.Fatal("expected the signature to verify")
+	}
+
+	if webhook.Verify("other", body, signature) {
+		t.Fatal("expected another secret to fail")
+	}
+
+	if webhook.Verify("secret", []byte(`{"type":"deleted"}`), signature) {
+		t.Fatal("expected another body to fail")
+	}
+}